@@ -49,6 +49,7 @@ func newServerCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(hub.NewGRPCServerCommand())
+	cmd.AddCommand(hub.NewMQTTServerCommand())
 
 	return cmd
 }
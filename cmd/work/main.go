@@ -11,8 +11,6 @@ import (
 	utilflag "k8s.io/component-base/cli/flag"
 	"k8s.io/component-base/logs"
 
-	ocmfeature "open-cluster-management.io/api/feature"
-
 	"open-cluster-management.io/ocm/pkg/cmd/hub"
 	"open-cluster-management.io/ocm/pkg/cmd/spoke"
 	"open-cluster-management.io/ocm/pkg/cmd/webhook"
@@ -28,7 +26,7 @@ func main() {
 	logs.InitLogs()
 	defer logs.FlushLogs()
 
-	utilruntime.Must(features.HubMutableFeatureGate.Add(ocmfeature.DefaultHubWorkFeatureGates))
+	utilruntime.Must(features.HubMutableFeatureGate.Add(features.DefaultHubWorkFeatureGates))
 	features.HubMutableFeatureGate.AddFlag(pflag.CommandLine)
 
 	command := newWorkCommand()
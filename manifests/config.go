@@ -51,6 +51,11 @@ type HubConfig struct {
 	GRPCServerImage                   string
 	GRPCAutoApprovedUsers             string
 	GRPCEndpointType                  string
+	// WorkAdmissionPolicyConfigMap is the ConfigMap of the admission policy of the ManifestWorks mounted
+	// to the work webhook, and WorkAdmissionPolicyHash is the hash of its data, so the webhook is rolled
+	// out to load the policy once it changes.
+	WorkAdmissionPolicyConfigMap string
+	WorkAdmissionPolicyHash      string
 	// TLS configuration injected into all managed hub component deployments
 	TLSMinVersion   string
 	TLSCipherSuites string
@@ -0,0 +1,33 @@
+package hub
+
+import (
+	"context"
+
+	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	"github.com/spf13/cobra"
+	"k8s.io/utils/clock"
+
+	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
+	mqttopts "open-cluster-management.io/ocm/pkg/server/mqtt"
+	"open-cluster-management.io/ocm/pkg/version"
+)
+
+func NewMQTTServerCommand() *cobra.Command {
+	opts := commonoptions.NewOptions()
+	mqttServerOpts := mqttopts.NewMQTTServerOptions()
+
+	cmdConfig := controllercmd.NewControllerCommandConfig("mqtt-server", version.Get(),
+		opts.StartWithQPS(func(ctx context.Context, cc *controllercmd.ControllerContext) error {
+			return mqttServerOpts.Run(ctx, cc)
+		}), clock.RealClock{})
+
+	cmd := cmdConfig.NewCommandWithContext(context.TODO())
+	cmd.Use = "mqtt"
+	cmd.Short = "Start the MQTT Server"
+
+	flags := cmd.Flags()
+	opts.AddFlags(flags)
+	mqttServerOpts.AddFlags(flags)
+
+	return cmd
+}
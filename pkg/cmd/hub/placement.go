@@ -13,30 +13,44 @@ import (
 
 func NewPlacementController() *cobra.Command {
 	opts := commonoptions.NewOptions()
+	placementOpts := controllers.NewPlacementControllerOptions()
 	cmdConfig := opts.
-		NewControllerCommandConfig("placement", version.Get(), controllers.RunControllerManager, clock.RealClock{})
+		NewControllerCommandConfig("placement", version.Get(), placementOpts.RunControllerManager, clock.RealClock{})
 	cmd := cmdConfig.NewCommandWithContext(context.TODO())
 	cmd.Use = "controller"
 	cmd.Short = "Start the Placement Scheduling Controller"
 
 	flags := cmd.Flags()
 	opts.AddFlags(flags)
+	placementOpts.AddFlags(flags)
 	opts.ApplyTLSToCommand(cmd)
 
+	// each replica schedules the placements of its own shard, so the replicas must not wait for
+	// the leader election when sharding is enabled.
+	cmd.PreRun = func(cmd *cobra.Command, args []string) {
+		if placementOpts.EnableSharding {
+			cmdConfig.DisableLeaderElection = true
+		}
+	}
+
 	return cmd
 }
 
 func NewDebugServer() *cobra.Command {
 	opts := commonoptions.NewOptions()
+	placementOpts := controllers.NewPlacementControllerOptions()
 	cmdConfig := opts.
-		NewControllerCommandConfig("placement-debug", version.Get(), controllers.RunDebugServer, clock.RealClock{})
+		NewControllerCommandConfig("placement-debug", version.Get(), placementOpts.RunDebugServer, clock.RealClock{})
 
 	cmd := cmdConfig.NewCommandWithContext(context.TODO())
 	cmd.Use = "debug"
 	cmd.Short = "Start the Placement Debug Service (standalone)"
 
+	// the scheduler options of the placement controller are accepted, so the what-if results
+	// match the decisions of the placement controller.
 	flags := cmd.Flags()
 	opts.AddFlags(flags)
+	placementOpts.AddFlags(flags)
 	opts.ApplyTLSToCommand(cmd)
 
 	return cmd
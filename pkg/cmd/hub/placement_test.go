@@ -70,3 +70,32 @@ func TestPlacementControllerCommandExecution(t *testing.T) {
 		t.Errorf("Command execution with --help failed: %v", err)
 	}
 }
+
+func TestDebugServerSchedulerFlags(t *testing.T) {
+	cmd := NewDebugServer()
+	for _, name := range []string{"extender-config", "price-table-configmap", "maintenance-windows-configmap"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected the scheduler flag %s on the debug server", name)
+		}
+	}
+}
+
+func TestPlacementControllerShardingDisablesLeaderElection(t *testing.T) {
+	cases := []struct {
+		args                          []string
+		expectedDisableLeaderElection string
+	}{
+		{args: []string{}, expectedDisableLeaderElection: "false"},
+		{args: []string{"--enable-sharding"}, expectedDisableLeaderElection: "true"},
+	}
+	for _, c := range cases {
+		cmd := NewPlacementController()
+		if err := cmd.ParseFlags(c.args); err != nil {
+			t.Fatal(err)
+		}
+		cmd.PreRun(cmd, nil)
+		if value := cmd.Flags().Lookup("disable-leader-election").Value.String(); value != c.expectedDisableLeaderElection {
+			t.Errorf("expected disable-leader-election %s with args %v, but got %s", c.expectedDisableLeaderElection, c.args, value)
+		}
+	}
+}
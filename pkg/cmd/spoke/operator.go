@@ -48,6 +48,11 @@ func NewKlusterletOperatorCmd() *cobra.Command {
 
 	flags.BoolVar(&klOptions.EnableSyncLabels, "enable-sync-labels", false,
 		"If set, will sync the labels of Klusterlet CR to all agent resources")
+	flags.StringVar(&klOptions.StatusJournalPVC, "status-journal-pvc", "",
+		"The name of the PersistentVolumeClaim in the agent namespace to keep the status journal of the work "+
+			"agent with the grpc driver. The journal is kept in an emptyDir if not set, which only survives "+
+			"the restarts of the container, so the status updates not published yet are lost once the pod "+
+			"is evicted or rescheduled")
 
 	opts.AddFlags(flags)
 
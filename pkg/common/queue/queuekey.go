@@ -22,6 +22,14 @@ func FileterByLabelKeyValue(key, value string) factory.EventFilterFunc {
 	}
 }
 
+func FilterByAnnotation(key string) factory.EventFilterFunc {
+	return func(obj interface{}) bool {
+		accessor, _ := meta.Accessor(obj)
+		_, ok := accessor.GetAnnotations()[key]
+		return ok
+	}
+}
+
 func FilterByNames(names ...string) factory.EventFilterFunc {
 	return func(obj interface{}) bool {
 		accessor, _ := meta.Accessor(obj)
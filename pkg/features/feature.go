@@ -3,7 +3,17 @@
 package features
 
 import (
+	"maps"
+
 	"k8s.io/component-base/featuregate"
+
+	ocmfeature "open-cluster-management.io/api/feature"
+)
+
+const (
+	// HelmSource will start a new controller in the Hub rendering the helm chart sources of the
+	// ManifestWorks into their manifests, and render the helm chart sources of the ManifestWorkReplicaSets.
+	HelmSource featuregate.Feature = "HelmSource"
 )
 
 var (
@@ -12,4 +22,12 @@ var (
 
 	// SpokeMutableFeatureGate of multiple mutable feature-gates for agent
 	SpokeMutableFeatureGate = featuregate.NewFeatureGate()
+
+	// DefaultHubWorkFeatureGates consists of the hub work feature keys in the api and the ones only
+	// defined in this repository.
+	DefaultHubWorkFeatureGates = func() map[featuregate.Feature]featuregate.FeatureSpec {
+		gates := maps.Clone(ocmfeature.DefaultHubWorkFeatureGates)
+		gates[HelmSource] = featuregate.FeatureSpec{Default: false, PreRelease: featuregate.Alpha}
+		return gates
+	}()
 )
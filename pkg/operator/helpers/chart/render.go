@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"helm.sh/helm/v3/pkg/chart"
@@ -88,7 +89,7 @@ func renderChart[T *ClusterManagerChartConfig | *KlusterletChartConfig](ctx cont
 		return nil, nil, err
 	}
 
-	crdObjects, rawObjects, err := renderManifests(operatorChart, values, logger)
+	crdObjects, rawObjects, err := renderManifests(operatorChart, values, true, logger)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error rendering cluster manager chart: %v", err)
 	}
@@ -160,6 +161,19 @@ func LoadChart(chartFS embed.FS, chartPrefix string, logger klog.Logger) (*chart
 	return userChart, nil
 }
 
+// RenderUserChart renders a chart provided by users with the values and the release options.
+// Unlike the embedded charts, the templates are rendered in non-strict mode so missing values are
+// allowed. It returns three values: CRD objects, other Kubernetes objects, error.
+func RenderUserChart(ctx context.Context, userChart *chart.Chart, userValues chartutil.Values,
+	releaseOptions chartutil.ReleaseOptions) ([][]byte, [][]byte, error) {
+	logger := klog.FromContext(ctx)
+	values, err := chartutil.ToRenderValues(userChart, userValues, releaseOptions, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, nil, err
+	}
+	return renderManifests(userChart, values, false, logger)
+}
+
 // JsonStructToValues converts the given json struct to a Values
 func JsonStructToValues(a interface{}) (chartutil.Values, error) {
 	raw, err := json.Marshal(a)
@@ -174,7 +188,7 @@ func JsonStructToValues(a interface{}) (chartutil.Values, error) {
 	return vals, nil
 }
 
-func renderManifests(chart *chart.Chart, values chartutil.Values, logger klog.Logger) ([][]byte, [][]byte, error) {
+func renderManifests(chart *chart.Chart, values chartutil.Values, strict bool, logger klog.Logger) ([][]byte, [][]byte, error) {
 	var rawCRDObjects, rawObjects [][]byte
 
 	// make sure the CRDs are at the top.
@@ -185,7 +199,7 @@ func renderManifests(chart *chart.Chart, values chartutil.Values, logger klog.Lo
 	}
 
 	helmEngine := engine.Engine{
-		Strict:   true,
+		Strict:   strict,
 		LintMode: false,
 	}
 
@@ -194,8 +208,20 @@ func renderManifests(chart *chart.Chart, values chartutil.Values, logger klog.Lo
 		return rawCRDObjects, rawObjects, err
 	}
 
+	// render the templates in the order of their names so the result is stable
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		// the notes are not manifests
+		if strings.HasSuffix(name, "NOTES.txt") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
 	namespaceObjects := [][]byte{}
-	for _, template := range templates {
+	for _, name := range names {
+		template := templates[name]
 		// skip the template only including `\n`
 		if len(template) < 2 {
 			continue
@@ -47,6 +47,10 @@ const (
 	SignerSecret      = "signer-secret"
 	CaBundleConfigmap = "ca-bundle-configmap"
 
+	// WorkAdmissionPolicyConfigmap is the optional ConfigMap with the admission policy of the ManifestWorks
+	// in the policy.yaml, which is loaded by the work webhook.
+	WorkAdmissionPolicyConfigmap = "work-admission-policy"
+
 	GRPCServerSecret = "grpc-server-serving-cert" //#nosec G101
 
 	PlacementDebugServingCertSecret = "placement-debug-serving-cert" //#nosec G101
@@ -2,6 +2,7 @@ package clustermanagercontroller
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	errorhelpers "errors"
 	"fmt"
@@ -37,6 +38,7 @@ import (
 	"open-cluster-management.io/ocm/manifests"
 	commonhelper "open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
 )
 
@@ -124,7 +126,7 @@ func NewClusterManagerController(
 		WithInformersQueueKeysFunc(helpers.ClusterManagerDeploymentQueueKeyFunc(controller.clusterManagerLister), deploymentInformer.Informer()).
 		WithFilteredEventsInformersQueueKeysFunc(
 			helpers.ClusterManagerQueueKeyFunc(controller.clusterManagerLister),
-			queue.FilterByNames(helpers.CaBundleConfigmap),
+			queue.FilterByNames(helpers.CaBundleConfigmap, helpers.WorkAdmissionPolicyConfigmap),
 			configMapInformer.Informer()).
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterManagerInformer.Informer()).
 		ToController("ClusterManagerController")
@@ -208,11 +210,12 @@ func (n *clusterManagerController) sync(ctx context.Context, controllerContext f
 	if clusterManager.Spec.WorkConfiguration != nil {
 		workFeatureGates = clusterManager.Spec.WorkConfiguration.FeatureGates
 	}
-	config.WorkFeatureGates, workFeatureMsgs = helpers.ConvertToFeatureGateFlags("Work", workFeatureGates, ocmfeature.DefaultHubWorkFeatureGates)
-	// start work controller if ManifestWorkReplicaSet or CleanUpCompletedManifestWork is enabled
-	config.WorkControllerEnabled = helpers.FeatureGateEnabled(workFeatureGates, ocmfeature.DefaultHubWorkFeatureGates, ocmfeature.ManifestWorkReplicaSet) ||
-		helpers.FeatureGateEnabled(workFeatureGates, ocmfeature.DefaultHubWorkFeatureGates, ocmfeature.CleanUpCompletedManifestWork)
-	config.CloudEventsDriverEnabled = helpers.FeatureGateEnabled(workFeatureGates, ocmfeature.DefaultHubWorkFeatureGates, ocmfeature.CloudEventsDrivers)
+	config.WorkFeatureGates, workFeatureMsgs = helpers.ConvertToFeatureGateFlags("Work", workFeatureGates, features.DefaultHubWorkFeatureGates)
+	// start work controller if ManifestWorkReplicaSet, CleanUpCompletedManifestWork or HelmSource is enabled
+	config.WorkControllerEnabled = helpers.FeatureGateEnabled(workFeatureGates, features.DefaultHubWorkFeatureGates, ocmfeature.ManifestWorkReplicaSet) ||
+		helpers.FeatureGateEnabled(workFeatureGates, features.DefaultHubWorkFeatureGates, ocmfeature.CleanUpCompletedManifestWork) ||
+		helpers.FeatureGateEnabled(workFeatureGates, features.DefaultHubWorkFeatureGates, features.HelmSource)
+	config.CloudEventsDriverEnabled = helpers.FeatureGateEnabled(workFeatureGates, features.DefaultHubWorkFeatureGates, ocmfeature.CloudEventsDrivers)
 
 	var addonFeatureGates []operatorapiv1.FeatureGate
 	if clusterManager.Spec.AddOnManagerConfiguration != nil {
@@ -329,6 +332,17 @@ func (n *clusterManagerController) sync(ctx context.Context, controllerContext f
 	config.WorkAPIServiceCABundle = encodedCaBundle
 	config.AddonAPIServiceCABundle = encodedCaBundle
 
+	// the admission policy of the ManifestWorks is loaded by the work webhook if the ConfigMap exists
+	policy, err := n.configMapLister.ConfigMaps(clusterManagerNamespace).Get(helpers.WorkAdmissionPolicyConfigmap)
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		config.WorkAdmissionPolicyConfigMap = policy.Name
+		config.WorkAdmissionPolicyHash = fmt.Sprintf("%x", sha256.Sum256([]byte(policy.Data["policy.yaml"])))[:16]
+	}
+
 	// check imagePulSecret here because there will be a warning event FailedToRetrieveImagePullSecret
 	// if imagePullSecret does not exist.
 	if config.ImagePullSecret, err = n.getImagePullSecret(ctx); err != nil {
@@ -1464,6 +1464,74 @@ func TestSyncDeployWithTLSConfig(t *testing.T) {
 	}
 }
 
+func TestSyncDeployWithWorkAdmissionPolicy(t *testing.T) {
+	cases := []struct {
+		name         string
+		policyExists bool
+	}{
+		{name: "no admission policy"},
+		{name: "admission policy", policyExists: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterManager := newClusterManager("testhub")
+			tc := newTestController(t, clusterManager)
+			clusterManagerNamespace := helpers.ClusterManagerNamespace(clusterManager.Name, clusterManager.Spec.DeployOption.Mode)
+
+			kubeInformers := kubeinformers.NewSharedInformerFactory(fakekube.NewSimpleClientset(), 5*time.Minute)
+			configMapStore := kubeInformers.Core().V1().ConfigMaps().Informer().GetStore()
+			if err := configMapStore.Add(newCaBundleConfigMap(clusterManagerNamespace)); err != nil {
+				t.Fatal(err)
+			}
+			if c.policyExists {
+				if err := configMapStore.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: helpers.WorkAdmissionPolicyConfigmap, Namespace: clusterManagerNamespace},
+					Data:       map[string]string{"policy.yaml": "maxManifestWorksPerNamespace: 100"},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tc.clusterManagerController.configMapLister = kubeInformers.Core().V1().ConfigMaps().Lister()
+
+			cd := setDeployment(clusterManager.Name, clusterManagerNamespace)
+			setup(t, tc, cd)
+
+			syncContext := testingcommon.NewFakeSyncContext(t, clusterManager.Name)
+			if err := tc.clusterManagerController.sync(ctx, syncContext, clusterManager.Name); err != nil {
+				t.Fatalf("unexpected sync error: %v", err)
+			}
+
+			var webhook *appsv1.Deployment
+			for _, action := range tc.managementKubeClient.Actions() {
+				if action.GetVerb() != "update" {
+					continue
+				}
+				if d, ok := action.(clienttesting.UpdateActionImpl).Object.(*appsv1.Deployment); ok && d.Name == "testhub-work-webhook" {
+					webhook = d
+				}
+			}
+			if webhook == nil {
+				t.Fatalf("work webhook deployment not found in sync actions")
+			}
+
+			podSpec := webhook.Spec.Template.Spec
+			hasPolicyFile := containsArg(podSpec.Containers[0].Args, "--admission-policy-file=")
+			hasPolicyVolume := false
+			for _, volume := range podSpec.Volumes {
+				if volume.ConfigMap != nil && volume.ConfigMap.Name == helpers.WorkAdmissionPolicyConfigmap {
+					hasPolicyVolume = true
+				}
+			}
+			_, hasPolicyHash := webhook.Spec.Template.Annotations["work.open-cluster-management.io/admission-policy-hash"]
+			if hasPolicyFile != c.policyExists || hasPolicyVolume != c.policyExists || hasPolicyHash != c.policyExists {
+				t.Errorf("expected the admission policy wired %v, but got the flag %v, the volume %v and the hash %v",
+					c.policyExists, hasPolicyFile, hasPolicyVolume, hasPolicyHash)
+			}
+		})
+	}
+}
+
 func TestPlacementFeatureGate(t *testing.T) {
 	tests := []struct {
 		name                   string
@@ -1614,6 +1682,18 @@ func TestSyncDeployWithTLSConfigGRPC(t *testing.T) {
 	if !containsArg(args, "--tls-cipher-suites=TLS_AES_256_GCM_SHA384") {
 		t.Errorf("gRPC server deployment missing --tls-cipher-suites flag (args: %v)", args)
 	}
+
+	// the replicas forward the requests to each other with the pod addresses
+	for _, arg := range []string{
+		"--peer-advertise-address=$(POD_IP):8090",
+		"--peer-server-name=testhub-grpc-server.open-cluster-management-hub.svc",
+		"--peer-ca-file=/var/run/secrets/hub/grpc/ca/ca-bundle.crt",
+		"--peer-lease-namespace=open-cluster-management-hub",
+	} {
+		if !containsArg(args, arg) {
+			t.Errorf("gRPC server deployment missing %s flag (args: %v)", arg, args)
+		}
+	}
 }
 
 func containsArg(args []string, prefix string) bool {
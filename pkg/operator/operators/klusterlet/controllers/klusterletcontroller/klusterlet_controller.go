@@ -58,6 +58,7 @@ type klusterletController struct {
 	deploymentReplicas            int32
 	disableAddonNamespace         bool
 	enableSyncLabels              bool
+	statusJournalPVC              string
 }
 
 type klusterletReconcile interface {
@@ -86,7 +87,8 @@ func NewKlusterletController(
 	controlPlaneNodeLabelSelector string,
 	deploymentReplicas int32,
 	disableAddonNamespace bool,
-	enableSyncLabels bool) factory.Controller {
+	enableSyncLabels bool,
+	statusJournalPVC string) factory.Controller {
 	controller := &klusterletController{
 		kubeClient: kubeClient,
 		patcher: patcher.NewPatcher[
@@ -100,6 +102,7 @@ func NewKlusterletController(
 		deploymentReplicas:            deploymentReplicas,
 		disableAddonNamespace:         disableAddonNamespace,
 		enableSyncLabels:              enableSyncLabels,
+		statusJournalPVC:              statusJournalPVC,
 	}
 
 	return factory.New().WithSync(controller.sync).
@@ -204,6 +207,10 @@ type klusterletConfig struct {
 	// DisableAddonNamespace is the flag to disable the creationg of default addon namespace.
 	DisableAddonNamespace bool
 
+	// StatusJournalPVC is the PersistentVolumeClaim keeping the status journal of the work agent. An
+	// emptyDir is used if it is empty.
+	StatusJournalPVC string
+
 	// Labels of the agents are synced from klusterlet CR.
 	Labels             map[string]string
 	RegistrationDriver RegistrationDriver
@@ -296,6 +303,7 @@ func (n *klusterletController) sync(ctx context.Context, controllerContext facto
 		ResourceRequirementResourceType: helpers.ResourceType(klusterlet),
 		ResourceRequirements:            resourceRequirements,
 		DisableAddonNamespace:           n.disableAddonNamespace,
+		StatusJournalPVC:                n.statusJournalPVC,
 	}
 
 	config.populateBootstrap(klusterlet)
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -1423,6 +1424,60 @@ func TestRenderingResourceRequirements(t *testing.T) {
 	}
 }
 
+func TestRenderingStatusJournal(t *testing.T) {
+	cases := []struct {
+		name     string
+		authType string
+		pvc      string
+	}{
+		{name: "csr", authType: "csr"},
+		{name: "grpc", authType: "grpc"},
+		{name: "grpc with pvc", authType: "grpc", pvc: "status-journal"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := newFakeKlusterletConfigWithResourceRequirement(t, nil)
+			config.RegistrationDriver = RegistrationDriver{AuthType: c.authType}
+			config.StatusJournalPVC = c.pvc
+			for _, file := range []string{
+				"klusterlet/management/klusterlet-agent-deployment.yaml",
+				"klusterlet/management/klusterlet-work-deployment.yaml",
+			} {
+				manifest, err := manifests.KlusterletManifestFiles.ReadFile(file)
+				if err != nil {
+					t.Fatalf("Failed to read file %s", file)
+				}
+				objData := assets.MustCreateAssetFromTemplate(file, manifest, config).Data
+				deploy := &appsv1.Deployment{}
+				if err = yaml.Unmarshal(objData, deploy); err != nil {
+					t.Fatalf("Failed to unmarshal deployment: %v", err)
+				}
+
+				// the status journal is only enabled for the grpc driver, and kept in the pvc if set
+				expected := c.authType == "grpc"
+				container := deploy.Spec.Template.Spec.Containers[0]
+				hasArg := slices.Contains(container.Args, "--status-journal-dir=/var/lib/work/status-journal")
+				hasMount := slices.ContainsFunc(container.VolumeMounts, func(m corev1.VolumeMount) bool {
+					return m.Name == "status-journal" && m.MountPath == "/var/lib/work/status-journal"
+				})
+				hasVolume := slices.ContainsFunc(deploy.Spec.Template.Spec.Volumes, func(v corev1.Volume) bool {
+					if v.Name != "status-journal" {
+						return false
+					}
+					if len(c.pvc) > 0 {
+						return v.PersistentVolumeClaim != nil && v.PersistentVolumeClaim.ClaimName == c.pvc
+					}
+					return v.EmptyDir != nil
+				})
+				if hasArg != expected || hasMount != expected || hasVolume != expected {
+					t.Errorf("expected status journal %v in %s, but got arg %v, mount %v and volume %v",
+						expected, file, hasArg, hasMount, hasVolume)
+				}
+			}
+		})
+	}
+}
+
 func TestClusterClaimConfigInSingletonMode(t *testing.T) {
 	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
 	claimConfig := &operatorapiv1.ClusterClaimConfiguration{
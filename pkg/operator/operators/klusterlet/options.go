@@ -33,6 +33,7 @@ type Options struct {
 	DeploymentReplicas            int32
 	DisableAddonNamespace         bool
 	EnableSyncLabels              bool
+	StatusJournalPVC              string
 }
 
 // RunKlusterletOperator starts a new klusterlet operator
@@ -122,7 +123,8 @@ func (o *Options) RunKlusterletOperator(ctx context.Context, controllerContext *
 		o.ControlPlaneNodeLabelSelector,
 		o.DeploymentReplicas,
 		o.DisableAddonNamespace,
-		o.EnableSyncLabels)
+		o.EnableSyncLabels,
+		o.StatusJournalPVC)
 
 	klusterletCleanupController := klusterletcontroller.NewKlusterletCleanupController(
 		kubeClient,
@@ -9,8 +9,12 @@ import (
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"k8s.io/apiserver/pkg/server/mux"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	kevents "k8s.io/client-go/tools/events"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
 
@@ -21,13 +25,23 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/scheduling"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/sharding"
 	"open-cluster-management.io/ocm/pkg/placement/debugger"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/cost"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/extender"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/maintenancewindow"
 )
 
+// RunControllerManager starts the placement scheduling controller with the default options.
+func RunControllerManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+	return NewPlacementControllerOptions().RunControllerManager(ctx, controllerContext)
+}
+
 // RunControllerManager starts the placement scheduling controller.
 // This controller requires leader election and runs the scheduling logic.
 // To be run alongside RunDebugServer using different ports.
-func RunControllerManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+func (o *PlacementControllerOptions) RunControllerManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
 	// setting up contextual logger
 	logger := klog.NewKlogr()
 	podName := os.Getenv("POD_NAME")
@@ -48,10 +62,10 @@ func RunControllerManager(ctx context.Context, controllerContext *controllercmd.
 
 	clusterInformers := clusterinformers.NewSharedInformerFactory(clusterClient, 10*time.Minute)
 
-	return RunControllerManagerWithInformers(ctx, controllerContext, kubeClient, clusterClient, clusterInformers)
+	return o.RunControllerManagerWithInformers(ctx, controllerContext, kubeClient, clusterClient, clusterInformers)
 }
 
-func RunControllerManagerWithInformers(
+func (o *PlacementControllerOptions) RunControllerManagerWithInformers(
 	ctx context.Context,
 	controllerContext *controllercmd.ControllerContext,
 	kubeClient kubernetes.Interface,
@@ -65,7 +79,12 @@ func RunControllerManagerWithInformers(
 
 	metrics := metrics.NewScheduleMetrics(clock.RealClock{})
 
-	scheduler := scheduling.NewPluginScheduler(
+	schedulerFactory, err := o.newSchedulerFactory(ctx, kubeClient)
+	if err != nil {
+		return err
+	}
+
+	scheduler := schedulerFactory(
 		scheduling.NewSchedulerHandler(
 			clusterClient,
 			clusterInformers.Cluster().V1beta1().PlacementDecisions().Lister(),
@@ -74,6 +93,25 @@ func RunControllerManagerWithInformers(
 			recorder, metrics),
 	)
 
+	history := scheduling.NewDecisionHistory(o.DecisionHistorySize)
+	if history != nil && controllerContext.Server != nil {
+		historyDebugger := debugger.NewDebugger(
+			scheduler,
+			kubeClient,
+			clusterInformers.Cluster().V1beta1().Placements(),
+			clusterInformers.Cluster().V1().ManagedClusters(),
+			clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
+			clusterInformers.Cluster().V1beta2().ManagedClusterSetBindings(),
+		).WithHistory(history)
+		controllerContext.Server.Handler.NonGoRestfulMux.HandlePrefix(
+			debugger.HistoryPath, http.HandlerFunc(historyDebugger.HistoryHandler))
+	}
+
+	sharder, err := o.newSharder(controllerContext, kubeClient, clusterInformers)
+	if err != nil {
+		return err
+	}
+
 	schedulingController := scheduling.NewSchedulingController(
 		ctx,
 		clusterClient,
@@ -84,11 +122,23 @@ func RunControllerManagerWithInformers(
 		clusterInformers.Cluster().V1beta1().PlacementDecisions(),
 		clusterInformers.Cluster().V1alpha1().AddOnPlacementScores(),
 		scheduler,
+		history,
+		scheduling.NewDecisionDamper(scheduling.DampingOptions{
+			StabilityWindow:   o.DecisionStabilityWindow,
+			MaxChangeFraction: o.MaxDecisionChangeFraction,
+			QPS:               o.DecisionUpdateQPS,
+			Burst:             o.DecisionUpdateBurst,
+		}),
+		sharder,
 		recorder, metrics,
 	)
 
 	go clusterInformers.Start(ctx.Done())
 
+	if sharder != nil {
+		go sharder.Run(ctx)
+	}
+
 	go schedulingController.Run(ctx, 1)
 
 	<-ctx.Done()
@@ -96,9 +146,16 @@ func RunControllerManagerWithInformers(
 	return nil
 }
 
-// RunDebugServer starts only the debug service without scheduling controller.
-// This server does not require leader election and only provides debug endpoints.
+// RunDebugServer starts only the debug service with the default options.
 func RunDebugServer(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+	return NewPlacementControllerOptions().RunDebugServer(ctx, controllerContext)
+}
+
+// RunDebugServer starts only the debug service without scheduling controller.
+// This server does not require leader election and only provides debug endpoints. The scheduler
+// options should be the same as the scheduling controller, so the what-if results match the
+// decisions of the scheduling controller.
+func (o *PlacementControllerOptions) RunDebugServer(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
 	logger := klog.NewKlogr()
 	podName := os.Getenv("POD_NAME")
 	if podName != "" {
@@ -110,7 +167,7 @@ func RunDebugServer(ctx context.Context, controllerContext *controllercmd.Contro
 		return fmt.Errorf("server is required for debug server but was nil")
 	}
 
-	debug, clusterInformers, err := NewDebuggerWithInformers(ctx, controllerContext.KubeConfig)
+	debug, clusterInformers, err := o.NewDebuggerWithInformers(ctx, controllerContext.KubeConfig)
 	if err != nil {
 		return err
 	}
@@ -125,11 +182,21 @@ func RunDebugServer(ctx context.Context, controllerContext *controllercmd.Contro
 	return nil
 }
 
-// NewDebuggerWithInformers creates a debugger with informers. This is useful for testing.
-// The caller is responsible for starting the informers.
+// NewDebuggerWithInformers creates a debugger with informers and the default options. This is
+// useful for testing. The caller is responsible for starting the informers.
 func NewDebuggerWithInformers(
 	ctx context.Context,
 	kubeConfig *rest.Config,
+) (*debugger.Debugger, clusterinformers.SharedInformerFactory, error) {
+	return NewPlacementControllerOptions().NewDebuggerWithInformers(ctx, kubeConfig)
+}
+
+// NewDebuggerWithInformers creates a debugger with informers, the scheduler of the debugger and
+// the what-if endpoint is built with the scheduler options. The caller is responsible for starting
+// the informers.
+func (o *PlacementControllerOptions) NewDebuggerWithInformers(
+	ctx context.Context,
+	kubeConfig *rest.Config,
 ) (*debugger.Debugger, clusterinformers.SharedInformerFactory, error) {
 	clusterClient, err := clusterclient.NewForConfig(kubeConfig)
 	if err != nil {
@@ -143,13 +210,18 @@ func NewDebuggerWithInformers(
 
 	clusterInformers := clusterinformers.NewSharedInformerFactory(clusterClient, 10*time.Minute)
 
-	scheduler := scheduling.NewPluginScheduler(
+	schedulerFactory, err := o.newSchedulerFactory(ctx, kubeClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scheduler := schedulerFactory(
 		scheduling.NewSchedulerHandler(
 			clusterClient,
 			clusterInformers.Cluster().V1beta1().PlacementDecisions().Lister(),
 			clusterInformers.Cluster().V1alpha1().AddOnPlacementScores().Lister(),
 			clusterInformers.Cluster().V1().ManagedClusters().Lister(),
-			nil, nil), // Debug server doesn't need event recorder or metrics
+			&kevents.FakeRecorder{}, nil), // Debug server doesn't record events or metrics
 	)
 
 	debug := debugger.NewDebugger(
@@ -159,11 +231,149 @@ func NewDebuggerWithInformers(
 		clusterInformers.Cluster().V1().ManagedClusters(),
 		clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
 		clusterInformers.Cluster().V1beta2().ManagedClusterSetBindings(),
+	).WithWhatIf(
+		clusterClient,
+		clusterInformers.Cluster().V1beta1().PlacementDecisions(),
+		clusterInformers.Cluster().V1alpha1().AddOnPlacementScores(),
+		schedulerFactory,
 	)
 
 	return debug, clusterInformers, nil
 }
 
+// newSchedulerFactory loads the extenders, the price source and the maintenance window source, and
+// returns the factory building the scheduler with them. Both the scheduling controller and the
+// debugger build the scheduler with the factory.
+func (o *PlacementControllerOptions) newSchedulerFactory(
+	ctx context.Context, kubeClient kubernetes.Interface) (debugger.SchedulerFactory, error) {
+	extenders, err := o.loadExtenders()
+	if err != nil {
+		return nil, err
+	}
+
+	priceSource, err := o.loadPriceSource(ctx, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	windowSource, err := o.loadWindowSource(ctx, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(handle plugins.Handle) scheduling.Scheduler {
+		return scheduling.NewPluginScheduler(handle).
+			WithExtenders(extenders...).
+			WithCost(priceSource, o.CostWeight).
+			WithMaintenanceWindows(windowSource)
+	}, nil
+}
+
+// loadExtenders builds the scheduler extenders from the extender config file if it is specified.
+func (o *PlacementControllerOptions) loadExtenders() ([]*extender.Extender, error) {
+	if len(o.ExtenderConfigFile) == 0 {
+		return nil, nil
+	}
+
+	config, err := extender.LoadConfig(o.ExtenderConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return extender.NewExtenders(config)
+}
+
+// loadPriceSource builds the price source of the Cost prioritizer from the price table file or
+// the ConfigMap if either is specified.
+func (o *PlacementControllerOptions) loadPriceSource(ctx context.Context, kubeClient kubernetes.Interface) (cost.PriceSource, error) {
+	switch {
+	case len(o.PriceTableFile) > 0 && len(o.PriceTableConfigMap) > 0:
+		return nil, fmt.Errorf("only one of price table file and price table configmap can be specified")
+	case len(o.PriceTableFile) > 0:
+		return cost.NewFilePriceSource(o.PriceTableFile)
+	case len(o.PriceTableConfigMap) > 0:
+		lister, name, err := newConfigMapLister(ctx, kubeClient, o.PriceTableConfigMap)
+		if err != nil {
+			return nil, err
+		}
+		return cost.NewConfigMapPriceSource(lister, name), nil
+	}
+	return nil, nil
+}
+
+// loadWindowSource builds the maintenance window source of the MaintenanceWindow filter from the
+// ConfigMap if it is specified.
+func (o *PlacementControllerOptions) loadWindowSource(
+	ctx context.Context, kubeClient kubernetes.Interface) (maintenancewindow.WindowSource, error) {
+	if len(o.MaintenanceWindowsConfigMap) == 0 {
+		return nil, nil
+	}
+
+	lister, name, err := newConfigMapLister(ctx, kubeClient, o.MaintenanceWindowsConfigMap)
+	if err != nil {
+		return nil, err
+	}
+	return maintenancewindow.NewConfigMapWindowSource(lister, name), nil
+}
+
+// newConfigMapLister returns the lister of the namespace of the ConfigMap with the namespace/name
+// key. The ConfigMap informer of the namespace is started here.
+func newConfigMapLister(ctx context.Context, kubeClient kubernetes.Interface, key string) (
+	corev1listers.ConfigMapNamespaceLister, string, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(namespace) == 0 {
+		return nil, "", fmt.Errorf("the namespace of the configmap %q is required", key)
+	}
+
+	kubeInformers := kubeinformers.NewSharedInformerFactoryWithOptions(
+		kubeClient, 10*time.Minute, kubeinformers.WithNamespace(namespace))
+	configMapInformer := kubeInformers.Core().V1().ConfigMaps()
+	// register the informer before starting the factory
+	configMapInformer.Informer()
+	go kubeInformers.Start(ctx.Done())
+
+	return configMapInformer.Lister().ConfigMaps(namespace), name, nil
+}
+
+// newSharder builds the sharder of this replica if the sharding is enabled. The identity of the replica
+// is the pod name, or the hostname if the pod name is not set.
+func (o *PlacementControllerOptions) newSharder(
+	controllerContext *controllercmd.ControllerContext,
+	kubeClient kubernetes.Interface,
+	clusterInformers clusterinformers.SharedInformerFactory,
+) (*sharding.Sharder, error) {
+	if !o.EnableSharding {
+		return nil, nil
+	}
+
+	identity := os.Getenv("POD_NAME")
+	if len(identity) == 0 {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		identity = hostname
+	}
+
+	namespace := o.ShardLeaseNamespace
+	if len(namespace) == 0 {
+		namespace = controllerContext.OperatorNamespace
+	}
+
+	return sharding.NewSharder(
+		kubeClient.CoordinationV1(),
+		clusterInformers.Cluster().V1beta1().Placements().Lister(),
+		sharding.Options{
+			Namespace:     namespace,
+			Identity:      identity,
+			LeaseDuration: o.ShardLeaseDuration,
+		})
+}
+
 func installDebugger(mux *mux.PathRecorderMux, d *debugger.Debugger) {
 	mux.HandlePrefix(debugger.DebugPath, http.HandlerFunc(d.Handler))
+	mux.HandleFunc(debugger.WhatIfPath, d.WhatIfHandler)
 }
@@ -54,6 +54,7 @@ var (
 
 	metrics = []k8smetrics.Registerable{
 		schedulingDuration, bindDuration, PluginDuration, CelDuration,
+		shardMembers, shardNamespaces, shardPlacements, shardRebalances,
 	}
 )
 
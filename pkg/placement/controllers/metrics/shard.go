@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	k8smetrics "k8s.io/component-base/metrics"
+)
+
+const (
+	// Constants for shard metric names.
+	ShardSubsystem         = "placement_shard"
+	ShardMembersKey        = "members"
+	ShardNamespacesKey     = "owned_namespaces"
+	ShardPlacementsKey     = "owned_placements"
+	ShardRebalancesKey     = "rebalances_total"
+	shardLabel             = "shard"
+	defaultShardIdentifier = "default"
+)
+
+// Metrics of the placement shards, labeled by the identity of the replica owning the shard.
+var (
+	shardMembers = k8smetrics.NewGaugeVec(&k8smetrics.GaugeOpts{
+		Subsystem:      ShardSubsystem,
+		Name:           ShardMembersKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of the live placement controller replicas seen by the shard.",
+	}, []string{shardLabel})
+
+	shardNamespaces = k8smetrics.NewGaugeVec(&k8smetrics.GaugeOpts{
+		Subsystem:      ShardSubsystem,
+		Name:           ShardNamespacesKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of the placement namespaces owned by the shard at the last rebalance.",
+	}, []string{shardLabel})
+
+	shardPlacements = k8smetrics.NewGaugeVec(&k8smetrics.GaugeOpts{
+		Subsystem:      ShardSubsystem,
+		Name:           ShardPlacementsKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of the placements owned by the shard at the last rebalance.",
+	}, []string{shardLabel})
+
+	shardRebalances = k8smetrics.NewCounterVec(&k8smetrics.CounterOpts{
+		Subsystem:      ShardSubsystem,
+		Name:           ShardRebalancesKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of the times the placement namespaces are rebalanced across the shards.",
+	}, []string{shardLabel})
+)
+
+// ShardMetrics records the metrics of a placement shard.
+type ShardMetrics struct {
+	members    k8smetrics.GaugeMetric
+	namespaces k8smetrics.GaugeMetric
+	placements k8smetrics.GaugeMetric
+	rebalances k8smetrics.CounterMetric
+}
+
+// NewShardMetrics creates a new ShardMetrics instance for the shard with the given identity.
+func NewShardMetrics(shard string) *ShardMetrics {
+	if len(shard) == 0 {
+		shard = defaultShardIdentifier
+	}
+	return &ShardMetrics{
+		members:    shardMembers.WithLabelValues(shard),
+		namespaces: shardNamespaces.WithLabelValues(shard),
+		placements: shardPlacements.WithLabelValues(shard),
+		rebalances: shardRebalances.WithLabelValues(shard),
+	}
+}
+
+// Rebalanced records a rebalance with the number of the members and the owned namespaces and placements.
+func (m *ShardMetrics) Rebalanced(members, namespaces, placements int) {
+	if m == nil {
+		return
+	}
+
+	m.rebalances.Inc()
+	m.members.Set(float64(members))
+	m.namespaces.Set(float64(namespaces))
+	m.placements.Set(float64(placements))
+}
@@ -0,0 +1,87 @@
+package hub
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/sharding"
+)
+
+// PlacementControllerOptions defines the flags for placement controller
+type PlacementControllerOptions struct {
+	// ExtenderConfigFile is the path of the config file of the out-of-tree scheduler extenders.
+	ExtenderConfigFile string
+	// DecisionHistorySize is the max number of the decision history records kept for each placement.
+	DecisionHistorySize int
+	// DecisionStabilityWindow is the minimum duration between two decision changes of a placement
+	// which remove clusters.
+	DecisionStabilityWindow time.Duration
+	// MaxDecisionChangeFraction is the max fraction of the decisions of a placement removed in one change.
+	MaxDecisionChangeFraction float64
+	// DecisionUpdateQPS and DecisionUpdateBurst limit the decision updates of all the placements.
+	DecisionUpdateQPS   float32
+	DecisionUpdateBurst int
+	// CostWeight is the default weight of the Cost prioritizer in the Additive placements. It is 0 by
+	// default, so the Cost prioritizer is only used by the placements configuring it in their prioritizer policy.
+	CostWeight int32
+	// PriceTableFile is the path of the price table file of the Cost prioritizer.
+	PriceTableFile string
+	// PriceTableConfigMap is the namespace/name of the ConfigMap with the price table of the Cost prioritizer.
+	PriceTableConfigMap string
+	// MaintenanceWindowsConfigMap is the namespace/name of the ConfigMap with the maintenance windows
+	// of the clusters keyed by cluster name.
+	MaintenanceWindowsConfigMap string
+	// EnableSharding splits the placement namespaces across the replicas of the placement controller.
+	EnableSharding bool
+	// ShardLeaseNamespace is the namespace of the shard Leases. The namespace of the controller is used if not set.
+	ShardLeaseNamespace string
+	// ShardLeaseDuration is the duration after which a replica not renewing its shard Lease is
+	// removed from the shards.
+	ShardLeaseDuration time.Duration
+}
+
+// NewPlacementControllerOptions returns a PlacementControllerOptions
+func NewPlacementControllerOptions() *PlacementControllerOptions {
+	return &PlacementControllerOptions{
+		DecisionHistorySize: 10,
+		DecisionUpdateBurst: 10,
+		ShardLeaseDuration:  sharding.DefaultLeaseDuration,
+	}
+}
+
+// AddFlags register and binds the default flags
+func (o *PlacementControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.ExtenderConfigFile, "extender-config", o.ExtenderConfigFile,
+		"The config file path of the scheduler extenders called by the placement controller to filter and score clusters.")
+	fs.IntVar(&o.DecisionHistorySize, "decision-history-size", o.DecisionHistorySize,
+		"The max number of the scheduling results which change the decisions kept in memory for each placement. "+
+			"The history is served on the debug path of the placement controller. Set to 0 to disable the history.")
+	fs.DurationVar(&o.DecisionStabilityWindow, "decision-stability-window", o.DecisionStabilityWindow,
+		"The minimum duration between two decision changes of a placement which remove clusters. "+
+			"Set to 0 to disable the stability window.")
+	fs.Float64Var(&o.MaxDecisionChangeFraction, "max-decision-change-fraction", o.MaxDecisionChangeFraction,
+		"The max fraction of the decisions of a placement which may be removed in one stability window. "+
+			"Set to 0 to disable the limit.")
+	fs.Float32Var(&o.DecisionUpdateQPS, "decision-update-qps", o.DecisionUpdateQPS,
+		"The max number of the decision updates of all the placements per second. Set to 0 to disable the rate limit.")
+	fs.IntVar(&o.DecisionUpdateBurst, "decision-update-burst", o.DecisionUpdateBurst,
+		"The burst of the decision updates of all the placements.")
+	fs.Int32Var(&o.CostWeight, "cost-weight", o.CostWeight,
+		"The default weight of the Cost prioritizer which prefers the cheaper clusters in the Additive placements. "+
+			"If it is 0, the Cost prioritizer is only used by the placements configuring it in the prioritizer policy.")
+	fs.StringVar(&o.PriceTableFile, "price-table-file", o.PriceTableFile,
+		"The path of the price table file keyed by provider, region and instance type used by the Cost prioritizer.")
+	fs.StringVar(&o.PriceTableConfigMap, "price-table-configmap", o.PriceTableConfigMap,
+		"The namespace/name of the ConfigMap with the price table in the key prices.yaml used by the Cost prioritizer.")
+	fs.StringVar(&o.MaintenanceWindowsConfigMap, "maintenance-windows-configmap", o.MaintenanceWindowsConfigMap,
+		"The namespace/name of the ConfigMap with the maintenance windows of the clusters keyed by cluster name. "+
+			"The clusters are kept out of the new decisions during their maintenance windows.")
+	fs.BoolVar(&o.EnableSharding, "enable-sharding", o.EnableSharding,
+		"Split the placement namespaces across the replicas of the placement controller with consistent hashing. "+
+			"Each replica schedules the placements in the namespaces it owns, so leader election is disabled.")
+	fs.StringVar(&o.ShardLeaseNamespace, "shard-lease-namespace", o.ShardLeaseNamespace,
+		"The namespace of the leases of the placement shards. The namespace of the controller is used if not set.")
+	fs.DurationVar(&o.ShardLeaseDuration, "shard-lease-duration", o.ShardLeaseDuration,
+		"The duration after which a replica not renewing its shard lease is removed and its namespaces are rebalanced.")
+}
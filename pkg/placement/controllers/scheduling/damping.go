@@ -0,0 +1,224 @@
+package scheduling
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/clock"
+
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+const (
+	// PlacementConditionDecisionDamped means the decisions of the placement are not fully updated to
+	// the scheduling result to avoid the decision churn.
+	PlacementConditionDecisionDamped = "DecisionDamped"
+
+	dampedReasonStabilityWindow = "StabilityWindow"
+	dampedReasonChangeLimit     = "ChangeLimit"
+	dampedReasonRateLimited     = "RateLimited"
+	notDampedReason             = "NotDamped"
+
+	// defaultChangeInterval is the interval to continue the changes limited by the max change
+	// fraction when the stability window is not set.
+	defaultChangeInterval = 30 * time.Second
+)
+
+// DampingOptions defines how the decision changes are damped.
+type DampingOptions struct {
+	// StabilityWindow is the minimum duration between two decision changes of a placement which
+	// remove clusters from the decisions.
+	StabilityWindow time.Duration
+	// MaxChangeFraction is the max fraction of the existing decisions of a placement which may be
+	// removed in one change. At least one decision can be changed. 0 means no limit.
+	MaxChangeFraction float64
+	// QPS and Burst limit the decision updates of all the placements.
+	QPS   float32
+	Burst int
+}
+
+// DecisionDamper damps the decision changes of the placements, so a flapping of many clusters does
+// not cause all the decisions to be rewritten at once. The stability window and the max change
+// fraction only damp the removal of the clusters: the added clusters are decided immediately, so a
+// placement may temporarily have more decisions than its number of clusters until the damped
+// clusters are removed. The clusters which are deleted are removed from the decisions without damping.
+type DecisionDamper struct {
+	options     DampingOptions
+	clock       clock.Clock
+	limiter     *rate.Limiter
+	lock        sync.Mutex
+	lastChanged map[string]time.Time
+}
+
+// dampedResult overrides the decisions of the schedule result with the damped decisions.
+type dampedResult struct {
+	ScheduleResult
+	decisions []*clusterapiv1.ManagedCluster
+}
+
+func (r *dampedResult) Decisions() []*clusterapiv1.ManagedCluster {
+	return r.decisions
+}
+
+// NewDecisionDamper returns a DecisionDamper. nil is returned if none of the damping is enabled.
+func NewDecisionDamper(options DampingOptions) *DecisionDamper {
+	if options.StabilityWindow <= 0 && options.MaxChangeFraction <= 0 && options.QPS <= 0 {
+		return nil
+	}
+
+	damper := &DecisionDamper{
+		options:     options,
+		clock:       clock.RealClock{},
+		lastChanged: map[string]time.Time{},
+	}
+	if options.QPS > 0 {
+		burst := options.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		damper.limiter = rate.NewLimiter(rate.Limit(options.QPS), burst)
+	}
+	return damper
+}
+
+// Damp returns the schedule result with the damped decisions, the DecisionDamped condition and the
+// duration after which the placement should be scheduled again to continue the damped changes.
+func (d *DecisionDamper) Damp(
+	key string,
+	existing sets.Set[string],
+	result ScheduleResult,
+	clusterLister clusterlisterv1.ManagedClusterLister,
+) (ScheduleResult, *metav1.Condition, *time.Duration) {
+	if d == nil {
+		return result, nil, nil
+	}
+
+	condition := &metav1.Condition{
+		Type:    PlacementConditionDecisionDamped,
+		Status:  metav1.ConditionFalse,
+		Reason:  notDampedReason,
+		Message: "Decisions are not damped",
+	}
+
+	// the deleted clusters are removed without damping
+	existing = existingClusterNames(existing, clusterLister)
+
+	desired := result.Decisions()
+	desiredNames := clusterNames(desired)
+	removed := sets.List(existing.Difference(desiredNames))
+	added := desiredNames.Difference(existing)
+	if len(removed) == 0 && added.Len() == 0 {
+		return result, condition, nil
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	now := d.clock.Now()
+
+	var dampedDecisions []*clusterapiv1.ManagedCluster
+	var requeueAfter *time.Duration
+	if len(removed) > 0 {
+		// keep the existing decisions within the stability window
+		if last, ok := d.lastChanged[key]; ok && d.options.StabilityWindow > 0 && now.Sub(last) < d.options.StabilityWindow {
+			remaining := d.options.StabilityWindow - now.Sub(last)
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = dampedReasonStabilityWindow
+			condition.Message = fmt.Sprintf("Decisions changed at %s, %d clusters are not removed within the stability window %s",
+				last.Format(time.RFC3339), len(removed), d.options.StabilityWindow)
+			dampedDecisions = keepRemoved(desired, removed, clusterLister)
+			requeueAfter = &remaining
+		} else if d.options.MaxChangeFraction > 0 {
+			// remove at most the max fraction of the existing decisions
+			limit := int(math.Floor(d.options.MaxChangeFraction * float64(existing.Len())))
+			if limit < 1 {
+				limit = 1
+			}
+			if len(removed) > limit {
+				dampedDecisions = keepRemoved(desired, removed[limit:], clusterLister)
+				interval := d.options.StabilityWindow
+				if interval <= 0 {
+					interval = defaultChangeInterval
+				}
+				requeueAfter = &interval
+				condition.Status = metav1.ConditionTrue
+				condition.Reason = dampedReasonChangeLimit
+				condition.Message = fmt.Sprintf("%d of %d clusters are removed from the decisions, the max change fraction is %v",
+					limit, len(removed), d.options.MaxChangeFraction)
+			}
+		}
+	}
+
+	// keep the existing decisions if the global rate limit is exceeded
+	if d.limiter != nil {
+		reservation := d.limiter.ReserveN(now, 1)
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			reservation.CancelAt(now)
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = dampedReasonRateLimited
+			condition.Message = fmt.Sprintf("Decision updates are rate limited, retry after %s", delay)
+			return &dampedResult{ScheduleResult: result, decisions: existingClusters(existing, clusterLister)}, condition, &delay
+		}
+	}
+
+	// the stability window starts when the clusters are removed
+	if len(removed) > 0 && condition.Reason != dampedReasonStabilityWindow {
+		d.lastChanged[key] = now
+	}
+	if dampedDecisions != nil {
+		return &dampedResult{ScheduleResult: result, decisions: dampedDecisions}, condition, requeueAfter
+	}
+	return result, condition, nil
+}
+
+// Forget removes the damping state of the placement with the given key.
+func (d *DecisionDamper) Forget(key string) {
+	if d == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.lastChanged, key)
+}
+
+// keepRemoved returns the desired decisions with the given clusters which are removed from the
+// desired decisions kept.
+func keepRemoved(
+	desired []*clusterapiv1.ManagedCluster,
+	kept []string,
+	clusterLister clusterlisterv1.ManagedClusterLister,
+) []*clusterapiv1.ManagedCluster {
+	decisions := append([]*clusterapiv1.ManagedCluster{}, desired...)
+	return append(decisions, existingClusters(sets.New[string](kept...), clusterLister)...)
+}
+
+// existingClusterNames returns the names of the given clusters which still exist.
+func existingClusterNames(names sets.Set[string], clusterLister clusterlisterv1.ManagedClusterLister) sets.Set[string] {
+	existing := sets.New[string]()
+	for name := range names {
+		if _, err := clusterLister.Get(name); err == nil {
+			existing.Insert(name)
+		}
+	}
+	return existing
+}
+
+// existingClusters returns the clusters with the given names sorted by name. The clusters which do
+// not exist anymore are skipped.
+func existingClusters(names sets.Set[string], clusterLister clusterlisterv1.ManagedClusterLister) []*clusterapiv1.ManagedCluster {
+	clusters := []*clusterapiv1.ManagedCluster{}
+	for _, name := range sets.List(names) {
+		cluster, err := clusterLister.Get(name)
+		if err != nil {
+			continue
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
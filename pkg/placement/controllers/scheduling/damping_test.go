@@ -0,0 +1,162 @@
+package scheduling
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	testingclock "k8s.io/utils/clock/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestDecisionDamper(t *testing.T) {
+	var clusters []runtime.Object
+	for i := 1; i <= 8; i++ {
+		clusters = append(clusters, testinghelpers.NewManagedCluster(fmt.Sprintf("cluster%d", i)).Build())
+	}
+	clusterLister := testinghelpers.NewClusterInformerFactory(clusterfake.NewSimpleClientset(), clusters...).
+		Cluster().V1().ManagedClusters().Lister()
+
+	type step struct {
+		key                  string
+		existing             []string
+		desired              []string
+		elapsed              time.Duration
+		expectedDecisions    []string
+		expectedReason       string
+		expectedRequeueAfter *time.Duration
+	}
+	duration := func(d time.Duration) *time.Duration {
+		return &d
+	}
+
+	cases := []struct {
+		name    string
+		options DampingOptions
+		steps   []step
+	}{
+		{
+			name:    "stability window",
+			options: DampingOptions{StabilityWindow: time.Minute},
+			steps: []step{
+				{
+					key: "ns/p1", existing: []string{"cluster1", "cluster2"}, desired: []string{"cluster2", "cluster3"},
+					expectedDecisions: []string{"cluster2", "cluster3"}, expectedReason: notDampedReason,
+				},
+				{
+					// cluster4 is added while the removal of cluster2 is damped
+					key: "ns/p1", existing: []string{"cluster2", "cluster3"}, desired: []string{"cluster3", "cluster4"},
+					elapsed:           20 * time.Second,
+					expectedDecisions: []string{"cluster3", "cluster4", "cluster2"}, expectedReason: dampedReasonStabilityWindow,
+					expectedRequeueAfter: duration(40 * time.Second),
+				},
+				{
+					// the deleted cluster is removed within the stability window
+					key: "ns/p1", existing: []string{"cluster2", "cluster3", "cluster9"}, desired: []string{"cluster2", "cluster3"},
+					expectedDecisions: []string{"cluster2", "cluster3"}, expectedReason: notDampedReason,
+				},
+				{
+					// adding clusters is not damped
+					key: "ns/p1", existing: []string{"cluster2", "cluster3"}, desired: []string{"cluster2", "cluster3", "cluster4"},
+					expectedDecisions: []string{"cluster2", "cluster3", "cluster4"}, expectedReason: notDampedReason,
+				},
+				{
+					key: "ns/p1", existing: []string{"cluster2", "cluster3", "cluster4"}, desired: []string{"cluster4"},
+					elapsed:           time.Minute,
+					expectedDecisions: []string{"cluster4"}, expectedReason: notDampedReason,
+				},
+			},
+		},
+		{
+			name:    "max change fraction",
+			options: DampingOptions{MaxChangeFraction: 0.25},
+			steps: []step{
+				{
+					key:      "ns/p1",
+					existing: []string{"cluster1", "cluster2", "cluster3", "cluster4"},
+					desired:  []string{"cluster5", "cluster6", "cluster7", "cluster8"},
+					// only cluster1 is removed, while all the desired clusters are added
+					expectedDecisions: []string{
+						"cluster5", "cluster6", "cluster7", "cluster8", "cluster2", "cluster3", "cluster4"},
+					expectedReason:       dampedReasonChangeLimit,
+					expectedRequeueAfter: duration(defaultChangeInterval),
+				},
+				{
+					key:               "ns/p1",
+					existing:          []string{"cluster1", "cluster2", "cluster3", "cluster4"},
+					desired:           []string{"cluster1", "cluster2", "cluster3", "cluster5"},
+					expectedDecisions: []string{"cluster1", "cluster2", "cluster3", "cluster5"},
+					expectedReason:    notDampedReason,
+				},
+			},
+		},
+		{
+			name:    "rate limit",
+			options: DampingOptions{QPS: 1, Burst: 1},
+			steps: []step{
+				{
+					key: "ns/p1", existing: []string{"cluster1"}, desired: []string{"cluster2"},
+					expectedDecisions: []string{"cluster2"}, expectedReason: notDampedReason,
+				},
+				{
+					key: "ns/p2", existing: []string{"cluster1"}, desired: []string{"cluster2"},
+					expectedDecisions: []string{"cluster1"}, expectedReason: dampedReasonRateLimited,
+					expectedRequeueAfter: duration(time.Second),
+				},
+				{
+					// no decision change is not rate limited
+					key: "ns/p3", existing: []string{"cluster1"}, desired: []string{"cluster1"},
+					expectedDecisions: []string{"cluster1"}, expectedReason: notDampedReason,
+				},
+				{
+					key: "ns/p2", existing: []string{"cluster1"}, desired: []string{"cluster2"},
+					elapsed:           time.Second,
+					expectedDecisions: []string{"cluster2"}, expectedReason: notDampedReason,
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fakeClock := testingclock.NewFakeClock(time.Now())
+			damper := NewDecisionDamper(c.options)
+			damper.clock = fakeClock
+
+			for i, s := range c.steps {
+				fakeClock.Step(s.elapsed)
+				result, condition, requeueAfter := damper.Damp(
+					s.key, sets.New[string](s.existing...), newHistoryScheduleResult(s.desired...), clusterLister)
+
+				var decisions []string
+				for _, cluster := range result.Decisions() {
+					decisions = append(decisions, cluster.Name)
+				}
+				if !reflect.DeepEqual(decisions, s.expectedDecisions) {
+					t.Errorf("step %d: expected decisions %v, but got %v", i, s.expectedDecisions, decisions)
+				}
+				if condition.Reason != s.expectedReason {
+					t.Errorf("step %d: expected reason %s, but got %s", i, s.expectedReason, condition.Reason)
+				}
+				if (condition.Status == metav1.ConditionTrue) != (s.expectedReason != notDampedReason) {
+					t.Errorf("step %d: unexpected condition status %s", i, condition.Status)
+				}
+				if !reflect.DeepEqual(requeueAfter, s.expectedRequeueAfter) {
+					t.Errorf("step %d: expected requeue after %v, but got %v", i, s.expectedRequeueAfter, requeueAfter)
+				}
+			}
+		})
+	}
+
+	// damping is disabled without options
+	if damper := NewDecisionDamper(DampingOptions{}); damper != nil {
+		t.Errorf("expected nil damper")
+	}
+}
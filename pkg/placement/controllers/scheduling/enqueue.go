@@ -19,6 +19,8 @@ import (
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	clusterapiv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	clustersdkv1beta2 "open-cluster-management.io/sdk-go/pkg/apis/cluster/v1beta2"
+
+	"open-cluster-management.io/ocm/pkg/placement/plugins/affinity"
 )
 
 const (
@@ -28,6 +30,7 @@ const (
 	placementsByClusterSetBinding  = "placementsByClusterSet"
 	clustersetBindingsByClusterSet = "clustersetBindingsByClusterSet"
 	placementsByScore              = "placementsByScore"
+	placementsByAffinity           = "placementsByAffinity"
 )
 
 type enqueuer struct {
@@ -51,6 +54,7 @@ func newEnqueuer(
 	err := placementInformer.Informer().AddIndexers(cache.Indexers{
 		placementsByScore:             indexPlacementsByScore,
 		placementsByClusterSetBinding: indexPlacementByClusterSetBinding,
+		placementsByAffinity:          indexPlacementsByAffinity,
 	})
 	if err != nil {
 		runtime.HandleError(err)
@@ -213,6 +217,38 @@ func (e *enqueuer) enqueuePlacementScore(obj interface{}) {
 	}
 }
 
+// enqueuePlacementDecision enqueues the placements whose affinity or anti-affinity references
+// the placement of the decision.
+func (e *enqueuer) enqueuePlacementDecision(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	decision, ok := obj.(*clusterapiv1beta1.PlacementDecision)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj %T is not a PlacementDecision", obj))
+		return
+	}
+
+	placementName, ok := decision.Labels[clusterapiv1beta1.PlacementLabel]
+	if !ok {
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s", decision.Namespace, placementName)
+	objs, err := e.placementIndexer.ByIndex(placementsByAffinity, key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	for _, o := range objs {
+		placement := o.(*clusterapiv1beta1.Placement)
+		e.logger.V(4).Info("Enqueue placement because of affinity", "placementNamespace", placement.Namespace, "placementName", placement.Name, "referencedPlacementKey", key)
+		e.enqueuePlacementFunc(placement, e.queue)
+	}
+}
+
 func indexPlacementByClusterSetBinding(obj interface{}) ([]string, error) {
 	placement, ok := obj.(*clusterapiv1beta1.Placement)
 	if !ok {
@@ -255,6 +291,26 @@ func indexPlacementsByScore(obj interface{}) ([]string, error) {
 	return keys, nil
 }
 
+func indexPlacementsByAffinity(obj interface{}) ([]string, error) {
+	placement, ok := obj.(*clusterapiv1beta1.Placement)
+	if !ok {
+		return []string{}, fmt.Errorf("obj %T is not a Placement", obj)
+	}
+
+	// the placement with invalid annotation is not indexed
+	terms, err := affinity.GetAffinityTerms(placement)
+	if err != nil || terms == nil {
+		return []string{}, nil
+	}
+
+	var keys []string
+	for _, name := range sets.List(terms.ReferencedPlacements()) {
+		keys = append(keys, fmt.Sprintf("%s/%s", placement.Namespace, name))
+	}
+
+	return keys, nil
+}
+
 func indexClusterSetBindingByClusterSet(obj interface{}) ([]string, error) {
 	binding, ok := obj.(*clusterapiv1beta2.ManagedClusterSetBinding)
 	if !ok {
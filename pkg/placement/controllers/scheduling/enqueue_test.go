@@ -22,6 +22,7 @@ import (
 
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/affinity"
 )
 
 func newClusterInformerFactory(t *testing.T, clusterClient clusterclient.Interface, objects ...runtime.Object) clusterinformers.SharedInformerFactory {
@@ -30,6 +31,7 @@ func newClusterInformerFactory(t *testing.T, clusterClient clusterclient.Interfa
 	err := clusterInformerFactory.Cluster().V1beta1().Placements().Informer().AddIndexers(cache.Indexers{
 		placementsByScore:             indexPlacementsByScore,
 		placementsByClusterSetBinding: indexPlacementByClusterSetBinding,
+		placementsByAffinity:          indexPlacementsByAffinity,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -412,3 +414,91 @@ func TestEnqueuePlacementsByScore(t *testing.T) {
 		})
 	}
 }
+
+func TestEnqueuePlacementsByAffinity(t *testing.T) {
+	affinityAnnotation := func(value string) map[string]string {
+		return map[string]string{affinity.PlacementAffinityAnnotation: value}
+	}
+
+	cases := []struct {
+		name       string
+		decision   interface{}
+		initObjs   []runtime.Object
+		queuedKeys []string
+	}{
+		{
+			name: "enqueue placements referencing the placement of the decision",
+			decision: testinghelpers.NewPlacementDecision("ns1", "placement1-decision-1").
+				WithLabel(clusterapiv1beta1.PlacementLabel, "placement1").Build(),
+			initObjs: []runtime.Object{
+				testinghelpers.NewPlacement("ns1", "placement1").Build(),
+				testinghelpers.NewPlacementWithAnnotations("ns1", "placement2",
+					affinityAnnotation(`{"affinity":{"required":["placement1"]}}`)).Build(),
+				testinghelpers.NewPlacementWithAnnotations("ns1", "placement3",
+					affinityAnnotation(`{"antiAffinity":{"preferred":["placement1"]}}`)).Build(),
+				testinghelpers.NewPlacementWithAnnotations("ns1", "placement4",
+					affinityAnnotation(`{"affinity":{"required":["placement2"]}}`)).Build(),
+				testinghelpers.NewPlacementWithAnnotations("ns2", "placement5",
+					affinityAnnotation(`{"affinity":{"required":["placement1"]}}`)).Build(),
+			},
+			queuedKeys: []string{
+				"ns1/placement2",
+				"ns1/placement3",
+			},
+		},
+		{
+			name:     "decision without placement label",
+			decision: testinghelpers.NewPlacementDecision("ns1", "placement1-decision-1").Build(),
+			initObjs: []runtime.Object{
+				testinghelpers.NewPlacementWithAnnotations("ns1", "placement2",
+					affinityAnnotation(`{"affinity":{"required":["placement1"]}}`)).Build(),
+			},
+			queuedKeys: []string{},
+		},
+		{
+			name: "tombstone",
+			decision: cache.DeletedFinalStateUnknown{
+				Key: "ns1/placement1-decision-1",
+				Obj: testinghelpers.NewPlacementDecision("ns1", "placement1-decision-1").
+					WithLabel(clusterapiv1beta1.PlacementLabel, "placement1").Build(),
+			},
+			initObjs: []runtime.Object{
+				testinghelpers.NewPlacementWithAnnotations("ns1", "placement2",
+					affinityAnnotation(`{"affinity":{"preferred":["placement1"]}}`)).Build(),
+			},
+			queuedKeys: []string{
+				"ns1/placement2",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			clusterClient := clusterfake.NewSimpleClientset(c.initObjs...)
+			clusterInformerFactory := newClusterInformerFactory(t, clusterClient, c.initObjs...)
+
+			syncCtx := testingcommon.NewFakeSyncContext(t, "fake")
+			q := newEnqueuer(
+				ctx,
+				syncCtx.Queue(),
+				clusterInformerFactory.Cluster().V1().ManagedClusters(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+				clusterInformerFactory.Cluster().V1beta1().Placements(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings(),
+			)
+			queuedKeys := sets.NewString()
+			fakeEnqueuePlacement := func(obj interface{}, queue workqueue.TypedRateLimitingInterface[string]) {
+				key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+				queuedKeys.Insert(key)
+			}
+			q.enqueuePlacementFunc = fakeEnqueuePlacement
+			q.enqueuePlacementDecision(c.decision)
+
+			expectedQueuedKeys := sets.NewString(c.queuedKeys...)
+			if !queuedKeys.Equal(expectedQueuedKeys) {
+				t.Errorf("expected queued placements %q, but got %s", strings.Join(expectedQueuedKeys.List(), ","), strings.Join(queuedKeys.List(), ","))
+			}
+		})
+	}
+}
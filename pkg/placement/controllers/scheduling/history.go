@@ -0,0 +1,109 @@
+package scheduling
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// HistoryRecord is the record of a scheduling result which changes the decisions of a placement.
+type HistoryRecord struct {
+	Time              metav1.Time         `json:"time"`
+	Added             []string            `json:"added,omitempty"`
+	Removed           []string            `json:"removed,omitempty"`
+	Decisions         []string            `json:"decisions"`
+	FilterResults     []FilterResult      `json:"filteredPipelineResults,omitempty"`
+	PrioritizeResults []PrioritizerResult `json:"prioritizeResults,omitempty"`
+	AggregatedScores  PrioritizerScore    `json:"aggregatedScores,omitempty"`
+	Message           string              `json:"message,omitempty"`
+}
+
+// DecisionHistory keeps the latest scheduling results which change the decisions of each placement
+// in memory. At most size records are kept for each placement, and the oldest record is dropped
+// when a new one is added.
+type DecisionHistory struct {
+	lock    sync.RWMutex
+	size    int
+	records map[string][]HistoryRecord
+}
+
+// NewDecisionHistory returns a DecisionHistory keeping at most size records for each placement.
+// nil is returned if the size is not positive, and the history is disabled.
+func NewDecisionHistory(size int) *DecisionHistory {
+	if size <= 0 {
+		return nil
+	}
+	return &DecisionHistory{
+		size:    size,
+		records: map[string][]HistoryRecord{},
+	}
+}
+
+// Record adds a record for the placement with the given key if the decisions change.
+func (h *DecisionHistory) Record(key string, existing sets.Set[string], result ScheduleResult, message string) {
+	if h == nil {
+		return
+	}
+
+	desired := clusterNames(result.Decisions())
+	added := desired.Difference(existing)
+	removed := existing.Difference(desired)
+	if added.Len() == 0 && removed.Len() == 0 {
+		return
+	}
+
+	record := HistoryRecord{
+		Time:              metav1.Now(),
+		Added:             sets.List(added),
+		Removed:           sets.List(removed),
+		Decisions:         sets.List(desired),
+		FilterResults:     result.FilterResults(),
+		PrioritizeResults: result.PrioritizerResults(),
+		AggregatedScores:  result.PrioritizerScores(),
+		Message:           message,
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	records := append(h.records[key], record)
+	if len(records) > h.size {
+		records = records[len(records)-h.size:]
+	}
+	h.records[key] = records
+}
+
+// Get returns the records of the placement with the given key, the oldest first.
+func (h *DecisionHistory) Get(key string) []HistoryRecord {
+	if h == nil {
+		return nil
+	}
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	records := make([]HistoryRecord, len(h.records[key]))
+	copy(records, h.records[key])
+	return records
+}
+
+// Delete removes the records of the placement with the given key.
+func (h *DecisionHistory) Delete(key string) {
+	if h == nil {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.records, key)
+}
+
+// clusterNames returns the names of the clusters in the decisions.
+func clusterNames(clusters []*clusterapiv1.ManagedCluster) sets.Set[string] {
+	names := sets.New[string]()
+	for _, cluster := range clusters {
+		names.Insert(cluster.Name)
+	}
+	return names
+}
@@ -0,0 +1,68 @@
+package scheduling
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func newHistoryScheduleResult(clusterNames ...string) *scheduleResult {
+	result := &scheduleResult{
+		filteredRecords: map[string][]*clusterapiv1.ManagedCluster{},
+		scoreSum:        PrioritizerScore{},
+	}
+	for _, name := range clusterNames {
+		result.scheduledDecisions = append(result.scheduledDecisions, testinghelpers.NewManagedCluster(name).Build())
+		result.scoreSum[name] = 100
+	}
+	return result
+}
+
+func TestDecisionHistory(t *testing.T) {
+	history := NewDecisionHistory(2)
+	key := "ns/placement"
+
+	// no record if the decisions do not change
+	history.Record(key, sets.New[string](), newHistoryScheduleResult(), "")
+	if records := history.Get(key); len(records) != 0 {
+		t.Errorf("expected no record, but got %v", records)
+	}
+
+	history.Record(key, sets.New[string](), newHistoryScheduleResult("cluster1", "cluster2"), "")
+	history.Record(key, sets.New[string]("cluster1", "cluster2"), newHistoryScheduleResult("cluster1", "cluster2"), "")
+	history.Record(key, sets.New[string]("cluster1", "cluster2"), newHistoryScheduleResult("cluster2", "cluster3"), "")
+	history.Record(key, sets.New[string]("cluster2", "cluster3"), newHistoryScheduleResult("cluster3"), "warning")
+
+	// only the latest 2 records are kept
+	records := history.Get(key)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, but got %v", records)
+	}
+	if !reflect.DeepEqual(records[0].Added, []string{"cluster3"}) || !reflect.DeepEqual(records[0].Removed, []string{"cluster1"}) {
+		t.Errorf("unexpected record %v", records[0])
+	}
+	if len(records[1].Added) != 0 || !reflect.DeepEqual(records[1].Removed, []string{"cluster2"}) ||
+		!reflect.DeepEqual(records[1].Decisions, []string{"cluster3"}) || records[1].Message != "warning" {
+		t.Errorf("unexpected record %v", records[1])
+	}
+	if !reflect.DeepEqual(records[1].AggregatedScores, PrioritizerScore{"cluster3": 100}) {
+		t.Errorf("unexpected scores %v", records[1].AggregatedScores)
+	}
+
+	history.Delete(key)
+	if records := history.Get(key); len(records) != 0 {
+		t.Errorf("expected no record after deletion, but got %v", records)
+	}
+
+	// the history is disabled with a non-positive size
+	disabled := NewDecisionHistory(0)
+	disabled.Record(key, sets.New[string](), newHistoryScheduleResult("cluster1"), "")
+	if records := disabled.Get(key); records != nil {
+		t.Errorf("expected no record, but got %v", records)
+	}
+}
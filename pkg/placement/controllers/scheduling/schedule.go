@@ -22,9 +22,14 @@ import (
 	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
 	"open-cluster-management.io/ocm/pkg/placement/plugins"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/addon"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/affinity"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/balance"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/cost"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/extender"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/maintenancewindow"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/predicate"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/resource"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/spread"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/steady"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/tainttoleration"
 )
@@ -34,6 +39,24 @@ const (
 	PrioritizerSteady                    string = "Steady"
 	PrioritizerResourceAllocatableCPU    string = "ResourceAllocatableCPU"
 	PrioritizerResourceAllocatableMemory string = "ResourceAllocatableMemory"
+	// PrioritizerResourceMostAllocatedCPU and the following prioritizers score the clusters by the
+	// ratio of the requested resource to the allocatable.
+	PrioritizerResourceMostAllocatedCPU     string = "ResourceMostAllocatedCPU"
+	PrioritizerResourceMostAllocatedMemory  string = "ResourceMostAllocatedMemory"
+	PrioritizerResourceLeastAllocatedCPU    string = "ResourceLeastAllocatedCPU"
+	PrioritizerResourceLeastAllocatedMemory string = "ResourceLeastAllocatedMemory"
+	PrioritizerSpread                       string = "Spread"
+	PrioritizerPlacementAffinity            string = "PlacementAffinity"
+	PrioritizerCost                         string = "Cost"
+
+	// defaultSpreadWeight is the weight of the Spread prioritizer for a placement with spreadConstraints
+	// in Additive mode. It is higher than the weights of Balance and Steady so the decisions are spread
+	// among the topologies first.
+	defaultSpreadWeight int32 = 3
+
+	// defaultPlacementAffinityWeight is the weight of the PlacementAffinity prioritizer for a placement
+	// with preferred affinity or anti-affinity in Additive mode.
+	defaultPlacementAffinityWeight int32 = 3
 )
 
 // PrioritizerScore defines the score for each cluster
@@ -163,6 +186,10 @@ type pluginScheduler struct {
 	handle             plugins.Handle
 	filters            []plugins.Filter
 	prioritizerWeights map[clusterapiv1beta1.ScoreCoordinate]int32
+	// extenderPrioritizers contains the prioritizers of the extenders, keyed by the BuiltIn prioritizer name.
+	extenderPrioritizers map[string]plugins.Prioritizer
+	// priceSource provides the price table to the Cost prioritizer.
+	priceSource cost.PriceSource
 }
 
 func NewPluginScheduler(handle plugins.Handle) *pluginScheduler {
@@ -171,11 +198,79 @@ func NewPluginScheduler(handle plugins.Handle) *pluginScheduler {
 		filters: []plugins.Filter{
 			predicate.New(handle),
 			tainttoleration.New(handle),
+			affinity.New(handle),
+			resource.NewResourceFit(handle),
+			maintenancewindow.New(handle, nil),
 		},
 		prioritizerWeights: defaultPrioritizerConfig,
 	}
 }
 
+// WithExtenders appends the extender filters to the built-in filters, and registers the extender
+// prioritizers so that placements can reference them by the name "Extender/<name>".
+func (s *pluginScheduler) WithExtenders(extenders ...*extender.Extender) *pluginScheduler {
+	if len(extenders) == 0 {
+		return s
+	}
+
+	if s.extenderPrioritizers == nil {
+		s.extenderPrioritizers = map[string]plugins.Prioritizer{}
+	}
+
+	for _, e := range extenders {
+		if e.IsFilter() {
+			s.filters = append(s.filters, e.Filter())
+		}
+		if e.IsPrioritizer() {
+			s.extenderPrioritizers[e.PluginName()] = e.Prioritizer()
+			if e.Weight() != 0 {
+				s.setDefaultWeight(e.PluginName(), e.Weight())
+			}
+		}
+	}
+
+	return s
+}
+
+// WithMaintenanceWindows sets the hub source of the maintenance windows of the clusters read by the
+// MaintenanceWindow filter in addition to the cluster annotations and ClusterClaims.
+func (s *pluginScheduler) WithMaintenanceWindows(windowSource maintenancewindow.WindowSource) *pluginScheduler {
+	if windowSource == nil {
+		return s
+	}
+
+	for i, f := range s.filters {
+		if _, ok := f.(*maintenancewindow.MaintenanceWindow); ok {
+			s.filters[i] = maintenancewindow.New(s.handle, windowSource)
+		}
+	}
+	return s
+}
+
+// WithCost sets the price source of the Cost prioritizer, and adds the Cost prioritizer to the
+// default prioritizers if the weight is not 0. The price source is optional.
+func (s *pluginScheduler) WithCost(priceSource cost.PriceSource, weight int32) *pluginScheduler {
+	s.priceSource = priceSource
+	if weight != 0 {
+		s.setDefaultWeight(PrioritizerCost, weight)
+	}
+	return s
+}
+
+// setDefaultWeight sets the default weight of the builtin prioritizer on a copy of the default
+// weights, so the package level defaultPrioritizerConfig is not changed.
+func (s *pluginScheduler) setDefaultWeight(name string, weight int32) {
+	weights := make(map[clusterapiv1beta1.ScoreCoordinate]int32, len(s.prioritizerWeights)+1)
+	for sc, w := range s.prioritizerWeights {
+		weights[sc] = w
+	}
+	weights[clusterapiv1beta1.ScoreCoordinate{
+		Type:    clusterapiv1beta1.ScoreCoordinateTypeBuiltIn,
+		BuiltIn: name,
+	}] = weight
+	s.prioritizerWeights = weights
+}
+
 func (s *pluginScheduler) Schedule(
 	ctx context.Context,
 	placement *clusterapiv1beta1.Placement,
@@ -233,7 +328,7 @@ func (s *pluginScheduler) Schedule(
 	}
 
 	// 2. Generate prioritizers for each placement whose weight != 0.
-	prioritizers, status := getPrioritizers(weights, s.handle)
+	prioritizers, status := getPrioritizers(weights, s.handle, s.extenderPrioritizers, s.priceSource)
 	switch {
 	case status.IsError():
 		return results, status
@@ -329,12 +424,9 @@ func selectClusters(placement *clusterapiv1beta1.Placement, clusters []*clustera
 	}
 
 	// truncate the cluster slice if the desired number of decisions is less than
-	// the number of the candidate clusters
-	if numOfDecisions < len(clusters) {
-		clusters = clusters[:numOfDecisions]
-	}
-
-	return clusters
+	// the number of the candidate clusters, the clusters violating the spreadConstraints
+	// with DoNotSchedule are not selected.
+	return spread.Select(placement, clusters, numOfDecisions)
 }
 
 // setRequeueAfter selects minimal time.Duration as requeue time
@@ -360,13 +452,40 @@ func getWeights(defaultWeight map[clusterapiv1beta1.ScoreCoordinate]int32,
 	case mode == clusterapiv1beta1.PrioritizerPolicyModeExact:
 		return mergeWeights(nil, placement.Spec.PrioritizerPolicy.Configurations)
 	case mode == clusterapiv1beta1.PrioritizerPolicyModeAdditive || mode == "":
-		return mergeWeights(defaultWeight, placement.Spec.PrioritizerPolicy.Configurations)
+		return mergeWeights(withPlacementDefaultWeights(defaultWeight, placement), placement.Spec.PrioritizerPolicy.Configurations)
 	default:
 		msg := fmt.Sprintf("incorrect prioritizer policy mode: %s", mode)
 		return nil, framework.NewStatus("", framework.Misconfigured, msg)
 	}
 }
 
+// withPlacementDefaultWeights returns a copy of the default weight with the prioritizers enabled
+// by the placement itself: Spread for the spreadConstraints and PlacementAffinity for the preferred
+// affinity and anti-affinity.
+func withPlacementDefaultWeights(defaultWeight map[clusterapiv1beta1.ScoreCoordinate]int32,
+	placement *clusterapiv1beta1.Placement) map[clusterapiv1beta1.ScoreCoordinate]int32 {
+	weights := make(map[clusterapiv1beta1.ScoreCoordinate]int32, len(defaultWeight)+2)
+	for sc, w := range defaultWeight {
+		weights[sc] = w
+	}
+
+	if len(placement.Spec.SpreadPolicy.SpreadConstraints) > 0 {
+		weights[clusterapiv1beta1.ScoreCoordinate{
+			Type:    clusterapiv1beta1.ScoreCoordinateTypeBuiltIn,
+			BuiltIn: PrioritizerSpread,
+		}] = defaultSpreadWeight
+	}
+
+	// the invalid annotation is reported by the PlacementAffinity filter
+	if terms, err := affinity.GetAffinityTerms(placement); err == nil && terms.HasPreferred() {
+		weights[clusterapiv1beta1.ScoreCoordinate{
+			Type:    clusterapiv1beta1.ScoreCoordinateTypeBuiltIn,
+			BuiltIn: PrioritizerPlacementAffinity,
+		}] = defaultPlacementAffinityWeight
+	}
+	return weights
+}
+
 func mergeWeights(defaultWeight map[clusterapiv1beta1.ScoreCoordinate]int32,
 	customizedWeight []clusterapiv1beta1.PrioritizerConfig,
 ) (map[clusterapiv1beta1.ScoreCoordinate]int32, *framework.Status) {
@@ -390,6 +509,7 @@ func mergeWeights(defaultWeight map[clusterapiv1beta1.ScoreCoordinate]int32,
 
 // Generate prioritizers for the placement.
 func getPrioritizers(weights map[clusterapiv1beta1.ScoreCoordinate]int32, handle plugins.Handle,
+	extenderPrioritizers map[string]plugins.Prioritizer, priceSource cost.PriceSource,
 ) (map[clusterapiv1beta1.ScoreCoordinate]plugins.Prioritizer, *framework.Status) {
 	result := make(map[clusterapiv1beta1.ScoreCoordinate]plugins.Prioritizer)
 	status := framework.NewStatus("", framework.Success, "")
@@ -403,8 +523,18 @@ func getPrioritizers(weights map[clusterapiv1beta1.ScoreCoordinate]int32, handle
 				result[k] = balance.New(handle)
 			case k.BuiltIn == PrioritizerSteady:
 				result[k] = steady.New(handle)
-			case k.BuiltIn == PrioritizerResourceAllocatableCPU || k.BuiltIn == PrioritizerResourceAllocatableMemory:
+			case k.BuiltIn == PrioritizerSpread:
+				result[k] = spread.New(handle)
+			case k.BuiltIn == PrioritizerPlacementAffinity:
+				result[k] = affinity.New(handle)
+			case k.BuiltIn == PrioritizerCost:
+				result[k] = cost.New(handle, priceSource)
+			case k.BuiltIn == PrioritizerResourceAllocatableCPU || k.BuiltIn == PrioritizerResourceAllocatableMemory,
+				k.BuiltIn == PrioritizerResourceMostAllocatedCPU || k.BuiltIn == PrioritizerResourceMostAllocatedMemory,
+				k.BuiltIn == PrioritizerResourceLeastAllocatedCPU || k.BuiltIn == PrioritizerResourceLeastAllocatedMemory:
 				result[k] = resource.NewResourcePrioritizerBuilder(handle).WithPrioritizerName(k.BuiltIn).Build()
+			case extenderPrioritizers[k.BuiltIn] != nil:
+				result[k] = extenderPrioritizers[k.BuiltIn]
 			default:
 				msg := fmt.Sprintf("incorrect builtin prioritizer: %s", k.BuiltIn)
 				return nil, framework.NewStatus("", framework.Misconfigured, msg)
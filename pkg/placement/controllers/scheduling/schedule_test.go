@@ -58,6 +58,18 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity",
+					FilteredClusters: []string{"cluster1"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit",
+					FilteredClusters: []string{"cluster1"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit,MaintenanceWindow",
+					FilteredClusters: []string{"cluster1"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -96,6 +108,18 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity",
+					FilteredClusters: []string{"cluster1"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit",
+					FilteredClusters: []string{"cluster1"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit,MaintenanceWindow",
+					FilteredClusters: []string{"cluster1"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -169,6 +193,18 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit,MaintenanceWindow",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -204,6 +240,18 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity",
+					FilteredClusters: []string{"cluster1"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit",
+					FilteredClusters: []string{"cluster1"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit,MaintenanceWindow",
+					FilteredClusters: []string{"cluster1"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -274,6 +322,18 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity",
+					FilteredClusters: []string{"cluster1", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit",
+					FilteredClusters: []string{"cluster1", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit,MaintenanceWindow",
+					FilteredClusters: []string{"cluster1", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -333,6 +393,18 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit,MaintenanceWindow",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -397,6 +469,18 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit,MaintenanceWindow",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -444,6 +528,18 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity",
+					FilteredClusters: []string{"cluster1", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit",
+					FilteredClusters: []string{"cluster1", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit,MaintenanceWindow",
+					FilteredClusters: []string{"cluster1", "cluster2"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -490,6 +586,18 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity",
+					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit",
+					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit,MaintenanceWindow",
+					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -541,6 +649,18 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity",
+					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit",
+					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit,MaintenanceWindow",
+					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -557,6 +677,70 @@ func TestSchedule(t *testing.T) {
 			expectedUnScheduled: 0,
 			expectedStatus:      *framework.NewStatus("", framework.Success, ""),
 		},
+		{
+			name: "placement with spread constraints",
+			placement: testinghelpers.NewPlacement(placementNamespace, placementName).WithNOC(2).
+				AddSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 1).Build(),
+			initObjs: []runtime.Object{
+				testinghelpers.NewClusterSet(clusterSetName).Build(),
+				testinghelpers.NewClusterSetBinding(placementNamespace, clusterSetName),
+			},
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithLabel(clusterapiv1beta2.ClusterSetLabel, clusterSetName).
+					WithLabel("region", "east").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel(clusterapiv1beta2.ClusterSetLabel, clusterSetName).
+					WithLabel("region", "east").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithLabel(clusterapiv1beta2.ClusterSetLabel, clusterSetName).
+					WithLabel("region", "west").Build(),
+			},
+			expectedDecisions: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithLabel(clusterapiv1beta2.ClusterSetLabel, clusterSetName).
+					WithLabel("region", "east").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithLabel(clusterapiv1beta2.ClusterSetLabel, clusterSetName).
+					WithLabel("region", "west").Build(),
+			},
+			expectedFilterResult: []FilterResult{
+				{
+					Name:             "Predicate",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration",
+					FilteredClusters: []string{"cluster1", "cluster3", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity",
+					FilteredClusters: []string{"cluster1", "cluster3", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit",
+					FilteredClusters: []string{"cluster1", "cluster3", "cluster2"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,PlacementAffinity,ResourceFit,MaintenanceWindow",
+					FilteredClusters: []string{"cluster1", "cluster3", "cluster2"},
+				},
+			},
+			expectedScoreResult: []PrioritizerResult{
+				{
+					Name:   "Balance",
+					Weight: 1,
+					Scores: PrioritizerScore{"cluster1": 100, "cluster2": 100, "cluster3": 100},
+				},
+				{
+					Name:   "Spread",
+					Weight: 3,
+					Scores: PrioritizerScore{"cluster1": 100, "cluster2": -100, "cluster3": 100},
+				},
+				{
+					Name:   "Steady",
+					Weight: 1,
+					Scores: PrioritizerScore{"cluster1": 0, "cluster2": 0, "cluster3": 0},
+				},
+			},
+			expectedUnScheduled: 0,
+			expectedStatus:      *framework.NewStatus("", framework.Success, ""),
+		},
 	}
 
 	for _, c := range cases {
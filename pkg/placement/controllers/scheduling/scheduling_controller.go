@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	errorhelpers "github.com/openshift/library-go/pkg/operator/v1helpers"
 	corev1 "k8s.io/api/core/v1"
@@ -41,6 +42,7 @@ import (
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/sharding"
 	"open-cluster-management.io/ocm/pkg/placement/helpers"
 )
 
@@ -67,6 +69,9 @@ type schedulingController struct {
 	placementLister         clusterlisterv1beta1.PlacementLister
 	placementDecisionLister clusterlisterv1beta1.PlacementDecisionLister
 	scheduler               Scheduler
+	history                 *DecisionHistory
+	damper                  *DecisionDamper
+	sharder                 *sharding.Sharder
 	eventsRecorder          kevents.EventRecorder
 	metricsRecorder         *metrics.ScheduleMetrics
 }
@@ -82,6 +87,9 @@ func NewSchedulingController(
 	placementDecisionInformer clusterinformerv1beta1.PlacementDecisionInformer,
 	placementScoreInformer clusterinformerv1alpha1.AddOnPlacementScoreInformer,
 	scheduler Scheduler,
+	history *DecisionHistory,
+	damper *DecisionDamper,
+	sharder *sharding.Sharder,
 	krecorder kevents.EventRecorder,
 	metricsRecorder *metrics.ScheduleMetrics,
 ) factory.Controller {
@@ -98,10 +106,21 @@ func NewSchedulingController(
 		placementLister:         placementInformer.Lister(),
 		placementDecisionLister: placementDecisionInformer.Lister(),
 		scheduler:               scheduler,
+		history:                 history,
+		damper:                  damper,
+		sharder:                 sharder,
 		eventsRecorder:          krecorder,
 		metricsRecorder:         metricsRecorder,
 	}
 
+	// Once the placement namespaces are rebalanced across the replicas, the placements owned by
+	// this replica are enqueued, so the placements taken over from other replicas are scheduled.
+	sharder.AddRebalanceHandler(func(keys []string) {
+		for _, key := range keys {
+			syncCtx.Queue().Add(key)
+		}
+	})
+
 	// setup event handler for cluster informer.
 	// Once a cluster changes, clusterEventHandler enqueues all placements which are
 	// impacted potentially for further reconciliation. It might not function before the
@@ -161,6 +180,21 @@ func NewSchedulingController(
 		utilruntime.HandleError(err)
 	}
 
+	// setup event handler for placementdecision informer
+	// Once a placementdecision changes, the placements with affinity or anti-affinity to the
+	// placement of the decision are enqueued. The placement owning the decision is enqueued by
+	// the filtered events informer below.
+	_, err = placementDecisionInformer.Informer().AddEventHandler(&cache.ResourceEventHandlerFuncs{
+		AddFunc: enQueuer.enqueuePlacementDecision,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			enQueuer.enqueuePlacementDecision(newObj)
+		},
+		DeleteFunc: enQueuer.enqueuePlacementDecision,
+	})
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+
 	return factory.New().
 		WithSyncContext(syncCtx).
 		WithInformersQueueKeysFunc(
@@ -183,9 +217,19 @@ func (c *schedulingController) sync(ctx context.Context, syncCtx factory.SyncCon
 	logger.V(4).Info("Reconciling placement")
 	ctx = klog.NewContext(ctx, logger)
 
+	// the placement is scheduled by the replica owning its namespace
+	if !c.sharder.OwnsKey(queueKey) {
+		logger.V(4).Info("Skip placement owned by another shard")
+		c.history.Delete(queueKey)
+		c.damper.Forget(queueKey)
+		return nil
+	}
+
 	placement, err := c.getPlacement(queueKey)
 	if errors.IsNotFound(err) {
 		// no work if placement is deleted
+		c.history.Delete(queueKey)
+		c.damper.Forget(queueKey)
 		return nil
 	}
 	if err != nil {
@@ -241,6 +285,23 @@ func (c *schedulingController) syncPlacement(ctx context.Context, syncCtx factor
 	// schedule placement with scheduler
 	c.metricsRecorder.StartSchedule(queueKey)
 	scheduleResult, status := c.scheduler.Schedule(ctx, placement, clusters)
+
+	// get the existing decisions to damp the decision changes and record the history
+	existingDecisions, err := c.getExistingDecisions(placement)
+	if err != nil {
+		return err
+	}
+	// a failed schedule is not damped, so its partial decisions do not take the damping budget
+	var dampedCondition *metav1.Condition
+	if !status.IsError() {
+		var dampedRequeueAfter *time.Duration
+		scheduleResult, dampedCondition, dampedRequeueAfter = c.damper.Damp(queueKey, existingDecisions, scheduleResult, c.clusterLister)
+		if syncCtx != nil && dampedRequeueAfter != nil {
+			logger.V(4).Info("Requeue placement after time to continue damped decision changes", "placementKey", queueKey, "time", *dampedRequeueAfter)
+			syncCtx.Queue().AddAfter(queueKey, *dampedRequeueAfter)
+		}
+	}
+
 	// generate placement decision and status
 	decisions, groupStatus, s := c.generatePlacementDecisionsAndStatus(placement, scheduleResult.Decisions())
 	if s.IsError() {
@@ -272,22 +333,58 @@ func (c *schedulingController) syncPlacement(ctx context.Context, syncCtx factor
 	if err != nil {
 		return err
 	}
+	c.history.Record(queueKey, existingDecisions, scheduleResult, status.Message())
 
 	// update placement status if necessary to signal no bindings
+	conditions := []metav1.Condition{misconfiguredCondition, satisfiedCondition}
+	if dampedCondition != nil {
+		conditions = append(conditions, *dampedCondition)
+	}
+	// the DecisionDamped condition is removed once the damping is disabled
+	var removedConditionTypes []string
+	if c.damper == nil {
+		removedConditionTypes = append(removedConditionTypes, PlacementConditionDecisionDamped)
+	}
 	if err := c.updateStatus(
-		ctx, placement, groupStatus, int32(len(scheduleResult.Decisions())), misconfiguredCondition, satisfiedCondition); err != nil { // nolint:gosec
+		ctx, placement, groupStatus, int32(len(scheduleResult.Decisions())), removedConditionTypes, conditions...); err != nil { // nolint:gosec
 		return err
 	}
 
 	return status.AsError()
 }
 
+// getExistingDecisions returns the cluster names in the existing decisions of the placement.
+func (c *schedulingController) getExistingDecisions(placement *clusterapiv1beta1.Placement) (sets.Set[string], error) {
+	existingDecisions := sets.New[string]()
+	if c.history == nil && c.damper == nil {
+		return existingDecisions, nil
+	}
+
+	requirement, err := labels.NewRequirement(clusterapiv1beta1.PlacementLabel, selection.Equals, []string{placement.Name})
+	if err != nil {
+		return existingDecisions, err
+	}
+	labelSelector := labels.NewSelector().Add(*requirement)
+	pds, err := c.placementDecisionLister.PlacementDecisions(placement.Namespace).List(labelSelector)
+	if err != nil {
+		return existingDecisions, err
+	}
+
+	for _, pd := range pds {
+		for _, d := range pd.Status.Decisions {
+			existingDecisions.Insert(d.ClusterName)
+		}
+	}
+	return existingDecisions, nil
+}
+
 // updateStatus updates the status of the placement according to intermediate scheduling data.
 func (c *schedulingController) updateStatus(
 	ctx context.Context,
 	placement *clusterapiv1beta1.Placement,
 	decisionGroupStatus []*clusterapiv1beta1.DecisionGroupStatus,
 	numberOfSelectedClusters int32,
+	removedConditionTypes []string,
 	conditions ...metav1.Condition,
 ) error {
 	newPlacement := placement.DeepCopy()
@@ -298,6 +395,9 @@ func (c *schedulingController) updateStatus(
 		newPlacement.Status.DecisionGroups = append(newPlacement.Status.DecisionGroups, *status)
 	}
 
+	for _, conditionType := range removedConditionTypes {
+		meta.RemoveStatusCondition(&newPlacement.Status.Conditions, conditionType)
+	}
 	for _, c := range conditions {
 		meta.SetStatusCondition(&newPlacement.Status.Conditions, c)
 	}
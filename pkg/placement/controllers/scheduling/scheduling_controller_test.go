@@ -8,12 +8,15 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/sets"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
 	kevents "k8s.io/client-go/tools/events"
 	"k8s.io/utils/clock"
@@ -26,12 +29,14 @@ import (
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/sharding"
 	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
 	"open-cluster-management.io/ocm/test/integration/util"
 )
 
 type testScheduler struct {
 	result ScheduleResult
+	status *framework.Status
 }
 
 const (
@@ -43,7 +48,7 @@ func (s *testScheduler) Schedule(ctx context.Context,
 	placement *clusterapiv1beta1.Placement,
 	clusters []*clusterapiv1.ManagedCluster,
 ) (ScheduleResult, *framework.Status) {
-	return s.result, nil
+	return s.result, s.status
 }
 
 func TestSchedulingController_sync(t *testing.T) {
@@ -566,6 +571,7 @@ func TestSchedulingController_sync(t *testing.T) {
 				placementLister:         clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
 				placementDecisionLister: clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Lister(),
 				scheduler:               s,
+				history:                 NewDecisionHistory(10),
 				eventsRecorder:          kevents.NewFakeRecorder(100),
 				metricsRecorder:         metrics.NewScheduleMetrics(clock.RealClock{}),
 			}
@@ -582,6 +588,140 @@ func TestSchedulingController_sync(t *testing.T) {
 	}
 }
 
+func TestSchedulingController_syncDamping(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithLabel(clusterapiv1beta2.ClusterSetLabel, "clusterset1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithLabel(clusterapiv1beta2.ClusterSetLabel, "clusterset1").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithLabel(clusterapiv1beta2.ClusterSetLabel, "clusterset1").Build(),
+	}
+	dampedPlacement := testinghelpers.NewPlacement(placementNamespace, placementName).Build()
+	dampedPlacement.Status.Conditions = []metav1.Condition{{
+		Type:   PlacementConditionDecisionDamped,
+		Status: metav1.ConditionTrue,
+		Reason: dampedReasonStabilityWindow,
+	}}
+
+	cases := []struct {
+		name                 string
+		placement            *clusterapiv1beta1.Placement
+		options              DampingOptions
+		status               *framework.Status
+		expectedDamped       bool
+		expectedDampedStatus metav1.ConditionStatus
+	}{
+		{
+			name:                 "the removed clusters are damped",
+			placement:            testinghelpers.NewPlacement(placementNamespace, placementName).Build(),
+			options:              DampingOptions{StabilityWindow: time.Hour},
+			expectedDamped:       true,
+			expectedDampedStatus: metav1.ConditionFalse,
+		},
+		{
+			name:      "the failed schedule is not damped",
+			placement: testinghelpers.NewPlacement(placementNamespace, placementName).Build(),
+			options:   DampingOptions{StabilityWindow: time.Hour},
+			status:    framework.NewStatus("", framework.Error, "failed to schedule"),
+		},
+		{
+			name:      "the DecisionDamped condition is removed once the damping is disabled",
+			placement: dampedPlacement,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			initObjs := []runtime.Object{
+				c.placement,
+				testinghelpers.NewClusterSet("clusterset1").Build(),
+				testinghelpers.NewClusterSetBinding(placementNamespace, "clusterset1"),
+				testinghelpers.NewPlacementDecision(placementNamespace, testinghelpers.PlacementDecisionName(placementName, 1)).
+					WithLabel(clusterapiv1beta1.PlacementLabel, placementName).
+					WithLabel(clusterapiv1beta1.DecisionGroupNameLabel, "").
+					WithLabel(clusterapiv1beta1.DecisionGroupIndexLabel, "0").
+					WithDecisions("cluster1", "cluster2", "cluster3").Build(),
+			}
+			for _, cluster := range clusters {
+				initObjs = append(initObjs, cluster)
+			}
+			ctrl, clusterClient, _ := newTestSchedulingController(t, initObjs)
+			ctrl.damper = NewDecisionDamper(c.options)
+			// cluster3 is removed from the decisions
+			ctrl.scheduler = &testScheduler{
+				result: &scheduleResult{
+					feasibleClusters:   clusters,
+					scheduledDecisions: clusters[:2],
+				},
+				status: c.status,
+			}
+
+			// the failed schedule is returned as the sync error after the status is updated
+			key := placementNamespace + "/" + placementName
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, key), key); err != nil && !c.status.IsError() {
+				t.Fatal(err)
+			}
+
+			if ctrl.damper != nil {
+				if _, damped := ctrl.damper.lastChanged[key]; damped != c.expectedDamped {
+					t.Errorf("expected the stability window started %v, but got %v", c.expectedDamped, damped)
+				}
+			}
+
+			var placement *clusterapiv1beta1.Placement
+			for _, action := range clusterClient.Actions() {
+				if action.GetVerb() == "patch" && action.GetResource().Resource == "placements" {
+					placement = &clusterapiv1beta1.Placement{}
+					if err := json.Unmarshal(action.(clienttesting.PatchActionImpl).Patch, placement); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if placement == nil {
+				t.Fatal("expected the placement status patched")
+			}
+			condition := meta.FindStatusCondition(placement.Status.Conditions, PlacementConditionDecisionDamped)
+			switch {
+			case len(c.expectedDampedStatus) == 0 && condition != nil:
+				t.Errorf("expected no DecisionDamped condition, but got %v", condition)
+			case len(c.expectedDampedStatus) > 0 && (condition == nil || condition.Status != c.expectedDampedStatus):
+				t.Errorf("expected DecisionDamped condition %s, but got %v", c.expectedDampedStatus, condition)
+			}
+		})
+	}
+}
+
+func TestSchedulingController_syncNotOwned(t *testing.T) {
+	placement := testinghelpers.NewPlacement(placementNamespace, placementName).Build()
+	clusterClient := clusterfake.NewSimpleClientset(placement)
+	clusterInformerFactory := newClusterInformerFactory(t, clusterClient, placement)
+
+	// the sharder owns no namespace before the first rebalance
+	sharder, err := sharding.NewSharder(kubefake.NewSimpleClientset().CoordinationV1(),
+		clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
+		sharding.Options{Namespace: "open-cluster-management-hub", Identity: "replica1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctrl := schedulingController{
+		clusterClient:           clusterClient,
+		clusterLister:           clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+		clusterSetLister:        clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets().Lister(),
+		clusterSetBindingLister: clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings().Lister(),
+		placementLister:         clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
+		placementDecisionLister: clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Lister(),
+		scheduler:               &testScheduler{result: &scheduleResult{}},
+		sharder:                 sharder,
+		eventsRecorder:          kevents.NewFakeRecorder(100),
+		metricsRecorder:         metrics.NewScheduleMetrics(clock.RealClock{}),
+	}
+
+	key := placementNamespace + "/" + placementName
+	if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, key), key); err != nil {
+		t.Errorf("unexpected err: %v", err)
+	}
+	testingcommon.AssertNoActions(t, clusterClient.Actions())
+}
+
 func TestGetValidManagedClusterSetBindings(t *testing.T) {
 	placementNamespace := "ns1"
 	cases := []struct {
@@ -0,0 +1,84 @@
+package sharding
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// defaultVirtualNodes is the number of the points of each member on the hash ring, which spreads
+// the keys evenly across a small number of members.
+const defaultVirtualNodes = 100
+
+// HashRing is a consistent hash ring of the members. When a member joins or leaves, only the keys
+// owned by that member move to other members.
+type HashRing struct {
+	members []string
+	points  []uint32
+	owners  map[uint32]string
+}
+
+// NewHashRing returns a HashRing of the members with the given number of virtual nodes for each
+// member. The default number of virtual nodes is used if virtualNodes is not positive.
+func NewHashRing(members []string, virtualNodes int) *HashRing {
+	if virtualNodes <= 0 {
+		virtualNodes = defaultVirtualNodes
+	}
+
+	sorted := append([]string{}, members...)
+	sort.Strings(sorted)
+
+	ring := &HashRing{
+		members: sorted,
+		owners:  map[uint32]string{},
+	}
+	for _, member := range sorted {
+		for i := 0; i < virtualNodes; i++ {
+			point := hash(member + "#" + strconv.Itoa(i))
+			// on a hash collision, the member with the smaller name keeps the point
+			if _, ok := ring.owners[point]; ok {
+				continue
+			}
+			ring.owners[point] = member
+			ring.points = append(ring.points, point)
+		}
+	}
+	sort.Slice(ring.points, func(i, j int) bool { return ring.points[i] < ring.points[j] })
+	return ring
+}
+
+// Owner returns the member owning the key. An empty string is returned if the ring has no member.
+func (r *HashRing) Owner(key string) string {
+	if r == nil || len(r.points) == 0 {
+		return ""
+	}
+
+	point := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= point })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// Members returns the sorted members of the ring.
+func (r *HashRing) Members() []string {
+	if r == nil {
+		return nil
+	}
+	return r.members
+}
+
+// hash returns the fnv hash of the key mixed by the murmur3 finalizer, since the fnv hashes of the
+// short keys differing only in the last characters are not spread evenly on the ring.
+func hash(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	v := h.Sum32()
+	v ^= v >> 16
+	v *= 0x85ebca6b
+	v ^= v >> 13
+	v *= 0xc2b2ae35
+	v ^= v >> 16
+	return v
+}
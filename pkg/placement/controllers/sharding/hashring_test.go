@@ -0,0 +1,52 @@
+package sharding
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHashRing(t *testing.T) {
+	var keys []string
+	for i := 0; i < 1000; i++ {
+		keys = append(keys, fmt.Sprintf("ns%d", i))
+	}
+
+	ring := NewHashRing([]string{"replica3", "replica1", "replica2"}, 0)
+	if members := ring.Members(); len(members) != 3 || members[0] != "replica1" {
+		t.Errorf("expect sorted members, but got %v", members)
+	}
+
+	owned := map[string]int{}
+	owners := map[string]string{}
+	for _, key := range keys {
+		owner := ring.Owner(key)
+		owned[owner]++
+		owners[key] = owner
+	}
+	for _, member := range ring.Members() {
+		// each member should own a fair share of the keys
+		if owned[member] < 200 {
+			t.Errorf("expect member %s owns at least 200 keys, but got %d", member, owned[member])
+		}
+	}
+
+	// only the keys of the removed member move
+	ring = NewHashRing([]string{"replica1", "replica3"}, 0)
+	for _, key := range keys {
+		owner := ring.Owner(key)
+		if owners[key] != "replica2" && owner != owners[key] {
+			t.Errorf("expect key %s stays with %s, but moved to %s", key, owners[key], owner)
+		}
+		if owner == "replica2" {
+			t.Errorf("expect key %s not owned by the removed member", key)
+		}
+	}
+
+	if owner := NewHashRing(nil, 0).Owner("ns1"); owner != "" {
+		t.Errorf("expect no owner in empty ring, but got %s", owner)
+	}
+	var nilRing *HashRing
+	if owner := nilRing.Owner("ns1"); owner != "" {
+		t.Errorf("expect no owner in nil ring, but got %s", owner)
+	}
+}
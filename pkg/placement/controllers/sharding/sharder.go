@@ -0,0 +1,291 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
+)
+
+const (
+	// ShardGroupLabel is the label of the shard Leases with the name of the shard group. The replicas
+	// with the Leases of the same shard group share the placement namespaces.
+	ShardGroupLabel = "cluster.open-cluster-management.io/placement-shard-group"
+
+	// DefaultShardGroup is the default name of the shard group.
+	DefaultShardGroup = "placement"
+
+	// DefaultLeaseDuration is the default duration of the shard Leases. A replica is removed from
+	// the shard group if its Lease is not renewed within the duration.
+	DefaultLeaseDuration = 30 * time.Second
+)
+
+// Options defines the shard of a placement controller replica.
+type Options struct {
+	// Namespace is the namespace of the shard Leases.
+	Namespace string
+	// Group is the name of the shard group.
+	Group string
+	// Identity is the unique identity of the replica, e.g. the pod name.
+	Identity string
+	// LeaseDuration is the duration of the shard Lease. The Lease is renewed every third of the duration.
+	LeaseDuration time.Duration
+	// VirtualNodes is the number of the virtual nodes of each replica on the hash ring.
+	VirtualNodes int
+}
+
+// Sharder splits the placement namespaces across the placement controller replicas with a consistent
+// hash ring. Each replica holds a Lease in the shard group, and the replicas with a live Lease are the
+// members of the ring. When a replica joins or leaves, the namespaces are rebalanced and the rebalance
+// handlers are called with the keys of the placements owned by the replica.
+type Sharder struct {
+	options         Options
+	leaseClient     coordv1client.LeasesGetter
+	placementLister clusterlisterv1beta1.PlacementLister
+	clock           clock.Clock
+	metrics         *metrics.ShardMetrics
+
+	lock        sync.RWMutex
+	ring        *HashRing
+	lastRenewed time.Time
+	handlers    []func(keys []string)
+}
+
+// NewSharder returns a Sharder of the replica. The replica owns no namespace until Run is called and
+// the first rebalance is done.
+func NewSharder(
+	leaseClient coordv1client.LeasesGetter,
+	placementLister clusterlisterv1beta1.PlacementLister,
+	options Options,
+) (*Sharder, error) {
+	if len(options.Namespace) == 0 {
+		return nil, fmt.Errorf("the namespace of the shard leases is required")
+	}
+	if len(options.Identity) == 0 {
+		return nil, fmt.Errorf("the identity of the shard is required")
+	}
+	if len(options.Group) == 0 {
+		options.Group = DefaultShardGroup
+	}
+	if options.LeaseDuration <= 0 {
+		options.LeaseDuration = DefaultLeaseDuration
+	}
+
+	return &Sharder{
+		options:         options,
+		leaseClient:     leaseClient,
+		placementLister: placementLister,
+		clock:           clock.RealClock{},
+		metrics:         metrics.NewShardMetrics(options.Identity),
+	}, nil
+}
+
+// Owns returns if the placements in the namespace are scheduled by this replica. A nil Sharder owns
+// all the namespaces.
+func (s *Sharder) Owns(namespace string) bool {
+	if s == nil {
+		return true
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.ring.Owner(namespace) == s.options.Identity
+}
+
+// OwnsKey returns if the placement with the namespace/name key is scheduled by this replica.
+func (s *Sharder) OwnsKey(key string) bool {
+	if s == nil {
+		return true
+	}
+
+	namespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return false
+	}
+	return s.Owns(namespace)
+}
+
+// AddRebalanceHandler adds a handler called with the keys of the placements owned by this replica
+// after each rebalance.
+func (s *Sharder) AddRebalanceHandler(handler func(keys []string)) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+// Run renews the shard Lease and rebalances the namespaces periodically until the context is done.
+// The Lease is deleted on return so the other replicas take over the namespaces without waiting for
+// the Lease to expire.
+func (s *Sharder) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithValues("shard", s.options.Identity)
+	logger.Info("Starting placement shard", "group", s.options.Group)
+
+	wait.UntilWithContext(ctx, s.sync, s.options.LeaseDuration/3)
+
+	releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.release(releaseCtx); err != nil {
+		logger.Error(err, "Failed to release the shard lease")
+	}
+}
+
+func (s *Sharder) sync(ctx context.Context) {
+	if err := s.renew(ctx); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to renew the shard lease: %w", err))
+	}
+	if err := s.rebalance(ctx); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to rebalance the placement shards: %w", err))
+	}
+}
+
+func (s *Sharder) leaseName() string {
+	return fmt.Sprintf("%s-%s", s.options.Group, s.options.Identity)
+}
+
+// renew creates or renews the shard Lease of the replica.
+func (s *Sharder) renew(ctx context.Context) error {
+	leaseClient := s.leaseClient.Leases(s.options.Namespace)
+	now := metav1.NewMicroTime(s.clock.Now())
+	durationSeconds := int32(s.options.LeaseDuration.Seconds())
+
+	lease, err := leaseClient.Get(ctx, s.leaseName(), metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.leaseName(),
+				Namespace: s.options.Namespace,
+				Labels:    map[string]string{ShardGroupLabel: s.options.Group},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &s.options.Identity,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		_, err = leaseClient.Create(ctx, lease, metav1.CreateOptions{})
+	case err != nil:
+		return err
+	default:
+		lease = lease.DeepCopy()
+		if lease.Labels == nil {
+			lease.Labels = map[string]string{}
+		}
+		lease.Labels[ShardGroupLabel] = s.options.Group
+		lease.Spec.HolderIdentity = &s.options.Identity
+		lease.Spec.LeaseDurationSeconds = &durationSeconds
+		lease.Spec.RenewTime = &now
+		_, err = leaseClient.Update(ctx, lease, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lastRenewed = now.Time
+	return nil
+}
+
+// rebalance rebuilds the hash ring with the replicas holding a live Lease. The replica removes itself
+// from the ring if its own Lease is not renewed within the lease duration, so that a replica which
+// cannot reach the apiserver stops scheduling the placements taken over by the other replicas.
+func (s *Sharder) rebalance(ctx context.Context) error {
+	now := s.clock.Now()
+	s.lock.RLock()
+	renewed := now.Before(s.lastRenewed.Add(s.options.LeaseDuration))
+	s.lock.RUnlock()
+
+	leases, err := s.leaseClient.Leases(s.options.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{ShardGroupLabel: s.options.Group}).String(),
+	})
+	if err != nil {
+		if !renewed {
+			s.setMembers(ctx, []string{})
+		}
+		return err
+	}
+
+	members := sets.New[string]()
+	for _, lease := range leases.Items {
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		duration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+		if now.Before(lease.Spec.RenewTime.Add(duration)) {
+			members.Insert(*lease.Spec.HolderIdentity)
+		}
+	}
+	if !renewed {
+		members.Delete(s.options.Identity)
+	}
+
+	s.setMembers(ctx, sets.List(members))
+	return nil
+}
+
+// setMembers rebuilds the hash ring if the members change and calls the rebalance handlers.
+func (s *Sharder) setMembers(ctx context.Context, members []string) {
+	s.lock.Lock()
+	if s.ring != nil && reflect.DeepEqual(s.ring.Members(), members) {
+		s.lock.Unlock()
+		return
+	}
+	s.ring = NewHashRing(members, s.options.VirtualNodes)
+	handlers := append([]func(keys []string){}, s.handlers...)
+	s.lock.Unlock()
+
+	placements, err := s.placementLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+
+	var keys []string
+	namespaces := sets.New[string]()
+	for _, placement := range placements {
+		if !s.Owns(placement.Namespace) {
+			continue
+		}
+		namespaces.Insert(placement.Namespace)
+		keys = append(keys, placement.Namespace+"/"+placement.Name)
+	}
+
+	klog.FromContext(ctx).Info("Placement shards rebalanced", "shard", s.options.Identity,
+		"members", members, "namespaces", namespaces.Len(), "placements", len(keys))
+	s.metrics.Rebalanced(len(members), namespaces.Len(), len(keys))
+
+	for _, handler := range handlers {
+		handler(keys)
+	}
+}
+
+// release deletes the shard Lease of the replica.
+func (s *Sharder) release(ctx context.Context) error {
+	err := s.leaseClient.Leases(s.options.Namespace).Delete(ctx, s.leaseName(), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
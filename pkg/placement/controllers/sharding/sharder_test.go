@@ -0,0 +1,127 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	testingclock "k8s.io/utils/clock/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+const leaseNamespace = "open-cluster-management-hub"
+
+func newTestSharder(t *testing.T, kubeClient *kubefake.Clientset, clock *testingclock.FakeClock,
+	identity string, objs ...runtime.Object) (*Sharder, *[]string) {
+	clusterInformerFactory := testinghelpers.NewClusterInformerFactory(clusterfake.NewSimpleClientset(), objs...)
+	sharder, err := NewSharder(kubeClient.CoordinationV1(),
+		clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
+		Options{Namespace: leaseNamespace, Identity: identity})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharder.clock = clock
+
+	keys := &[]string{}
+	sharder.AddRebalanceHandler(func(owned []string) {
+		*keys = owned
+	})
+	return sharder, keys
+}
+
+func TestSharder(t *testing.T) {
+	var placements []runtime.Object
+	for i := 0; i < 20; i++ {
+		placements = append(placements, testinghelpers.NewPlacement(fmt.Sprintf("ns%d", i), "test").Build())
+	}
+
+	kubeClient := kubefake.NewSimpleClientset()
+	clock := testingclock.NewFakeClock(time.Now())
+	sharder1, keys1 := newTestSharder(t, kubeClient, clock, "replica1", placements...)
+	sharder2, keys2 := newTestSharder(t, kubeClient, clock, "replica2", placements...)
+
+	if sharder1.Owns("ns0") {
+		t.Errorf("expect no namespace owned before the first rebalance")
+	}
+
+	// replica1 owns all the namespaces when it is the only member
+	sharder1.sync(context.TODO())
+	if len(*keys1) != 20 {
+		t.Errorf("expect replica1 owns 20 placements, but got %d", len(*keys1))
+	}
+
+	// the namespaces are split after replica2 joins
+	sharder2.sync(context.TODO())
+	sharder1.sync(context.TODO())
+	if len(*keys1) == 0 || len(*keys2) == 0 || len(*keys1)+len(*keys2) != 20 {
+		t.Errorf("expect placements split across replicas, but got %d and %d", len(*keys1), len(*keys2))
+	}
+	for i := 0; i < 20; i++ {
+		namespace := fmt.Sprintf("ns%d", i)
+		if sharder1.Owns(namespace) == sharder2.Owns(namespace) {
+			t.Errorf("expect namespace %s owned by exactly one replica", namespace)
+		}
+		if sharder1.OwnsKey(namespace+"/test") != sharder1.Owns(namespace) {
+			t.Errorf("expect the same ownership of namespace %s and its placement", namespace)
+		}
+	}
+
+	// no rebalance if the members do not change
+	*keys1 = nil
+	sharder1.sync(context.TODO())
+	if *keys1 != nil {
+		t.Errorf("expect no rebalance without member changes")
+	}
+
+	// replica1 takes over all the namespaces after the lease of replica2 expires
+	clock.Step(20 * time.Second)
+	sharder1.sync(context.TODO())
+	clock.Step(20 * time.Second)
+	sharder1.sync(context.TODO())
+	if len(*keys1) != 20 {
+		t.Errorf("expect replica1 owns 20 placements, but got %d", len(*keys1))
+	}
+
+	// replica2 owns nothing since its own lease is expired
+	kubeClient.PrependReactor("update", "leases", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("failed to update")
+	})
+	sharder2.sync(context.TODO())
+	for i := 0; i < 20; i++ {
+		if sharder2.Owns(fmt.Sprintf("ns%d", i)) {
+			t.Errorf("expect replica2 owns no namespace")
+		}
+	}
+
+	// the lease is deleted on release
+	if err := sharder1.release(context.TODO()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	leases, _ := kubeClient.CoordinationV1().Leases(leaseNamespace).List(context.TODO(), metav1.ListOptions{})
+	if len(leases.Items) != 1 {
+		t.Errorf("expect 1 lease left, but got %d", len(leases.Items))
+	}
+}
+
+func TestNewSharder(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	if _, err := NewSharder(kubeClient.CoordinationV1(), nil, Options{Identity: "replica1"}); err == nil {
+		t.Errorf("expect error without namespace")
+	}
+	if _, err := NewSharder(kubeClient.CoordinationV1(), nil, Options{Namespace: leaseNamespace}); err == nil {
+		t.Errorf("expect error without identity")
+	}
+
+	var sharder *Sharder
+	if !sharder.Owns("ns1") || !sharder.OwnsKey("ns1/test") {
+		t.Errorf("expect nil sharder owns all the namespaces")
+	}
+}
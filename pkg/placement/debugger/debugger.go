@@ -16,10 +16,12 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
+	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
 	clusterinformerv1beta1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta1"
 	clusterinformerv1beta2 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta2"
 	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterlisterv1alpha1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1alpha1"
 	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
 	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
@@ -41,6 +43,15 @@ type Debugger struct {
 	clusterSetLister        clusterlisterv1beta2.ManagedClusterSetLister
 	clusterSetBindingLister clusterlisterv1beta2.ManagedClusterSetBindingLister
 	placementLister         clusterlisterv1beta1.PlacementLister
+
+	// the client, listers and the scheduler factory used by the what-if endpoint
+	clusterClient           clusterclient.Interface
+	placementDecisionLister clusterlisterv1beta1.PlacementDecisionLister
+	scoreLister             clusterlisterv1alpha1.AddOnPlacementScoreLister
+	schedulerFactory        SchedulerFactory
+
+	// the decision history recorded by the scheduling controller
+	history *scheduling.DecisionHistory
 }
 
 // ClusterScore represents a cluster with its score
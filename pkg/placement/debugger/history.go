@@ -0,0 +1,62 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/client-go/tools/cache"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/scheduling"
+)
+
+// HistoryPath is the path of the decision history of the placements, e.g.
+// /debug/history/placements/<namespace>/<name>
+const HistoryPath = "/debug/history/placements/"
+
+// HistoryResult is the decision history of a placement returned by debugger
+type HistoryResult struct {
+	Records []scheduling.HistoryRecord `json:"records,omitempty"`
+	Error   string                     `json:"error,omitempty"`
+}
+
+// WithHistory enables the endpoint of the decision history.
+func (d *Debugger) WithHistory(history *scheduling.DecisionHistory) *Debugger {
+	d.history = history
+	return d
+}
+
+// HistoryHandler returns the decision history of the placement, the oldest record first.
+func (d *Debugger) HistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if d.history == nil {
+		d.reportErr(w, http.StatusNotImplemented, fmt.Errorf("decision history is not enabled"))
+		return
+	}
+	if r.Method != http.MethodGet {
+		d.reportErr(w, http.StatusMethodNotAllowed, fmt.Errorf("only GET is supported"))
+		return
+	}
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(strings.TrimPrefix(r.URL.Path, HistoryPath))
+	if err != nil {
+		d.reportErr(w, http.StatusBadRequest, err)
+		return
+	}
+	if namespace == "" || name == "" {
+		d.reportErr(w, http.StatusBadRequest, fmt.Errorf("invalid history path: namespace and name required"))
+		return
+	}
+
+	// Check if user has permission to create placements in this namespace
+	if err := d.checkPermission(r, namespace); err != nil {
+		d.reportPermissionErr(w, err)
+		return
+	}
+
+	result := HistoryResult{Records: d.history.Get(namespace + "/" + name)}
+	resultByte, _ := json.Marshal(result)
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resultByte)
+}
@@ -0,0 +1,99 @@
+package debugger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/scheduling"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestHistoryHandler(t *testing.T) {
+	history := scheduling.NewDecisionHistory(10)
+	history.Record("test/placement1", sets.New[string]("cluster1"), &testResult{}, "")
+
+	kubeClient := kubefake.NewClientset()
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sar := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		sar.Status.Allowed = true
+		return true, sar, nil
+	})
+	clusterInformerFactory := testinghelpers.NewClusterInformerFactory(clusterfake.NewSimpleClientset())
+	debugger := NewDebugger(
+		&testScheduler{result: &testResult{}},
+		kubeClient,
+		clusterInformerFactory.Cluster().V1beta1().Placements(),
+		clusterInformerFactory.Cluster().V1().ManagedClusters(),
+		clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+		clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings(),
+	).WithHistory(history)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := request.WithUser(r.Context(), &user.DefaultInfo{Name: "test-user"})
+		debugger.HistoryHandler(w, r.WithContext(ctx))
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name            string
+		path            string
+		expectedStatus  int
+		expectedRemoved [][]string
+	}{
+		{
+			name:            "placement with history",
+			path:            HistoryPath + "test/placement1",
+			expectedStatus:  http.StatusOK,
+			expectedRemoved: [][]string{{"cluster1"}},
+		},
+		{
+			name:           "placement without history",
+			path:           HistoryPath + "test/placement2",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid path",
+			path:           HistoryPath + "placement1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := http.Get(server.URL + c.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != c.expectedStatus {
+				t.Errorf("expected status %d, but got %d", c.expectedStatus, res.StatusCode)
+			}
+
+			result := &HistoryResult{}
+			if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+				t.Fatalf("unexpected error decoding result: %v", err)
+			}
+			var removed [][]string
+			for _, record := range result.Records {
+				removed = append(removed, record.Removed)
+			}
+			if !reflect.DeepEqual(removed, c.expectedRemoved) {
+				t.Errorf("expected removed %v, but got %v", c.expectedRemoved, removed)
+			}
+		})
+	}
+}
@@ -0,0 +1,428 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	kevents "k8s.io/client-go/tools/events"
+
+	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterinformerv1alpha1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1alpha1"
+	clusterinformerv1beta1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterlisterv1alpha1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/scheduling"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// WhatIfPath is the path of the dry-run endpoint which evaluates hypothetical changes
+	// against all the placements.
+	WhatIfPath = "/debug/whatif"
+
+	// outputText is the value of the "output" query parameter to return the result as plain text.
+	outputText = "text"
+)
+
+// SchedulerFactory builds a scheduler with the given plugin handle.
+type SchedulerFactory func(handle plugins.Handle) scheduling.Scheduler
+
+// WhatIfRequest contains the hypothetical changes to evaluate.
+type WhatIfRequest struct {
+	// Namespace limits the evaluation to the placements in the namespace. All the placements
+	// are evaluated if it is empty.
+	Namespace string `json:"namespace,omitempty"`
+	// Clusters contains the changes of the ManagedClusters.
+	Clusters []ClusterChange `json:"clusters,omitempty"`
+	// Scores contains the changes of the AddOnPlacementScores.
+	Scores []ScoreChange `json:"scores,omitempty"`
+}
+
+// ClusterChange is a hypothetical change of a ManagedCluster.
+type ClusterChange struct {
+	Name string `json:"name"`
+	// Remove removes the cluster, e.g. to evaluate draining the cluster.
+	Remove bool `json:"remove,omitempty"`
+	// AddTaints adds or replaces the taints with the same key and effect.
+	AddTaints []clusterv1.Taint `json:"addTaints,omitempty"`
+	// RemoveTaints removes the taints with the keys.
+	RemoveTaints []string `json:"removeTaints,omitempty"`
+	// SetLabels adds or updates the labels.
+	SetLabels map[string]string `json:"setLabels,omitempty"`
+	// RemoveLabels removes the labels, e.g. the clusterset label to remove the cluster from a set.
+	RemoveLabels []string `json:"removeLabels,omitempty"`
+}
+
+// ScoreChange is a hypothetical change of an AddOnPlacementScore.
+type ScoreChange struct {
+	// ClusterName is the namespace of the AddOnPlacementScore.
+	ClusterName string `json:"clusterName"`
+	Name        string `json:"name"`
+	// Remove removes the AddOnPlacementScore.
+	Remove bool `json:"remove,omitempty"`
+	// Scores adds or replaces the score items with the same name.
+	Scores []clusterv1alpha1.AddOnPlacementScoreItem `json:"scores,omitempty"`
+}
+
+// WhatIfResult is the result of the dry-run.
+type WhatIfResult struct {
+	// Placements contains the placements whose decisions change.
+	Placements []PlacementDiff `json:"placements,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// PlacementDiff is the difference between the current decisions of a placement and the
+// decisions with the hypothetical changes.
+type PlacementDiff struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Added     []string `json:"added,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+	Decisions []string `json:"decisions"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// WithWhatIf enables the dry-run endpoint. The factory builds a scheduler on top of the in-memory
+// overlay of the listers for each request, it should be the same factory that builds the scheduler
+// of the scheduling controller so the dry run has the same plugins and options.
+func (d *Debugger) WithWhatIf(
+	clusterClient clusterclient.Interface,
+	placementDecisionInformer clusterinformerv1beta1.PlacementDecisionInformer,
+	placementScoreInformer clusterinformerv1alpha1.AddOnPlacementScoreInformer,
+	schedulerFactory SchedulerFactory,
+) *Debugger {
+	d.clusterClient = clusterClient
+	d.placementDecisionLister = placementDecisionInformer.Lister()
+	d.scoreLister = placementScoreInformer.Lister()
+	d.schedulerFactory = schedulerFactory
+	return d
+}
+
+// WhatIfHandler evaluates the hypothetical changes posted in the request body and returns the
+// decision changes of the affected placements. The placements in the namespaces where the user
+// is not allowed to create placements are not evaluated.
+func (d *Debugger) WhatIfHandler(w http.ResponseWriter, r *http.Request) {
+	if d.schedulerFactory == nil {
+		d.reportErr(w, http.StatusNotImplemented, fmt.Errorf("what-if is not enabled"))
+		return
+	}
+	if r.Method != http.MethodPost {
+		d.reportErr(w, http.StatusMethodNotAllowed, fmt.Errorf("only POST is supported"))
+		return
+	}
+
+	whatIf, err := d.parseWhatIfFromBody(r)
+	if err != nil {
+		d.reportErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	clusterLister, err := d.overlayClusters(whatIf.Clusters)
+	if err != nil {
+		d.reportErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	scoreLister, err := d.overlayScores(whatIf.Scores)
+	if err != nil {
+		d.reportErr(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// the events of the dry run are discarded and the dry run is not recorded in the scheduling metrics.
+	scheduler := d.schedulerFactory(scheduling.NewSchedulerHandler(
+		d.clusterClient, d.placementDecisionLister, scoreLister, clusterLister, &kevents.FakeRecorder{}, nil))
+
+	var placements []*clusterv1beta1.Placement
+	if len(whatIf.Namespace) > 0 {
+		placements, err = d.placementLister.Placements(whatIf.Namespace).List(labels.Everything())
+	} else {
+		placements, err = d.placementLister.List(labels.Everything())
+	}
+	if err != nil {
+		d.reportErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	sort.Slice(placements, func(i, j int) bool {
+		if placements[i].Namespace == placements[j].Namespace {
+			return placements[i].Name < placements[j].Name
+		}
+		return placements[i].Namespace < placements[j].Namespace
+	})
+
+	result := WhatIfResult{}
+	allowedNamespaces := map[string]bool{}
+	for _, placement := range placements {
+		if !placement.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if placement.Annotations[clusterv1beta1.PlacementDisableAnnotation] == "true" {
+			continue
+		}
+
+		allowed, checked := allowedNamespaces[placement.Namespace]
+		if !checked {
+			allowed = d.checkPermission(r, placement.Namespace) == nil
+			allowedNamespaces[placement.Namespace] = allowed
+		}
+		if !allowed {
+			continue
+		}
+
+		diff := d.diffPlacement(r, scheduler, clusterLister, placement)
+		if len(diff.Added) > 0 || len(diff.Removed) > 0 || len(diff.Error) > 0 {
+			result.Placements = append(result.Placements, diff)
+		}
+	}
+
+	if r.URL.Query().Get("output") == outputText {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(formatWhatIfResult(result)))
+		return
+	}
+
+	resultByte, _ := json.Marshal(result)
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resultByte)
+}
+
+// diffPlacement schedules the placement against the overlay and compares the result with the
+// current decisions of the placement.
+func (d *Debugger) diffPlacement(
+	r *http.Request,
+	scheduler scheduling.Scheduler,
+	clusterLister clusterlisterv1.ManagedClusterLister,
+	placement *clusterv1beta1.Placement,
+) PlacementDiff {
+	diff := PlacementDiff{Namespace: placement.Namespace, Name: placement.Name, Decisions: []string{}}
+
+	bindings, err := scheduling.GetValidManagedClusterSetBindings(placement.Namespace, d.clusterSetBindingLister, d.clusterSetLister)
+	if err != nil {
+		diff.Error = err.Error()
+		return diff
+	}
+	clusterSetNames := scheduling.GetEligibleClusterSets(placement, bindings)
+	clusters, err := scheduling.GetAvailableClusters(clusterSetNames, d.clusterSetLister, clusterLister)
+	if err != nil {
+		diff.Error = err.Error()
+		return diff
+	}
+
+	scheduleResult, status := scheduler.Schedule(r.Context(), placement, clusters)
+	if status.IsError() {
+		diff.Error = status.AsError().Error()
+		return diff
+	}
+
+	current := sets.New[string]()
+	selector := labels.SelectorFromSet(labels.Set{clusterv1beta1.PlacementLabel: placement.Name})
+	decisions, err := d.placementDecisionLister.PlacementDecisions(placement.Namespace).List(selector)
+	if err != nil {
+		diff.Error = err.Error()
+		return diff
+	}
+	for _, decision := range decisions {
+		for _, cd := range decision.Status.Decisions {
+			current.Insert(cd.ClusterName)
+		}
+	}
+
+	desired := sets.New[string]()
+	for _, cluster := range scheduleResult.Decisions() {
+		desired.Insert(cluster.Name)
+	}
+
+	diff.Decisions = sets.List(desired)
+	diff.Added = sets.List(desired.Difference(current))
+	diff.Removed = sets.List(current.Difference(desired))
+	return diff
+}
+
+// overlayClusters returns a cluster lister with the changes applied on a copy of the current clusters.
+func (d *Debugger) overlayClusters(changes []ClusterChange) (clusterlisterv1.ManagedClusterLister, error) {
+	clusters, err := d.clusterLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	changesByName := map[string]ClusterChange{}
+	for _, change := range changes {
+		changesByName[change.Name] = change
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, cluster := range clusters {
+		change, ok := changesByName[cluster.Name]
+		if !ok {
+			if err := indexer.Add(cluster); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if change.Remove {
+			continue
+		}
+		if err := indexer.Add(applyClusterChange(cluster.DeepCopy(), change)); err != nil {
+			return nil, err
+		}
+	}
+
+	return clusterlisterv1.NewManagedClusterLister(indexer), nil
+}
+
+func applyClusterChange(cluster *clusterv1.ManagedCluster, change ClusterChange) *clusterv1.ManagedCluster {
+	if len(change.SetLabels) > 0 && cluster.Labels == nil {
+		cluster.Labels = map[string]string{}
+	}
+	for key, value := range change.SetLabels {
+		cluster.Labels[key] = value
+	}
+	for _, key := range change.RemoveLabels {
+		delete(cluster.Labels, key)
+	}
+
+	removedTaints := sets.New[string](change.RemoveTaints...)
+	var taints []clusterv1.Taint
+	for _, taint := range cluster.Spec.Taints {
+		if removedTaints.Has(taint.Key) {
+			continue
+		}
+		replaced := false
+		for _, added := range change.AddTaints {
+			if added.Key == taint.Key && added.Effect == taint.Effect {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			taints = append(taints, taint)
+		}
+	}
+	for _, taint := range change.AddTaints {
+		if taint.TimeAdded.IsZero() {
+			taint.TimeAdded = metav1.NewTime(time.Now())
+		}
+		taints = append(taints, taint)
+	}
+	cluster.Spec.Taints = taints
+
+	return cluster
+}
+
+// overlayScores returns a score lister with the changes applied on a copy of the current scores.
+func (d *Debugger) overlayScores(changes []ScoreChange) (clusterlisterv1alpha1.AddOnPlacementScoreLister, error) {
+	scores, err := d.scoreLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	changesByKey := map[string]ScoreChange{}
+	for _, change := range changes {
+		changesByKey[fmt.Sprintf("%s/%s", change.ClusterName, change.Name)] = change
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, score := range scores {
+		key := fmt.Sprintf("%s/%s", score.Namespace, score.Name)
+		change, ok := changesByKey[key]
+		if !ok {
+			if err := indexer.Add(score); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		delete(changesByKey, key)
+		if change.Remove {
+			continue
+		}
+		if err := indexer.Add(applyScoreChange(score.DeepCopy(), change)); err != nil {
+			return nil, err
+		}
+	}
+
+	// the scores which do not exist yet
+	for _, change := range changesByKey {
+		if change.Remove {
+			continue
+		}
+		score := &clusterv1alpha1.AddOnPlacementScore{
+			ObjectMeta: metav1.ObjectMeta{Namespace: change.ClusterName, Name: change.Name},
+		}
+		if err := indexer.Add(applyScoreChange(score, change)); err != nil {
+			return nil, err
+		}
+	}
+
+	return clusterlisterv1alpha1.NewAddOnPlacementScoreLister(indexer), nil
+}
+
+func applyScoreChange(score *clusterv1alpha1.AddOnPlacementScore, change ScoreChange) *clusterv1alpha1.AddOnPlacementScore {
+	for _, item := range change.Scores {
+		replaced := false
+		for i := range score.Status.Scores {
+			if score.Status.Scores[i].Name == item.Name {
+				score.Status.Scores[i].Value = item.Value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			score.Status.Scores = append(score.Status.Scores, item)
+		}
+	}
+	return score
+}
+
+func (d *Debugger) parseWhatIfFromBody(r *http.Request) (*WhatIfRequest, error) {
+	defer r.Body.Close()
+
+	if r.ContentLength > maxRequestBodyBytes {
+		return nil, fmt.Errorf("request body too large: %d bytes exceeds maximum of %d bytes", r.ContentLength, maxRequestBodyBytes)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(body) > maxRequestBodyBytes {
+		return nil, fmt.Errorf("request body too large: exceeds maximum of %d bytes", maxRequestBodyBytes)
+	}
+
+	whatIf := &WhatIfRequest{}
+	if err := json.Unmarshal(body, whatIf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal what-if request JSON: %w", err)
+	}
+	return whatIf, nil
+}
+
+// formatWhatIfResult prints one line for each affected placement, e.g.
+// default/placement1 +cluster3 -cluster1
+func formatWhatIfResult(result WhatIfResult) string {
+	var sb strings.Builder
+	for _, p := range result.Placements {
+		sb.WriteString(fmt.Sprintf("%s/%s", p.Namespace, p.Name))
+		for _, name := range p.Added {
+			sb.WriteString(" +" + name)
+		}
+		for _, name := range p.Removed {
+			sb.WriteString(" -" + name)
+		}
+		if len(p.Error) > 0 {
+			sb.WriteString(" error: " + p.Error)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,229 @@
+package debugger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterapiv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/scheduling"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+func newWhatIfServer(t *testing.T, initObjs []runtime.Object, deniedNamespace string) *httptest.Server {
+	t.Helper()
+
+	clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+	clusterInformerFactory := testinghelpers.NewClusterInformerFactory(clusterClient, initObjs...)
+	kubeClient := kubefake.NewClientset()
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sar := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		sar.Status.Allowed = sar.Spec.ResourceAttributes.Namespace != deniedNamespace
+		return true, sar, nil
+	})
+
+	debugger := NewDebugger(
+		&testScheduler{result: &testResult{}},
+		kubeClient,
+		clusterInformerFactory.Cluster().V1beta1().Placements(),
+		clusterInformerFactory.Cluster().V1().ManagedClusters(),
+		clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+		clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings(),
+	).WithWhatIf(
+		clusterClient,
+		clusterInformerFactory.Cluster().V1beta1().PlacementDecisions(),
+		clusterInformerFactory.Cluster().V1alpha1().AddOnPlacementScores(),
+		func(handle plugins.Handle) scheduling.Scheduler {
+			return scheduling.NewPluginScheduler(handle)
+		},
+	)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userInfo := &user.DefaultInfo{
+			Name:   "test-user",
+			Groups: []string{"system:authenticated"},
+		}
+		ctx := request.WithUser(r.Context(), userInfo)
+		debugger.WhatIfHandler(w, r.WithContext(ctx))
+	})
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWhatIf(t *testing.T) {
+	clusterSetSelector := clusterapiv1beta2.ManagedClusterSelector{
+		SelectorType: clusterapiv1beta2.LabelSelector,
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"clusterset": "test-set"},
+		},
+	}
+	initObjs := []runtime.Object{
+		testinghelpers.NewManagedCluster("cluster1").WithLabel("clusterset", "test-set").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithLabel("clusterset", "test-set").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithLabel("clusterset", "test-set").Build(),
+		testinghelpers.NewClusterSet("test-set").WithClusterSelector(clusterSetSelector).Build(),
+		testinghelpers.NewClusterSetBinding("ns1", "test-set"),
+		testinghelpers.NewClusterSetBinding("ns2", "test-set"),
+		testinghelpers.NewClusterSetBinding("denied", "test-set"),
+		testinghelpers.NewPlacement("ns1", "placement1").WithNOC(2).
+			WithPrioritizerPolicy(clusterapiv1beta1.PrioritizerPolicyModeExact).
+			WithPrioritizerConfig("Steady", 1).Build(),
+		testinghelpers.NewPlacementDecision("ns1", testinghelpers.PlacementDecisionName("placement1", 1)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, "placement1").
+			WithDecisions("cluster1", "cluster2").Build(),
+		testinghelpers.NewPlacement("ns2", "placement2").WithNOC(1).
+			WithPrioritizerPolicy(clusterapiv1beta1.PrioritizerPolicyModeExact).
+			WithScoreCoordinateAddOn("demo", "demo", 1).Build(),
+		testinghelpers.NewPlacementDecision("ns2", testinghelpers.PlacementDecisionName("placement2", 1)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, "placement2").
+			WithDecisions("cluster1").Build(),
+		testinghelpers.NewAddOnPlacementScore("cluster1", "demo").WithScore("demo", 50).Build(),
+		testinghelpers.NewAddOnPlacementScore("cluster2", "demo").WithScore("demo", 10).Build(),
+		testinghelpers.NewPlacement("denied", "placement3").WithNOC(1).Build(),
+	}
+
+	cases := []struct {
+		name            string
+		whatIf          WhatIfRequest
+		expectedDiffs   []PlacementDiff
+		expectedTextOut string
+	}{
+		{
+			name:   "no change",
+			whatIf: WhatIfRequest{},
+		},
+		{
+			name: "add taint",
+			whatIf: WhatIfRequest{
+				Namespace: "ns1",
+				Clusters: []ClusterChange{{
+					Name:      "cluster1",
+					AddTaints: []clusterapiv1.Taint{{Key: "maintenance", Effect: clusterapiv1.TaintEffectNoSelect}},
+				}},
+			},
+			expectedDiffs: []PlacementDiff{{
+				Namespace: "ns1", Name: "placement1",
+				Added: []string{"cluster3"}, Removed: []string{"cluster1"}, Decisions: []string{"cluster2", "cluster3"},
+			}},
+			expectedTextOut: "ns1/placement1 +cluster3 -cluster1\n",
+		},
+		{
+			name: "remove cluster from clusterset",
+			whatIf: WhatIfRequest{
+				Namespace: "ns1",
+				Clusters:  []ClusterChange{{Name: "cluster2", RemoveLabels: []string{"clusterset"}}},
+			},
+			expectedDiffs: []PlacementDiff{{
+				Namespace: "ns1", Name: "placement1",
+				Added: []string{"cluster3"}, Removed: []string{"cluster2"}, Decisions: []string{"cluster1", "cluster3"},
+			}},
+			expectedTextOut: "ns1/placement1 +cluster3 -cluster2\n",
+		},
+		{
+			name: "remove clusters",
+			whatIf: WhatIfRequest{
+				Clusters: []ClusterChange{{Name: "cluster1", Remove: true}, {Name: "cluster2", Remove: true}},
+			},
+			expectedDiffs: []PlacementDiff{
+				{
+					Namespace: "ns1", Name: "placement1",
+					Added: []string{"cluster3"}, Removed: []string{"cluster1", "cluster2"}, Decisions: []string{"cluster3"},
+				},
+				{
+					Namespace: "ns2", Name: "placement2",
+					Added: []string{"cluster3"}, Removed: []string{"cluster1"}, Decisions: []string{"cluster3"},
+				},
+			},
+			expectedTextOut: "ns1/placement1 +cluster3 -cluster1 -cluster2\nns2/placement2 +cluster3 -cluster1\n",
+		},
+		{
+			name: "change score",
+			whatIf: WhatIfRequest{
+				Scores: []ScoreChange{{
+					ClusterName: "cluster2",
+					Name:        "demo",
+					Scores:      []clusterapiv1alpha1.AddOnPlacementScoreItem{{Name: "demo", Value: 100}},
+				}},
+			},
+			expectedDiffs: []PlacementDiff{{
+				Namespace: "ns2", Name: "placement2",
+				Added: []string{"cluster2"}, Removed: []string{"cluster1"}, Decisions: []string{"cluster2"},
+			}},
+			expectedTextOut: "ns2/placement2 +cluster2 -cluster1\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := newWhatIfServer(t, initObjs, "denied")
+
+			body, err := json.Marshal(c.whatIf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := http.Post(server.URL+WhatIfPath, "application/json", bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer res.Body.Close()
+
+			result := &WhatIfResult{}
+			if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+				t.Fatalf("unexpected error decoding result: %v", err)
+			}
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("expected status 200, but got %d: %s", res.StatusCode, result.Error)
+			}
+			if !reflect.DeepEqual(result.Placements, c.expectedDiffs) {
+				t.Errorf("expected diffs %v, but got %v", c.expectedDiffs, result.Placements)
+			}
+
+			res, err = http.Post(server.URL+WhatIfPath+"?output=text", "application/json", bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer res.Body.Close()
+
+			text, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("unexpected error reading response: %v", err)
+			}
+			if string(text) != c.expectedTextOut {
+				t.Errorf("expected text output %q, but got %q", c.expectedTextOut, string(text))
+			}
+		})
+	}
+}
+
+func TestWhatIfMethodNotAllowed(t *testing.T) {
+	server := newWhatIfServer(t, nil, "")
+
+	res, err := http.Get(server.URL + WhatIfPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer res.Body.Close()
+
+	assertErrorResponse(t, res, http.StatusMethodNotAllowed, "only POST is supported")
+}
@@ -127,6 +127,18 @@ func (b *PlacementBuilder) AddToleration(toleration *clusterapiv1beta1.Toleratio
 	return b
 }
 
+func (b *PlacementBuilder) AddSpreadConstraint(
+	topologyKey string, topologyKeyType clusterapiv1beta1.TopologyKeyType, maxSkew int32) *PlacementBuilder {
+	b.placement.Spec.SpreadPolicy.SpreadConstraints = append(b.placement.Spec.SpreadPolicy.SpreadConstraints,
+		clusterapiv1beta1.SpreadConstraintsTerm{
+			TopologyKey:       topologyKey,
+			TopologyKeyType:   topologyKeyType,
+			MaxSkew:           maxSkew,
+			WhenUnsatisfiable: clusterapiv1beta1.ScheduleAnyway,
+		})
+	return b
+}
+
 func (b *PlacementBuilder) WithNumOfSelectedClusters(nosc int32, placementName string) *PlacementBuilder {
 	b.placement.Status.NumberOfSelectedClusters = nosc
 	b.placement.Status.DecisionGroups = []clusterapiv1beta1.DecisionGroupStatus{
@@ -272,6 +284,14 @@ func (b *ManagedClusterBuilder) WithLabel(name, value string) *ManagedClusterBui
 	return b
 }
 
+func (b *ManagedClusterBuilder) WithAnnotation(name, value string) *ManagedClusterBuilder {
+	if b.cluster.Annotations == nil {
+		b.cluster.Annotations = map[string]string{}
+	}
+	b.cluster.Annotations[name] = value
+	return b
+}
+
 func (b *ManagedClusterBuilder) WithClaim(name, value string) *ManagedClusterBuilder {
 	claimMap := map[string]string{}
 	for _, claim := range b.cluster.Status.ClusterClaims {
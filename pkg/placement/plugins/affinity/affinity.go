@@ -0,0 +1,249 @@
+package affinity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// PlacementAffinityAnnotation defines the affinity and anti-affinity of a placement to the
+	// decisions of other placements in the same namespace. The value is a json of AffinityTerms, e.g.
+	// {"affinity":{"required":["placement-a"]},"antiAffinity":{"preferred":["placement-c"]}}
+	PlacementAffinityAnnotation = "cluster.open-cluster-management.io/experimental-placement-affinity"
+
+	placementLabel = clusterapiv1beta1.PlacementLabel
+	description    = `
+	PlacementAffinity filters and scores the clusters based on the decisions of other placements
+	in the same namespace. With required affinity, only the clusters selected by all the referenced
+	placements are kept, and with required anti-affinity, the clusters selected by any of the
+	referenced placements are filtered out. With preferred affinity and anti-affinity, the clusters
+	selected by the referenced placements are given a higher or a lower score.
+	`
+)
+
+var _ plugins.Filter = &PlacementAffinity{}
+var _ plugins.Prioritizer = &PlacementAffinity{}
+
+// AffinityTerms is the value of the PlacementAffinityAnnotation.
+type AffinityTerms struct {
+	// Affinity places the clusters on the same clusters as the referenced placements.
+	Affinity AffinityTerm `json:"affinity,omitempty"`
+	// AntiAffinity never or preferably does not co-locate the clusters with the referenced placements.
+	AntiAffinity AffinityTerm `json:"antiAffinity,omitempty"`
+}
+
+// AffinityTerm contains the names of the referenced placements.
+type AffinityTerm struct {
+	// Required placements must be satisfied, the unsatisfied clusters are filtered out.
+	Required []string `json:"required,omitempty"`
+	// Preferred placements are considered by scores.
+	Preferred []string `json:"preferred,omitempty"`
+}
+
+// GetAffinityTerms parses the affinity terms from the annotation of the placement. The
+// placement itself is ignored if it is referenced.
+func GetAffinityTerms(placement *clusterapiv1beta1.Placement) (*AffinityTerms, error) {
+	value, ok := placement.Annotations[PlacementAffinityAnnotation]
+	if !ok || len(value) == 0 {
+		return nil, nil
+	}
+
+	terms := &AffinityTerms{}
+	if err := json.Unmarshal([]byte(value), terms); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s: %v", PlacementAffinityAnnotation, err)
+	}
+
+	removeSelf := func(names []string) []string {
+		var result []string
+		for _, name := range names {
+			if name != placement.Name && len(name) > 0 {
+				result = append(result, name)
+			}
+		}
+		return result
+	}
+	terms.Affinity.Required = removeSelf(terms.Affinity.Required)
+	terms.Affinity.Preferred = removeSelf(terms.Affinity.Preferred)
+	terms.AntiAffinity.Required = removeSelf(terms.AntiAffinity.Required)
+	terms.AntiAffinity.Preferred = removeSelf(terms.AntiAffinity.Preferred)
+	return terms, nil
+}
+
+// ReferencedPlacements returns the names of all the placements referenced by the terms.
+func (t *AffinityTerms) ReferencedPlacements() sets.Set[string] {
+	names := sets.New[string]()
+	if t == nil {
+		return names
+	}
+	names.Insert(t.Affinity.Required...)
+	names.Insert(t.Affinity.Preferred...)
+	names.Insert(t.AntiAffinity.Required...)
+	names.Insert(t.AntiAffinity.Preferred...)
+	return names
+}
+
+// HasPreferred returns true if there are preferred affinity or anti-affinity terms.
+func (t *AffinityTerms) HasPreferred() bool {
+	return t != nil && (len(t.Affinity.Preferred) > 0 || len(t.AntiAffinity.Preferred) > 0)
+}
+
+type PlacementAffinity struct {
+	handle plugins.Handle
+}
+
+func New(handle plugins.Handle) *PlacementAffinity {
+	return &PlacementAffinity{
+		handle: handle,
+	}
+}
+
+func (p *PlacementAffinity) Name() string {
+	return reflect.TypeOf(*p).Name()
+}
+
+func (p *PlacementAffinity) Description() string {
+	return description
+}
+
+func (p *PlacementAffinity) Filter(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginFilterResult, *framework.Status) {
+	status := framework.NewStatus(p.Name(), framework.Success, "")
+
+	terms, err := GetAffinityTerms(placement)
+	if err != nil {
+		return plugins.PluginFilterResult{}, framework.NewStatus(p.Name(), framework.Misconfigured, err.Error())
+	}
+	if terms == nil || len(clusters) == 0 ||
+		(len(terms.Affinity.Required) == 0 && len(terms.AntiAffinity.Required) == 0) {
+		return plugins.PluginFilterResult{
+			Filtered: clusters,
+		}, status
+	}
+
+	var affinityDecisions []sets.Set[string]
+	for _, name := range terms.Affinity.Required {
+		decisions, err := p.getDecisionClusterNames(placement.Namespace, name)
+		if err != nil {
+			return plugins.PluginFilterResult{}, framework.NewStatus(p.Name(), framework.Error, err.Error())
+		}
+		affinityDecisions = append(affinityDecisions, decisions)
+	}
+
+	antiAffinityDecisions := sets.New[string]()
+	for _, name := range terms.AntiAffinity.Required {
+		decisions, err := p.getDecisionClusterNames(placement.Namespace, name)
+		if err != nil {
+			return plugins.PluginFilterResult{}, framework.NewStatus(p.Name(), framework.Error, err.Error())
+		}
+		antiAffinityDecisions = antiAffinityDecisions.Union(decisions)
+	}
+
+	matched := []*clusterapiv1.ManagedCluster{}
+	for _, cluster := range clusters {
+		if antiAffinityDecisions.Has(cluster.Name) {
+			continue
+		}
+		satisfied := true
+		for _, decisions := range affinityDecisions {
+			if !decisions.Has(cluster.Name) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			matched = append(matched, cluster)
+		}
+	}
+
+	return plugins.PluginFilterResult{
+		Filtered: matched,
+	}, status
+}
+
+func (p *PlacementAffinity) Score(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
+	scores := map[string]int64{}
+	for _, cluster := range clusters {
+		scores[cluster.Name] = 0
+	}
+
+	terms, err := GetAffinityTerms(placement)
+	if err != nil {
+		return plugins.PluginScoreResult{}, framework.NewStatus(p.Name(), framework.Misconfigured, err.Error())
+	}
+	if !terms.HasPreferred() {
+		return plugins.PluginScoreResult{
+			Scores: scores,
+		}, framework.NewStatus(p.Name(), framework.Success, "")
+	}
+
+	// each preferred affinity placement selecting the cluster adds 1 to the count, and each
+	// preferred anti-affinity placement selecting the cluster subtracts 1 from the count.
+	counts := map[string]int64{}
+	for _, name := range terms.Affinity.Preferred {
+		decisions, err := p.getDecisionClusterNames(placement.Namespace, name)
+		if err != nil {
+			return plugins.PluginScoreResult{}, framework.NewStatus(p.Name(), framework.Error, err.Error())
+		}
+		for clusterName := range decisions {
+			counts[clusterName]++
+		}
+	}
+	for _, name := range terms.AntiAffinity.Preferred {
+		decisions, err := p.getDecisionClusterNames(placement.Namespace, name)
+		if err != nil {
+			return plugins.PluginScoreResult{}, framework.NewStatus(p.Name(), framework.Error, err.Error())
+		}
+		for clusterName := range decisions {
+			counts[clusterName]--
+		}
+	}
+
+	// normalize the count to a score between 100 and -100.
+	total := int64(len(terms.Affinity.Preferred) + len(terms.AntiAffinity.Preferred))
+	for clusterName := range scores {
+		scores[clusterName] = counts[clusterName] * plugins.MaxClusterScore / total
+	}
+
+	return plugins.PluginScoreResult{
+		Scores: scores,
+	}, framework.NewStatus(p.Name(), framework.Success, "")
+}
+
+func (p *PlacementAffinity) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(p.Name(), framework.Success, "")
+}
+
+// getDecisionClusterNames returns the cluster names in the decisions of the referenced placement.
+func (p *PlacementAffinity) getDecisionClusterNames(namespace, placementName string) (sets.Set[string], error) {
+	clusterNames := sets.New[string]()
+	requirement, err := labels.NewRequirement(placementLabel, selection.Equals, []string{placementName})
+	if err != nil {
+		return clusterNames, err
+	}
+
+	labelSelector := labels.NewSelector().Add(*requirement)
+	decisions, err := p.handle.DecisionLister().PlacementDecisions(namespace).List(labelSelector)
+	if err != nil {
+		return clusterNames, err
+	}
+
+	for _, decision := range decisions {
+		for _, d := range decision.Status.Decisions {
+			clusterNames.Insert(d.ClusterName)
+		}
+	}
+	return clusterNames, nil
+}
@@ -0,0 +1,148 @@
+package affinity
+
+import (
+	"context"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func newPlacement(annotation string) *clusterapiv1beta1.Placement {
+	return testinghelpers.NewPlacementWithAnnotations("test", "test",
+		map[string]string{PlacementAffinityAnnotation: annotation}).Build()
+}
+
+func testClusters() []*clusterapiv1.ManagedCluster {
+	return []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").Build(),
+		testinghelpers.NewManagedCluster("cluster3").Build(),
+	}
+}
+
+var existingDecisions = []runtime.Object{
+	testinghelpers.NewPlacementDecision("test", "a-decision-1").WithLabel(placementLabel, "a").WithDecisions("cluster1", "cluster2").Build(),
+	testinghelpers.NewPlacementDecision("test", "b-decision-1").WithLabel(placementLabel, "b").WithDecisions("cluster2").Build(),
+	testinghelpers.NewPlacementDecision("test", "test-decision-1").WithLabel(placementLabel, "test").WithDecisions("cluster3").Build(),
+	testinghelpers.NewPlacementDecision("other", "a-decision-1").WithLabel(placementLabel, "a").WithDecisions("cluster3").Build(),
+}
+
+func TestFilter(t *testing.T) {
+	cases := []struct {
+		name             string
+		placement        *clusterapiv1beta1.Placement
+		expectedClusters []string
+		expectedCode     framework.Code
+	}{
+		{
+			name:             "no annotation",
+			placement:        testinghelpers.NewPlacement("test", "test").Build(),
+			expectedClusters: []string{"cluster1", "cluster2", "cluster3"},
+		},
+		{
+			name:         "invalid annotation",
+			placement:    newPlacement("invalid"),
+			expectedCode: framework.Misconfigured,
+		},
+		{
+			name:             "required affinity",
+			placement:        newPlacement(`{"affinity":{"required":["a"]}}`),
+			expectedClusters: []string{"cluster1", "cluster2"},
+		},
+		{
+			name:             "required affinity to multiple placements",
+			placement:        newPlacement(`{"affinity":{"required":["a","b"]}}`),
+			expectedClusters: []string{"cluster2"},
+		},
+		{
+			name:             "required affinity to placement without decisions",
+			placement:        newPlacement(`{"affinity":{"required":["c"]}}`),
+			expectedClusters: []string{},
+		},
+		{
+			name:             "required anti-affinity",
+			placement:        newPlacement(`{"antiAffinity":{"required":["b"]}}`),
+			expectedClusters: []string{"cluster1", "cluster3"},
+		},
+		{
+			name:             "required affinity and anti-affinity",
+			placement:        newPlacement(`{"affinity":{"required":["a"]},"antiAffinity":{"required":["b"]}}`),
+			expectedClusters: []string{"cluster1"},
+		},
+		{
+			name:             "reference to itself is ignored",
+			placement:        newPlacement(`{"antiAffinity":{"required":["test"]}}`),
+			expectedClusters: []string{"cluster1", "cluster2", "cluster3"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := New(testinghelpers.NewFakePluginHandle(t, nil, existingDecisions...))
+			result, status := p.Filter(context.TODO(), c.placement, testClusters())
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected code %v, but got %v: %s", c.expectedCode, status.Code(), status.Message())
+			}
+			if status.IsError() {
+				return
+			}
+
+			clusters := []string{}
+			for _, cluster := range result.Filtered {
+				clusters = append(clusters, cluster.Name)
+			}
+			if !apiequality.Semantic.DeepEqual(clusters, c.expectedClusters) {
+				t.Errorf("expected %v, but got %v", c.expectedClusters, clusters)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		name           string
+		placement      *clusterapiv1beta1.Placement
+		expectedScores map[string]int64
+	}{
+		{
+			name:           "no annotation",
+			placement:      testinghelpers.NewPlacement("test", "test").Build(),
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": 0},
+		},
+		{
+			name:           "preferred affinity",
+			placement:      newPlacement(`{"affinity":{"preferred":["a"]}}`),
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": 100, "cluster3": 0},
+		},
+		{
+			name:           "preferred anti-affinity",
+			placement:      newPlacement(`{"antiAffinity":{"preferred":["b"]}}`),
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": -100, "cluster3": 0},
+		},
+		{
+			name:           "preferred affinity and anti-affinity",
+			placement:      newPlacement(`{"affinity":{"preferred":["a"]},"antiAffinity":{"preferred":["b"]}}`),
+			expectedScores: map[string]int64{"cluster1": 50, "cluster2": 0, "cluster3": 0},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := New(testinghelpers.NewFakePluginHandle(t, nil, existingDecisions...))
+			result, status := p.Score(context.TODO(), c.placement, testClusters())
+			if status.IsError() {
+				t.Fatalf("unexpected error: %v", status.AsError())
+			}
+			if !apiequality.Semantic.DeepEqual(result.Scores, c.expectedScores) {
+				t.Errorf("expected %v, but got %v", c.expectedScores, result.Scores)
+			}
+		})
+	}
+}
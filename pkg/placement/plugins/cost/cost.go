@@ -0,0 +1,154 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/helpers"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// PriceKey is the name of the ClusterClaim or the key of the label of the cluster price.
+	PriceKey = "price.open-cluster-management.io"
+	// ProviderKey, RegionKey and InstanceTypeKey are the names of the ClusterClaims or the keys of the
+	// labels to look up the cluster price in the price table.
+	ProviderKey     = "platform.open-cluster-management.io"
+	RegionKey       = "region.open-cluster-management.io"
+	InstanceTypeKey = "instancetype.open-cluster-management.io"
+
+	description = `
+	Cost prioritizer prefers the cheaper clusters. The price of a cluster is read from the
+	ClusterClaim price.open-cluster-management.io, the label with the same key, or the price table
+	keyed by the provider, the region and the instance type of the cluster. The cheapest cluster
+	is given the highest score, while the most expensive one is given the lowest score. The clusters
+	without a valid price are given the lowest score as well, unless none of the clusters has a price,
+	in which case all the clusters are given score 0.
+	`
+)
+
+var _ plugins.Prioritizer = &Cost{}
+
+type Cost struct {
+	handle      plugins.Handle
+	priceSource PriceSource
+}
+
+// New returns a Cost prioritizer. The priceSource is optional.
+func New(handle plugins.Handle, priceSource PriceSource) *Cost {
+	return &Cost{
+		handle:      handle,
+		priceSource: priceSource,
+	}
+}
+
+func (c *Cost) Name() string {
+	return reflect.TypeOf(*c).Name()
+}
+
+func (c *Cost) Description() string {
+	return description
+}
+
+func (c *Cost) Score(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
+	scores := map[string]int64{}
+	var warnings []string
+
+	var table *PriceTable
+	if c.priceSource != nil {
+		var err error
+		table, err = c.priceSource.PriceTable()
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to get price table: %v", err))
+		}
+	}
+
+	prices := map[string]float64{}
+	var invalid []string
+	for _, cluster := range clusters {
+		scores[cluster.Name] = plugins.MinClusterScore
+		price, ok, err := getPrice(cluster, table)
+		if err != nil {
+			invalid = append(invalid, cluster.Name)
+			continue
+		}
+		if ok {
+			prices[cluster.Name] = price
+		}
+	}
+	if len(invalid) > 0 {
+		warnings = append(warnings, fmt.Sprintf("invalid price of clusters %s", strings.Join(invalid, ",")))
+	}
+
+	status := framework.NewStatus(c.Name(), framework.Success, "")
+	if len(warnings) > 0 {
+		status = framework.NewStatus(c.Name(), framework.Warning, warnings...)
+	}
+	if len(prices) == 0 {
+		for name := range scores {
+			scores[name] = 0
+		}
+		return plugins.PluginScoreResult{Scores: scores}, status
+	}
+
+	minPrice, maxPrice := -1.0, -1.0
+	for _, price := range prices {
+		if minPrice < 0 || price < minPrice {
+			minPrice = price
+		}
+		if price > maxPrice {
+			maxPrice = price
+		}
+	}
+
+	// score = (0.5 - (price - min(price)) / (max(price) - min(price))) * 2 * 100
+	for name, price := range prices {
+		if maxPrice == minPrice {
+			scores[name] = plugins.MaxClusterScore
+			continue
+		}
+		ratio := (price - minPrice) / (maxPrice - minPrice)
+		scores[name] = int64((0.5 - ratio) * 2.0 * float64(plugins.MaxClusterScore))
+	}
+
+	return plugins.PluginScoreResult{
+		Scores: scores,
+	}, status
+}
+
+func (c *Cost) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(c.Name(), framework.Success, "")
+}
+
+// getPrice returns the price of the cluster from the ClusterClaim, the label or the price table in order.
+func getPrice(cluster *clusterapiv1.ManagedCluster, table *PriceTable) (float64, bool, error) {
+	claims := helpers.GetClusterClaims(cluster)
+	value, ok := claims[PriceKey]
+	if !ok {
+		value, ok = cluster.Labels[PriceKey]
+	}
+	if ok {
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil || price < 0 {
+			return 0, false, fmt.Errorf("invalid price %q", value)
+		}
+		return price, true, nil
+	}
+
+	get := func(key string) string {
+		if v, ok := claims[key]; ok {
+			return v
+		}
+		return cluster.Labels[key]
+	}
+	price, ok := table.Lookup(get(ProviderKey), get(RegionKey), get(InstanceTypeKey))
+	return price, ok, nil
+}
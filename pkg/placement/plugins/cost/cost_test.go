@@ -0,0 +1,130 @@
+package cost
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+type fakePriceSource struct {
+	table *PriceTable
+	err   error
+}
+
+func (s *fakePriceSource) PriceTable() (*PriceTable, error) {
+	return s.table, s.err
+}
+
+func TestScoreClusterWithCost(t *testing.T) {
+	table := &PriceTable{
+		Prices: []PriceEntry{
+			{Provider: "AWS", Price: 2},
+			{Provider: "AWS", Region: "us-east-1", InstanceType: "m5.xlarge", Price: 1},
+			{Provider: "GCP", Price: 3},
+		},
+	}
+
+	cases := []struct {
+		name           string
+		priceSource    PriceSource
+		clusters       []*clusterapiv1.ManagedCluster
+		expectedScores map[string]int64
+		expectedCode   framework.Code
+	}{
+		{
+			name: "no price",
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").Build(),
+				testinghelpers.NewManagedCluster("cluster2").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0},
+			expectedCode:   framework.Success,
+		},
+		{
+			name: "price from claims and labels",
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithClaim(PriceKey, "1").WithLabel(PriceKey, "10").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel(PriceKey, "2").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithClaim(PriceKey, "3").Build(),
+				testinghelpers.NewManagedCluster("cluster4").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": 0, "cluster3": -100, "cluster4": -100},
+			expectedCode:   framework.Success,
+		},
+		{
+			name: "same price",
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithLabel(PriceKey, "1.5").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel(PriceKey, "1.5").Build(),
+				testinghelpers.NewManagedCluster("cluster3").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": 100, "cluster3": -100},
+			expectedCode:   framework.Success,
+		},
+		{
+			name:        "price from table",
+			priceSource: &fakePriceSource{table: table},
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithClaim(ProviderKey, "AWS").
+					WithClaim(RegionKey, "us-east-1").WithClaim(InstanceTypeKey, "m5.xlarge").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel(ProviderKey, "AWS").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithClaim(ProviderKey, "GCP").Build(),
+				testinghelpers.NewManagedCluster("cluster4").WithClaim(ProviderKey, "Azure").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": 0, "cluster3": -100, "cluster4": -100},
+			expectedCode:   framework.Success,
+		},
+		{
+			name:        "price from claim overrides table",
+			priceSource: &fakePriceSource{table: table},
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithClaim(ProviderKey, "GCP").WithClaim(PriceKey, "0.5").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithClaim(ProviderKey, "AWS").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": -100},
+			expectedCode:   framework.Success,
+		},
+		{
+			name: "invalid price",
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithLabel(PriceKey, "abc").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel(PriceKey, "-1").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithLabel(PriceKey, "1").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": -100, "cluster2": -100, "cluster3": 100},
+			expectedCode:   framework.Warning,
+		},
+		{
+			name:        "failed to get price table",
+			priceSource: &fakePriceSource{err: errors.New("not found")},
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithLabel(PriceKey, "1").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel(ProviderKey, "AWS").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": -100},
+			expectedCode:   framework.Warning,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cost := New(testinghelpers.NewFakePluginHandle(t, nil), c.priceSource)
+			placement := testinghelpers.NewPlacement("test", "test").Build()
+
+			scoreResult, status := cost.Score(context.TODO(), placement, c.clusters)
+			if status.Code() != c.expectedCode {
+				t.Errorf("Expect status code %v, but got %v: %s", c.expectedCode, status.Code(), status.Message())
+			}
+			if !apiequality.Semantic.DeepEqual(scoreResult.Scores, c.expectedScores) {
+				t.Errorf("Expect score %v, but got %v", c.expectedScores, scoreResult.Scores)
+			}
+		})
+	}
+}
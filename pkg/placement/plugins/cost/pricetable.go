@@ -0,0 +1,157 @@
+package cost
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// PriceTableKey is the key of the price table in the data of the ConfigMap.
+const PriceTableKey = "prices.yaml"
+
+// PriceTable is a list of the prices keyed by the provider, the region and the instance type of
+// clusters, e.g.
+//
+//	prices:
+//	- provider: AWS
+//	  region: us-east-1
+//	  instanceType: m5.xlarge
+//	  price: 0.192
+//	- provider: AWS
+//	  price: 0.2
+type PriceTable struct {
+	Prices []PriceEntry `json:"prices"`
+}
+
+// PriceEntry is the price of the clusters matching the provider, the region and the instance type.
+// An empty field matches any value.
+type PriceEntry struct {
+	Provider     string  `json:"provider,omitempty"`
+	Region       string  `json:"region,omitempty"`
+	InstanceType string  `json:"instanceType,omitempty"`
+	Price        float64 `json:"price"`
+}
+
+// PriceSource provides the price table.
+type PriceSource interface {
+	PriceTable() (*PriceTable, error)
+}
+
+// ParsePriceTable parses the price table from yaml or json data.
+func ParsePriceTable(data []byte) (*PriceTable, error) {
+	table := &PriceTable{}
+	if err := yaml.Unmarshal(data, table); err != nil {
+		return nil, fmt.Errorf("invalid price table: %v", err)
+	}
+	for _, entry := range table.Prices {
+		if entry.Price < 0 {
+			return nil, fmt.Errorf("invalid price table: negative price %v", entry.Price)
+		}
+	}
+	return table, nil
+}
+
+// Lookup returns the price of the most specific entry matching the provider, the region and the
+// instance type. If several entries are equally specific, the first one is used.
+func (t *PriceTable) Lookup(provider, region, instanceType string) (float64, bool) {
+	if t == nil {
+		return 0, false
+	}
+
+	matches := func(field, value string) (bool, int) {
+		if len(field) == 0 {
+			return true, 0
+		}
+		return field == value, 1
+	}
+
+	price, found, specificity := 0.0, false, -1
+	for _, entry := range t.Prices {
+		providerMatched, p := matches(entry.Provider, provider)
+		regionMatched, r := matches(entry.Region, region)
+		instanceTypeMatched, i := matches(entry.InstanceType, instanceType)
+		if !providerMatched || !regionMatched || !instanceTypeMatched {
+			continue
+		}
+		if p+r+i > specificity {
+			price, found, specificity = entry.Price, true, p+r+i
+		}
+	}
+	return price, found
+}
+
+type staticPriceSource struct {
+	table *PriceTable
+}
+
+// NewFilePriceSource returns a PriceSource with the price table loaded from the file.
+func NewFilePriceSource(path string) (PriceSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	table, err := ParsePriceTable(data)
+	if err != nil {
+		return nil, err
+	}
+	return &staticPriceSource{table: table}, nil
+}
+
+func (s *staticPriceSource) PriceTable() (*PriceTable, error) {
+	return s.table, nil
+}
+
+type configMapPriceSource struct {
+	lister corev1listers.ConfigMapNamespaceLister
+	name   string
+
+	lock            sync.Mutex
+	resourceVersion string
+	table           *PriceTable
+	missing         bool
+}
+
+// NewConfigMapPriceSource returns a PriceSource reading the price table from the ConfigMap. The
+// price table is parsed again only when the ConfigMap changes. A missing ConfigMap is logged once
+// and is treated as an empty price table.
+func NewConfigMapPriceSource(lister corev1listers.ConfigMapNamespaceLister, name string) PriceSource {
+	return &configMapPriceSource{
+		lister: lister,
+		name:   name,
+	}
+}
+
+func (s *configMapPriceSource) PriceTable() (*PriceTable, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	cm, err := s.lister.Get(s.name)
+	switch {
+	case errors.IsNotFound(err):
+		if !s.missing {
+			klog.Warningf("The price table ConfigMap %s is not found, only the prices of the clusters are used", s.name)
+			s.missing = true
+		}
+		s.resourceVersion, s.table = "", nil
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	s.missing = false
+
+	if cm.ResourceVersion == s.resourceVersion && s.table != nil {
+		return s.table, nil
+	}
+
+	table, err := ParsePriceTable([]byte(cm.Data[PriceTableKey]))
+	if err != nil {
+		return nil, err
+	}
+	s.resourceVersion, s.table = cm.ResourceVersion, table
+	return table, nil
+}
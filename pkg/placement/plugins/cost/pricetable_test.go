@@ -0,0 +1,145 @@
+package cost
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const testPriceTable = `
+prices:
+- provider: AWS
+  price: 0.2
+- provider: AWS
+  region: us-east-1
+  price: 0.15
+- provider: AWS
+  region: us-east-1
+  instanceType: m5.xlarge
+  price: 0.1
+- region: us-east-1
+  price: 0.5
+- price: 1
+`
+
+func TestPriceTableLookup(t *testing.T) {
+	table, err := ParsePriceTable([]byte(testPriceTable))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		name          string
+		provider      string
+		region        string
+		instanceType  string
+		expectedPrice float64
+	}{
+		{name: "exact match", provider: "AWS", region: "us-east-1", instanceType: "m5.xlarge", expectedPrice: 0.1},
+		{name: "provider and region", provider: "AWS", region: "us-east-1", instanceType: "m5.large", expectedPrice: 0.15},
+		{name: "provider only", provider: "AWS", region: "us-west-1", expectedPrice: 0.2},
+		{name: "first of the same specificity", provider: "GCP", region: "us-east-1", expectedPrice: 0.5},
+		{name: "default", provider: "GCP", region: "us-west-1", expectedPrice: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			price, ok := table.Lookup(c.provider, c.region, c.instanceType)
+			if !ok {
+				t.Fatalf("expect price found")
+			}
+			if price != c.expectedPrice {
+				t.Errorf("expect price %v, but got %v", c.expectedPrice, price)
+			}
+		})
+	}
+
+	var nilTable *PriceTable
+	if _, ok := nilTable.Lookup("AWS", "", ""); ok {
+		t.Errorf("expect no price found in nil table")
+	}
+	if _, ok := (&PriceTable{Prices: []PriceEntry{{Provider: "AWS", Price: 1}}}).Lookup("GCP", "", ""); ok {
+		t.Errorf("expect no price found")
+	}
+}
+
+func TestParsePriceTable(t *testing.T) {
+	if _, err := ParsePriceTable([]byte(`{"prices":[{"provider":"AWS","price":-1}]}`)); err == nil {
+		t.Errorf("expect error for negative price")
+	}
+	if _, err := ParsePriceTable([]byte(`prices: abc`)); err == nil {
+		t.Errorf("expect error for invalid price table")
+	}
+}
+
+func TestFilePriceSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.yaml")
+	if err := os.WriteFile(path, []byte(testPriceTable), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := NewFilePriceSource(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	table, err := source.PriceTable()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(table.Prices) != 5 {
+		t.Errorf("expect 5 prices, but got %d", len(table.Prices))
+	}
+
+	if _, err := NewFilePriceSource(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("expect error for missing file")
+	}
+}
+
+func TestConfigMapPriceSource(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	source := NewConfigMapPriceSource(corev1listers.NewConfigMapLister(indexer).ConfigMaps("ocm"), "prices")
+
+	// the missing configmap is treated as an empty price table
+	if table, err := source.PriceTable(); err != nil || table != nil {
+		t.Errorf("expect no price table and no error for missing configmap, but got %v, %v", table, err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ocm", Name: "prices", ResourceVersion: "1"},
+		Data:       map[string]string{PriceTableKey: testPriceTable},
+	}
+	if err := indexer.Add(cm); err != nil {
+		t.Fatal(err)
+	}
+	table, err := source.PriceTable()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(table.Prices) != 5 {
+		t.Errorf("expect 5 prices, but got %d", len(table.Prices))
+	}
+
+	// the cached table is returned if the configmap is not changed
+	cached, _ := source.PriceTable()
+	if cached != table {
+		t.Errorf("expect the cached price table")
+	}
+
+	updated := cm.DeepCopy()
+	updated.ResourceVersion = "2"
+	updated.Data[PriceTableKey] = `{"prices":[{"price":1}]}`
+	if err := indexer.Update(updated); err != nil {
+		t.Fatal(err)
+	}
+	table, err = source.PriceTable()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(table.Prices) != 1 {
+		t.Errorf("expect 1 price, but got %d", len(table.Prices))
+	}
+}
@@ -0,0 +1,316 @@
+package extender
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// PluginNamePrefix is the prefix of the extender plugin names. A placement references an
+	// extender prioritizer with the BuiltIn prioritizer name "Extender/<name>", e.g. "Extender/cost".
+	PluginNamePrefix = "Extender/"
+
+	defaultTimeout = 5 * time.Second
+
+	description = `
+	Extender calls an out-of-tree scheduling service over HTTP or gRPC to filter or score
+	the managed clusters of a placement.
+	`
+)
+
+// Protocol is the transport used to call an extender.
+type Protocol string
+
+const (
+	ProtocolHTTP Protocol = "HTTP"
+	ProtocolGRPC Protocol = "GRPC"
+)
+
+var _ plugins.Filter = &filter{}
+var _ plugins.Prioritizer = &prioritizer{}
+
+// Config is the configuration of the scheduler extenders, it is loaded from a yaml or json file.
+type Config struct {
+	Extenders []ExtenderConfig `json:"extenders"`
+}
+
+// ExtenderConfig defines how to reach an out-of-tree scheduling service.
+type ExtenderConfig struct {
+	// Name is the unique name of the extender.
+	Name string `json:"name"`
+
+	// Protocol is either HTTP or GRPC, HTTP by default.
+	Protocol Protocol `json:"protocol,omitempty"`
+
+	// Address is the url prefix of the extender when the protocol is HTTP, the filter and
+	// prioritize calls are posted to <address>/filter and <address>/prioritize. It is the
+	// target (host:port) of the gRPC server when the protocol is GRPC.
+	Address string `json:"address"`
+
+	// Filter indicates the extender filters the clusters. The extender filters are run after
+	// the built-in filters for every placement.
+	Filter bool `json:"filter,omitempty"`
+
+	// Prioritizer indicates the extender scores the clusters. A placement enables it with the
+	// BuiltIn prioritizer "Extender/<name>".
+	Prioritizer bool `json:"prioritizer,omitempty"`
+
+	// Weight is the default weight of the extender prioritizer. 0 means the prioritizer is
+	// only used by the placements referencing it in their PrioritizerPolicy.
+	Weight int32 `json:"weight,omitempty"`
+
+	// Timeout of each call to the extender, 5s by default.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+
+	// Ignorable indicates the failure of the extender is ignored. The clusters are not
+	// filtered and not scored by the extender if the call fails.
+	Ignorable bool `json:"ignorable,omitempty"`
+
+	// CAFile is the CA bundle used to verify the server certificate of the extender. The
+	// extender is called without TLS if it is empty.
+	CAFile string `json:"caFile,omitempty"`
+}
+
+// Args is the request sent to the extender.
+type Args struct {
+	Placement *clusterapiv1beta1.Placement   `json:"placement"`
+	Clusters  []*clusterapiv1.ManagedCluster `json:"clusters"`
+}
+
+// FilterResult is the response of the extender filter call.
+type FilterResult struct {
+	// ClusterNames contains the names of the clusters satisfying the extender.
+	ClusterNames []string `json:"clusterNames"`
+	// Error is set if the extender fails to filter the clusters.
+	Error string `json:"error,omitempty"`
+}
+
+// ScoreResult is the response of the extender prioritize call.
+type ScoreResult struct {
+	// Scores contains the score of each cluster, keyed by cluster name.
+	Scores map[string]int64 `json:"scores"`
+	// Error is set if the extender fails to score the clusters.
+	Error string `json:"error,omitempty"`
+}
+
+// client calls the extender with a certain protocol.
+type client interface {
+	Filter(ctx context.Context, args *Args) (*FilterResult, error)
+	Prioritize(ctx context.Context, args *Args) (*ScoreResult, error)
+}
+
+// Extender is an out-of-tree scheduling service.
+type Extender struct {
+	config ExtenderConfig
+	client client
+}
+
+// LoadConfig reads the extender config from a file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse extender config %q: %w", path, err)
+	}
+	return config, nil
+}
+
+// NewExtenders builds the extenders with the given config.
+func NewExtenders(config *Config) ([]*Extender, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	names := sets.New[string]()
+	var extenders []*Extender
+	for _, c := range config.Extenders {
+		if len(c.Name) == 0 {
+			return nil, fmt.Errorf("extender name is required")
+		}
+		if names.Has(c.Name) {
+			return nil, fmt.Errorf("duplicated extender %q", c.Name)
+		}
+		names.Insert(c.Name)
+
+		if len(c.Address) == 0 {
+			return nil, fmt.Errorf("address of extender %q is required", c.Name)
+		}
+		if c.Timeout.Duration <= 0 {
+			c.Timeout.Duration = defaultTimeout
+		}
+
+		var cli client
+		var err error
+		switch c.Protocol {
+		case "", ProtocolHTTP:
+			cli, err = newHTTPClient(c)
+		case ProtocolGRPC:
+			cli, err = newGRPCClient(c)
+		default:
+			err = fmt.Errorf("unsupported protocol %q", c.Protocol)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to build extender %q: %w", c.Name, err)
+		}
+
+		extenders = append(extenders, &Extender{config: c, client: cli})
+	}
+	return extenders, nil
+}
+
+// Name returns the name of the extender.
+func (e *Extender) Name() string {
+	return e.config.Name
+}
+
+// Weight returns the default weight of the extender prioritizer.
+func (e *Extender) Weight() int32 {
+	return e.config.Weight
+}
+
+// IsFilter returns true if the extender filters the clusters.
+func (e *Extender) IsFilter() bool {
+	return e.config.Filter
+}
+
+// IsPrioritizer returns true if the extender scores the clusters.
+func (e *Extender) IsPrioritizer() bool {
+	return e.config.Prioritizer
+}
+
+// Filter returns the filter plugin of the extender.
+func (e *Extender) Filter() plugins.Filter {
+	return &filter{extender: e}
+}
+
+// Prioritizer returns the prioritizer plugin of the extender.
+func (e *Extender) Prioritizer() plugins.Prioritizer {
+	return &prioritizer{extender: e}
+}
+
+// PluginName returns the plugin name of the extender, which is also the BuiltIn prioritizer
+// name referenced by the placements.
+func (e *Extender) PluginName() string {
+	return PluginNamePrefix + e.config.Name
+}
+
+type filter struct {
+	extender *Extender
+}
+
+func (f *filter) Name() string {
+	return f.extender.PluginName()
+}
+
+func (f *filter) Description() string {
+	return description
+}
+
+func (f *filter) Filter(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginFilterResult, *framework.Status) {
+	if len(clusters) == 0 {
+		return plugins.PluginFilterResult{Filtered: clusters}, framework.NewStatus(f.Name(), framework.Success, "")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.extender.config.Timeout.Duration)
+	defer cancel()
+
+	result, err := f.extender.client.Filter(ctx, &Args{Placement: placement, Clusters: clusters})
+	if err == nil && len(result.Error) > 0 {
+		err = fmt.Errorf("%s", result.Error)
+	}
+	if err != nil {
+		if f.extender.config.Ignorable {
+			return plugins.PluginFilterResult{Filtered: clusters}, framework.NewStatus(
+				f.Name(), framework.Warning, fmt.Sprintf("extender filter is ignored: %v", err))
+		}
+		return plugins.PluginFilterResult{}, framework.NewStatus(f.Name(), framework.Error, err.Error())
+	}
+
+	// only keep the clusters in the candidate list, the extender is not allowed to add clusters.
+	names := sets.New[string](result.ClusterNames...)
+	filtered := []*clusterapiv1.ManagedCluster{}
+	for _, cluster := range clusters {
+		if names.Has(cluster.Name) {
+			filtered = append(filtered, cluster)
+		}
+	}
+
+	return plugins.PluginFilterResult{Filtered: filtered}, framework.NewStatus(f.Name(), framework.Success, "")
+}
+
+func (f *filter) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(f.Name(), framework.Success, "")
+}
+
+type prioritizer struct {
+	extender *Extender
+}
+
+func (p *prioritizer) Name() string {
+	return p.extender.PluginName()
+}
+
+func (p *prioritizer) Description() string {
+	return description
+}
+
+func (p *prioritizer) Score(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
+	scores := map[string]int64{}
+	if len(clusters) == 0 {
+		return plugins.PluginScoreResult{Scores: scores}, framework.NewStatus(p.Name(), framework.Success, "")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.extender.config.Timeout.Duration)
+	defer cancel()
+
+	result, err := p.extender.client.Prioritize(ctx, &Args{Placement: placement, Clusters: clusters})
+	if err == nil && len(result.Error) > 0 {
+		err = fmt.Errorf("%s", result.Error)
+	}
+	if err != nil {
+		if p.extender.config.Ignorable {
+			return plugins.PluginScoreResult{Scores: scores}, framework.NewStatus(
+				p.Name(), framework.Warning, fmt.Sprintf("extender prioritizer is ignored: %v", err))
+		}
+		return plugins.PluginScoreResult{}, framework.NewStatus(p.Name(), framework.Error, err.Error())
+	}
+
+	// only keep the scores of the candidate clusters and clamp them into the valid range.
+	for _, cluster := range clusters {
+		score, ok := result.Scores[cluster.Name]
+		if !ok {
+			continue
+		}
+		switch {
+		case score > plugins.MaxClusterScore:
+			score = plugins.MaxClusterScore
+		case score < plugins.MinClusterScore:
+			score = plugins.MinClusterScore
+		}
+		scores[cluster.Name] = score
+	}
+
+	return plugins.PluginScoreResult{Scores: scores}, framework.NewStatus(p.Name(), framework.Success, "")
+}
+
+func (p *prioritizer) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(p.Name(), framework.Success, "")
+}
@@ -0,0 +1,269 @@
+package extender
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+// fakeExtender filters out the clusters without label "cost" and scores the rest by the label value.
+type fakeExtender struct {
+	delay time.Duration
+}
+
+func (f *fakeExtender) filter(args *Args) *FilterResult {
+	time.Sleep(f.delay)
+	result := &FilterResult{}
+	for _, cluster := range args.Clusters {
+		if _, ok := cluster.Labels["cost"]; ok {
+			result.ClusterNames = append(result.ClusterNames, cluster.Name)
+		}
+	}
+	// try to inject a cluster not in the candidate list
+	result.ClusterNames = append(result.ClusterNames, "injected")
+	return result
+}
+
+func (f *fakeExtender) prioritize(args *Args) *ScoreResult {
+	time.Sleep(f.delay)
+	result := &ScoreResult{Scores: map[string]int64{}}
+	for _, cluster := range args.Clusters {
+		switch cluster.Labels["cost"] {
+		case "low":
+			result.Scores[cluster.Name] = 200
+		case "high":
+			result.Scores[cluster.Name] = -100
+		}
+	}
+	return result
+}
+
+func newHTTPExtenderServer(f *fakeExtender) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+filterPath, func(w http.ResponseWriter, r *http.Request) {
+		args := &Args{}
+		if err := json.NewDecoder(r.Body).Decode(args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.filter(args))
+	})
+	mux.HandleFunc("/"+prioritizePath, func(w http.ResponseWriter, r *http.Request) {
+		args := &Args{}
+		if err := json.NewDecoder(r.Body).Decode(args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.prioritize(args))
+	})
+	return httptest.NewServer(mux)
+}
+
+func newGRPCExtenderServer(t *testing.T, f *fakeExtender) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer(grpc.ForceServerCodec(JSONCodec{}))
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: ServiceName,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Filter",
+				Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+					args := &Args{}
+					if err := dec(args); err != nil {
+						return nil, err
+					}
+					return f.filter(args), nil
+				},
+			},
+			{
+				MethodName: "Prioritize",
+				Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+					args := &Args{}
+					if err := dec(args); err != nil {
+						return nil, err
+					}
+					return f.prioritize(args), nil
+				},
+			},
+		},
+	}, struct{}{})
+
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+func testClusters() []*clusterapiv1.ManagedCluster {
+	return []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithLabel("cost", "low").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithLabel("cost", "high").Build(),
+		testinghelpers.NewManagedCluster("cluster3").Build(),
+	}
+}
+
+func TestExtender(t *testing.T) {
+	httpServer := newHTTPExtenderServer(&fakeExtender{})
+	defer httpServer.Close()
+	slowServer := newHTTPExtenderServer(&fakeExtender{delay: 500 * time.Millisecond})
+	defer slowServer.Close()
+	grpcAddress := newGRPCExtenderServer(t, &fakeExtender{})
+
+	cases := []struct {
+		name                string
+		config              ExtenderConfig
+		expectedFiltered    []string
+		expectedScores      map[string]int64
+		expectedStatusCode  framework.Code
+		expectedScoreStatus framework.Code
+	}{
+		{
+			name:                "http extender",
+			config:              ExtenderConfig{Name: "cost", Address: httpServer.URL},
+			expectedFiltered:    []string{"cluster1", "cluster2"},
+			expectedScores:      map[string]int64{"cluster1": 100, "cluster2": -100},
+			expectedStatusCode:  framework.Success,
+			expectedScoreStatus: framework.Success,
+		},
+		{
+			name:                "grpc extender",
+			config:              ExtenderConfig{Name: "cost", Protocol: ProtocolGRPC, Address: grpcAddress},
+			expectedFiltered:    []string{"cluster1", "cluster2"},
+			expectedScores:      map[string]int64{"cluster1": 100, "cluster2": -100},
+			expectedStatusCode:  framework.Success,
+			expectedScoreStatus: framework.Success,
+		},
+		{
+			name: "timeout",
+			config: ExtenderConfig{
+				Name: "cost", Address: slowServer.URL, Timeout: metav1.Duration{Duration: 100 * time.Millisecond}},
+			expectedStatusCode:  framework.Error,
+			expectedScoreStatus: framework.Error,
+		},
+		{
+			name: "timeout ignorable",
+			config: ExtenderConfig{
+				Name: "cost", Address: slowServer.URL, Timeout: metav1.Duration{Duration: 100 * time.Millisecond}, Ignorable: true},
+			expectedFiltered:    []string{"cluster1", "cluster2", "cluster3"},
+			expectedScores:      map[string]int64{},
+			expectedStatusCode:  framework.Warning,
+			expectedScoreStatus: framework.Warning,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			extenders, err := NewExtenders(&Config{Extenders: []ExtenderConfig{c.config}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			e := extenders[0]
+			if e.PluginName() != "Extender/cost" {
+				t.Errorf("unexpected plugin name %s", e.PluginName())
+			}
+
+			placement := testinghelpers.NewPlacement("test", "test").Build()
+
+			filterResult, status := e.Filter().Filter(context.TODO(), placement, testClusters())
+			if status.Code() != c.expectedStatusCode {
+				t.Errorf("expected filter status %v, but got %v: %s", c.expectedStatusCode, status.Code(), status.Message())
+			}
+			var filtered []string
+			for _, cluster := range filterResult.Filtered {
+				filtered = append(filtered, cluster.Name)
+			}
+			if len(filtered) != len(c.expectedFiltered) {
+				t.Fatalf("expected filtered %v, but got %v", c.expectedFiltered, filtered)
+			}
+			for i := range filtered {
+				if filtered[i] != c.expectedFiltered[i] {
+					t.Errorf("expected filtered %v, but got %v", c.expectedFiltered, filtered)
+				}
+			}
+
+			scoreResult, status := e.Prioritizer().Score(context.TODO(), placement, testClusters())
+			if status.Code() != c.expectedScoreStatus {
+				t.Errorf("expected score status %v, but got %v: %s", c.expectedScoreStatus, status.Code(), status.Message())
+			}
+			if len(scoreResult.Scores) != len(c.expectedScores) {
+				t.Fatalf("expected scores %v, but got %v", c.expectedScores, scoreResult.Scores)
+			}
+			for name, score := range c.expectedScores {
+				if scoreResult.Scores[name] != score {
+					t.Errorf("expected scores %v, but got %v", c.expectedScores, scoreResult.Scores)
+				}
+			}
+		})
+	}
+}
+
+func TestNewExtenders(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      *Config
+		expectedErr bool
+	}{
+		{
+			name:   "nil config",
+			config: nil,
+		},
+		{
+			name:        "no name",
+			config:      &Config{Extenders: []ExtenderConfig{{Address: "http://localhost"}}},
+			expectedErr: true,
+		},
+		{
+			name: "duplicated name",
+			config: &Config{Extenders: []ExtenderConfig{
+				{Name: "a", Address: "http://localhost"},
+				{Name: "a", Address: "http://localhost"},
+			}},
+			expectedErr: true,
+		},
+		{
+			name:        "no address",
+			config:      &Config{Extenders: []ExtenderConfig{{Name: "a"}}},
+			expectedErr: true,
+		},
+		{
+			name:        "unknown protocol",
+			config:      &Config{Extenders: []ExtenderConfig{{Name: "a", Address: "localhost:8080", Protocol: "UDP"}}},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := NewExtenders(c.config)
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestJSONCodecNotRegistered(t *testing.T) {
+	if codec := encoding.GetCodecV2(jsonCodecName); codec != nil {
+		t.Errorf("expected the json codec not registered globally, but got %v", codec)
+	}
+}
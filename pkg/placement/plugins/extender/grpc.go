@@ -0,0 +1,83 @@
+package extender
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// ServiceName is the full name of the gRPC service implemented by an extender. The
+	// messages are encoded with the "json" content-subtype, so the extender does not need
+	// to share generated protobuf code with the placement controller. The extender server
+	// decodes the messages with a json codec, e.g. grpc.ForceServerCodec(JSONCodec{}).
+	ServiceName = "io.open_cluster_management.placement.extender.v1.Extender"
+
+	FilterMethod     = "/" + ServiceName + "/Filter"
+	PrioritizeMethod = "/" + ServiceName + "/Prioritize"
+
+	jsonCodecName = "json"
+)
+
+// JSONCodec encodes the gRPC messages with json. It is set on each call of the extender client
+// instead of being registered globally, so the other gRPC clients and servers in the process
+// are not affected.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (JSONCodec) Name() string {
+	return jsonCodecName
+}
+
+// grpcClient calls the extender gRPC service.
+type grpcClient struct {
+	conn *grpc.ClientConn
+}
+
+func newGRPCClient(config ExtenderConfig) (client, error) {
+	creds := insecure.NewCredentials()
+	if len(config.CAFile) > 0 {
+		tlsConfig, err := loadTLSConfig(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	// the connection is established lazily on the first call.
+	conn, err := grpc.NewClient(config.Address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(JSONCodec{})),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grpcClient{conn: conn}, nil
+}
+
+func (c *grpcClient) Filter(ctx context.Context, args *Args) (*FilterResult, error) {
+	result := &FilterResult{}
+	if err := c.conn.Invoke(ctx, FilterMethod, args, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *grpcClient) Prioritize(ctx context.Context, args *Args) (*ScoreResult, error) {
+	result := &ScoreResult{}
+	if err := c.conn.Invoke(ctx, PrioritizeMethod, args, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package extender
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	filterPath     = "filter"
+	prioritizePath = "prioritize"
+)
+
+// httpClient posts the args as json to the extender.
+type httpClient struct {
+	urlPrefix string
+	client    *http.Client
+}
+
+func newHTTPClient(config ExtenderConfig) (client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(config.CAFile) > 0 {
+		tlsConfig, err := loadTLSConfig(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &httpClient{
+		urlPrefix: strings.TrimSuffix(config.Address, "/"),
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   config.Timeout.Duration,
+		},
+	}, nil
+}
+
+func (c *httpClient) Filter(ctx context.Context, args *Args) (*FilterResult, error) {
+	result := &FilterResult{}
+	if err := c.send(ctx, filterPath, args, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *httpClient) Prioritize(ctx context.Context, args *Args) (*ScoreResult, error) {
+	result := &ScoreResult{}
+	if err := c.send(ctx, prioritizePath, args, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *httpClient) send(ctx context.Context, action string, args *Args, result interface{}) error {
+	body, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/%s", c.urlPrefix, action)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed %s with extender at %s, code %d: %s", action, url, resp.StatusCode, string(data))
+	}
+
+	return json.Unmarshal(data, result)
+}
+
+func loadTLSConfig(caFile string) (*tls.Config, error) {
+	caData, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("no valid certificate found in %s", caFile)
+	}
+
+	return &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
@@ -0,0 +1,199 @@
+package maintenancewindow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears is the max number of the years searched for the next activation of a schedule, so
+// a schedule never activated, e.g. on Feb 30, does not loop forever.
+const maxSearchYears = 5
+
+// schedule is a standard cron schedule with the fields minute, hour, day of month, month and day of week.
+// Each field is a bit set of the matched values.
+type schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar are true if the day of month or the day of week field starts with "*".
+	// As in the standard cron, if both fields are restricted, a day matching either of them matches.
+	domStar, dowStar bool
+}
+
+type bounds struct {
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	minutes = bounds{0, 59, nil}
+	hours   = bounds{0, 23, nil}
+	doms    = bounds{1, 31, nil}
+	months  = bounds{1, 12, map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is Sunday as well as 0
+	dows = bounds{0, 7, map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	macros = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// parseSchedule parses a cron schedule with 5 fields, e.g. "0 2 * * sat", or a macro, e.g. "@daily".
+func parseSchedule(spec string) (*schedule, error) {
+	if macro, ok := macros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in cron schedule %q, but got %d", spec, len(fields))
+	}
+
+	s := &schedule{
+		domStar: strings.HasPrefix(fields[2], "*"),
+		dowStar: strings.HasPrefix(fields[4], "*"),
+	}
+	var err error
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], doms); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dows); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseField parses a comma separated list of "*", values or ranges with optional steps, e.g. "1-5/2".
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangeAndStep := strings.Split(part, "/")
+		if len(rangeAndStep) > 2 {
+			return 0, fmt.Errorf("invalid cron field %q", part)
+		}
+
+		var low, high uint
+		var err error
+		switch lowAndHigh := strings.Split(rangeAndStep[0], "-"); {
+		case rangeAndStep[0] == "*":
+			low, high = b.min, b.max
+		case len(lowAndHigh) == 1:
+			if low, err = parseValue(lowAndHigh[0], b); err != nil {
+				return 0, err
+			}
+			high = low
+			// a single value with a step means the range from the value to the max
+			if len(rangeAndStep) == 2 {
+				high = b.max
+			}
+		case len(lowAndHigh) == 2:
+			if low, err = parseValue(lowAndHigh[0], b); err != nil {
+				return 0, err
+			}
+			if high, err = parseValue(lowAndHigh[1], b); err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("invalid cron range %q", rangeAndStep[0])
+		}
+		if low > high {
+			return 0, fmt.Errorf("invalid cron range %q: %d is greater than %d", rangeAndStep[0], low, high)
+		}
+
+		step := uint64(1)
+		if len(rangeAndStep) == 2 {
+			if step, err = strconv.ParseUint(rangeAndStep[1], 10, 8); err != nil || step == 0 {
+				return 0, fmt.Errorf("invalid cron step %q", rangeAndStep[1])
+			}
+		}
+
+		for i := uint64(low); i <= uint64(high); i += step {
+			bits |= 1 << i
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (uint, error) {
+	if v, ok := b.names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cron value %q", value)
+	}
+	if uint(v) < b.min || uint(v) > b.max {
+		return 0, fmt.Errorf("cron value %d is out of range [%d, %d]", v, b.min, b.max)
+	}
+	return uint(v), nil
+}
+
+// next returns the first activation of the schedule after t in the location of t. A zero time is
+// returned if the schedule is not activated in the next years.
+func (s *schedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + maxSearchYears
+
+WRAP:
+	for t.Year() <= yearLimit {
+		for s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			if t.Month() == time.January {
+				continue WRAP
+			}
+		}
+		for !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			if t.Day() == 1 {
+				continue WRAP
+			}
+		}
+		for s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if t.Hour() == 0 {
+				continue WRAP
+			}
+		}
+		for s.minute&(1<<uint(t.Minute())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+			if t.Minute() == 0 {
+				continue WRAP
+			}
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *schedule) dayMatches(t time.Time) bool {
+	domMatched := s.dom&(1<<uint(t.Day())) != 0
+	dowMatched := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatched && dowMatched
+	}
+	return domMatched || dowMatched
+}
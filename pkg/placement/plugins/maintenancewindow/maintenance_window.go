@@ -0,0 +1,252 @@
+package maintenancewindow
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/utils/clock"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/helpers"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+var _ plugins.Filter = &MaintenanceWindow{}
+var MaintenanceClock = clock.Clock(clock.RealClock{})
+
+const (
+	// MaintenanceWindowsAnnotation is the annotation of the ManagedCluster with the maintenance windows.
+	MaintenanceWindowsAnnotation = "cluster.open-cluster-management.io/maintenance-windows"
+	// MaintenanceWindowsClaim is the name of the ClusterClaim with the maintenance windows.
+	MaintenanceWindowsClaim = "maintenancewindows.open-cluster-management.io"
+
+	// MaintenanceWindowEffectAnnotation is the annotation of the Placement to set how the clusters in
+	// maintenance are selected. The value is one of NoSelectIfNew (default), NoSelect and Ignore.
+	MaintenanceWindowEffectAnnotation = "cluster.open-cluster-management.io/experimental-maintenance-window-effect"
+
+	// EffectNoSelectIfNew keeps the clusters in maintenance out of the new decisions.
+	EffectNoSelectIfNew = "NoSelectIfNew"
+	// EffectNoSelect removes the clusters in maintenance from the decisions as well.
+	EffectNoSelect = "NoSelect"
+	// EffectIgnore selects the clusters regardless of the maintenance windows.
+	EffectIgnore = "Ignore"
+
+	placementLabel = "cluster.open-cluster-management.io/placement"
+	description    = `
+	MaintenanceWindow is a plugin that keeps the managed clusters out of the new decisions during
+	their maintenance windows. The windows are cron schedules with durations read from the cluster
+	annotation cluster.open-cluster-management.io/maintenance-windows, the ClusterClaim
+	maintenancewindows.open-cluster-management.io or the hub ConfigMap keyed by cluster name.
+	The placement is scheduled again at the next window boundary of the clusters it filters.
+	`
+)
+
+// WindowSource provides the maintenance windows of the clusters from the hub.
+type WindowSource interface {
+	// Windows returns the maintenance windows of the cluster, false if the cluster has no windows.
+	Windows(clusterName string) (string, bool, error)
+}
+
+type MaintenanceWindow struct {
+	handle       plugins.Handle
+	windowSource WindowSource
+
+	// boundaries is the next window boundary of the clusters filtered in the last Filter call of
+	// each placement, which is returned by RequeueAfter.
+	lock       sync.Mutex
+	boundaries map[string]time.Time
+}
+
+// New returns a MaintenanceWindow filter. The windowSource is optional.
+func New(handle plugins.Handle, windowSource WindowSource) *MaintenanceWindow {
+	return &MaintenanceWindow{
+		handle:       handle,
+		windowSource: windowSource,
+		boundaries:   map[string]time.Time{},
+	}
+}
+
+func (m *MaintenanceWindow) Name() string {
+	return reflect.TypeOf(m).Elem().Name()
+}
+
+func (m *MaintenanceWindow) Description() string {
+	return description
+}
+
+func (m *MaintenanceWindow) Filter(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginFilterResult, *framework.Status) {
+	key := placementKey(placement)
+	m.setBoundary(key, time.Time{})
+
+	effect, err := getEffect(placement)
+	if err != nil {
+		return plugins.PluginFilterResult{}, framework.NewStatus(m.Name(), framework.Misconfigured, err.Error())
+	}
+	if effect == EffectIgnore || len(clusters) == 0 {
+		return plugins.PluginFilterResult{Filtered: clusters}, framework.NewStatus(m.Name(), framework.Success, "")
+	}
+
+	var decisionClusterNames sets.Set[string]
+	if effect == EffectNoSelectIfNew {
+		decisionClusterNames = getDecisionClusterNames(m.handle, placement)
+	}
+
+	now := MaintenanceClock.Now()
+	var warnings []string
+	var requeueTime time.Time
+	matched := []*clusterapiv1.ManagedCluster{}
+	for _, cluster := range clusters {
+		if decisionClusterNames.Has(cluster.Name) {
+			matched = append(matched, cluster)
+			continue
+		}
+
+		windows, err := m.getWindows(cluster)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("cluster %s: %v", cluster.Name, err))
+		}
+		inMaintenance, boundary := InMaintenance(windows, now)
+		if !inMaintenance {
+			matched = append(matched, cluster)
+		}
+
+		// with the effect NoSelectIfNew, only the ends of the windows of the excluded clusters change
+		// the decisions, since the clusters already selected are kept.
+		if boundary.IsZero() || (!inMaintenance && effect == EffectNoSelectIfNew) {
+			continue
+		}
+		if requeueTime.IsZero() || boundary.Before(requeueTime) {
+			requeueTime = boundary
+		}
+	}
+	m.setBoundary(key, requeueTime)
+
+	status := framework.NewStatus(m.Name(), framework.Success, "")
+	if len(warnings) > 0 {
+		status = framework.NewStatus(m.Name(), framework.Warning, warnings...)
+	}
+	if len(matched) == len(clusters) {
+		return plugins.PluginFilterResult{Filtered: clusters}, status
+	}
+	return plugins.PluginFilterResult{
+		Filtered: matched,
+	}, status
+}
+
+// RequeueAfter returns the next window boundary of the clusters filtered in the last Filter call of
+// the placement. With the effect NoSelectIfNew, only the ends of the windows of the clusters excluded
+// by the filter are considered, since the starts do not change the decisions.
+func (m *MaintenanceWindow) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	status := framework.NewStatus(m.Name(), framework.Success, "")
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	key := placementKey(placement)
+	boundary, ok := m.boundaries[key]
+	if !ok {
+		return plugins.PluginRequeueResult{}, status
+	}
+	delete(m.boundaries, key)
+	return plugins.PluginRequeueResult{RequeueTime: &boundary}, status
+}
+
+func (m *MaintenanceWindow) setBoundary(key string, boundary time.Time) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if boundary.IsZero() {
+		delete(m.boundaries, key)
+		return
+	}
+	m.boundaries[key] = boundary
+}
+
+func placementKey(placement *clusterapiv1beta1.Placement) string {
+	return placement.Namespace + "/" + placement.Name
+}
+
+// getWindows returns the maintenance windows of the cluster from the annotation, the ClusterClaim
+// or the window source in order.
+func (m *MaintenanceWindow) getWindows(cluster *clusterapiv1.ManagedCluster) ([]Window, error) {
+	value, ok := cluster.Annotations[MaintenanceWindowsAnnotation]
+	if !ok {
+		value, ok = helpers.GetClusterClaims(cluster)[MaintenanceWindowsClaim]
+	}
+	if !ok && m.windowSource != nil {
+		var err error
+		value, ok, err = m.windowSource.Windows(cluster.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !ok {
+		return nil, nil
+	}
+	return ParseWindows(value)
+}
+
+func getEffect(placement *clusterapiv1beta1.Placement) (string, error) {
+	effect, ok := placement.Annotations[MaintenanceWindowEffectAnnotation]
+	if !ok {
+		return EffectNoSelectIfNew, nil
+	}
+	switch effect {
+	case EffectNoSelectIfNew, EffectNoSelect, EffectIgnore:
+		return effect, nil
+	}
+	return "", fmt.Errorf("invalid maintenance window effect %q, should be one of %s", effect,
+		strings.Join([]string{EffectNoSelectIfNew, EffectNoSelect, EffectIgnore}, ","))
+}
+
+func getDecisionClusterNames(handle plugins.Handle, placement *clusterapiv1beta1.Placement) sets.Set[string] {
+	existingDecisions := sets.New[string]()
+	selector := labels.SelectorFromSet(labels.Set{placementLabel: placement.Name})
+	decisions, err := handle.DecisionLister().PlacementDecisions(placement.Namespace).List(selector)
+	if err != nil {
+		return existingDecisions
+	}
+
+	for _, decision := range decisions {
+		for _, d := range decision.Status.Decisions {
+			existingDecisions.Insert(d.ClusterName)
+		}
+	}
+	return existingDecisions
+}
+
+type configMapWindowSource struct {
+	lister corev1listers.ConfigMapNamespaceLister
+	name   string
+}
+
+// NewConfigMapWindowSource returns a WindowSource reading the maintenance windows from the ConfigMap,
+// whose data is keyed by the cluster name.
+func NewConfigMapWindowSource(lister corev1listers.ConfigMapNamespaceLister, name string) WindowSource {
+	return &configMapWindowSource{
+		lister: lister,
+		name:   name,
+	}
+}
+
+func (s *configMapWindowSource) Windows(clusterName string) (string, bool, error) {
+	cm, err := s.lister.Get(s.name)
+	if errors.IsNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := cm.Data[clusterName]
+	return value, ok, nil
+}
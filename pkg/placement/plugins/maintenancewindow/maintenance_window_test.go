@@ -0,0 +1,191 @@
+package maintenancewindow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	testingclock "k8s.io/utils/clock/testing"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+// Saturday 03:00 UTC
+var fakeTime = time.Date(2024, time.June, 1, 3, 0, 0, 0, time.UTC)
+
+func TestMaintenanceWindow(t *testing.T) {
+	MaintenanceClock = testingclock.NewFakeClock(fakeTime)
+
+	// cluster1 is in maintenance until 06:00, cluster2 from the claim until 04:00, cluster3 from the
+	// configmap until 05:00, and cluster4 will be in maintenance from 08:00.
+	cluster1 := testinghelpers.NewManagedCluster("cluster1").WithAnnotation(MaintenanceWindowsAnnotation, "0 2 * * sat 4h").Build()
+	cluster2 := testinghelpers.NewManagedCluster("cluster2").WithClaim(MaintenanceWindowsClaim, "0 1 * * * 3h").Build()
+	cluster3 := testinghelpers.NewManagedCluster("cluster3").Build()
+	cluster4 := testinghelpers.NewManagedCluster("cluster4").WithAnnotation(MaintenanceWindowsAnnotation, "0 8 * * * 1h").Build()
+	clusters := []*clusterapiv1.ManagedCluster{cluster1, cluster2, cluster3, cluster4}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ocm", Name: "windows"},
+		Data: map[string]string{
+			"cluster1": "0 0 * * * 1h",
+			"cluster3": "0 3 * * * 2h",
+		},
+	}
+
+	cases := []struct {
+		name                 string
+		placement            *clusterapiv1beta1.Placement
+		initObjs             []runtime.Object
+		expectedClusterNames []string
+		expectedRequeueTime  *time.Time
+		expectedCode         framework.Code
+	}{
+		{
+			name:                 "clusters in maintenance are filtered",
+			placement:            testinghelpers.NewPlacement("test", "test").Build(),
+			expectedClusterNames: []string{"cluster4"},
+			expectedRequeueTime:  timePtr(time.Date(2024, time.June, 1, 4, 0, 0, 0, time.UTC)),
+			expectedCode:         framework.Success,
+		},
+		{
+			name:      "clusters in decisions are kept",
+			placement: testinghelpers.NewPlacement("test", "test").Build(),
+			initObjs: []runtime.Object{
+				testinghelpers.NewPlacementDecision("test", testinghelpers.PlacementDecisionName("test", 1)).
+					WithLabel(placementLabel, "test").WithDecisions("cluster1").Build(),
+			},
+			expectedClusterNames: []string{"cluster1", "cluster4"},
+			expectedRequeueTime:  timePtr(time.Date(2024, time.June, 1, 4, 0, 0, 0, time.UTC)),
+			expectedCode:         framework.Success,
+		},
+		{
+			name: "clusters in decisions are removed with NoSelect",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				MaintenanceWindowEffectAnnotation: EffectNoSelect,
+			}).Build(),
+			initObjs: []runtime.Object{
+				testinghelpers.NewPlacementDecision("test", testinghelpers.PlacementDecisionName("test", 1)).
+					WithLabel(placementLabel, "test").WithDecisions("cluster1").Build(),
+			},
+			expectedClusterNames: []string{"cluster4"},
+			expectedRequeueTime:  timePtr(time.Date(2024, time.June, 1, 4, 0, 0, 0, time.UTC)),
+			expectedCode:         framework.Success,
+		},
+		{
+			name: "maintenance windows are ignored",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				MaintenanceWindowEffectAnnotation: EffectIgnore,
+			}).Build(),
+			expectedClusterNames: []string{"cluster1", "cluster2", "cluster3", "cluster4"},
+			expectedCode:         framework.Success,
+		},
+		{
+			name: "invalid effect",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				MaintenanceWindowEffectAnnotation: "Invalid",
+			}).Build(),
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := indexer.Add(configMap); err != nil {
+				t.Fatal(err)
+			}
+			windowSource := NewConfigMapWindowSource(corev1listers.NewConfigMapLister(indexer).ConfigMaps("ocm"), "windows")
+
+			objs := append(c.initObjs, cluster1, cluster2, cluster3, cluster4)
+			p := New(testinghelpers.NewFakePluginHandle(t, nil, objs...), windowSource)
+
+			result, status := p.Filter(context.TODO(), c.placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Errorf("expect status code %v, but got %v: %s", c.expectedCode, status.Code(), status.Message())
+			}
+			if status.Code() == framework.Misconfigured {
+				return
+			}
+
+			var clusterNames []string
+			for _, cluster := range result.Filtered {
+				clusterNames = append(clusterNames, cluster.Name)
+			}
+			if !reflect.DeepEqual(clusterNames, c.expectedClusterNames) {
+				t.Errorf("expect clusters %v, but got %v", c.expectedClusterNames, clusterNames)
+			}
+
+			requeueResult, _ := p.RequeueAfter(context.TODO(), c.placement)
+			if !reflect.DeepEqual(requeueResult.RequeueTime, c.expectedRequeueTime) {
+				t.Errorf("expect requeue time %v, but got %v", c.expectedRequeueTime, requeueResult.RequeueTime)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowNoSelectRequeue(t *testing.T) {
+	MaintenanceClock = testingclock.NewFakeClock(fakeTime)
+
+	// the start of the next window is a boundary with NoSelect
+	cluster := testinghelpers.NewManagedCluster("cluster1").WithAnnotation(MaintenanceWindowsAnnotation, "30 3 * * * 1h").Build()
+	placement := testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+		MaintenanceWindowEffectAnnotation: EffectNoSelect,
+	}).Build()
+	p := New(testinghelpers.NewFakePluginHandle(t, nil, cluster), nil)
+
+	p.Filter(context.TODO(), placement, []*clusterapiv1.ManagedCluster{cluster})
+	requeueResult, _ := p.RequeueAfter(context.TODO(), placement)
+	expected := time.Date(2024, time.June, 1, 3, 30, 0, 0, time.UTC)
+	if requeueResult.RequeueTime == nil || !requeueResult.RequeueTime.Equal(expected) {
+		t.Errorf("expect requeue time %v, but got %v", expected, requeueResult.RequeueTime)
+	}
+
+	// the start of the next window is not a boundary with NoSelectIfNew
+	placement = testinghelpers.NewPlacement("test", "test").Build()
+	p.Filter(context.TODO(), placement, []*clusterapiv1.ManagedCluster{cluster})
+	requeueResult, _ = p.RequeueAfter(context.TODO(), placement)
+	if requeueResult.RequeueTime != nil {
+		t.Errorf("expect no requeue, but got %v", requeueResult.RequeueTime)
+	}
+
+	// the windows of the clusters not filtered by the plugin are not boundaries
+	placement = testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+		MaintenanceWindowEffectAnnotation: EffectNoSelect,
+	}).Build()
+	p.Filter(context.TODO(), placement, []*clusterapiv1.ManagedCluster{testinghelpers.NewManagedCluster("cluster2").Build()})
+	requeueResult, _ = p.RequeueAfter(context.TODO(), placement)
+	if requeueResult.RequeueTime != nil {
+		t.Errorf("expect no requeue, but got %v", requeueResult.RequeueTime)
+	}
+}
+
+func TestMaintenanceWindowInvalid(t *testing.T) {
+	MaintenanceClock = testingclock.NewFakeClock(fakeTime)
+
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithAnnotation(MaintenanceWindowsAnnotation, "invalid").Build(),
+	}
+	p := New(testinghelpers.NewFakePluginHandle(t, nil, clusters[0]), nil)
+
+	result, status := p.Filter(context.TODO(), testinghelpers.NewPlacement("test", "test").Build(), clusters)
+	if status.Code() != framework.Warning {
+		t.Errorf("expect warning, but got %v", status.Code())
+	}
+	if len(result.Filtered) != 1 {
+		t.Errorf("expect cluster with invalid windows not filtered")
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,120 @@
+package maintenancewindow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// maxMergedWindows is the max number of the overlapping or adjacent windows merged into one
+	// maintenance period. The maintenance is evaluated again at the end of the merged windows.
+	maxMergedWindows = 100
+	// maxActiveStarts is the max number of the starts of a window within its duration.
+	maxActiveStarts = 10000
+)
+
+// Window is a maintenance window starting at each activation of a cron schedule and lasting for
+// the duration.
+type Window struct {
+	schedule *schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// ParseWindows parses the maintenance windows separated by ";" or new lines. Each window is a cron
+// schedule of the window start followed by the window duration, with an optional time zone prefix
+// (UTC by default), e.g.
+//
+//	TZ=Europe/Berlin 0 2 * * sat 4h; 30 1 1 * * 90m
+func ParseWindows(value string) ([]Window, error) {
+	var windows []Window
+	for _, spec := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '\n' }) {
+		if len(strings.TrimSpace(spec)) == 0 {
+			continue
+		}
+		window, err := parseWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func parseWindow(spec string) (Window, error) {
+	fields := strings.Fields(spec)
+	location := time.UTC
+	if len(fields) > 0 && (strings.HasPrefix(fields[0], "TZ=") || strings.HasPrefix(fields[0], "CRON_TZ=")) {
+		var err error
+		location, err = time.LoadLocation(fields[0][strings.Index(fields[0], "=")+1:])
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid time zone in maintenance window %q: %v", spec, err)
+		}
+		fields = fields[1:]
+	}
+	if len(fields) < 2 {
+		return Window{}, fmt.Errorf("invalid maintenance window %q: a cron schedule and a duration are required", spec)
+	}
+
+	duration, err := time.ParseDuration(fields[len(fields)-1])
+	if err != nil || duration <= 0 {
+		return Window{}, fmt.Errorf("invalid duration in maintenance window %q", spec)
+	}
+	s, err := parseSchedule(strings.Join(fields[:len(fields)-1], " "))
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid maintenance window %q: %v", spec, err)
+	}
+
+	return Window{schedule: s, duration: duration, location: location}, nil
+}
+
+// activeEnd returns the end of the window if the window is active at t, which is the end of the
+// last start of the window before t.
+func (w Window) activeEnd(t time.Time) (time.Time, bool) {
+	start := w.schedule.next(t.In(w.location).Add(-w.duration))
+	if start.IsZero() || start.After(t) {
+		return time.Time{}, false
+	}
+
+	for i := 0; i < maxActiveStarts; i++ {
+		next := w.schedule.next(start)
+		if next.IsZero() || next.After(t) {
+			break
+		}
+		start = next
+	}
+	return start.Add(w.duration), true
+}
+
+// InMaintenance returns if any of the windows is active at t. If active, the end of the maintenance
+// is returned, otherwise the start of the next window. A zero time is returned if there is no next
+// window.
+func InMaintenance(windows []Window, t time.Time) (bool, time.Time) {
+	var end time.Time
+	at := t
+	for i := 0; i < maxMergedWindows; i++ {
+		latest := time.Time{}
+		for _, w := range windows {
+			if e, ok := w.activeEnd(at); ok && e.After(latest) {
+				latest = e
+			}
+		}
+		if !latest.After(at) {
+			break
+		}
+		end, at = latest, latest
+	}
+	if !end.IsZero() {
+		return true, end
+	}
+
+	var next time.Time
+	for _, w := range windows {
+		n := w.schedule.next(t.In(w.location))
+		if !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return false, next
+}
@@ -0,0 +1,143 @@
+package maintenancewindow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// Saturday
+	now := time.Date(2024, time.June, 1, 10, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		spec     string
+		expected time.Time
+	}{
+		{name: "every minute", spec: "* * * * *", expected: time.Date(2024, time.June, 1, 10, 31, 0, 0, time.UTC)},
+		{name: "daily", spec: "@daily", expected: time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "hourly step", spec: "0 */4 * * *", expected: time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)},
+		{name: "weekday names", spec: "0 2 * * mon-fri", expected: time.Date(2024, time.June, 3, 2, 0, 0, 0, time.UTC)},
+		{name: "sunday as 7", spec: "0 2 * * 7", expected: time.Date(2024, time.June, 2, 2, 0, 0, 0, time.UTC)},
+		{name: "list", spec: "15,45 10 * * *", expected: time.Date(2024, time.June, 1, 10, 45, 0, 0, time.UTC)},
+		{name: "month name", spec: "0 0 1 dec *", expected: time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "next year", spec: "0 0 1 1 *", expected: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month or day of week", spec: "0 0 15 * sun", expected: time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "leap day", spec: "0 0 29 2 *", expected: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "never", spec: "0 0 30 2 *", expected: time.Time{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := parseSchedule(c.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if next := s.next(now); !next.Equal(c.expected) {
+				t.Errorf("expect next %v, but got %v", c.expected, next)
+			}
+		})
+	}
+}
+
+func TestParseWindows(t *testing.T) {
+	windows, err := ParseWindows("0 2 * * sat 4h; TZ=UTC 30 1 1 * * 90m\n@daily 1h")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(windows) != 3 {
+		t.Errorf("expect 3 windows, but got %d", len(windows))
+	}
+
+	for _, value := range []string{
+		"0 2 * * sat",
+		"0 2 * * sat -1h",
+		"0 2 * * 4h",
+		"60 2 * * * 4h",
+		"0 2 * * 8 4h",
+		"0 5-2 * * * 4h",
+		"*/0 * * * * 4h",
+		"TZ=Invalid/Zone 0 2 * * * 4h",
+	} {
+		if _, err := ParseWindows(value); err == nil {
+			t.Errorf("expect error for %q", value)
+		}
+	}
+}
+
+func TestInMaintenance(t *testing.T) {
+	cases := []struct {
+		name             string
+		windows          string
+		now              time.Time
+		expectedActive   bool
+		expectedBoundary time.Time
+	}{
+		{
+			name:             "before window",
+			windows:          "0 2 * * * 4h",
+			now:              time.Date(2024, time.June, 1, 1, 0, 0, 0, time.UTC),
+			expectedBoundary: time.Date(2024, time.June, 1, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "at window start",
+			windows:          "0 2 * * * 4h",
+			now:              time.Date(2024, time.June, 1, 2, 0, 0, 0, time.UTC),
+			expectedActive:   true,
+			expectedBoundary: time.Date(2024, time.June, 1, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "in window",
+			windows:          "0 2 * * * 4h",
+			now:              time.Date(2024, time.June, 1, 5, 59, 0, 0, time.UTC),
+			expectedActive:   true,
+			expectedBoundary: time.Date(2024, time.June, 1, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "at window end",
+			windows:          "0 2 * * * 4h",
+			now:              time.Date(2024, time.June, 1, 6, 0, 0, 0, time.UTC),
+			expectedBoundary: time.Date(2024, time.June, 2, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "overlapping windows are merged",
+			windows:          "0 2 * * * 4h; 0 5 * * * 2h",
+			now:              time.Date(2024, time.June, 1, 3, 0, 0, 0, time.UTC),
+			expectedActive:   true,
+			expectedBoundary: time.Date(2024, time.June, 1, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "adjacent activations are merged",
+			windows:          "0 * * * * 1h",
+			now:              time.Date(2024, time.June, 1, 3, 0, 0, 0, time.UTC),
+			expectedActive:   true,
+			expectedBoundary: time.Date(2024, time.June, 1, 3, 0, 0, 0, time.UTC).Add(maxMergedWindows * time.Hour),
+		},
+		{
+			name:             "window across midnight in time zone",
+			windows:          "TZ=Asia/Shanghai 0 23 * * * 2h",
+			now:              time.Date(2024, time.June, 1, 16, 0, 0, 0, time.UTC),
+			expectedActive:   true,
+			expectedBoundary: time.Date(2024, time.June, 1, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "no window",
+			windows: "",
+			now:     time.Date(2024, time.June, 1, 3, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			windows, err := ParseWindows(c.windows)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			active, boundary := InMaintenance(windows, c.now)
+			if active != c.expectedActive {
+				t.Errorf("expect active %v, but got %v", c.expectedActive, active)
+			}
+			if !boundary.Equal(c.expectedBoundary) {
+				t.Errorf("expect boundary %v, but got %v", c.expectedBoundary, boundary)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package resource
+
+import (
+	"context"
+	"reflect"
+
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const fitDescription = `
+	ResourceFit filters out the clusters without enough headroom for the resource request declared
+	by the placement. The headroom of a cluster is the allocatable resource in the status of the
+	ManagedCluster, minus the resource already requested if it is reported by the optional
+	AddOnPlacementScore resource-usage.
+	`
+
+var _ plugins.Filter = &ResourceFit{}
+
+type ResourceFit struct {
+	handle plugins.Handle
+}
+
+func NewResourceFit(handle plugins.Handle) *ResourceFit {
+	return &ResourceFit{
+		handle: handle,
+	}
+}
+
+func (r *ResourceFit) Name() string {
+	return reflect.TypeOf(*r).Name()
+}
+
+func (r *ResourceFit) Description() string {
+	return fitDescription
+}
+
+func (r *ResourceFit) Filter(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginFilterResult, *framework.Status) {
+	request, err := GetResourceRequest(placement)
+	if err != nil {
+		return plugins.PluginFilterResult{}, framework.NewStatus(r.Name(), framework.Misconfigured, err.Error())
+	}
+	if len(request) == 0 {
+		return plugins.PluginFilterResult{
+			Filtered: clusters,
+		}, framework.NewStatus(r.Name(), framework.Success, "")
+	}
+
+	matched := []*clusterapiv1.ManagedCluster{}
+	for _, cluster := range clusters {
+		fit, err := r.fit(ctx, cluster, request)
+		if err != nil {
+			return plugins.PluginFilterResult{}, framework.NewStatus(r.Name(), framework.Error, err.Error())
+		}
+		if fit {
+			matched = append(matched, cluster)
+		}
+	}
+
+	return plugins.PluginFilterResult{
+		Filtered: matched,
+	}, framework.NewStatus(r.Name(), framework.Success, "")
+}
+
+func (r *ResourceFit) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(r.Name(), framework.Success, "")
+}
+
+// fit returns true if the cluster has enough headroom for every requested resource. The cluster
+// without the allocatable of a requested resource does not fit.
+func (r *ResourceFit) fit(ctx context.Context, cluster *clusterapiv1.ManagedCluster,
+	request map[clusterapiv1.ResourceName]apiresource.Quantity) (bool, error) {
+	for resourceName, quantity := range request {
+		v, ok := cluster.Status.Allocatable[resourceName]
+		if !ok {
+			return false, nil
+		}
+
+		requested, err := getClusterRequestedResource(ctx, r.handle, cluster.Name, resourceName)
+		if err != nil {
+			return false, err
+		}
+
+		if v.AsApproximateFloat64()-requested < quantity.AsApproximateFloat64() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
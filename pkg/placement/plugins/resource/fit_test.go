@@ -0,0 +1,92 @@
+package resource
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestResourceFit(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").
+			WithResource(clusterapiv1.ResourceCPU, "4", "4").WithResource(clusterapiv1.ResourceMemory, "8Gi", "8Gi").Build(),
+		testinghelpers.NewManagedCluster("cluster2").
+			WithResource(clusterapiv1.ResourceCPU, "4", "4").WithResource(clusterapiv1.ResourceMemory, "8Gi", "8Gi").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithResource(clusterapiv1.ResourceCPU, "1", "1").Build(),
+	}
+	usages := []runtime.Object{
+		testinghelpers.NewAddOnPlacementScore("cluster2", ResourceUsageName).
+			WithScore(CPURequestedScoreName, 3000).WithScore(MemoryRequestedScoreName, 1024).Build(),
+	}
+
+	cases := []struct {
+		name             string
+		placement        *clusterapiv1beta1.Placement
+		expectedClusters []string
+		expectedCode     framework.Code
+	}{
+		{
+			name:             "no request",
+			placement:        testinghelpers.NewPlacement("test", "test").Build(),
+			expectedClusters: []string{"cluster1", "cluster2", "cluster3"},
+			expectedCode:     framework.Success,
+		},
+		{
+			name: "cpu request",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ResourceRequestAnnotation: `{"cpu":"1500m"}`,
+			}).Build(),
+			expectedClusters: []string{"cluster1"},
+			expectedCode:     framework.Success,
+		},
+		{
+			name: "memory request",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ResourceRequestAnnotation: `{"memory":"7Gi"}`,
+			}).Build(),
+			expectedClusters: []string{"cluster1", "cluster2"},
+			expectedCode:     framework.Success,
+		},
+		{
+			name: "cpu and memory request",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ResourceRequestAnnotation: `{"cpu":"1","memory":"8Gi"}`,
+			}).Build(),
+			expectedClusters: []string{"cluster1"},
+			expectedCode:     framework.Success,
+		},
+		{
+			name: "invalid request",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ResourceRequestAnnotation: `cpu=1`,
+			}).Build(),
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fit := NewResourceFit(testinghelpers.NewFakePluginHandle(t, nil, usages...))
+			result, status := fit.Filter(context.TODO(), c.placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Errorf("Expect status code %v, but got %v: %s", c.expectedCode, status.Code(), status.Message())
+			}
+
+			var names []string
+			for _, cluster := range result.Filtered {
+				names = append(names, cluster.Name)
+			}
+			if !reflect.DeepEqual(names, c.expectedClusters) {
+				t.Errorf("Expect clusters %v, but got %v", c.expectedClusters, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// ResourceRequestAnnotation declares the resource request of the workload selected by the placement.
+	// The value is a json of the resource quantities, e.g. {"cpu":"2","memory":"4Gi"}. The clusters
+	// without enough headroom are filtered out.
+	ResourceRequestAnnotation = "cluster.open-cluster-management.io/experimental-resource-request"
+
+	// ResourceUsageName is the name of the optional AddOnPlacementScore in the cluster namespace which
+	// reports the resource already requested on the managed cluster, e.g. by an addon summing up the
+	// resource requests of the pods. Without it, the plugins rely on the capacity and allocatable in
+	// the status of the ManagedCluster only.
+	ResourceUsageName = "resource-usage"
+	// CPURequestedScoreName is the score name of the requested cpu in millicores.
+	CPURequestedScoreName = "cpuRequested"
+	// MemoryRequestedScoreName is the score name of the requested memory in MiB.
+	MemoryRequestedScoreName = "memoryRequested"
+)
+
+var ResourceClock = clock.Clock(clock.RealClock{})
+
+// GetResourceRequest parses the resource request from the annotation of the placement.
+func GetResourceRequest(placement *clusterapiv1beta1.Placement) (map[clusterapiv1.ResourceName]apiresource.Quantity, error) {
+	value, ok := placement.Annotations[ResourceRequestAnnotation]
+	if !ok || len(value) == 0 {
+		return nil, nil
+	}
+
+	request := map[clusterapiv1.ResourceName]apiresource.Quantity{}
+	if err := json.Unmarshal([]byte(value), &request); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s: %v", ResourceRequestAnnotation, err)
+	}
+	return request, nil
+}
+
+// getClusterRequestedResource returns the requested resource on the cluster reported by the
+// AddOnPlacementScore. The value is in the same unit as the allocatable of the cluster, cores for
+// cpu and bytes for memory. 0 is returned if the requested resource is not reported or expired.
+func getClusterRequestedResource(ctx context.Context, handle plugins.Handle,
+	clusterName string, resourceName clusterapiv1.ResourceName) (float64, error) {
+	var scoreName string
+	var unit float64
+	switch resourceName {
+	case clusterapiv1.ResourceCPU:
+		scoreName, unit = CPURequestedScoreName, 0.001
+	case clusterapiv1.ResourceMemory:
+		scoreName, unit = MemoryRequestedScoreName, 1024*1024
+	default:
+		return 0, nil
+	}
+
+	usage, err := handle.ScoreLister().AddOnPlacementScores(clusterName).Get(ResourceUsageName)
+	switch {
+	case errors.IsNotFound(err):
+		return 0, nil
+	case err != nil:
+		return 0, err
+	}
+
+	if usage.Status.ValidUntil != nil && ResourceClock.Now().After(usage.Status.ValidUntil.Time) {
+		klog.FromContext(ctx).V(4).Info("Resource usage expired", "cluster", clusterName)
+		return 0, nil
+	}
+
+	for _, score := range usage.Status.Scores {
+		if score.Name == scoreName {
+			return float64(score.Value) * unit, nil
+		}
+	}
+	return 0, nil
+}
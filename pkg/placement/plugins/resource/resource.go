@@ -3,8 +3,10 @@ package resource
 import (
 	"context"
 	"fmt"
+	"math"
 	"regexp"
 	"sort"
+	"strings"
 
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
@@ -20,7 +22,17 @@ const (
 	decisions based on the resource allocatable of managed clusters.
 	The clusters that has the most allocatable are given the highest score,
 	while the least is given the lowest score.
+	ResourceMostAllocatedCPU/Memory and ResourceLeastAllocatedCPU/Memory prioritizer makes the
+	scheduling decisions based on the ratio of the used resource to the capacity, including the
+	resource request of the placement. The used resource is the capacity which is not allocatable,
+	plus the resource requested on the cluster if it is reported by the AddOnPlacementScore
+	resource-usage. The MostAllocated mode packs the workloads onto the clusters with the least
+	headroom, while the LeastAllocated mode spreads them to the clusters with the most headroom.
 	`
+
+	algorithmAllocatable    = "Allocatable"
+	algorithmMostAllocated  = "MostAllocated"
+	algorithmLeastAllocated = "LeastAllocated"
 )
 
 var _ plugins.Prioritizer = &ResourcePrioritizer{}
@@ -62,11 +74,12 @@ func (r *ResourcePrioritizerBuilder) Build() *ResourcePrioritizer {
 }
 
 // parese prioritizerName to algorithm and resource.
-// For example, prioritizerName ResourceAllocatableCPU will return Allocatable, CPU.
+// For example, prioritizerName ResourceAllocatableCPU will return Allocatable, CPU, and
+// prioritizerName ResourceMostAllocatedMemory will return MostAllocated, Memory.
 func parsePrioritizerName(prioritizerName string) (algorithm string, resource clusterapiv1.ResourceName) {
 	s := regexp.MustCompile("[A-Z]+[a-z]*").FindAllString(prioritizerName, -1)
-	if len(s) == 3 {
-		return s[1], resourceMap[s[2]]
+	if len(s) >= 3 {
+		return strings.Join(s[1:len(s)-1], ""), resourceMap[s[len(s)-1]]
 	}
 	return "", ""
 }
@@ -82,8 +95,11 @@ func (r *ResourcePrioritizer) Description() string {
 func (r *ResourcePrioritizer) Score(ctx context.Context, placement *clusterapiv1beta1.Placement,
 	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
 	status := framework.NewStatus(r.Name(), framework.Success, "")
-	if r.algorithm == "Allocatable" {
+	switch r.algorithm {
+	case algorithmAllocatable:
 		return mostResourceAllocatableScores(r.resource, clusters), status
+	case algorithmMostAllocated, algorithmLeastAllocated:
+		return r.allocatedScores(ctx, placement, clusters)
 	}
 	return plugins.PluginScoreResult{}, status
 }
@@ -127,6 +143,57 @@ func mostResourceAllocatableScores(resourceName clusterapiv1.ResourceName, clust
 	}
 }
 
+// Calculate clusters scores based on the ratio of the used resource, including the resource request
+// of the placement, to the capacity. The used resource is the capacity minus the allocatable, which
+// is reserved by the system or held by the unschedulable nodes, plus the optional requested resource
+// reported by the AddOnPlacementScore.
+// In MostAllocated mode, the clusters with the highest ratio are given the highest score, and in
+// LeastAllocated mode, the clusters with the lowest ratio are given the highest score.
+// The score range is from -100 to 100. The clusters without the allocatable or capacity are not scored.
+func (r *ResourcePrioritizer) allocatedScores(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
+	scores := map[string]int64{}
+
+	request, err := GetResourceRequest(placement)
+	if err != nil {
+		return plugins.PluginScoreResult{}, framework.NewStatus(r.Name(), framework.Misconfigured, err.Error())
+	}
+	var placementRequest float64
+	if q, ok := request[r.resource]; ok {
+		placementRequest = q.AsApproximateFloat64()
+	}
+
+	for _, cluster := range clusters {
+		allocatable, capacity, err := getClusterResource(cluster, r.resource)
+		if err != nil {
+			continue
+		}
+
+		requested, err := getClusterRequestedResource(ctx, r.handle, cluster.Name, r.resource)
+		if err != nil {
+			return plugins.PluginScoreResult{}, framework.NewStatus(r.Name(), framework.Error, err.Error())
+		}
+
+		// ratio = (resource_x_capacity - resource_x_allocatable + resource_x_requested + placement_request_x) / resource_x_capacity,
+		// limited to [0, 1]
+		ratio := 1.0
+		if capacity > 0 {
+			ratio = math.Min(math.Max((capacity-allocatable+requested+placementRequest)/capacity, 0), 1)
+		}
+
+		// score = (ratio - 0.5) * 2 * 100 for MostAllocated, and the opposite for LeastAllocated
+		score := int64((ratio - 0.5) * 2.0 * 100.0)
+		if r.algorithm == algorithmLeastAllocated {
+			score = -score
+		}
+		scores[cluster.Name] = score
+	}
+
+	return plugins.PluginScoreResult{
+		Scores: scores,
+	}, framework.NewStatus(r.Name(), framework.Success, "")
+}
+
 // Go through one cluster resources and return the allocatable and capacity of the resourceName.
 func getClusterResource(cluster *clusterapiv1.ManagedCluster, resourceName clusterapiv1.ResourceName) (allocatable, capacity float64, err error) {
 	if v, exist := cluster.Status.Allocatable[resourceName]; exist {
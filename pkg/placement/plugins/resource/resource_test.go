@@ -10,6 +10,7 @@ import (
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
 )
 
@@ -143,3 +144,103 @@ func TestScoreClusterWithResource(t *testing.T) {
 		})
 	}
 }
+
+func TestScoreClusterWithAllocated(t *testing.T) {
+	usage := func(clusterName string, cpu, memory int32) runtime.Object {
+		return testinghelpers.NewAddOnPlacementScore(clusterName, ResourceUsageName).
+			WithScore(CPURequestedScoreName, cpu).WithScore(MemoryRequestedScoreName, memory).Build()
+	}
+
+	cases := []struct {
+		name           string
+		prioritizer    string
+		placement      *clusterapiv1beta1.Placement
+		clusters       []*clusterapiv1.ManagedCluster
+		usages         []runtime.Object
+		expectedScores map[string]int64
+		expectedCode   framework.Code
+	}{
+		{
+			name:        "ResourceMostAllocatedCPU",
+			prioritizer: "ResourceMostAllocatedCPU",
+			placement:   testinghelpers.NewPlacement("test", "test").Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithResource(clusterapiv1.ResourceCPU, "10", "10").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithResource(clusterapiv1.ResourceCPU, "10", "10").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithResource(clusterapiv1.ResourceCPU, "10", "10").Build(),
+			},
+			usages:         []runtime.Object{usage("cluster1", 10000, 0), usage("cluster2", 5000, 0)},
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": 0, "cluster3": -100},
+			expectedCode:   framework.Success,
+		},
+		{
+			name:        "ResourceLeastAllocatedCPU",
+			prioritizer: "ResourceLeastAllocatedCPU",
+			placement:   testinghelpers.NewPlacement("test", "test").Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithResource(clusterapiv1.ResourceCPU, "10", "10").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithResource(clusterapiv1.ResourceCPU, "10", "10").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithResource(clusterapiv1.ResourceCPU, "10", "10").Build(),
+			},
+			usages:         []runtime.Object{usage("cluster1", 10000, 0), usage("cluster2", 5000, 0)},
+			expectedScores: map[string]int64{"cluster1": -100, "cluster2": 0, "cluster3": 100},
+			expectedCode:   framework.Success,
+		},
+		{
+			name:        "ResourceLeastAllocatedCPU without resource usage",
+			prioritizer: "ResourceLeastAllocatedCPU",
+			placement:   testinghelpers.NewPlacement("test", "test").Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithResource(clusterapiv1.ResourceCPU, "2", "10").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithResource(clusterapiv1.ResourceCPU, "5", "10").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithResource(clusterapiv1.ResourceCPU, "10", "10").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": -60, "cluster2": 0, "cluster3": 100},
+			expectedCode:   framework.Success,
+		},
+		{
+			name:        "ResourceMostAllocatedMemory with placement request",
+			prioritizer: "ResourceMostAllocatedMemory",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ResourceRequestAnnotation: `{"memory":"1Gi"}`,
+			}).Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithResource(clusterapiv1.ResourceMemory, "4Gi", "4Gi").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithResource(clusterapiv1.ResourceMemory, "2Gi", "4Gi").Build(),
+				testinghelpers.NewManagedCluster("cluster3").Build(),
+			},
+			usages:         []runtime.Object{usage("cluster1", 0, 1024)},
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 50},
+			expectedCode:   framework.Success,
+		},
+		{
+			name:        "invalid placement request",
+			prioritizer: "ResourceLeastAllocatedMemory",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ResourceRequestAnnotation: `{"memory":"abc"}`,
+			}).Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithResource(clusterapiv1.ResourceMemory, "4Gi", "4Gi").Build(),
+			},
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resource := NewResourcePrioritizerBuilder(testinghelpers.NewFakePluginHandle(t, nil, c.usages...)).
+				WithPrioritizerName(c.prioritizer).Build()
+
+			scoreResult, status := resource.Score(context.TODO(), c.placement, c.clusters)
+			if status.Code() != c.expectedCode {
+				t.Errorf("Expect status code %v, but got %v: %s", c.expectedCode, status.Code(), status.Message())
+			}
+			if c.expectedCode != framework.Success {
+				return
+			}
+			if !apiequality.Semantic.DeepEqual(scoreResult.Scores, c.expectedScores) {
+				t.Errorf("Expect score %v, but got %v", c.expectedScores, scoreResult.Scores)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package spread
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/helpers"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	placementLabel = clusterapiv1beta1.PlacementLabel
+	description    = `
+	Spread prioritizer spreads the decisions evenly among the topologies defined by the
+	spreadConstraints of the placement. The clusters in each topology are ranked, the existing
+	decisions first and then by name, and every MaxSkew clusters of a topology form a tier. The
+	clusters in the lower tier are given the higher score, so the same number of clusters are
+	selected from each topology before the next tier is considered. The clusters without the
+	topology key are given the lowest score. For the constraints with DoNotSchedule, the clusters
+	are selected only while the skew between the topologies is within MaxSkew.
+	`
+)
+
+var _ plugins.Prioritizer = &Spread{}
+
+type Spread struct {
+	handle plugins.Handle
+}
+
+func New(handle plugins.Handle) *Spread {
+	return &Spread{
+		handle: handle,
+	}
+}
+
+func (s *Spread) Name() string {
+	return reflect.TypeOf(*s).Name()
+}
+
+func (s *Spread) Description() string {
+	return description
+}
+
+func (s *Spread) Score(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
+	scores := map[string]int64{}
+	constraints := placement.Spec.SpreadPolicy.SpreadConstraints
+	if len(constraints) == 0 || len(clusters) == 0 {
+		for _, cluster := range clusters {
+			scores[cluster.Name] = 0
+		}
+		return plugins.PluginScoreResult{Scores: scores}, framework.NewStatus(s.Name(), framework.Success, "")
+	}
+
+	existingDecisions, err := s.getExistingDecisions(placement)
+	if err != nil {
+		return plugins.PluginScoreResult{}, framework.NewStatus(s.Name(), framework.Error, err.Error())
+	}
+
+	// rank the clusters in each topology, the existing decisions first to keep the decisions stable.
+	ordered := make([]*clusterapiv1.ManagedCluster, len(clusters))
+	copy(ordered, clusters)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		iExisting, jExisting := existingDecisions.Has(ordered[i].Name), existingDecisions.Has(ordered[j].Name)
+		if iExisting != jExisting {
+			return iExisting
+		}
+		return ordered[i].Name < ordered[j].Name
+	})
+
+	// calculate the tier of each cluster for every constraint.
+	tiers := map[string][]int{}
+	for _, constraint := range constraints {
+		maxSkew := int(constraint.MaxSkew)
+		if maxSkew < 1 {
+			maxSkew = 1
+		}
+
+		counts := map[string]int{}
+		for _, cluster := range ordered {
+			topology, ok := getTopology(cluster, constraint)
+			if !ok {
+				tiers[cluster.Name] = append(tiers[cluster.Name], math.MaxInt32)
+				continue
+			}
+			tiers[cluster.Name] = append(tiers[cluster.Name], counts[topology]/maxSkew)
+			counts[topology]++
+		}
+	}
+
+	// sort the clusters by tiers, the constraint with smaller index is considered first.
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return compareTiers(tiers[ordered[i].Name], tiers[ordered[j].Name]) < 0
+	})
+
+	// give the same rank to the clusters with the same tiers.
+	ranks := map[string]int{}
+	rank := 0
+	for i, cluster := range ordered {
+		if i > 0 && compareTiers(tiers[ordered[i-1].Name], tiers[cluster.Name]) != 0 {
+			rank++
+		}
+		ranks[cluster.Name] = rank
+	}
+
+	// normalize the rank to a score between 100 and -100.
+	for name, r := range ranks {
+		if rank == 0 {
+			scores[name] = plugins.MaxClusterScore
+			continue
+		}
+		scores[name] = plugins.MaxClusterScore - int64(r)*(plugins.MaxClusterScore-plugins.MinClusterScore)/int64(rank)
+	}
+
+	return plugins.PluginScoreResult{
+		Scores: scores,
+	}, framework.NewStatus(s.Name(), framework.Success, "")
+}
+
+func (s *Spread) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(s.Name(), framework.Success, "")
+}
+
+// Select selects at most n clusters in the order of the sorted clusters. For each spreadConstraint
+// with DoNotSchedule, a cluster is selected only if the number of the selected clusters in its
+// topology exceeds the least selected topology by no more than MaxSkew after it is selected, and the
+// clusters without the topology key are not selected. The skipped clusters are reconsidered once more
+// clusters are selected in the other topologies, so fewer than n clusters are selected only if the
+// constraints cannot be satisfied.
+func Select(placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster, n int) []*clusterapiv1.ManagedCluster {
+	var constraints []clusterapiv1beta1.SpreadConstraintsTerm
+	for _, constraint := range placement.Spec.SpreadPolicy.SpreadConstraints {
+		if constraint.WhenUnsatisfiable == clusterapiv1beta1.DoNotSchedule {
+			constraints = append(constraints, constraint)
+		}
+	}
+	if len(constraints) == 0 {
+		if n < len(clusters) {
+			return clusters[:n]
+		}
+		return clusters
+	}
+
+	// the topologies of the candidate clusters are the domains of each constraint.
+	counts := make([]map[string]int, len(constraints))
+	var candidates []*clusterapiv1.ManagedCluster
+	for i := range constraints {
+		counts[i] = map[string]int{}
+	}
+	for _, cluster := range clusters {
+		topologies, ok := getTopologies(cluster, constraints)
+		if !ok {
+			continue
+		}
+		for i, topology := range topologies {
+			counts[i][topology] = 0
+		}
+		candidates = append(candidates, cluster)
+	}
+
+	var selected []*clusterapiv1.ManagedCluster
+	selectedNames := sets.New[string]()
+	for progress := true; progress && len(selected) < n; {
+		progress = false
+		for _, cluster := range candidates {
+			if len(selected) >= n {
+				break
+			}
+			if selectedNames.Has(cluster.Name) {
+				continue
+			}
+			topologies, _ := getTopologies(cluster, constraints)
+			if !withinMaxSkew(constraints, counts, topologies) {
+				continue
+			}
+			for i, topology := range topologies {
+				counts[i][topology]++
+			}
+			selected = append(selected, cluster)
+			selectedNames.Insert(cluster.Name)
+			progress = true
+		}
+	}
+
+	// keep the order of the sorted clusters.
+	result := make([]*clusterapiv1.ManagedCluster, 0, len(selected))
+	for _, cluster := range clusters {
+		if selectedNames.Has(cluster.Name) {
+			result = append(result, cluster)
+		}
+	}
+	return result
+}
+
+// withinMaxSkew returns if the skew of every constraint is within MaxSkew after a cluster in the
+// topologies is selected.
+func withinMaxSkew(constraints []clusterapiv1beta1.SpreadConstraintsTerm, counts []map[string]int, topologies []string) bool {
+	for i, constraint := range constraints {
+		maxSkew := int(constraint.MaxSkew)
+		if maxSkew < 1 {
+			maxSkew = 1
+		}
+		minCount := math.MaxInt32
+		for _, count := range counts[i] {
+			minCount = min(minCount, count)
+		}
+		if counts[i][topologies[i]]+1-minCount > maxSkew {
+			return false
+		}
+	}
+	return true
+}
+
+// getTopologies returns the topology of the cluster for each constraint, it returns false if the cluster
+// does not have the topology key of any constraint.
+func getTopologies(cluster *clusterapiv1.ManagedCluster, constraints []clusterapiv1beta1.SpreadConstraintsTerm) ([]string, bool) {
+	topologies := make([]string, 0, len(constraints))
+	for _, constraint := range constraints {
+		topology, ok := getTopology(cluster, constraint)
+		if !ok {
+			return nil, false
+		}
+		topologies = append(topologies, topology)
+	}
+	return topologies, true
+}
+
+// getExistingDecisions returns the cluster names in the decisions of the placement.
+func (s *Spread) getExistingDecisions(placement *clusterapiv1beta1.Placement) (sets.Set[string], error) {
+	existingDecisions := sets.New[string]()
+	requirement, err := labels.NewRequirement(placementLabel, selection.Equals, []string{placement.Name})
+	if err != nil {
+		return existingDecisions, err
+	}
+
+	labelSelector := labels.NewSelector().Add(*requirement)
+	decisions, err := s.handle.DecisionLister().PlacementDecisions(placement.Namespace).List(labelSelector)
+	if err != nil {
+		return existingDecisions, err
+	}
+
+	for _, decision := range decisions {
+		for _, d := range decision.Status.Decisions {
+			existingDecisions.Insert(d.ClusterName)
+		}
+	}
+	return existingDecisions, nil
+}
+
+// getTopology returns the value of the topology key of the cluster.
+func getTopology(cluster *clusterapiv1.ManagedCluster, constraint clusterapiv1beta1.SpreadConstraintsTerm) (string, bool) {
+	switch constraint.TopologyKeyType {
+	case clusterapiv1beta1.TopologyKeyTypeLabel:
+		value, ok := cluster.Labels[constraint.TopologyKey]
+		return value, ok
+	case clusterapiv1beta1.TopologyKeyTypeClaim:
+		value, ok := helpers.GetClusterClaims(cluster)[constraint.TopologyKey]
+		return value, ok
+	}
+	return "", false
+}
+
+// compareTiers compares two tier slices in lexicographic order.
+func compareTiers(x, y []int) int {
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if x[i] != y[i] {
+			if x[i] < y[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(x) - len(y)
+}
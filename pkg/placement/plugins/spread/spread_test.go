@@ -0,0 +1,183 @@
+package spread
+
+import (
+	"context"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestScoreClusterWithSpread(t *testing.T) {
+	cases := []struct {
+		name              string
+		placement         *clusterapiv1beta1.Placement
+		clusters          []*clusterapiv1.ManagedCluster
+		existingDecisions []runtime.Object
+		expectedScores    map[string]int64
+	}{
+		{
+			name:      "no spread constraints",
+			placement: testinghelpers.NewPlacement("test", "test").Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithLabel("region", "east").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel("region", "east").Build(),
+			},
+			existingDecisions: []runtime.Object{},
+			expectedScores:    map[string]int64{"cluster1": 0, "cluster2": 0},
+		},
+		{
+			name: "spread by label",
+			placement: testinghelpers.NewPlacement("test", "test").
+				AddSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 1).Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithLabel("region", "east").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel("region", "east").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithLabel("region", "east").Build(),
+				testinghelpers.NewManagedCluster("cluster4").WithLabel("region", "west").Build(),
+				testinghelpers.NewManagedCluster("cluster5").WithLabel("region", "west").Build(),
+			},
+			existingDecisions: []runtime.Object{},
+			expectedScores: map[string]int64{
+				"cluster1": 100, "cluster4": 100, "cluster2": 0, "cluster5": 0, "cluster3": -100},
+		},
+		{
+			name: "spread by claim with max skew",
+			placement: testinghelpers.NewPlacement("test", "test").
+				AddSpreadConstraint("zone", clusterapiv1beta1.TopologyKeyTypeClaim, 2).Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithClaim("zone", "a").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithClaim("zone", "a").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithClaim("zone", "a").Build(),
+				testinghelpers.NewManagedCluster("cluster4").WithClaim("zone", "b").Build(),
+			},
+			existingDecisions: []runtime.Object{},
+			expectedScores:    map[string]int64{"cluster1": 100, "cluster2": 100, "cluster4": 100, "cluster3": -100},
+		},
+		{
+			name: "clusters without topology key",
+			placement: testinghelpers.NewPlacement("test", "test").
+				AddSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 1).Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithLabel("region", "east").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel("region", "east").Build(),
+				testinghelpers.NewManagedCluster("cluster3").Build(),
+			},
+			existingDecisions: []runtime.Object{},
+			expectedScores:    map[string]int64{"cluster1": 100, "cluster2": 0, "cluster3": -100},
+		},
+		{
+			name: "existing decisions are ranked first",
+			placement: testinghelpers.NewPlacement("test", "test").
+				AddSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 1).Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithLabel("region", "east").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel("region", "east").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithLabel("region", "west").Build(),
+			},
+			existingDecisions: []runtime.Object{
+				testinghelpers.NewPlacementDecision("test", "test1").WithLabel(placementLabel, "test").WithDecisions("cluster2").Build(),
+			},
+			expectedScores: map[string]int64{"cluster2": 100, "cluster3": 100, "cluster1": -100},
+		},
+		{
+			name: "multiple constraints",
+			placement: testinghelpers.NewPlacement("test", "test").
+				AddSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 1).
+				AddSpreadConstraint("zone", clusterapiv1beta1.TopologyKeyTypeLabel, 1).Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithLabel("region", "east").WithLabel("zone", "a").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel("region", "east").WithLabel("zone", "b").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithLabel("region", "west").WithLabel("zone", "a").Build(),
+			},
+			existingDecisions: []runtime.Object{},
+			expectedScores:    map[string]int64{"cluster1": 100, "cluster3": 0, "cluster2": -100},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			spread := &Spread{
+				handle: testinghelpers.NewFakePluginHandle(t, nil, c.existingDecisions...),
+			}
+
+			scoreResult, status := spread.Score(context.TODO(), c.placement, c.clusters)
+			if status.IsError() {
+				t.Errorf("unexpected error: %v", status.AsError())
+			}
+			if !apiequality.Semantic.DeepEqual(scoreResult.Scores, c.expectedScores) {
+				t.Errorf("expected %v, but got %v", c.expectedScores, scoreResult.Scores)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	doNotSchedule := func(placement *clusterapiv1beta1.Placement) *clusterapiv1beta1.Placement {
+		for i := range placement.Spec.SpreadPolicy.SpreadConstraints {
+			placement.Spec.SpreadPolicy.SpreadConstraints[i].WhenUnsatisfiable = clusterapiv1beta1.DoNotSchedule
+		}
+		return placement
+	}
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithLabel("region", "east").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithLabel("region", "east").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithLabel("region", "east").Build(),
+		testinghelpers.NewManagedCluster("cluster4").WithLabel("region", "west").Build(),
+		testinghelpers.NewManagedCluster("cluster5").Build(),
+	}
+
+	cases := []struct {
+		name      string
+		placement *clusterapiv1beta1.Placement
+		n         int
+		expected  []string
+	}{
+		{
+			name: "schedule anyway",
+			placement: testinghelpers.NewPlacement("test", "test").
+				AddSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 1).Build(),
+			n:        3,
+			expected: []string{"cluster1", "cluster2", "cluster3"},
+		},
+		{
+			name: "do not schedule over max skew",
+			placement: doNotSchedule(testinghelpers.NewPlacement("test", "test").
+				AddSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 1).Build()),
+			n:        4,
+			expected: []string{"cluster1", "cluster2", "cluster4"},
+		},
+		{
+			name: "do not schedule with larger max skew",
+			placement: doNotSchedule(testinghelpers.NewPlacement("test", "test").
+				AddSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 2).Build()),
+			n:        5,
+			expected: []string{"cluster1", "cluster2", "cluster3", "cluster4"},
+		},
+		{
+			name: "do not schedule less clusters than required",
+			placement: doNotSchedule(testinghelpers.NewPlacement("test", "test").
+				AddSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 1).Build()),
+			n:        2,
+			expected: []string{"cluster1", "cluster4"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			selected := Select(c.placement, clusters, c.n)
+			var names []string
+			for _, cluster := range selected {
+				names = append(names, cluster.Name)
+			}
+			if !apiequality.Semantic.DeepEqual(names, c.expected) {
+				t.Errorf("expected %v, but got %v", c.expected, names)
+			}
+		})
+	}
+}
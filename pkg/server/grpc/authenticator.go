@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	grpcauthn "open-cluster-management.io/sdk-go/pkg/server/grpc/authn"
+)
+
+var _ grpcauthn.Authenticator = &ForwardedIdentityAuthenticator{}
+
+// ForwardedIdentityAuthenticator authenticates the requests forwarded by the other replicas of the gRPC
+// server. A forwarded request is authenticated by the bearer token of the forwarding replica, which must
+// be the user of the replicas, and is handled as the user and groups that it is forwarded with. The other
+// requests are left to the next authenticators.
+type ForwardedIdentityAuthenticator struct {
+	client      kubernetes.Interface
+	replicaUser string
+}
+
+// NewForwardedIdentityAuthenticator returns a ForwardedIdentityAuthenticator trusting the identities
+// forwarded by the replica user.
+func NewForwardedIdentityAuthenticator(client kubernetes.Interface, replicaUser string) *ForwardedIdentityAuthenticator {
+	return &ForwardedIdentityAuthenticator{client: client, replicaUser: replicaUser}
+}
+
+func (a *ForwardedIdentityAuthenticator) Authenticate(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(forwardedByKey)) == 0 {
+		return ctx, status.Error(codes.Unauthenticated, "the request is not forwarded")
+	}
+	users := md.Get(forwardedUserKey)
+	if len(users) == 0 {
+		return ctx, status.Error(codes.Unauthenticated, "the forwarded request has no user")
+	}
+	authorization := md.Get(authorizationKey)
+	if len(authorization) == 0 {
+		return ctx, status.Error(codes.Unauthenticated, "the forwarded request has no token")
+	}
+
+	user, err := reviewToken(ctx, a.client, strings.TrimPrefix(authorization[0], "Bearer "))
+	if err != nil {
+		return ctx, err
+	}
+	if user != a.replicaUser {
+		return ctx, status.Error(codes.PermissionDenied,
+			fmt.Sprintf("the request is not forwarded by a replica, but by %s", user))
+	}
+
+	ctx = context.WithValue(ctx, grpcauthn.ContextUserKey, users[0])
+	return context.WithValue(ctx, grpcauthn.ContextGroupsKey, md.Get(forwardedGroupsKey)), nil
+}
+
+// reviewToken returns the user authenticated by the token.
+func reviewToken(ctx context.Context, client kubernetes.Interface, token string) (string, error) {
+	tr, err := client.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	if !tr.Status.Authenticated {
+		return "", status.Error(codes.Unauthenticated, "token not authenticated")
+	}
+	return tr.Status.User.Username, nil
+}
@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
@@ -16,6 +17,25 @@ import (
 	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
 	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	v1alpha1addonce "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/addon/v1alpha1"
+	v1beta1addonce "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/addon/v1beta1"
+	clusterce "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/cluster"
+	csrce "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/csr"
+	eventce "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/event"
+	leasece "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/lease"
+	sace "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/serviceaccount"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/work/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/server"
+
+	"open-cluster-management.io/ocm/pkg/server/services/addon/v1alpha1"
+	"open-cluster-management.io/ocm/pkg/server/services/addon/v1beta1"
+	"open-cluster-management.io/ocm/pkg/server/services/cluster"
+	"open-cluster-management.io/ocm/pkg/server/services/csr"
+	"open-cluster-management.io/ocm/pkg/server/services/event"
+	"open-cluster-management.io/ocm/pkg/server/services/lease"
+	"open-cluster-management.io/ocm/pkg/server/services/tokenrequest"
+	"open-cluster-management.io/ocm/pkg/server/services/work"
 )
 
 type Clients struct {
@@ -75,3 +95,46 @@ func (h *Clients) Run(ctx context.Context) {
 	go h.WorkInformers.Start(ctx.Done())
 	go h.AddOnInformers.Start(ctx.Done())
 }
+
+// RegisterServices registers the cluster, CSR, addon, lease, event, work and token request services to
+// the event server, except the services of the excluded data types.
+func (h *Clients) RegisterServices(ctx context.Context, eventServer server.AgentEventServer,
+	excluded ...types.CloudEventsDataType) {
+	// the services are created once they are registered, so the informers of the excluded services are
+	// not started.
+	services := []struct {
+		dataType   types.CloudEventsDataType
+		newService func() server.Service
+	}{
+		{clusterce.ManagedClusterEventDataType, func() server.Service {
+			return cluster.NewClusterService(h.ClusterClient, h.ClusterInformers.Cluster().V1().ManagedClusters())
+		}},
+		{csrce.CSREventDataType, func() server.Service {
+			return csr.NewCSRService(h.KubeClient, h.KubeInformers.Certificates().V1().CertificateSigningRequests())
+		}},
+		{v1alpha1addonce.ManagedClusterAddOnEventDataType, func() server.Service {
+			return v1alpha1.NewAddonService(h.AddOnClient, h.AddOnInformers.Addon().V1alpha1().ManagedClusterAddOns())
+		}},
+		{v1beta1addonce.ManagedClusterAddOnEventDataType, func() server.Service {
+			return v1beta1.NewAddonService(h.AddOnClient, h.AddOnInformers.Addon().V1beta1().ManagedClusterAddOns())
+		}},
+		{eventce.EventEventDataType, func() server.Service {
+			return event.NewEventService(h.KubeClient)
+		}},
+		{leasece.LeaseEventDataType, func() server.Service {
+			return lease.NewLeaseService(h.KubeClient, h.KubeInformers.Coordination().V1().Leases())
+		}},
+		{payload.ManifestBundleEventDataType, func() server.Service {
+			return work.NewWorkService(h.WorkClient, h.WorkInformers.Work().V1().ManifestWorks())
+		}},
+		{sace.TokenRequestDataType, func() server.Service {
+			return tokenrequest.NewTokenRequestService(h.KubeClient)
+		}},
+	}
+	for _, s := range services {
+		if slices.Contains(excluded, s.dataType) {
+			continue
+		}
+		eventServer.RegisterService(ctx, s.dataType, s.newService())
+	}
+}
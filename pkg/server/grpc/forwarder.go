@@ -0,0 +1,275 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"k8s.io/klog/v2"
+
+	sace "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/serviceaccount"
+	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
+	grpcprotocol "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protocol"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	cloudeventsgrpc "open-cluster-management.io/sdk-go/pkg/cloudevents/server/grpc"
+	"open-cluster-management.io/sdk-go/pkg/server/grpc/authn"
+)
+
+const (
+	// forwardedByKey is the metadata key of the requests forwarded by a replica with the identity of
+	// the replica. A forwarded request is handled only by the replica holding the stream of the cluster
+	// and is never forwarded again.
+	forwardedByKey = "x-ocm-grpc-forwarded-by"
+	// forwardedUserKey and forwardedGroupsKey are the metadata keys of the user and groups that the
+	// forwarded request is authenticated as by the forwarding replica.
+	forwardedUserKey   = "x-ocm-grpc-forwarded-user"
+	forwardedGroupsKey = "x-ocm-grpc-forwarded-groups"
+
+	authorizationKey = "authorization"
+
+	// DefaultForwardTimeout is the default timeout of forwarding a request to a peer.
+	DefaultForwardTimeout = 10 * time.Second
+)
+
+// TokenSource returns the bearer token that the replica authenticates with to the peers.
+type TokenSource func() (string, error)
+
+// PeerLister lists the addresses of the other replicas of the gRPC server.
+type PeerLister interface {
+	Peers() []string
+}
+
+type subscriptionKey struct {
+	clusterName string
+	dataType    types.CloudEventsDataType
+}
+
+// ForwardingBroker is a GRPCBroker of a replica of the gRPC server running behind a load balancer.
+//
+// The spec events from the informers are seen by every replica and are sent by the replica holding the
+// stream of the cluster, but the spec resync responses and the token responses are sent by the replica
+// receiving the request. When the agent publishes such a request to a replica which does not hold the
+// stream of the cluster, the request is forwarded to the peers, and is handled by the peer holding the
+// stream. The request is forwarded with the bearer token of the replica and the user and groups that the
+// agent is authenticated as, by either a bearer token or a client certificate. The peer trusts the
+// forwarded identity once the replica is authenticated by the ForwardedIdentityAuthenticator, and
+// authorizes the request as the agent.
+type ForwardingBroker struct {
+	*cloudeventsgrpc.GRPCBroker
+	identity       string
+	peers          PeerLister
+	token          TokenSource
+	forwardTimeout time.Duration
+	dialOptions    []grpc.DialOption
+
+	lock          sync.Mutex
+	subscriptions map[subscriptionKey]int
+	conns         map[string]*grpc.ClientConn
+}
+
+// NewForwardingBroker returns a ForwardingBroker of the replica which forwards the requests to the
+// peers with the token and the dial options.
+func NewForwardingBroker(broker *cloudeventsgrpc.GRPCBroker, identity string, peers PeerLister, token TokenSource,
+	dialOptions ...grpc.DialOption) *ForwardingBroker {
+	return &ForwardingBroker{
+		GRPCBroker:     broker,
+		identity:       identity,
+		peers:          peers,
+		token:          token,
+		forwardTimeout: DefaultForwardTimeout,
+		dialOptions:    dialOptions,
+		subscriptions:  map[subscriptionKey]int{},
+		conns:          map[string]*grpc.ClientConn{},
+	}
+}
+
+// Subscribe tracks the streams held by this replica and subscribes the agent to the broker.
+func (b *ForwardingBroker) Subscribe(subReq *pbv1.SubscriptionRequest, subServer pbv1.CloudEventService_SubscribeServer) error {
+	dataType, err := types.ParseCloudEventsDataType(subReq.DataType)
+	if err != nil {
+		return b.GRPCBroker.Subscribe(subReq, subServer)
+	}
+
+	key := subscriptionKey{clusterName: subReq.ClusterName, dataType: *dataType}
+	b.lock.Lock()
+	b.subscriptions[key]++
+	b.lock.Unlock()
+
+	defer func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		b.subscriptions[key]--
+		if b.subscriptions[key] <= 0 {
+			delete(b.subscriptions, key)
+		}
+	}()
+
+	return b.GRPCBroker.Subscribe(subReq, subServer)
+}
+
+// Publish handles the request responded on the stream of the cluster by the replica holding the stream,
+// and handles the other requests locally.
+func (b *ForwardingBroker) Publish(ctx context.Context, pubReq *pbv1.PublishRequest) (*emptypb.Empty, error) {
+	key, ok := streamKey(ctx, pubReq)
+	if !ok {
+		return b.GRPCBroker.Publish(ctx, pubReq)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get(forwardedByKey)) > 0 {
+		if !b.isSubscribed(key) {
+			return nil, status.Error(codes.NotFound, fmt.Sprintf("no %s stream of cluster %s", key.dataType, key.clusterName))
+		}
+		return b.GRPCBroker.Publish(ctx, pubReq)
+	}
+
+	if b.isSubscribed(key) {
+		return b.GRPCBroker.Publish(ctx, pubReq)
+	}
+
+	forwarded, err := b.forward(ctx, key, pubReq)
+	if err != nil {
+		return nil, err
+	}
+	if forwarded {
+		return &emptypb.Empty{}, nil
+	}
+
+	// no peer holds the stream, the agent may be reconnecting to this replica
+	return b.GRPCBroker.Publish(ctx, pubReq)
+}
+
+// forward publishes the request to the peers until a peer holding the stream of the cluster handles it.
+// It returns false if the request is not handled by any peer, and an error if the request cannot be
+// forwarded since the identity of the agent is unknown.
+func (b *ForwardingBroker) forward(ctx context.Context, key subscriptionKey, pubReq *pbv1.PublishRequest) (bool, error) {
+	logger := klog.FromContext(ctx).WithValues("clusterName", key.clusterName, "dataType", key.dataType)
+	peers := b.peers.Peers()
+	b.closeStaleConns(peers)
+	if len(peers) == 0 {
+		return false, nil
+	}
+
+	user, _ := ctx.Value(authn.ContextUserKey).(string)
+	groups, _ := ctx.Value(authn.ContextGroupsKey).([]string)
+	if len(user) == 0 {
+		return false, status.Error(codes.Unauthenticated,
+			fmt.Sprintf("the request of cluster %s is not forwarded to the replica holding the stream "+
+				"since it is not authenticated", key.clusterName))
+	}
+	token, err := b.token()
+	if err != nil {
+		return false, status.Error(codes.Internal, fmt.Sprintf("failed to get the token of the replica: %v", err))
+	}
+
+	md := metadata.Pairs(authorizationKey, "Bearer "+token, forwardedByKey, b.identity, forwardedUserKey, user)
+	for _, group := range groups {
+		md.Append(forwardedGroupsKey, group)
+	}
+	forwardCtx := metadata.NewOutgoingContext(ctx, md)
+	for _, peer := range peers {
+		conn, err := b.conn(peer)
+		if err != nil {
+			logger.Error(err, "failed to connect to the peer", "peer", peer)
+			continue
+		}
+
+		err = b.publishToPeer(forwardCtx, conn, pubReq)
+		switch status.Code(err) {
+		case codes.OK:
+			logger.V(4).Info("the request is forwarded to the peer", "peer", peer)
+			return true, nil
+		case codes.NotFound:
+			continue
+		case codes.Unavailable, codes.DeadlineExceeded:
+			logger.V(4).Info("the peer is unavailable", "peer", peer, "error", err)
+			continue
+		default:
+			// the peer holds the stream but fails to handle the request
+			return true, err
+		}
+	}
+
+	return false, nil
+}
+
+// publishToPeer publishes the request to a peer in the forward timeout, so a peer which is not responding
+// does not block the request from being forwarded to the other peers.
+func (b *ForwardingBroker) publishToPeer(ctx context.Context, conn *grpc.ClientConn, pubReq *pbv1.PublishRequest) error {
+	peerCtx, cancel := context.WithTimeout(ctx, b.forwardTimeout)
+	defer cancel()
+	_, err := pbv1.NewCloudEventServiceClient(conn).Publish(peerCtx, pubReq)
+	return err
+}
+
+func (b *ForwardingBroker) isSubscribed(key subscriptionKey) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.subscriptions[key] > 0
+}
+
+func (b *ForwardingBroker) conn(peer string) (*grpc.ClientConn, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if conn, ok := b.conns[peer]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.NewClient(peer, b.dialOptions...)
+	if err != nil {
+		return nil, err
+	}
+	b.conns[peer] = conn
+	return conn, nil
+}
+
+// closeStaleConns closes the connections to the replicas which are no longer peers.
+func (b *ForwardingBroker) closeStaleConns(peers []string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for peer, conn := range b.conns {
+		if slices.Contains(peers, peer) {
+			continue
+		}
+		_ = conn.Close()
+		delete(b.conns, peer)
+	}
+}
+
+// Close closes the connections to the peers.
+func (b *ForwardingBroker) Close() {
+	b.closeStaleConns(nil)
+}
+
+// streamKey returns the stream that the request is responded on, which is the stream of the cluster for
+// the spec resync requests and the token requests.
+func streamKey(ctx context.Context, pubReq *pbv1.PublishRequest) (subscriptionKey, bool) {
+	if pubReq.Event == nil {
+		return subscriptionKey{}, false
+	}
+	evt, err := binding.ToEvent(ctx, grpcprotocol.NewMessage(pubReq.Event))
+	if err != nil {
+		return subscriptionKey{}, false
+	}
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return subscriptionKey{}, false
+	}
+	if eventType.Action != types.ResyncRequestAction && eventType.CloudEventsDataType != sace.TokenRequestDataType {
+		return subscriptionKey{}, false
+	}
+	clusterName, err := evt.Context.GetExtension(types.ExtensionClusterName)
+	if err != nil {
+		return subscriptionKey{}, false
+	}
+	return subscriptionKey{clusterName: fmt.Sprintf("%s", clusterName), dataType: eventType.CloudEventsDataType}, true
+}
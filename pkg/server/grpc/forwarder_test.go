@@ -0,0 +1,404 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	sace "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/serviceaccount"
+	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
+	grpcprotocol "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protocol"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/server"
+	cloudeventsgrpc "open-cluster-management.io/sdk-go/pkg/cloudevents/server/grpc"
+	grpcauthn "open-cluster-management.io/sdk-go/pkg/server/grpc/authn"
+)
+
+var testDataType = types.CloudEventsDataType{Group: "test", Version: "v1", Resource: "tests"}
+
+// fakeService lists a resource of cluster1 and responds the status updates on the stream of the cluster.
+type fakeService struct {
+	dataType types.CloudEventsDataType
+
+	mu       sync.Mutex
+	handler  server.EventHandler
+	lists    int
+	requests int
+}
+
+func (s *fakeService) List(_ context.Context, _ types.ListOptions) ([]*cloudevents.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists++
+
+	evt := newTestEvent(s.dataType, types.ResyncResponseAction)
+	evt.SetExtension(types.ExtensionResourceID, "r1")
+	evt.SetExtension(types.ExtensionResourceVersion, "1")
+	return []*cloudevents.Event{&evt}, nil
+}
+
+func (s *fakeService) HandleStatusUpdate(ctx context.Context, _ *cloudevents.Event) error {
+	s.mu.Lock()
+	s.requests++
+	handler := s.handler
+	s.mu.Unlock()
+
+	evt := newTestEvent(s.dataType, types.CreateRequestAction)
+	return handler.HandleEvent(ctx, &evt)
+}
+
+func (s *fakeService) RegisterHandler(_ context.Context, handler server.EventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = handler
+}
+
+func (s *fakeService) counts() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lists, s.requests
+}
+
+func newTestEvent(dataType types.CloudEventsDataType, action types.EventAction) cloudevents.Event {
+	return types.NewEventBuilder("test", types.CloudEventsType{
+		CloudEventsDataType: dataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              action,
+	}).WithClusterName("cluster1").NewEvent()
+}
+
+const testReplicaUser = "system:serviceaccount:open-cluster-management-hub:grpc-server-sa"
+
+// newTestKubeClient returns a kube client authenticating the token of the replicas and the token of the
+// agent of cluster1.
+func newTestKubeClient() *kubefake.Clientset {
+	kubeClient := kubefake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		tr := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		switch tr.Spec.Token {
+		case "replica":
+			tr.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true, User: authenticationv1.UserInfo{Username: testReplicaUser}}
+		case "agent":
+			tr.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true, User: authenticationv1.UserInfo{
+					Username: "agent", Groups: []string{"system:open-cluster-management:cluster1"}}}
+		}
+		return true, tr, nil
+	})
+	return kubeClient
+}
+
+type identity struct {
+	user   string
+	groups []string
+}
+
+type testReplica struct {
+	address      string
+	registry     *ReplicaRegistry
+	service      *fakeService
+	tokenService *fakeService
+	client       pbv1.CloudEventServiceClient
+	// forwarded is the identities that the forwarded requests are authenticated as
+	forwarded chan identity
+}
+
+// startReplica starts a replica of the gRPC server in the process, which authenticates the forwarded
+// requests and the bearer tokens, and handles the requests without an identity.
+func startReplica(t *testing.T, ctx context.Context, kubeClient *kubefake.Clientset, name string) *testReplica {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replica := &testReplica{
+		address:      listener.Addr().String(),
+		service:      &fakeService{dataType: testDataType},
+		tokenService: &fakeService{dataType: sace.TokenRequestDataType},
+		forwarded:    make(chan identity, 10),
+	}
+	replica.registry = newTestRegistry(t, kubeClient, nil, name, replica.address)
+
+	broker := cloudeventsgrpc.NewGRPCBroker(&cloudeventsgrpc.BrokerOptions{HeartbeatDisabled: true})
+	broker.RegisterService(ctx, testDataType, replica.service)
+	broker.RegisterService(ctx, sace.TokenRequestDataType, replica.tokenService)
+	forwardingBroker := NewForwardingBroker(broker, name, replica.registry,
+		func() (string, error) { return "replica", nil },
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+
+	authenticators := []grpcauthn.Authenticator{
+		NewForwardedIdentityAuthenticator(kubeClient, testReplicaUser),
+		grpcauthn.NewTokenAuthenticator(kubeClient),
+	}
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(
+		func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			for _, authenticator := range authenticators {
+				if authCtx, err := authenticator.Authenticate(ctx); err == nil {
+					ctx = authCtx
+					break
+				}
+			}
+			md, _ := metadata.FromIncomingContext(ctx)
+			if values := md.Get(forwardedByKey); len(values) > 0 {
+				user, _ := ctx.Value(grpcauthn.ContextUserKey).(string)
+				groups, _ := ctx.Value(grpcauthn.ContextGroupsKey).([]string)
+				replica.forwarded <- identity{user: user, groups: groups}
+			}
+			return handler(ctx, req)
+		}))
+	pbv1.RegisterCloudEventServiceServer(grpcServer, forwardingBroker)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(func() {
+		grpcServer.Stop()
+		forwardingBroker.Close()
+	})
+
+	conn, err := grpc.NewClient(replica.address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	replica.client = pbv1.NewCloudEventServiceClient(conn)
+	return replica
+}
+
+// subscribe subscribes to the replica and returns the events received on the stream.
+func (r *testReplica) subscribe(t *testing.T, ctx context.Context, dataType types.CloudEventsDataType) <-chan cloudevents.Event {
+	stream, err := r.client.Subscribe(ctx, &pbv1.SubscriptionRequest{ClusterName: "cluster1", DataType: dataType.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Header(); err != nil {
+		t.Fatal(err)
+	}
+
+	evts := make(chan cloudevents.Event, 10)
+	go func() {
+		for {
+			pbEvt, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			evt, err := binding.ToEvent(ctx, grpcprotocol.NewMessage(pbEvt))
+			if err != nil {
+				t.Errorf("failed to convert the event: %v", err)
+				return
+			}
+			evts <- *evt
+		}
+	}()
+	return evts
+}
+
+func publish(ctx context.Context, client pbv1.CloudEventServiceClient, evt cloudevents.Event, kvs ...string) error {
+	pbEvt := &pbv1.CloudEvent{}
+	if err := grpcprotocol.WritePBMessage(ctx, binding.ToMessage(&evt), pbEvt); err != nil {
+		return err
+	}
+	if len(kvs) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, kvs...)
+	}
+	_, err := client.Publish(ctx, &pbv1.PublishRequest{Event: pbEvt})
+	return err
+}
+
+func newResyncRequest(t *testing.T) cloudevents.Event {
+	evt := newTestEvent(testDataType, types.ResyncRequestAction)
+	if err := evt.SetData(cloudevents.ApplicationJSON, &payload.ResourceVersionList{}); err != nil {
+		t.Fatal(err)
+	}
+	return evt
+}
+
+func receiveEvent(t *testing.T, evts <-chan cloudevents.Event, expectedType types.CloudEventsType) {
+	select {
+	case evt := <-evts:
+		if evt.Type() != expectedType.String() {
+			t.Fatalf("expected event %s, but got %s", expectedType, evt.Type())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timeout to receive the event %s", expectedType)
+	}
+}
+
+func TestForwardingBroker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kubeClient := newTestKubeClient()
+	replica1 := startReplica(t, ctx, kubeClient, "replica1")
+	replica2 := startReplica(t, ctx, kubeClient, "replica2")
+	replica3 := startReplica(t, ctx, kubeClient, "replica3")
+	for _, replica := range []*testReplica{replica1, replica2, replica3, replica1, replica2} {
+		replica.registry.sync(ctx)
+	}
+
+	// the agent holds its streams on replica2
+	specEvents := replica2.subscribe(t, ctx, testDataType)
+	tokenEvents := replica2.subscribe(t, ctx, sace.TokenRequestDataType)
+
+	// the resync request received by replica1 is responded on the stream held by replica2
+	if err := publish(ctx, replica1.client, newResyncRequest(t), authorizationKey, "Bearer agent"); err != nil {
+		t.Fatal(err)
+	}
+	receiveEvent(t, specEvents, types.CloudEventsType{
+		CloudEventsDataType: testDataType, SubResource: types.SubResourceSpec, Action: types.ResyncResponseAction})
+	if lists, _ := replica2.service.counts(); lists != 1 {
+		t.Errorf("expected the resync request handled by replica2, but got %d", lists)
+	}
+	if lists, _ := replica1.service.counts(); lists != 0 {
+		t.Errorf("expected the resync request not handled by replica1, but got %d", lists)
+	}
+	expectForwarded(t, replica2, identity{user: "agent", groups: []string{"system:open-cluster-management:cluster1"}})
+
+	// the token request received by replica3 is responded on the stream held by replica2
+	if err := publish(ctx, replica3.client, newTestEvent(sace.TokenRequestDataType, types.CreateRequestAction),
+		authorizationKey, "Bearer agent"); err != nil {
+		t.Fatal(err)
+	}
+	receiveEvent(t, tokenEvents, types.CloudEventsType{
+		CloudEventsDataType: sace.TokenRequestDataType, SubResource: types.SubResourceSpec, Action: types.CreateRequestAction})
+	if _, requests := replica2.tokenService.counts(); requests != 1 {
+		t.Errorf("expected the token request handled by replica2, but got %d", requests)
+	}
+	if _, requests := replica3.tokenService.counts(); requests != 0 {
+		t.Errorf("expected the token request not handled by replica3, but got %d", requests)
+	}
+
+	expectForwarded(t, replica2, identity{user: "agent", groups: []string{"system:open-cluster-management:cluster1"}})
+
+	// the resync request without an authenticated identity is rejected rather than handled locally
+	err := publish(ctx, replica1.client, newResyncRequest(t))
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected unauthenticated error, but got %v", err)
+	}
+	if lists, _ := replica1.service.counts(); lists != 0 {
+		t.Errorf("expected the resync request not handled by replica1, but got %d", lists)
+	}
+
+	// the forwarded request is rejected by the replica which does not hold the stream, replica3 may have
+	// rejected the requests forwarded by the others too
+	for len(replica3.forwarded) > 0 {
+		<-replica3.forwarded
+	}
+	err = publish(ctx, replica3.client, newResyncRequest(t), authorizationKey, "Bearer replica",
+		forwardedByKey, "replica1", forwardedUserKey, "agent")
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected not found error, but got %v", err)
+	}
+	expectForwarded(t, replica3, identity{user: "agent"})
+
+	// the identity forwarded by an agent rather than a replica is not trusted
+	if err := publish(ctx, replica2.client, newResyncRequest(t), authorizationKey, "Bearer agent",
+		forwardedByKey, "replica1", forwardedUserKey, "system:admin"); err != nil {
+		t.Fatal(err)
+	}
+	expectForwarded(t, replica2, identity{user: "agent", groups: []string{"system:open-cluster-management:cluster1"}})
+
+	// the request is handled locally once the peers are gone
+	if err := replica2.registry.release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := replica3.registry.release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := replica1.registry.refreshPeers(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := publish(ctx, replica1.client, newResyncRequest(t), authorizationKey, "Bearer agent"); err != nil {
+		t.Fatal(err)
+	}
+	if lists, _ := replica1.service.counts(); lists != 1 {
+		t.Errorf("expected the resync request handled by replica1, but got %d", lists)
+	}
+}
+
+func expectForwarded(t *testing.T, replica *testReplica, expected identity) {
+	t.Helper()
+	select {
+	case actual := <-replica.forwarded:
+		if actual.user != expected.user || !slices.Equal(actual.groups, expected.groups) {
+			t.Errorf("expected the request forwarded as %v, but got %v", expected, actual)
+		}
+	default:
+		t.Errorf("expected the request forwarded to the replica")
+	}
+}
+
+// hangingServer is a peer which does not respond the requests until they are canceled.
+type hangingServer struct {
+	pbv1.UnimplementedCloudEventServiceServer
+}
+
+func (hangingServer) Publish(ctx context.Context, _ *pbv1.PublishRequest) (*emptypb.Empty, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type staticPeers []string
+
+func (p staticPeers) Peers() []string {
+	return p
+}
+
+func TestForwardTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hangingPeer := grpc.NewServer()
+	pbv1.RegisterCloudEventServiceServer(hangingPeer, hangingServer{})
+	go func() {
+		_ = hangingPeer.Serve(listener)
+	}()
+	defer hangingPeer.Stop()
+
+	// the agent holds its stream on replica2
+	replica2 := startReplica(t, ctx, newTestKubeClient(), "replica2")
+	specEvents := replica2.subscribe(t, ctx, testDataType)
+
+	broker := cloudeventsgrpc.NewGRPCBroker(&cloudeventsgrpc.BrokerOptions{HeartbeatDisabled: true})
+	broker.RegisterService(ctx, testDataType, &fakeService{dataType: testDataType})
+	forwardingBroker := NewForwardingBroker(broker, "replica1",
+		staticPeers{listener.Addr().String(), replica2.address},
+		func() (string, error) { return "replica", nil },
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	forwardingBroker.forwardTimeout = 100 * time.Millisecond
+	defer forwardingBroker.Close()
+
+	// the request is forwarded to replica2 once the hanging peer times out
+	pbEvt := &pbv1.CloudEvent{}
+	evt := newResyncRequest(t)
+	if err := grpcprotocol.WritePBMessage(ctx, binding.ToMessage(&evt), pbEvt); err != nil {
+		t.Fatal(err)
+	}
+	agentCtx := context.WithValue(ctx, grpcauthn.ContextUserKey, "agent")
+	if _, err := forwardingBroker.Publish(agentCtx, &pbv1.PublishRequest{Event: pbEvt}); err != nil {
+		t.Fatal(err)
+	}
+	receiveEvent(t, specEvents, types.CloudEventsType{
+		CloudEventsDataType: testDataType, SubResource: types.SubResourceSpec, Action: types.ResyncResponseAction})
+	expectForwarded(t, replica2, identity{user: "agent"})
+}
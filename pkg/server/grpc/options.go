@@ -2,36 +2,26 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
-	v1alpha1addonce "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/addon/v1alpha1"
-	v1beta1addonce "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/addon/v1beta1"
-	clusterce "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/cluster"
-	csrce "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/csr"
-	eventce "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/event"
-	leasece "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/lease"
-	sace "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/serviceaccount"
-	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/work/payload"
 	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
 	cloudeventsgrpc "open-cluster-management.io/sdk-go/pkg/cloudevents/server/grpc"
 	grpcauthz "open-cluster-management.io/sdk-go/pkg/cloudevents/server/grpc/authz/kube"
 	cemetrics "open-cluster-management.io/sdk-go/pkg/cloudevents/server/grpc/metrics"
 	sdkgrpc "open-cluster-management.io/sdk-go/pkg/server/grpc"
 	grpcauthn "open-cluster-management.io/sdk-go/pkg/server/grpc/authn"
-
-	"open-cluster-management.io/ocm/pkg/server/services/addon/v1alpha1"
-	"open-cluster-management.io/ocm/pkg/server/services/addon/v1beta1"
-	"open-cluster-management.io/ocm/pkg/server/services/cluster"
-	"open-cluster-management.io/ocm/pkg/server/services/csr"
-	"open-cluster-management.io/ocm/pkg/server/services/event"
-	"open-cluster-management.io/ocm/pkg/server/services/lease"
-	"open-cluster-management.io/ocm/pkg/server/services/tokenrequest"
-	"open-cluster-management.io/ocm/pkg/server/services/work"
 )
 
 type GRPCServerOptions struct {
@@ -42,16 +32,43 @@ type GRPCServerOptions struct {
 	// These take precedence over the YAML config file loaded by LoadGRPCServerOptions.
 	TLSMinVersionOverride   string
 	TLSCipherSuitesOverride string
+
+	// PeerAdvertiseAddress is the address that the other replicas of the server use to reach this replica.
+	// The requests responded on the stream of a cluster are forwarded to the replica holding the stream
+	// only if it is set.
+	PeerAdvertiseAddress string
+	// PeerServerName is the server name to verify the serving certificate of the other replicas.
+	PeerServerName string
+	// PeerCAFile is the CA bundle to verify the serving certificate of the other replicas.
+	PeerCAFile string
+	// PeerLeaseNamespace is the namespace of the replica Leases. The namespace of the server is used if not set.
+	PeerLeaseNamespace string
+	// PeerLeaseDuration is the duration of the Lease registering this replica to the other replicas.
+	PeerLeaseDuration time.Duration
 }
 
 func NewGRPCServerOptions() *GRPCServerOptions {
 	return &GRPCServerOptions{
 		grpcBrokerOptions: cloudeventsgrpc.NewBrokerOptions(),
+		PeerLeaseDuration: DefaultReplicaLeaseDuration,
 	}
 }
 
 func (o *GRPCServerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.GRPCServerConfig, "server-config", o.GRPCServerConfig, "Location of the server configuration file.")
+	fs.StringVar(&o.PeerAdvertiseAddress, "peer-advertise-address", o.PeerAdvertiseAddress,
+		"The address (host:port) that the other replicas of the server use to reach this replica. If it is set, "+
+			"the spec resync and token requests of a cluster whose stream is held by another replica are forwarded "+
+			"to that replica.")
+	fs.StringVar(&o.PeerServerName, "peer-server-name", o.PeerServerName,
+		"The server name to verify the serving certificate of the other replicas.")
+	fs.StringVar(&o.PeerCAFile, "peer-ca-file", o.PeerCAFile,
+		"The CA bundle to verify the serving certificate of the other replicas. The requests are not forwarded "+
+			"to the other replicas if it is not set.")
+	fs.StringVar(&o.PeerLeaseNamespace, "peer-lease-namespace", o.PeerLeaseNamespace,
+		"The namespace of the leases registering the replicas. The namespace of the server is used if not set.")
+	fs.DurationVar(&o.PeerLeaseDuration, "peer-lease-duration", o.PeerLeaseDuration,
+		"The duration of the Lease registering this replica to the other replicas.")
 	o.grpcBrokerOptions.AddFlags(fs)
 }
 
@@ -77,29 +94,31 @@ func (o *GRPCServerOptions) Run(ctx context.Context, controllerContext *controll
 	}
 
 	// initialize grpc broker and register services
-	grpcEventServer := cloudeventsgrpc.NewGRPCBroker(o.grpcBrokerOptions)
-	grpcEventServer.RegisterService(ctx, clusterce.ManagedClusterEventDataType,
-		cluster.NewClusterService(clients.ClusterClient, clients.ClusterInformers.Cluster().V1().ManagedClusters()))
-	grpcEventServer.RegisterService(ctx, csrce.CSREventDataType,
-		csr.NewCSRService(clients.KubeClient, clients.KubeInformers.Certificates().V1().CertificateSigningRequests()))
-	grpcEventServer.RegisterService(ctx, v1alpha1addonce.ManagedClusterAddOnEventDataType,
-		v1alpha1.NewAddonService(clients.AddOnClient, clients.AddOnInformers.Addon().V1alpha1().ManagedClusterAddOns()))
-	grpcEventServer.RegisterService(ctx, v1beta1addonce.ManagedClusterAddOnEventDataType,
-		v1beta1.NewAddonService(clients.AddOnClient, clients.AddOnInformers.Addon().V1beta1().ManagedClusterAddOns()))
-	grpcEventServer.RegisterService(ctx, eventce.EventEventDataType,
-		event.NewEventService(clients.KubeClient))
-	grpcEventServer.RegisterService(ctx, leasece.LeaseEventDataType,
-		lease.NewLeaseService(clients.KubeClient, clients.KubeInformers.Coordination().V1().Leases()))
-	grpcEventServer.RegisterService(ctx, payload.ManifestBundleEventDataType,
-		work.NewWorkService(clients.WorkClient, clients.WorkInformers.Work().V1().ManifestWorks()))
-	grpcEventServer.RegisterService(ctx, sace.TokenRequestDataType, tokenrequest.NewTokenRequestService(clients.KubeClient))
+	grpcBroker := cloudeventsgrpc.NewGRPCBroker(o.grpcBrokerOptions)
+	clients.RegisterServices(ctx, grpcBroker)
+
+	grpcServer := sdkgrpc.NewGRPCServer(serverOptions)
+	var grpcEventServer pbv1.CloudEventServiceServer = grpcBroker
+	if len(o.PeerAdvertiseAddress) > 0 {
+		forwardingBroker, replicaUser, err := o.newForwardingBroker(ctx, controllerContext, clients, grpcBroker)
+		if err != nil {
+			return err
+		}
+		if forwardingBroker != nil {
+			defer forwardingBroker.Close()
+			grpcEventServer = forwardingBroker
+			// the requests forwarded by the other replicas are authenticated before the token authenticator,
+			// which would authenticate them as the forwarding replica.
+			grpcServer.WithAuthenticator(NewForwardedIdentityAuthenticator(clients.KubeClient, replicaUser))
+		}
+	}
 
 	// start clients
 	go clients.Run(ctx)
 
 	// initialize and run grpc server
 	authorizer := grpcauthz.NewSARAuthorizer(clients.KubeClient)
-	return sdkgrpc.NewGRPCServer(serverOptions).
+	return grpcServer.
 		WithAuthenticator(grpcauthn.NewTokenAuthenticator(clients.KubeClient)).
 		WithAuthenticator(grpcauthn.NewMtlsAuthenticator()).
 		WithUnaryAuthorizer(authorizer).
@@ -110,3 +129,92 @@ func (o *GRPCServerOptions) Run(ctx context.Context, controllerContext *controll
 		WithExtraMetrics(cemetrics.CloudEventsGRPCMetrics()...).
 		Run(ctx)
 }
+
+// newForwardingBroker registers this replica with a Lease and returns a broker forwarding the requests to
+// the other replicas, and the user of the replicas. The other replicas are verified with the peer CA file,
+// and the replica is authenticated by the bearer token in the kubeconfig of the server. If the peer CA file
+// or the bearer token is not available, the requests are not forwarded and a nil broker is returned.
+func (o *GRPCServerOptions) newForwardingBroker(ctx context.Context, controllerContext *controllercmd.ControllerContext,
+	clients *Clients, broker *cloudeventsgrpc.GRPCBroker) (*ForwardingBroker, string, error) {
+	identity := os.Getenv("POD_NAME")
+	if len(identity) == 0 {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, "", err
+		}
+		identity = hostname
+	}
+
+	namespace := o.PeerLeaseNamespace
+	if len(namespace) == 0 {
+		namespace = controllerContext.OperatorNamespace
+	}
+
+	registry, err := NewReplicaRegistry(clients.KubeClient.CoordinationV1(), ReplicaOptions{
+		Namespace:     namespace,
+		Identity:      identity,
+		Address:       o.PeerAdvertiseAddress,
+		LeaseDuration: o.PeerLeaseDuration,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(o.PeerCAFile) == 0 {
+		klog.Warningf("The requests are not forwarded to the other replicas since the peer CA file is not set")
+		return nil, "", nil
+	}
+	caPEM, err := os.ReadFile(o.PeerCAFile)
+	if err != nil {
+		klog.Warningf("The requests are not forwarded to the other replicas since the peer CA file "+
+			"cannot be read: %v", err)
+		return nil, "", nil
+	}
+	certPool := x509.NewCertPool()
+	if ok := certPool.AppendCertsFromPEM(caPEM); !ok {
+		return nil, "", fmt.Errorf("failed to append the CA of the peers to cert pool")
+	}
+	creds := credentials.NewTLS(&tls.Config{
+		RootCAs:    certPool,
+		ServerName: o.PeerServerName,
+		MinVersion: tls.VersionTLS12,
+	})
+
+	tokenSource, err := newTokenSource(controllerContext.KubeConfig)
+	if err != nil {
+		klog.Warningf("The requests are not forwarded to the other replicas: %v", err)
+		return nil, "", nil
+	}
+	token, err := tokenSource()
+	if err != nil {
+		return nil, "", err
+	}
+	replicaUser, err := reviewToken(ctx, clients.KubeClient, token)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to authenticate the replica: %w", err)
+	}
+
+	go registry.Run(ctx)
+	return NewForwardingBroker(broker, identity, registry, tokenSource, grpc.WithTransportCredentials(creds)), replicaUser, nil
+}
+
+// newTokenSource returns the bearer token of the kubeconfig. The token file is read each time, since the
+// projected service account token is rotated.
+func newTokenSource(config *rest.Config) (TokenSource, error) {
+	switch {
+	case len(config.BearerTokenFile) > 0:
+		return func() (string, error) {
+			token, err := os.ReadFile(config.BearerTokenFile)
+			if err != nil {
+				return "", err
+			}
+			return strings.TrimSpace(string(token)), nil
+		}, nil
+	case len(config.BearerToken) > 0:
+		return func() (string, error) {
+			return config.BearerToken, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("the kubeconfig of the server has no bearer token to authenticate to the peers")
+	}
+}
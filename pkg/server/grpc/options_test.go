@@ -11,6 +11,9 @@ import (
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"github.com/spf13/pflag"
 	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
+
+	cloudeventsgrpc "open-cluster-management.io/sdk-go/pkg/cloudevents/server/grpc"
 )
 
 func TestNewGRPCServerOptions(t *testing.T) {
@@ -238,3 +241,96 @@ grpc_private_key_file: ` + keyFile + `
 		t.Errorf("Expected certificate-related error, got: %v", err)
 	}
 }
+
+func TestNewForwardingBroker(t *testing.T) {
+	t.Setenv("POD_NAME", "grpc-server-1")
+	caFile := filepath.Join(t.TempDir(), "ca-bundle.crt")
+	caPEM, _, err := certutil.GenerateSelfSignedCertKey("grpc-server", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(caFile, caPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name             string
+		namespace        string
+		caFile           string
+		kubeConfig       *rest.Config
+		expectedError    string
+		expectedDisabled bool
+	}{
+		{
+			name:          "missing lease namespace",
+			caFile:        caFile,
+			kubeConfig:    &rest.Config{BearerToken: "replica"},
+			expectedError: "the namespace of the replica leases is required",
+		},
+		{
+			name:             "missing peer CA file",
+			namespace:        "open-cluster-management-hub",
+			kubeConfig:       &rest.Config{BearerToken: "replica"},
+			expectedDisabled: true,
+		},
+		{
+			name:             "unreadable peer CA file",
+			namespace:        "open-cluster-management-hub",
+			caFile:           filepath.Join(t.TempDir(), "missing.crt"),
+			kubeConfig:       &rest.Config{BearerToken: "replica"},
+			expectedDisabled: true,
+		},
+		{
+			name:             "missing bearer token",
+			namespace:        "open-cluster-management-hub",
+			caFile:           caFile,
+			kubeConfig:       &rest.Config{},
+			expectedDisabled: true,
+		},
+		{
+			name:       "forwarding enabled",
+			namespace:  "open-cluster-management-hub",
+			caFile:     caFile,
+			kubeConfig: &rest.Config{BearerToken: "replica"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := NewGRPCServerOptions()
+			opts.PeerAdvertiseAddress = "10.0.0.1:8090"
+			opts.PeerLeaseNamespace = c.namespace
+			opts.PeerCAFile = c.caFile
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			controllerContext := &controllercmd.ControllerContext{KubeConfig: c.kubeConfig}
+			broker := cloudeventsgrpc.NewGRPCBroker(cloudeventsgrpc.NewBrokerOptions())
+			forwardingBroker, replicaUser, err := opts.newForwardingBroker(
+				ctx, controllerContext, &Clients{KubeClient: newTestKubeClient()}, broker)
+			if len(c.expectedError) > 0 {
+				if err == nil || !strings.Contains(err.Error(), c.expectedError) {
+					t.Errorf("expected error %q, but got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.expectedDisabled {
+				if forwardingBroker != nil {
+					t.Errorf("expected the requests not forwarded")
+				}
+				return
+			}
+			if forwardingBroker == nil {
+				t.Fatal("expected the requests forwarded")
+			}
+			defer forwardingBroker.Close()
+			if replicaUser != testReplicaUser {
+				t.Errorf("expected replica user %s, but got %s", testReplicaUser, replicaUser)
+			}
+		})
+	}
+}
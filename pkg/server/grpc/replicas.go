@@ -0,0 +1,206 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// ReplicaLabel is the label of the Leases registering the replicas of the gRPC server.
+	ReplicaLabel = "open-cluster-management.io/grpc-server-replica"
+
+	// ReplicaAddressAnnotation is the annotation of the replica Lease with the address that the other
+	// replicas use to reach the replica.
+	ReplicaAddressAnnotation = "open-cluster-management.io/grpc-server-address"
+
+	// DefaultReplicaLeaseDuration is the default duration of the replica Leases. A replica is no longer
+	// a peer of the other replicas if its Lease is not renewed within the duration.
+	DefaultReplicaLeaseDuration = 30 * time.Second
+)
+
+// ReplicaOptions defines a replica of the gRPC server.
+type ReplicaOptions struct {
+	// Namespace is the namespace of the replica Leases.
+	Namespace string
+	// Identity is the unique identity of the replica, e.g. the pod name.
+	Identity string
+	// Address is the address (host:port) that the other replicas use to reach the replica.
+	Address string
+	// LeaseDuration is the duration of the replica Lease. The Lease is renewed every third of the duration.
+	LeaseDuration time.Duration
+}
+
+// ReplicaRegistry registers a replica of the gRPC server with a Lease and discovers the other replicas
+// holding a live Lease, which are the peers that the replica forwards the requests to.
+type ReplicaRegistry struct {
+	options     ReplicaOptions
+	leaseClient coordv1client.LeasesGetter
+	clock       clock.Clock
+
+	lock  sync.RWMutex
+	peers []string
+}
+
+// NewReplicaRegistry returns a ReplicaRegistry of the replica. The replica has no peer until Run is
+// called and the Leases are listed.
+func NewReplicaRegistry(leaseClient coordv1client.LeasesGetter, options ReplicaOptions) (*ReplicaRegistry, error) {
+	if len(options.Namespace) == 0 {
+		return nil, fmt.Errorf("the namespace of the replica leases is required")
+	}
+	if len(options.Identity) == 0 {
+		return nil, fmt.Errorf("the identity of the replica is required")
+	}
+	if len(options.Address) == 0 {
+		return nil, fmt.Errorf("the address of the replica is required")
+	}
+	if options.LeaseDuration <= 0 {
+		options.LeaseDuration = DefaultReplicaLeaseDuration
+	}
+
+	return &ReplicaRegistry{
+		options:     options,
+		leaseClient: leaseClient,
+		clock:       clock.RealClock{},
+	}, nil
+}
+
+// Peers returns the addresses of the other replicas holding a live Lease.
+func (r *ReplicaRegistry) Peers() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return slices.Clone(r.peers)
+}
+
+// Run renews the replica Lease and refreshes the peers periodically until the context is done. The
+// Lease is deleted on return so the other replicas stop forwarding the requests to this replica
+// without waiting for the Lease to expire.
+func (r *ReplicaRegistry) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithValues("replica", r.options.Identity)
+	logger.Info("Starting gRPC server replica registry", "address", r.options.Address)
+
+	wait.UntilWithContext(ctx, r.sync, r.options.LeaseDuration/3)
+
+	releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.release(releaseCtx); err != nil {
+		logger.Error(err, "Failed to release the replica lease")
+	}
+}
+
+func (r *ReplicaRegistry) sync(ctx context.Context) {
+	if err := r.renew(ctx); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to renew the replica lease: %w", err))
+	}
+	if err := r.refreshPeers(ctx); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to refresh the gRPC server replicas: %w", err))
+	}
+}
+
+func (r *ReplicaRegistry) leaseName() string {
+	return fmt.Sprintf("grpc-server-%s", r.options.Identity)
+}
+
+// renew creates or renews the Lease of the replica.
+func (r *ReplicaRegistry) renew(ctx context.Context) error {
+	leaseClient := r.leaseClient.Leases(r.options.Namespace)
+	now := metav1.NewMicroTime(r.clock.Now())
+	durationSeconds := int32(r.options.LeaseDuration.Seconds())
+
+	lease, err := leaseClient.Get(ctx, r.leaseName(), metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        r.leaseName(),
+				Namespace:   r.options.Namespace,
+				Labels:      map[string]string{ReplicaLabel: "true"},
+				Annotations: map[string]string{ReplicaAddressAnnotation: r.options.Address},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &r.options.Identity,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		_, err = leaseClient.Create(ctx, lease, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	}
+
+	lease = lease.DeepCopy()
+	if lease.Labels == nil {
+		lease.Labels = map[string]string{}
+	}
+	lease.Labels[ReplicaLabel] = "true"
+	if lease.Annotations == nil {
+		lease.Annotations = map[string]string{}
+	}
+	lease.Annotations[ReplicaAddressAnnotation] = r.options.Address
+	lease.Spec.HolderIdentity = &r.options.Identity
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.RenewTime = &now
+	_, err = leaseClient.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// refreshPeers lists the replica Leases and keeps the addresses of the other replicas with a live Lease.
+func (r *ReplicaRegistry) refreshPeers(ctx context.Context) error {
+	leases, err := r.leaseClient.Leases(r.options.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{ReplicaLabel: "true"}).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	now := r.clock.Now()
+	var peers []string
+	for _, lease := range leases.Items {
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		if *lease.Spec.HolderIdentity == r.options.Identity {
+			continue
+		}
+		address := lease.Annotations[ReplicaAddressAnnotation]
+		if len(address) == 0 || address == r.options.Address {
+			continue
+		}
+		duration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+		if now.Before(lease.Spec.RenewTime.Add(duration)) {
+			peers = append(peers, address)
+		}
+	}
+	slices.Sort(peers)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !slices.Equal(r.peers, peers) {
+		klog.FromContext(ctx).Info("gRPC server replicas changed", "replica", r.options.Identity, "peers", peers)
+	}
+	r.peers = peers
+	return nil
+}
+
+// release deletes the Lease of the replica.
+func (r *ReplicaRegistry) release(ctx context.Context) error {
+	err := r.leaseClient.Leases(r.options.Namespace).Delete(ctx, r.leaseName(), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
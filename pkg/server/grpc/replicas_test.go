@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+const replicaNamespace = "open-cluster-management-hub"
+
+func newTestRegistry(t *testing.T, kubeClient *kubefake.Clientset, clock *testingclock.FakeClock,
+	identity, address string) *ReplicaRegistry {
+	registry, err := NewReplicaRegistry(kubeClient.CoordinationV1(),
+		ReplicaOptions{Namespace: replicaNamespace, Identity: identity, Address: address})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clock != nil {
+		registry.clock = clock
+	}
+	return registry
+}
+
+func TestReplicaRegistry(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	clock := testingclock.NewFakeClock(time.Now())
+	registry1 := newTestRegistry(t, kubeClient, clock, "replica1", "10.0.0.1:8090")
+	registry2 := newTestRegistry(t, kubeClient, clock, "replica2", "10.0.0.2:8090")
+
+	// the replica has no peer when it is the only replica
+	registry1.sync(context.TODO())
+	if peers := registry1.Peers(); len(peers) != 0 {
+		t.Errorf("expect no peer, but got %v", peers)
+	}
+
+	// the replicas discover each other
+	registry2.sync(context.TODO())
+	registry1.sync(context.TODO())
+	if peers := registry1.Peers(); len(peers) != 1 || peers[0] != "10.0.0.2:8090" {
+		t.Errorf("expect peers [10.0.0.2:8090] of replica1, but got %v", peers)
+	}
+	if peers := registry2.Peers(); len(peers) != 1 || peers[0] != "10.0.0.1:8090" {
+		t.Errorf("expect peers [10.0.0.1:8090] of replica2, but got %v", peers)
+	}
+
+	// the address of the replica is updated
+	registry2.options.Address = "10.0.0.3:8090"
+	registry2.sync(context.TODO())
+	registry1.sync(context.TODO())
+	if peers := registry1.Peers(); len(peers) != 1 || peers[0] != "10.0.0.3:8090" {
+		t.Errorf("expect peers [10.0.0.3:8090] of replica1, but got %v", peers)
+	}
+
+	// the replica is no longer a peer once its lease expires
+	clock.Step(DefaultReplicaLeaseDuration + time.Second)
+	registry1.sync(context.TODO())
+	if peers := registry1.Peers(); len(peers) != 0 {
+		t.Errorf("expect no peer after replica2 expires, but got %v", peers)
+	}
+
+	// the lease is deleted on release
+	registry2.sync(context.TODO())
+	if err := registry2.release(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	registry1.sync(context.TODO())
+	if peers := registry1.Peers(); len(peers) != 0 {
+		t.Errorf("expect no peer after replica2 is released, but got %v", peers)
+	}
+	if err := registry2.release(context.TODO()); err != nil {
+		t.Errorf("expect no error to release a deleted lease, but got %v", err)
+	}
+
+	leases, err := kubeClient.CoordinationV1().Leases(replicaNamespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leases.Items) != 1 || leases.Items[0].Name != "grpc-server-replica1" {
+		t.Errorf("expect the lease of replica1 only, but got %d leases", len(leases.Items))
+	}
+}
+
+func TestNewReplicaRegistry(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	cases := []struct {
+		name    string
+		options ReplicaOptions
+	}{
+		{name: "missing namespace", options: ReplicaOptions{Identity: "replica1", Address: "10.0.0.1:8090"}},
+		{name: "missing identity", options: ReplicaOptions{Namespace: replicaNamespace, Address: "10.0.0.1:8090"}},
+		{name: "missing address", options: ReplicaOptions{Namespace: replicaNamespace, Identity: "replica1"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := NewReplicaRegistry(kubeClient.CoordinationV1(), c.options); err == nil {
+				t.Errorf("expect error, but got nil")
+			}
+		})
+	}
+
+	registry, err := NewReplicaRegistry(kubeClient.CoordinationV1(),
+		ReplicaOptions{Namespace: replicaNamespace, Identity: "replica1", Address: "10.0.0.1:8090"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registry.options.LeaseDuration != DefaultReplicaLeaseDuration {
+		t.Errorf("expect the default lease duration, but got %v", registry.options.LeaseDuration)
+	}
+}
@@ -0,0 +1,77 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
+	grpcprotocol "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protocol"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/server/grpc/authn"
+	"open-cluster-management.io/sdk-go/pkg/server/grpc/authz"
+)
+
+var agentEventsTopicRegex = regexp.MustCompile(types.MQTTAgentEventsTopicPattern)
+
+// Authenticator maps an event received from the MQTT broker to the identity of the agent which published
+// it, since the MQTT broker does not pass the client credentials to the subscribers.
+type Authenticator interface {
+	// Authenticate returns the cluster of the agent and a context with the user and groups of the agent,
+	// which are used to authorize the event, or an error if the event is not published by the agent of the
+	// cluster in its extension.
+	Authenticate(ctx context.Context, topic string, evt cloudevents.Event) (context.Context, string, error)
+}
+
+// TopicAuthenticator identifies the agent by the cluster of the agent events topic, e.g.
+// sources/hub/clusters/<cluster>/agentevents. The MQTT broker must only allow each client to publish to
+// the agent events topic of its own cluster with its ACLs, so the cluster of the topic is the identity of
+// the agent. The agent has the user system:open-cluster-management:<cluster>:agent in the group
+// system:open-cluster-management:<cluster>, which is bound to the agent permissions by the registration.
+type TopicAuthenticator struct{}
+
+var _ Authenticator = TopicAuthenticator{}
+
+func (TopicAuthenticator) Authenticate(
+	ctx context.Context, topic string, evt cloudevents.Event) (context.Context, string, error) {
+	matches := agentEventsTopicRegex.FindStringSubmatch(topic)
+	if len(matches) != 6 || matches[5] == "+" {
+		return ctx, "", fmt.Errorf("the event is not published to the agent events topic of a cluster: %s", topic)
+	}
+	clusterName := matches[5]
+
+	eventClusterName, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+	if err != nil {
+		return ctx, "", fmt.Errorf("failed to get clustername extension: %v", err)
+	}
+	if eventClusterName != clusterName {
+		return ctx, "", fmt.Errorf("the event of cluster %s is published to the topic of cluster %s",
+			eventClusterName, clusterName)
+	}
+
+	group := fmt.Sprintf("system:open-cluster-management:%s", clusterName)
+	ctx = context.WithValue(ctx, authn.ContextUserKey, fmt.Sprintf("%s:agent", group))
+	ctx = context.WithValue(ctx, authn.ContextGroupsKey, []string{group, "system:authenticated"})
+	return ctx, clusterName, nil
+}
+
+// authorizeEvent authorizes the event of the agent in the context with the same authorizer and request as
+// the events published to the gRPC server.
+func authorizeEvent(ctx context.Context, authorizer authz.UnaryAuthorizer, evt cloudevents.Event) error {
+	pbEvt := &pbv1.CloudEvent{}
+	if err := grpcprotocol.WritePBMessage(ctx, binding.ToMessage(&evt), pbEvt); err != nil {
+		return err
+	}
+	decision, err := authorizer.AuthorizeRequest(ctx, &pbv1.PublishRequest{Event: pbEvt})
+	if err != nil {
+		return err
+	}
+	if decision != authz.DecisionAllow {
+		return fmt.Errorf("the event %s is not allowed", evt.Type())
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	mqttoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/utils"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/server"
+	"open-cluster-management.io/sdk-go/pkg/server/grpc/authz"
+
+	"open-cluster-management.io/ocm/pkg/server/services"
+)
+
+// ReconnectInterval is the interval to reconnect to the MQTT broker after the connection is lost.
+var ReconnectInterval = 5 * time.Second
+
+// SubscriberExpiration is the duration after which a cluster is removed from the subscribers if its agent
+// has not published any event, since the disconnection of the agents is not known by the bridge.
+var SubscriberExpiration = 10 * time.Minute
+
+var _ server.AgentEventServer = &MQTTBroker{}
+
+// MQTTBroker bridges the services and the agents through an MQTT broker. It publishes the resource spec
+// events of the services to the source events topic of each cluster, and handles the resource status
+// updates and the spec resync requests published by the agents to the agent events topic. Each event is
+// authenticated by the topic it is published to and authorized against the permissions of the agent.
+type MQTTBroker struct {
+	transport transport
+	// hasBroadcast is true if the source broadcast topic is set to request the status of all agents
+	hasBroadcast  bool
+	authenticator Authenticator
+	authorizer    authz.UnaryAuthorizer
+	services      map[types.CloudEventsDataType]server.Service
+	// clusters is the last time each cluster has published an authorized event to the broker
+	clusters map[string]time.Time
+	clock    clock.Clock
+	mu       sync.Mutex
+}
+
+// NewMQTTBroker returns an MQTTBroker which connects to the MQTT broker with the client ID, and publishes
+// and subscribes to the topics of the source.
+func NewMQTTBroker(mqttOptions *mqttoptions.MQTTOptions, clientID, sourceID string,
+	authenticator Authenticator, authorizer authz.UnaryAuthorizer) *MQTTBroker {
+	return &MQTTBroker{
+		transport:     newSourceTransport(mqttOptions, clientID, sourceID),
+		hasBroadcast:  len(mqttOptions.Topics.SourceBroadcast) > 0,
+		authenticator: authenticator,
+		authorizer:    authorizer,
+		services:      make(map[types.CloudEventsDataType]server.Service),
+		clusters:      make(map[string]time.Time),
+		clock:         clock.RealClock{},
+	}
+}
+
+func (bkr *MQTTBroker) RegisterService(ctx context.Context, t types.CloudEventsDataType, service server.Service) {
+	bkr.services[t] = service
+	service.RegisterHandler(ctx, bkr)
+}
+
+// Subscribers returns the clusters which have published events to the broker in the subscriber
+// expiration, since the subscriptions of the agents are maintained by the MQTT broker. The expired
+// clusters are removed.
+func (bkr *MQTTBroker) Subscribers() sets.Set[string] {
+	bkr.mu.Lock()
+	defer bkr.mu.Unlock()
+
+	subscribers := sets.New[string]()
+	now := bkr.clock.Now()
+	for clusterName, lastSeen := range bkr.clusters {
+		if now.Sub(lastSeen) > SubscriberExpiration {
+			delete(bkr.clusters, clusterName)
+			continue
+		}
+		subscribers.Insert(clusterName)
+	}
+	return subscribers
+}
+
+// Run connects to the MQTT broker and handles the events of the agents until the context is done. It
+// reconnects to the MQTT broker when the connection is lost.
+func (bkr *MQTTBroker) Run(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+	for {
+		if err := bkr.receive(ctx); err != nil {
+			logger.Error(err, "mqtt connection is lost, reconnecting", "interval", ReconnectInterval)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(ReconnectInterval):
+		}
+	}
+}
+
+// receive connects and subscribes to the MQTT broker, and returns when the context is done or the
+// connection is lost.
+func (bkr *MQTTBroker) receive(ctx context.Context) error {
+	if err := bkr.transport.Connect(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := bkr.transport.Close(context.Background()); err != nil {
+			klog.FromContext(ctx).Error(err, "failed to close mqtt transport")
+		}
+	}()
+	if err := bkr.transport.Subscribe(ctx); err != nil {
+		return err
+	}
+
+	receiveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	receiveErr := make(chan error, 1)
+	go func() {
+		receiveErr <- bkr.transport.Receive(receiveCtx, bkr.handleEvent)
+	}()
+
+	// the status updates published while the bridge was disconnected are lost, so the status of all the
+	// agents is requested again.
+	bkr.resyncStatus(ctx)
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-bkr.transport.ErrorChan():
+		return err
+	case err := <-receiveErr:
+		if err == nil {
+			err = fmt.Errorf("mqtt receiver is stopped")
+		}
+		return err
+	}
+}
+
+// resyncStatus publishes a status resync request of each registered service to all the agents by the
+// source broadcast topic.
+func (bkr *MQTTBroker) resyncStatus(ctx context.Context) {
+	if !bkr.hasBroadcast {
+		return
+	}
+	logger := klog.FromContext(ctx)
+	for dataType := range bkr.services {
+		eventType := types.CloudEventsType{
+			CloudEventsDataType: dataType,
+			SubResource:         types.SubResourceStatus,
+			Action:              types.ResyncRequestAction,
+		}
+		evt := types.NewEventBuilder(services.CloudEventsSourceKube, eventType).WithClusterName(types.ClusterAll).NewEvent()
+		if err := evt.SetData(cloudevents.ApplicationJSON, &payload.ResourceStatusHashList{}); err != nil {
+			logger.Error(err, "failed to build status resync request", "eventDataType", dataType)
+			continue
+		}
+		if err := bkr.transport.Send(ctx, evt); err != nil {
+			logger.Error(err, "failed to send status resync request", "eventDataType", dataType)
+		}
+	}
+}
+
+// handleEvent handles a status update or a spec resync request of an agent once it is authenticated and
+// authorized. The errors are logged since they are not returned to the agent with MQTT.
+func (bkr *MQTTBroker) handleEvent(ctx context.Context, topic string, evt cloudevents.Event) {
+	logger := klog.FromContext(ctx).WithValues("topic", topic, "eventType", evt.Type(), "extensions", evt.Extensions())
+	logger.V(4).Info("receive the event with mqtt broker")
+
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		logger.Error(err, "failed to parse cloud event type")
+		return
+	}
+	authCtx, clusterName, err := bkr.authenticator.Authenticate(ctx, topic, evt)
+	if err != nil {
+		logger.Error(err, "failed to authenticate the event")
+		return
+	}
+	if err := authorizeEvent(authCtx, bkr.authorizer, evt); err != nil {
+		logger.Error(err, "failed to authorize the event")
+		return
+	}
+
+	bkr.mu.Lock()
+	bkr.clusters[clusterName] = bkr.clock.Now()
+	bkr.mu.Unlock()
+
+	if eventType.Action == types.ResyncRequestAction {
+		if err := bkr.respondResyncSpecRequest(ctx, eventType.CloudEventsDataType, &evt); err != nil {
+			logger.Error(err, "failed to respond resync spec request")
+		}
+		return
+	}
+
+	service, ok := bkr.services[eventType.CloudEventsDataType]
+	if !ok {
+		logger.Error(nil, "failed to find service for event type", "eventDataType", eventType.CloudEventsDataType)
+		return
+	}
+	if err := service.HandleStatusUpdate(ctx, &evt); err != nil {
+		logger.Error(err, "failed to handle status update")
+	}
+}
+
+// respondResyncSpecRequest responds the spec resync request of an agent in the same way as the gRPC
+// broker:
+//   - all the resources of the cluster are sent if the request has no resource versions.
+//   - a resource is sent if it is deleting, or its version is newer than the version in the request.
+//   - a delete event is sent for a resource in the request which does not exist in the service.
+func (bkr *MQTTBroker) respondResyncSpecRequest(
+	ctx context.Context, eventDataType types.CloudEventsDataType, evt *cloudevents.Event) error {
+	logger := klog.FromContext(ctx)
+
+	resourceVersions, err := payload.DecodeSpecResyncRequest(*evt)
+	if err != nil {
+		return err
+	}
+	clusterName, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+	if err != nil {
+		return err
+	}
+	service, ok := bkr.services[eventDataType]
+	if !ok {
+		return fmt.Errorf("failed to find service for event type %s", eventDataType)
+	}
+
+	evts, err := service.List(ctx, types.ListOptions{ClusterName: clusterName, CloudEventsDataType: eventDataType})
+	if err != nil {
+		return err
+	}
+
+	respEventType := types.CloudEventsType{
+		CloudEventsDataType: eventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncResponseAction,
+	}
+	resourceIDs := sets.New[string]()
+	for _, evt := range evts {
+		evt.SetType(respEventType.String())
+		resourceID, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionResourceID])
+		if err != nil {
+			logger.Error(err, "failed to get resourceid extension", "extensions", evt.Extensions())
+			continue
+		}
+		resourceIDs.Insert(resourceID)
+
+		if _, deleting := evt.Extensions()[types.ExtensionDeletionTimestamp]; !deleting {
+			currentVersion, err := utils.GetResourceVersionFromEvent(respEventType, *evt)
+			if err != nil {
+				logger.V(4).Info("ignore the event since it has an invalid resourceVersion", "error", err)
+				continue
+			}
+			if currentVersion != 0 && currentVersion <= findResourceVersion(resourceID, resourceVersions.Versions) {
+				continue
+			}
+		}
+
+		if err := bkr.HandleEvent(ctx, evt); err != nil {
+			logger.Error(err, "failed to respond resync spec request", "resourceID", resourceID)
+		}
+	}
+
+	// the resources do not exist on the source, but exist on the agent, delete them
+	for _, rv := range resourceVersions.Versions {
+		if resourceIDs.Has(rv.ResourceID) {
+			continue
+		}
+		deleteEvt := types.NewEventBuilder(services.CloudEventsSourceKube, respEventType).
+			WithResourceID(rv.ResourceID).
+			WithClusterName(clusterName).
+			WithDeletionTimestamp(time.Now()).
+			NewEvent()
+		if err := bkr.HandleEvent(ctx, &deleteEvt); err != nil {
+			logger.Error(err, "failed to respond resync spec request", "resourceID", rv.ResourceID)
+		}
+	}
+	return nil
+}
+
+// HandleEvent publishes the spec event of a service to the source events topic of the cluster.
+func (bkr *MQTTBroker) HandleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	if evt == nil {
+		return fmt.Errorf("event is nil")
+	}
+	return bkr.transport.Send(ctx, *evt)
+}
+
+// findResourceVersion returns the resource version for the given ID from the list of resource versions.
+func findResourceVersion(id string, versions []payload.ResourceVersion) int64 {
+	for _, version := range versions {
+		if id == version.ResourceID {
+			return version.ResourceVersion
+		}
+	}
+	return 0
+}
@@ -0,0 +1,366 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	testingclock "k8s.io/utils/clock/testing"
+
+	workpayload "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/work/payload"
+	mqttoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+	mqttv2 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/v2/mqtt"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/server"
+	grpcauthz "open-cluster-management.io/sdk-go/pkg/cloudevents/server/grpc/authz/kube"
+
+	"open-cluster-management.io/ocm/pkg/server/services"
+)
+
+var testDataType = workpayload.ManifestBundleEventDataType
+
+// newTestAuthorizer returns a SAR authorizer which only allows the agent of cluster1.
+func newTestAuthorizer() *grpcauthz.SARAuthorizer {
+	kubeClient := kubefake.NewClientset()
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sar := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		sar.Status.Allowed = sar.Spec.ResourceAttributes.Namespace == "cluster1" &&
+			slices.Contains(sar.Spec.Groups, "system:open-cluster-management:cluster1")
+		return true, sar, nil
+	})
+	return grpcauthz.NewSARAuthorizer(kubeClient)
+}
+
+// allowHook allows all the clients of the embedded broker.
+type allowHook struct {
+	mochi.HookBase
+}
+
+func (h *allowHook) ID() string {
+	return "allow-all"
+}
+
+func (h *allowHook) Provides(b byte) bool {
+	return b == mochi.OnConnectAuthenticate || b == mochi.OnACLCheck
+}
+
+func (h *allowHook) OnConnectAuthenticate(_ *mochi.Client, _ packets.Packet) bool {
+	return true
+}
+
+func (h *allowHook) OnACLCheck(_ *mochi.Client, _ string, _ bool) bool {
+	return true
+}
+
+// startEmbeddedBroker starts an MQTT broker in the process and returns its address.
+func startEmbeddedBroker(t *testing.T) string {
+	broker := mochi.New(&mochi.Options{})
+	if err := broker.AddHook(new(allowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	listener := listeners.NewTCP(listeners.Config{ID: "test", Address: "127.0.0.1:0"})
+	if err := broker.AddListener(listener); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		if err := broker.Serve(); err != nil {
+			t.Errorf("failed to serve the embedded broker: %v", err)
+		}
+	}()
+	t.Cleanup(func() { _ = broker.Close() })
+	return listener.Address()
+}
+
+type fakeService struct {
+	mu            sync.Mutex
+	handler       server.EventHandler
+	evts          []*cloudevents.Event
+	statusUpdates chan *cloudevents.Event
+}
+
+func (s *fakeService) List(_ context.Context, _ types.ListOptions) ([]*cloudevents.Event, error) {
+	var evts []*cloudevents.Event
+	for _, evt := range s.evts {
+		copied := evt.Clone()
+		evts = append(evts, &copied)
+	}
+	return evts, nil
+}
+
+func (s *fakeService) HandleStatusUpdate(_ context.Context, evt *cloudevents.Event) error {
+	s.statusUpdates <- evt
+	return nil
+}
+
+func (s *fakeService) RegisterHandler(_ context.Context, handler server.EventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = handler
+}
+
+func newTestEvent(action types.EventAction, subResource types.EventSubResource, resourceID string) cloudevents.Event {
+	return newTestClusterEvent("cluster1", action, subResource, resourceID)
+}
+
+func newTestClusterEvent(clusterName string, action types.EventAction, subResource types.EventSubResource,
+	resourceID string) cloudevents.Event {
+	evt := types.NewEventBuilder(services.CloudEventsSourceKube, types.CloudEventsType{
+		CloudEventsDataType: testDataType,
+		SubResource:         subResource,
+		Action:              action,
+	}).WithClusterName(clusterName).WithResourceID(resourceID).WithOriginalSource(services.CloudEventsSourceKube).NewEvent()
+	evt.SetExtension(types.ExtensionResourceVersion, "2")
+	return evt
+}
+
+func receiveEvent(t *testing.T, evts <-chan cloudevents.Event, expectedType types.CloudEventsType) cloudevents.Event {
+	select {
+	case evt := <-evts:
+		if evt.Type() != expectedType.String() {
+			t.Fatalf("expected event %s, but got %s", expectedType, evt.Type())
+		}
+		return evt
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timeout to receive the event %s", expectedType)
+	}
+	return cloudevents.Event{}
+}
+
+func TestMQTTBroker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	brokerHost := startEmbeddedBroker(t)
+	topics := types.Topics{
+		SourceEvents:    "sources/hub/clusters/+/sourceevents",
+		AgentEvents:     "sources/hub/clusters/+/agentevents",
+		SourceBroadcast: "sources/+/sourcebroadcast",
+	}
+
+	// start the agent before the bridge to receive the status resync request of the bridge
+	agentTransport := mqttv2.NewAgentOptions(&mqttoptions.MQTTOptions{
+		Topics:    topics,
+		KeepAlive: 60,
+		PubQoS:    1,
+		SubQoS:    1,
+		Dialer:    &mqttoptions.MQTTDialer{BrokerHost: brokerHost, Timeout: 5 * time.Second},
+	}, "cluster1", "agent1").CloudEventsTransport
+	if err := agentTransport.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := agentTransport.Subscribe(ctx); err != nil {
+		t.Fatal(err)
+	}
+	agentEvents := make(chan cloudevents.Event, 10)
+	go func() {
+		_ = agentTransport.Receive(ctx, func(_ context.Context, evt cloudevents.Event) {
+			agentEvents <- evt
+		})
+	}()
+
+	configFile := filepath.Join(t.TempDir(), "mqtt.yaml")
+	config := fmt.Sprintf("brokerHost: %s\ntopics:\n  sourceEvents: %s\n  agentEvents: %s\n  sourceBroadcast: %s\n",
+		brokerHost, topics.SourceEvents, topics.AgentEvents, topics.SourceBroadcast)
+	if err := os.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := NewMQTTServerOptions()
+	opts.MQTTConfig = configFile
+	bridge, err := opts.NewBroker(newTestAuthorizer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	specEvt := newTestEvent(types.CreateRequestAction, types.SubResourceSpec, "r1")
+	service := &fakeService{evts: []*cloudevents.Event{&specEvt}, statusUpdates: make(chan *cloudevents.Event, 10)}
+	bridge.RegisterService(ctx, testDataType, service)
+	go func() {
+		if err := bridge.Run(ctx); err != nil {
+			t.Errorf("failed to run the bridge: %v", err)
+		}
+	}()
+
+	// the bridge requests the status of all the agents once it is connected
+	evt := receiveEvent(t, agentEvents, types.CloudEventsType{
+		CloudEventsDataType: testDataType, SubResource: types.SubResourceStatus, Action: types.ResyncRequestAction})
+	if evt.Source() != services.CloudEventsSourceKube {
+		t.Errorf("expected the status resync request from source %s, but got %s", services.CloudEventsSourceKube, evt.Source())
+	}
+
+	// the status update of the agent is handled by the service
+	if err := agentTransport.Send(ctx, newTestEvent(types.UpdateRequestAction, types.SubResourceStatus, "r1")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case evt := <-service.statusUpdates:
+		if resourceID, _ := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionResourceID]); resourceID != "r1" {
+			t.Errorf("expected the status update of r1, but got %s", resourceID)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout to receive the status update")
+	}
+	if !bridge.Subscribers().Has("cluster1") {
+		t.Errorf("expected cluster1 in the subscribers, but got %v", bridge.Subscribers())
+	}
+
+	// the spec event of the service is published to the agent
+	service.mu.Lock()
+	handler := service.handler
+	service.mu.Unlock()
+	if err := handler.HandleEvent(ctx, &specEvt); err != nil {
+		t.Fatal(err)
+	}
+	receiveEvent(t, agentEvents, types.CloudEventsType{
+		CloudEventsDataType: testDataType, SubResource: types.SubResourceSpec, Action: types.CreateRequestAction})
+
+	// the spec resync request of the agent is responded with the newer resources and the deleted resources
+	resyncRequest := types.NewEventBuilder(services.CloudEventsSourceKube, types.CloudEventsType{
+		CloudEventsDataType: testDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncRequestAction,
+	}).WithClusterName("cluster1").WithOriginalSource(types.SourceAll).NewEvent()
+	if err := resyncRequest.SetData(cloudevents.ApplicationJSON, &payload.ResourceVersionList{
+		Versions: []payload.ResourceVersion{{ResourceID: "r1", ResourceVersion: 1}, {ResourceID: "r2", ResourceVersion: 1}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := agentTransport.Send(ctx, resyncRequest); err != nil {
+		t.Fatal(err)
+	}
+	resyncResponse := types.CloudEventsType{
+		CloudEventsDataType: testDataType, SubResource: types.SubResourceSpec, Action: types.ResyncResponseAction}
+	for _, expected := range []struct {
+		resourceID string
+		deleting   bool
+	}{{resourceID: "r1"}, {resourceID: "r2", deleting: true}} {
+		evt := receiveEvent(t, agentEvents, resyncResponse)
+		resourceID, _ := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionResourceID])
+		_, deleting := evt.Extensions()[types.ExtensionDeletionTimestamp]
+		if resourceID != expected.resourceID || deleting != expected.deleting {
+			t.Errorf("expected resync response of %s with deleting %v, but got %s with deleting %v",
+				expected.resourceID, expected.deleting, resourceID, deleting)
+		}
+	}
+}
+
+func TestHandleEvent(t *testing.T) {
+	statusUpdate := newTestEvent(types.UpdateRequestAction, types.SubResourceStatus, "r1")
+	cases := []struct {
+		name             string
+		topic            string
+		evt              cloudevents.Event
+		expectedHandled  bool
+		expectedClusters []string
+	}{
+		{
+			name:             "authorized status update",
+			topic:            "sources/hub/clusters/cluster1/agentevents",
+			evt:              statusUpdate,
+			expectedHandled:  true,
+			expectedClusters: []string{"cluster1"},
+		},
+		{
+			name:  "status update of another cluster",
+			topic: "sources/hub/clusters/cluster2/agentevents",
+			evt:   statusUpdate,
+		},
+		{
+			name:  "status update published to the source events topic",
+			topic: "sources/hub/clusters/cluster1/sourceevents",
+			evt:   statusUpdate,
+		},
+		{
+			name:  "unauthorized status update",
+			topic: "sources/hub/clusters/cluster2/agentevents",
+			evt:   newTestClusterEvent("cluster2", types.UpdateRequestAction, types.SubResourceStatus, "r1"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			service := &fakeService{statusUpdates: make(chan *cloudevents.Event, 1)}
+			bkr := &MQTTBroker{
+				authenticator: TopicAuthenticator{},
+				authorizer:    newTestAuthorizer(),
+				services:      map[types.CloudEventsDataType]server.Service{testDataType: service},
+				clusters:      make(map[string]time.Time),
+				clock:         testingclock.NewFakeClock(time.Now()),
+			}
+
+			bkr.handleEvent(context.Background(), c.topic, c.evt)
+			if handled := len(service.statusUpdates) == 1; handled != c.expectedHandled {
+				t.Errorf("expected the status update handled %v, but got %v", c.expectedHandled, handled)
+			}
+			if subscribers := sets.List(bkr.Subscribers()); !slices.Equal(subscribers, c.expectedClusters) {
+				t.Errorf("expected subscribers %v, but got %v", c.expectedClusters, subscribers)
+			}
+		})
+	}
+}
+
+func TestSubscribersExpiration(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	bkr := &MQTTBroker{
+		clusters: map[string]time.Time{
+			"cluster1": fakeClock.Now(),
+			"cluster2": fakeClock.Now().Add(-SubscriberExpiration - time.Second),
+		},
+		clock: fakeClock,
+	}
+
+	if subscribers := sets.List(bkr.Subscribers()); !slices.Equal(subscribers, []string{"cluster1"}) {
+		t.Errorf("expected subscribers [cluster1], but got %v", subscribers)
+	}
+	if _, ok := bkr.clusters["cluster2"]; ok {
+		t.Errorf("expected the expired cluster2 to be removed")
+	}
+
+	fakeClock.Step(SubscriberExpiration + time.Second)
+	if subscribers := bkr.Subscribers(); subscribers.Len() != 0 {
+		t.Errorf("expected no subscribers, but got %v", sets.List(subscribers))
+	}
+}
+
+func TestMQTTServerOptionsValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		opts        *MQTTServerOptions
+		expectedErr bool
+	}{
+		{
+			name:        "no config file",
+			opts:        NewMQTTServerOptions(),
+			expectedErr: true,
+		},
+		{
+			name:        "no source id",
+			opts:        &MQTTServerOptions{MQTTConfig: "mqtt.yaml"},
+			expectedErr: true,
+		},
+		{
+			name: "valid",
+			opts: &MQTTServerOptions{MQTTConfig: "mqtt.yaml", SourceID: "hub"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.opts.Validate(); (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	"github.com/spf13/pflag"
+
+	csrce "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/csr"
+	sace "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/serviceaccount"
+	mqttoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+	grpcauthz "open-cluster-management.io/sdk-go/pkg/cloudevents/server/grpc/authz/kube"
+	"open-cluster-management.io/sdk-go/pkg/server/grpc/authz"
+
+	"open-cluster-management.io/ocm/pkg/server/grpc"
+)
+
+type MQTTServerOptions struct {
+	// MQTTConfig is the MQTT config file of the sdk-go with the broker host, the credentials and the topics.
+	// The source events and agent events topics should have the source ID, e.g.
+	// sources/<source-id>/clusters/+/sourceevents and sources/<source-id>/clusters/+/agentevents.
+	MQTTConfig string
+	// SourceID is the source ID in the topics.
+	SourceID string
+	// ClientID is the MQTT client ID, which defaults to the source ID appended with the host name.
+	ClientID string
+}
+
+func NewMQTTServerOptions() *MQTTServerOptions {
+	return &MQTTServerOptions{
+		SourceID: "hub",
+	}
+}
+
+func (o *MQTTServerOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.MQTTConfig, "mqtt-config", o.MQTTConfig, "Location of the MQTT broker configuration file.")
+	fs.StringVar(&o.SourceID, "source-id", o.SourceID, "The source ID in the MQTT topics.")
+	fs.StringVar(&o.ClientID, "client-id", o.ClientID,
+		"The MQTT client ID. It defaults to the source ID appended with the host name if it is not set.")
+}
+
+func (o *MQTTServerOptions) Validate() error {
+	if len(o.MQTTConfig) == 0 {
+		return fmt.Errorf("the MQTT config file is required")
+	}
+	if len(o.SourceID) == 0 {
+		return fmt.Errorf("the source ID is required")
+	}
+	return nil
+}
+
+// NewBroker returns an MQTTBroker with the MQTT config file, which authorizes the events of the agents
+// with the authorizer.
+func (o *MQTTServerOptions) NewBroker(authorizer authz.UnaryAuthorizer) (*MQTTBroker, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	mqttOptions, err := mqttoptions.BuildMQTTOptionsFromFlags(o.MQTTConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID := o.ClientID
+	if len(clientID) == 0 {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		clientID = fmt.Sprintf("%s-%s", o.SourceID, hostname)
+	}
+	return NewMQTTBroker(mqttOptions, clientID, o.SourceID, TopicAuthenticator{}, authorizer), nil
+}
+
+// Run registers the same services as the gRPC server against the MQTT broker, so the agents with the
+// mqtt driver are served without a source controller. The CSR and token request services are not
+// registered, since the agents are authenticated by the MQTT broker rather than the hub.
+func (o *MQTTServerOptions) Run(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+	clients, err := grpc.NewClients(controllerContext)
+	if err != nil {
+		return err
+	}
+
+	broker, err := o.NewBroker(grpcauthz.NewSARAuthorizer(clients.KubeClient))
+	if err != nil {
+		return err
+	}
+	clients.RegisterServices(ctx, broker, csrce.CSREventDataType, sace.TokenRequestDataType)
+
+	// start clients
+	go clients.Run(ctx)
+
+	return broker.Run(ctx)
+}
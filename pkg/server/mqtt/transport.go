@@ -0,0 +1,197 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	cloudeventsmqtt "github.com/cloudevents/sdk-go/protocol/mqtt_paho/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/eclipse/paho.golang/paho"
+	"k8s.io/klog/v2"
+
+	mqttoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+)
+
+// receiveHandlerFn handles an event received from the topic.
+type receiveHandlerFn func(ctx context.Context, topic string, evt cloudevents.Event)
+
+// transport is the MQTT transport of the bridge.
+type transport interface {
+	Connect(ctx context.Context) error
+	Send(ctx context.Context, evt cloudevents.Event) error
+	Subscribe(ctx context.Context) error
+	Receive(ctx context.Context, handleFn receiveHandlerFn) error
+	ErrorChan() <-chan error
+	Close(ctx context.Context) error
+}
+
+// sourceTransport is the same as the v2 MQTT source transport of the sdk-go, except that the events are
+// received with the topics they are published to, which identify the clusters of the agents.
+type sourceTransport struct {
+	opts     *mqttoptions.MQTTOptions
+	clientID string
+	sourceID string
+
+	mu         sync.RWMutex
+	subscribed bool
+	closeChan  chan struct{}
+	errorChan  chan error
+	msgChan    chan *paho.Publish
+	client     *paho.Client
+}
+
+func newSourceTransport(opts *mqttoptions.MQTTOptions, clientID, sourceID string) *sourceTransport {
+	return &sourceTransport{
+		opts:      opts,
+		clientID:  clientID,
+		sourceID:  sourceID,
+		errorChan: make(chan error, 1),
+	}
+}
+
+func (t *sourceTransport) Connect(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	logger := klog.FromContext(ctx)
+	conn, err := t.opts.Dialer.Dial()
+	if err != nil {
+		return err
+	}
+
+	t.client = paho.NewClient(paho.ClientConfig{
+		ClientID: t.clientID,
+		Conn:     conn,
+		OnClientError: func(err error) {
+			select {
+			case t.errorChan <- err:
+			default:
+				logger.Error(err, "mqtt client error")
+			}
+		},
+	})
+	t.client.SetDebugLogger(mqttoptions.NewPahoDebugLogger(logger))
+	t.client.SetErrorLogger(mqttoptions.NewPahoErrorLogger(logger))
+
+	connAck, err := t.client.Connect(ctx, t.opts.GetMQTTConnectOption(t.clientID))
+	if err != nil {
+		return err
+	}
+	if connAck.ReasonCode != 0 {
+		return fmt.Errorf("failed to establish the connection: %s", connAck.String())
+	}
+
+	t.closeChan = make(chan struct{})
+	t.msgChan = make(chan *paho.Publish, 100)
+	logger.Info("mqtt is connected", "brokerHost", t.opts.Dialer.BrokerHost)
+	return nil
+}
+
+func (t *sourceTransport) Send(ctx context.Context, evt cloudevents.Event) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.client == nil {
+		return fmt.Errorf("transport not connected")
+	}
+	topic, err := mqttoptions.SourcePubTopic(ctx, t.opts, t.sourceID, evt.Context)
+	if err != nil {
+		return err
+	}
+	msg := &paho.Publish{QoS: byte(t.opts.PubQoS), Topic: topic}
+	if err := cloudeventsmqtt.WritePubMessage(ctx, (*binding.EventMessage)(&evt), msg); err != nil {
+		return err
+	}
+	_, err = t.client.Publish(ctx, msg)
+	return err
+}
+
+func (t *sourceTransport) Subscribe(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client == nil {
+		return fmt.Errorf("transport not connected")
+	}
+	if t.subscribed {
+		return fmt.Errorf("transport has already subscribed")
+	}
+	subscribe, err := mqttoptions.SourceSubscribe(t.opts, t.sourceID)
+	if err != nil {
+		return err
+	}
+
+	closeChan, msgChan := t.closeChan, t.msgChan
+	t.client.AddOnPublishReceived(func(m paho.PublishReceived) (bool, error) {
+		select {
+		case msgChan <- m.Packet:
+			return true, nil
+		case <-closeChan:
+			return false, fmt.Errorf("transport closed")
+		}
+	})
+	if _, err := t.client.Subscribe(ctx, subscribe); err != nil {
+		return err
+	}
+	t.subscribed = true
+
+	logger := klog.FromContext(ctx)
+	for _, sub := range subscribe.Subscriptions {
+		logger.Info("subscribed to mqtt broker", "topic", sub.Topic, "QoS", sub.QoS)
+	}
+	return nil
+}
+
+// Receive invokes the handler with each event received and its topic until the context is done or the
+// transport is closed.
+func (t *sourceTransport) Receive(ctx context.Context, handleFn receiveHandlerFn) error {
+	t.mu.RLock()
+	if !t.subscribed {
+		t.mu.RUnlock()
+		return fmt.Errorf("transport not subscribed")
+	}
+	closeChan, msgChan := t.closeChan, t.msgChan
+	t.mu.RUnlock()
+
+	logger := klog.FromContext(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-closeChan:
+			return nil
+		case m := <-msgChan:
+			evt, err := binding.ToEvent(ctx, cloudeventsmqtt.NewMessage(m))
+			if err != nil {
+				logger.Error(err, "invalid event", "topic", m.Topic)
+				continue
+			}
+			handleFn(ctx, m.Topic, *evt)
+		}
+	}
+}
+
+func (t *sourceTransport) ErrorChan() <-chan error {
+	return t.errorChan
+}
+
+func (t *sourceTransport) Close(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	klog.FromContext(ctx).Info("close mqtt transport")
+
+	if t.client == nil {
+		return nil
+	}
+	if t.closeChan != nil {
+		select {
+		case <-t.closeChan:
+		default:
+			close(t.closeChan)
+		}
+	}
+	t.subscribed = false
+	return t.client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+}
@@ -11,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -850,3 +851,48 @@ func TestNameMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestGetHook(t *testing.T) {
+	withHook := func(apiVersion, kind, hook string) *unstructured.Unstructured {
+		obj := testingcommon.NewUnstructured(apiVersion, kind, "ns1", "test")
+		obj.SetAnnotations(map[string]string{HookAnnotation: hook})
+		return obj
+	}
+	cases := []struct {
+		name         string
+		obj          *unstructured.Unstructured
+		expectedHook string
+		expectedErr  bool
+	}{
+		{
+			name: "not a hook",
+			obj:  testingcommon.NewUnstructured("batch/v1", "Job", "ns1", "test"),
+		},
+		{
+			name:         "pre-delete hook",
+			obj:          withHook("batch/v1", "Job", HookPreDelete),
+			expectedHook: HookPreDelete,
+		},
+		{
+			name:        "invalid hook",
+			obj:         withHook("batch/v1", "Job", "post-delete"),
+			expectedErr: true,
+		},
+		{
+			name:        "hook which is not a job",
+			obj:         withHook("apps/v1", "Deployment", HookPreApply),
+			expectedErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hook, err := GetHook(c.obj)
+			if (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, got %v", c.expectedErr, err)
+			}
+			if hook != c.expectedHook {
+				t.Errorf("expected hook %q, got %q", c.expectedHook, hook)
+			}
+		})
+	}
+}
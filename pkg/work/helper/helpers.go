@@ -36,6 +36,28 @@ const (
 	// unknownKind is returned by resourcehelper.GuessObjectGroupVersionKind() when it
 	// cannot tell the kind of the given object
 	unknownKind = "<unknown>"
+
+	// DriftDetectionAnnotation is the annotation of the ManifestWork to set the drift detection mode.
+	// With the mode Audit, the differences between the resources and the manifests are reported by the
+	// Drifted condition of each manifest. The mode does not change the update strategy of the manifests,
+	// so the drifts are only kept on the cluster with the strategy CreateOnly or ReadOnly.
+	DriftDetectionAnnotation = "work.open-cluster-management.io/drift-detection"
+	// DriftDetectionAudit is the drift detection mode which reports the drifts without correcting them.
+	DriftDetectionAudit = "Audit"
+
+	// ManifestDrifted is the condition type of a manifest whose resource diverges from the manifest.
+	ManifestDrifted = "Drifted"
+
+	// HookAnnotation marks a Job in the manifests as a lifecycle hook of the ManifestWork. A pre-apply
+	// hook is applied before the other manifests, which are applied once the hook is complete. A
+	// post-apply hook is applied after all the other manifests are ready. A pre-delete hook is not
+	// applied with the other manifests, and it runs to complete when the ManifestWork is deleted before
+	// the applied resources are removed, or until the pre-delete hook timeout of the work agent expires.
+	// A completed hook is not run again unless it is renamed.
+	HookAnnotation = "work.open-cluster-management.io/hook"
+	HookPreApply   = "pre-apply"
+	HookPostApply  = "post-apply"
+	HookPreDelete  = "pre-delete"
 )
 
 var (
@@ -46,6 +68,44 @@ func init() {
 	_ = apiextensionsv1.AddToScheme(genericScheme)
 }
 
+// IsDriftAuditMode returns if the drifts of the resources in the ManifestWork are audited instead of
+// being corrected.
+func IsDriftAuditMode(work *workapiv1.ManifestWork) bool {
+	return work.Annotations[DriftDetectionAnnotation] == DriftDetectionAudit
+}
+
+// GetHook returns the lifecycle hook of the manifest set by the HookAnnotation, or an empty string if
+// the manifest is not a hook. Only a batch/v1 Job could be a hook.
+func GetHook(obj *unstructured.Unstructured) (string, error) {
+	hook, ok := obj.GetAnnotations()[HookAnnotation]
+	if !ok {
+		return "", nil
+	}
+	switch hook {
+	case HookPreApply, HookPostApply, HookPreDelete:
+	default:
+		return "", fmt.Errorf("invalid value %q of the annotation %s: it should be one of %s, %s and %s",
+			hook, HookAnnotation, HookPreApply, HookPostApply, HookPreDelete)
+	}
+	if obj.GroupVersionKind() != (schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}) {
+		return "", fmt.Errorf("invalid annotation %s: only a batch/v1 Job could be a hook, but it is %s",
+			HookAnnotation, obj.GroupVersionKind())
+	}
+	return hook, nil
+}
+
+// IsJobFailed returns if the Job has the Failed condition.
+func IsJobFailed(obj *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == "Failed" && condition["status"] == string(metav1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
+
 // MergeManifestConditions return a new ManifestCondition array which merges the existing manifest
 // conditions and the new manifest conditions. Rules to match ManifestCondition between two arrays:
 // 1. match the manifest condition with the whole ManifestResourceMeta;
@@ -0,0 +1,193 @@
+package clustertemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	// ClusterTemplateAnnotation enables the per-cluster templating of the manifests of the
+	// ManifestWorkReplicaSet when it is "true".
+	ClusterTemplateAnnotation = "work.open-cluster-management.io/cluster-template"
+
+	// TemplateValuesAnnotation is the annotation of the ManifestWorkReplicaSet with the values of the
+	// templates, in json or yaml.
+	TemplateValuesAnnotation = "work.open-cluster-management.io/template-values"
+
+	leftDelimiter = "{{"
+)
+
+// Data is the data to render the templates for a cluster.
+type Data struct {
+	ClusterName        string
+	ClusterLabels      map[string]string
+	ClusterAnnotations map[string]string
+	ClusterClaims      map[string]string
+	Values             map[string]interface{}
+}
+
+// NewData returns the template data of the cluster with the values.
+func NewData(cluster *clusterv1.ManagedCluster, values map[string]interface{}) Data {
+	data := Data{
+		ClusterName:        cluster.Name,
+		ClusterLabels:      cluster.Labels,
+		ClusterAnnotations: cluster.Annotations,
+		ClusterClaims:      map[string]string{},
+		Values:             values,
+	}
+	for _, claim := range cluster.Status.ClusterClaims {
+		data.ClusterClaims[claim.Name] = claim.Value
+	}
+	return data
+}
+
+// funcMap returns the repeatable sprig functions. The functions reading the environment of the hub
+// controller are removed, so the templates could not read the credentials or the configurations of it.
+func funcMap() template.FuncMap {
+	funcs := sprig.HermeticTxtFuncMap()
+	delete(funcs, "env")
+	delete(funcs, "expandenv")
+	return funcs
+}
+
+// Parse parses the template with the hermetic sprig functions. A missing key in the data is an error in
+// the strict mode, otherwise it is rendered as the zero value.
+func Parse(name, text string, strict bool) (*template.Template, error) {
+	missingKey := "missingkey=zero"
+	if strict {
+		missingKey = "missingkey=error"
+	}
+	return template.New(name).Funcs(funcMap()).Option(missingKey).Parse(text)
+}
+
+// Execute renders the template with the data.
+func Execute(tmpl *template.Template, data Data) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Enabled returns if the per-cluster templating is enabled in the annotations.
+func Enabled(annotations map[string]string) bool {
+	return annotations[ClusterTemplateAnnotation] == "true"
+}
+
+// GetValues returns the template values in the annotations.
+func GetValues(annotations map[string]string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	value, ok := annotations[TemplateValuesAnnotation]
+	if !ok {
+		return values, nil
+	}
+	if err := yaml.Unmarshal([]byte(value), &values); err != nil {
+		return nil, fmt.Errorf("invalid template values annotation: %v", err)
+	}
+	return values, nil
+}
+
+// ValidateManifests parses the templates in the manifests.
+func ValidateManifests(manifests []workapiv1.Manifest) error {
+	_, err := walkManifests(manifests, func(value string) (string, error) {
+		if _, err := Parse("manifest", value, true); err != nil {
+			return "", err
+		}
+		return value, nil
+	})
+	return err
+}
+
+// RenderManifests renders the templates in the manifests with the data of a cluster. Only the string
+// values of the manifests are templated, so a template could not change the structure or the type of
+// the fields.
+func RenderManifests(manifests []workapiv1.Manifest, data Data) ([]workapiv1.Manifest, error) {
+	return walkManifests(manifests, func(value string) (string, error) {
+		tmpl, err := Parse("manifest", value, true)
+		if err != nil {
+			return "", err
+		}
+		return Execute(tmpl, data)
+	})
+}
+
+// walkManifests calls the render func on each string value with templates in the manifests, and
+// returns the manifests with the rendered values. The manifests without templates are not changed.
+func walkManifests(manifests []workapiv1.Manifest, render func(value string) (string, error)) ([]workapiv1.Manifest, error) {
+	var result []workapiv1.Manifest
+	for i, manifest := range manifests {
+		if !bytes.Contains(manifest.Raw, []byte(leftDelimiter)) {
+			result = append(result, manifest)
+			continue
+		}
+
+		var obj interface{}
+		decoder := json.NewDecoder(bytes.NewReader(manifest.Raw))
+		// keep the numbers as they are
+		decoder.UseNumber()
+		if err := decoder.Decode(&obj); err != nil {
+			return nil, fmt.Errorf("failed to decode manifest %d: %w", i, err)
+		}
+		rendered, err := walk(fmt.Sprintf("manifests[%d]", i), obj, render)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(rendered)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, workapiv1.Manifest{RawExtension: runtime.RawExtension{Raw: raw}})
+	}
+	return result, nil
+}
+
+func walk(path string, obj interface{}, render func(value string) (string, error)) (interface{}, error) {
+	switch value := obj.(type) {
+	case map[string]interface{}:
+		// walk the fields in order so the first invalid template is reported
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			rendered, err := walk(path+"."+k, value[k], render)
+			if err != nil {
+				return nil, err
+			}
+			value[k] = rendered
+		}
+		return value, nil
+	case []interface{}:
+		for i, v := range value {
+			rendered, err := walk(fmt.Sprintf("%s[%d]", path, i), v, render)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = rendered
+		}
+		return value, nil
+	case string:
+		if !strings.Contains(value, leftDelimiter) {
+			return value, nil
+		}
+		rendered, err := render(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template in %s: %w", path, err)
+		}
+		return rendered, nil
+	default:
+		return value, nil
+	}
+}
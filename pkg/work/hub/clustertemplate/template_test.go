@@ -0,0 +1,159 @@
+package clustertemplate
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newManifest(raw string) workapiv1.Manifest {
+	return workapiv1.Manifest{RawExtension: runtime.RawExtension{Raw: []byte(raw)}}
+}
+
+func TestRenderManifests(t *testing.T) {
+	cluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cluster1",
+			Labels:      map[string]string{"region": "east"},
+			Annotations: map[string]string{"example.com/domain": "apps.example.com"},
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: "platform.open-cluster-management.io", Value: "AWS"}},
+		},
+	}
+	values := map[string]interface{}{
+		"registries": map[string]interface{}{"east": "east.registry.io", "west": "west.registry.io"},
+	}
+
+	cases := []struct {
+		name        string
+		manifest    string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "no template",
+			manifest: `{"kind": "ConfigMap", "data": {"replicas": 3}}`,
+			expected: `{"kind": "ConfigMap", "data": {"replicas": 3}}`,
+		},
+		{
+			name: "cluster name, labels, annotations and claims",
+			manifest: `{"kind":"ConfigMap","metadata":{"name":"{{ .ClusterName }}-config"},"data":{` +
+				`"region":"{{ .ClusterLabels.region }}",` +
+				`"host":"console.{{ index .ClusterAnnotations \"example.com/domain\" }}",` +
+				`"platform":"{{ index .ClusterClaims \"platform.open-cluster-management.io\" | lower }}",` +
+				`"replicas":12345678901234567890}}`,
+			expected: `{"data":{"host":"console.apps.example.com","platform":"aws","region":"east",` +
+				`"replicas":12345678901234567890},"kind":"ConfigMap","metadata":{"name":"cluster1-config"}}`,
+		},
+		{
+			name: "values",
+			manifest: `{"kind":"Deployment","spec":{"template":{"spec":{"containers":[` +
+				`{"image":"{{ index .Values.registries .ClusterLabels.region }}/nginx:1.0"}]}}}}`,
+			expected: `{"kind":"Deployment","spec":{"template":{"spec":{"containers":[` +
+				`{"image":"east.registry.io/nginx:1.0"}]}}}}`,
+		},
+		{
+			name:        "missing label",
+			manifest:    `{"kind":"ConfigMap","data":{"zone":"{{ .ClusterLabels.zone }}"}}`,
+			expectedErr: "invalid template in manifests[0].data.zone",
+		},
+		{
+			name:        "invalid template",
+			manifest:    `{"kind":"ConfigMap","data":{"zone":"{{ .ClusterLabels.zone "}}`,
+			expectedErr: "invalid template in manifests[0].data.zone",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			manifests, err := RenderManifests([]workapiv1.Manifest{newManifest(c.manifest)}, NewData(cluster, values))
+			if len(c.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), c.expectedErr) {
+					t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(manifests[0].Raw) != c.expected {
+				t.Errorf("expected manifest %s, but got %s", c.expected, string(manifests[0].Raw))
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		manifests   []workapiv1.Manifest
+		expectedErr string
+	}{
+		{
+			name: "valid templates",
+			annotations: map[string]string{
+				TemplateValuesAnnotation: "registry: registry.io",
+			},
+			manifests: []workapiv1.Manifest{
+				newManifest(`{"kind":"ConfigMap","data":{"zone":"{{ .ClusterLabels.zone | default \"a\" }}"}}`),
+			},
+		},
+		{
+			name: "invalid values",
+			annotations: map[string]string{
+				TemplateValuesAnnotation: "registry",
+			},
+			expectedErr: "invalid template values annotation",
+		},
+		{
+			name: "undefined function",
+			manifests: []workapiv1.Manifest{
+				newManifest(`{"kind":"ConfigMap","data":{"zone":"{{ zone }}"}}`),
+			},
+			expectedErr: `function "zone" not defined`,
+		},
+		{
+			name: "env function",
+			manifests: []workapiv1.Manifest{
+				newManifest(`{"kind":"ConfigMap","data":{"token":"{{ env \"KUBECONFIG\" }}"}}`),
+			},
+			expectedErr: `function "env" not defined`,
+		},
+		{
+			name: "expandenv function",
+			manifests: []workapiv1.Manifest{
+				newManifest(`{"kind":"ConfigMap","data":{"home":"{{ expandenv \"$HOME\" }}"}}`),
+			},
+			expectedErr: `function "expandenv" not defined`,
+		},
+		{
+			name: "non-hermetic function",
+			manifests: []workapiv1.Manifest{
+				newManifest(`{"kind":"ConfigMap","data":{"id":"{{ uuidv4 }}"}}`),
+			},
+			expectedErr: `function "uuidv4" not defined`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := GetValues(c.annotations)
+			if err == nil {
+				err = ValidateManifests(c.manifests)
+			}
+			if len(c.expectedErr) == 0 && err != nil {
+				t.Errorf("expected no error, but got %v", err)
+			}
+			if len(c.expectedErr) > 0 && (err == nil || !strings.Contains(err.Error(), c.expectedErr)) {
+				t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package manifestworkhelmsource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	worklisters "open-cluster-management.io/api/client/work/listers/work/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/basecontroller/factory"
+	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
+)
+
+// ManifestWorkHelmSourceController renders the helm source of a manifestwork into its manifests. The
+// chart is read from the namespace of the manifestwork, and the values are rendered with the labels and
+// claims of the cluster. The manifests of the manifestwork are replaced by the rendered ones.
+type ManifestWorkHelmSourceController struct {
+	workClient   workclientset.Interface
+	workLister   worklisters.ManifestWorkLister
+	helmRenderer *helmsource.Renderer
+}
+
+// NewManifestWorkHelmSourceController creates a new ManifestWorkHelmSourceController
+func NewManifestWorkHelmSourceController(
+	workClient workclientset.Interface,
+	manifestWorkInformer workinformers.ManifestWorkInformer,
+	helmRenderer *helmsource.Renderer,
+	helmChartConfigMapInformer corev1informers.ConfigMapInformer,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+) factory.Controller {
+	controller := &ManifestWorkHelmSourceController{
+		workClient:   workClient,
+		workLister:   manifestWorkInformer.Lister(),
+		helmRenderer: helmRenderer,
+	}
+
+	return factory.New().
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByMetaNamespaceName,
+			queue.FilterByAnnotation(helmsource.HelmSourceAnnotation),
+			manifestWorkInformer.Informer(),
+		).
+		WithInformersQueueKeysFunc(
+			controller.namespaceQueueKeysFunc,
+			helmChartConfigMapInformer.Informer(),
+		).
+		WithInformersQueueKeysFunc(
+			controller.clusterQueueKeysFunc,
+			clusterInformer.Informer(),
+		).
+		WithSync(controller.sync).
+		ToController("ManifestWorkHelmSourceController")
+}
+
+// namespaceQueueKeysFunc enqueues the manifestworks with helm sources in the namespace of the changed
+// chart ConfigMap.
+func (c *ManifestWorkHelmSourceController) namespaceQueueKeysFunc(obj runtime.Object) []string {
+	accessor, _ := meta.Accessor(obj)
+	return c.helmSourceKeys(accessor.GetNamespace())
+}
+
+// clusterQueueKeysFunc enqueues the manifestworks with helm sources for the changed cluster.
+func (c *ManifestWorkHelmSourceController) clusterQueueKeysFunc(obj runtime.Object) []string {
+	accessor, _ := meta.Accessor(obj)
+	return c.helmSourceKeys(accessor.GetName())
+}
+
+func (c *ManifestWorkHelmSourceController) helmSourceKeys(namespace string) []string {
+	works, err := c.workLister.ManifestWorks(namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return []string{}
+	}
+
+	var keys []string
+	for _, work := range works {
+		if _, ok := work.Annotations[helmsource.HelmSourceAnnotation]; !ok {
+			continue
+		}
+		keys = append(keys, fmt.Sprintf("%s/%s", work.Namespace, work.Name))
+	}
+	return keys
+}
+
+func (c *ManifestWorkHelmSourceController) sync(ctx context.Context, _ factory.SyncContext, key string) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling ManifestWork helm source", "key", key)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	manifestWork, err := c.workLister.ManifestWorks(namespace).Get(name)
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if manifestWork.DeletionTimestamp != nil {
+		return nil
+	}
+
+	source, ok, err := helmsource.GetHelmSource(manifestWork.Annotations)
+	if err != nil {
+		// the annotation is validated by the webhook, the invalid one is not retried
+		utilruntime.HandleError(fmt.Errorf("manifestwork %s: %w", key, err))
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	// the manifestwork is in the namespace of the cluster
+	manifests, err := c.helmRenderer.Render(ctx, namespace, namespace, source)
+	if err != nil {
+		return fmt.Errorf("failed to render helm source of manifestwork %s: %w", key, err)
+	}
+
+	if manifestsEqual(manifests, manifestWork.Spec.Workload.Manifests) {
+		return nil
+	}
+
+	newSpec := manifestWork.Spec.DeepCopy()
+	newSpec.Workload.Manifests = manifests
+	workPatcher := patcher.NewPatcher[
+		*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](
+		c.workClient.WorkV1().ManifestWorks(namespace))
+	if _, err := workPatcher.PatchSpec(ctx, manifestWork, *newSpec, manifestWork.Spec); err != nil {
+		return err
+	}
+	logger.V(2).Info("Updated manifests rendered from helm source", "key", key, "manifests", len(manifests))
+	return nil
+}
+
+// manifestsEqual compares the manifests by their content, ignoring the formatting of the raw json.
+func manifestsEqual(a, b []workapiv1.Manifest) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		var objA, objB interface{}
+		if err := json.Unmarshal(a[i].Raw, &objA); err != nil {
+			return false
+		}
+		if err := json.Unmarshal(b[i].Raw, &objB); err != nil {
+			return false
+		}
+		if !reflect.DeepEqual(objA, objB) {
+			return false
+		}
+	}
+	return true
+}
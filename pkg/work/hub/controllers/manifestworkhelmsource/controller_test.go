@@ -0,0 +1,164 @@
+package manifestworkhelmsource
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	fakeclusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
+	helpertest "open-cluster-management.io/ocm/pkg/work/hub/test"
+)
+
+const testConfigMapTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: default
+data:
+  region: {{ .Values.region }}
+`
+
+func newHelmSourceWork(manifests ...workapiv1.Manifest) *workapiv1.ManifestWork {
+	return &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "cluster1",
+			Annotations: map[string]string{
+				helmsource.HelmSourceAnnotation: `{"chart":{"configMap":"nginx"},"values":"region: {{ .ClusterLabels.region }}"}`,
+			},
+		},
+		Spec: workapiv1.ManifestWorkSpec{
+			Workload: workapiv1.ManifestsTemplate{Manifests: manifests},
+		},
+	}
+}
+
+func newRawManifest(raw string) workapiv1.Manifest {
+	return workapiv1.Manifest{RawExtension: runtime.RawExtension{Raw: []byte(raw)}}
+}
+
+func TestSync(t *testing.T) {
+	archive, err := helpertest.CreateTestChartArchive("nginx", map[string]string{
+		"Chart.yaml":        "apiVersion: v2\nname: nginx\nversion: 0.1.0\n",
+		"templates/cm.yaml": testConfigMapTemplate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workWithoutSource := newHelmSourceWork(newRawManifest(`{"apiVersion":"v1","kind":"ConfigMap"}`))
+	workWithoutSource.Annotations = nil
+
+	cases := []struct {
+		name            string
+		work            *workapiv1.ManifestWork
+		expectErr       bool
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name: "no helm source",
+			work: workWithoutSource,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name: "render manifests",
+			work: newHelmSourceWork(),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				work := &workapiv1.ManifestWork{}
+				if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, work); err != nil {
+					t.Fatal(err)
+				}
+				if len(work.Spec.Workload.Manifests) != 1 {
+					t.Fatalf("expected 1 manifest, but got %d", len(work.Spec.Workload.Manifests))
+				}
+				cm := &corev1.ConfigMap{}
+				if err := json.Unmarshal(work.Spec.Workload.Manifests[0].Raw, cm); err != nil {
+					t.Fatal(err)
+				}
+				if cm.Data["region"] != "east" {
+					t.Errorf("expected region east, but got %q", cm.Data["region"])
+				}
+			},
+		},
+		{
+			name: "manifests up to date",
+			work: newHelmSourceWork(newRawManifest(
+				`{"kind": "ConfigMap", "apiVersion": "v1", "metadata": {"name": "test", "namespace": "default"}, "data": {"region": "east"}}`)),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name: "chart not found",
+			work: func() *workapiv1.ManifestWork {
+				work := newHelmSourceWork()
+				work.Annotations[helmsource.HelmSourceAnnotation] = `{"chart":{"configMap":"missing"}}`
+				return work
+			}(),
+			expectErr: true,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			workClient := fakeworkclient.NewSimpleClientset(c.work)
+			workInformerFactory := workinformers.NewSharedInformerFactory(workClient, 5*time.Minute)
+			if err := workInformerFactory.Work().V1().ManifestWorks().Informer().GetStore().Add(c.work); err != nil {
+				t.Fatal(err)
+			}
+
+			kubeInformerFactory := kubeinformers.NewSharedInformerFactory(fakekube.NewSimpleClientset(), 5*time.Minute)
+			if err := kubeInformerFactory.Core().V1().ConfigMaps().Informer().GetStore().Add(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "cluster1"},
+				BinaryData: map[string][]byte{helmsource.DefaultChartKey: archive},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(fakeclusterclient.NewSimpleClientset(), 5*time.Minute)
+			if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(&clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{"region": "east"}},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			controller := &ManifestWorkHelmSourceController{
+				workClient: workClient,
+				workLister: workInformerFactory.Work().V1().ManifestWorks().Lister(),
+				helmRenderer: helmsource.NewRenderer(
+					kubeInformerFactory.Core().V1().ConfigMaps().Lister(),
+					clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				),
+			}
+
+			key := c.work.Namespace + "/" + c.work.Name
+			err := controller.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, key), key)
+			if c.expectErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectErr, err)
+			}
+			c.validateActions(t, workClient.Actions())
+		})
+	}
+}
@@ -15,10 +15,15 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
 	clusterinformerv1beta1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
 	workinformerv1 "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
 	workinformerv1alpha1 "open-cluster-management.io/api/client/work/informers/externalversions/work/v1alpha1"
@@ -32,6 +37,8 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
+	"open-cluster-management.io/ocm/pkg/work/hub/rollout"
 )
 
 // maxRequeueTime is the same as the informer resync period
@@ -120,6 +127,11 @@ func NewManifestWorkReplicaSetController(
 	manifestWorkInformer workinformerv1.ManifestWorkInformer,
 	placementInformer clusterinformerv1beta1.PlacementInformer,
 	placeDecisionInformer clusterinformerv1beta1.PlacementDecisionInformer,
+	helmRenderer *helmsource.Renderer,
+	helmChartConfigMapInformer corev1informers.ConfigMapInformer,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	revisionClient appsv1client.ControllerRevisionsGetter,
+	revisionInformer appsinformers.ControllerRevisionInformer,
 ) factory.Controller {
 	controller := newController(
 		workClient,
@@ -128,6 +140,9 @@ func NewManifestWorkReplicaSetController(
 		manifestWorkInformer,
 		placementInformer,
 		placeDecisionInformer,
+		clusterInformer.Lister(),
+		helmRenderer,
+		&revisionStore{client: revisionClient, lister: revisionInformer.Lister()},
 	)
 
 	err := manifestWorkReplicaSetInformer.Informer().AddIndexers(
@@ -153,9 +168,11 @@ func NewManifestWorkReplicaSetController(
 			return []string{fmt.Sprintf("%s/%s", keys[0], keys[1])}
 		},
 			queue.FileterByLabel(workapiv1alpha1.ManifestWorkReplicaSetControllerNameLabelKey),
-			manifestWorkInformer.Informer()).
+			manifestWorkInformer.Informer(), revisionInformer.Informer()).
 		WithInformersQueueKeysFunc(controller.placementDecisionQueueKeysFunc, placeDecisionInformer.Informer()).
 		WithInformersQueueKeysFunc(controller.placementQueueKeysFunc, placementInformer.Informer()).
+		WithInformersQueueKeysFunc(controller.helmChartQueueKeysFunc, helmChartConfigMapInformer.Informer()).
+		WithInformersQueueKeysFunc(controller.clusterQueueKeysFunc, clusterInformer.Informer()).
 		WithSync(controller.sync).ToController("ManifestWorkReplicaSetController")
 }
 
@@ -166,6 +183,9 @@ func newController(
 	manifestWorkInformer workinformerv1.ManifestWorkInformer,
 	placementInformer clusterinformerv1beta1.PlacementInformer,
 	placeDecisionInformer clusterinformerv1beta1.PlacementDecisionInformer,
+	clusterLister clusterlisterv1.ManagedClusterLister,
+	helmRenderer *helmsource.Renderer,
+	revisions *revisionStore,
 ) *ManifestWorkReplicaSetController {
 	return &ManifestWorkReplicaSetController{
 		workClient:                    workClient,
@@ -186,6 +206,9 @@ func newController(
 				manifestWorkLister:  manifestWorkInformer.Lister(),
 				placementLister:     placementInformer.Lister(),
 				placeDecisionLister: placeDecisionInformer.Lister(),
+				clusterLister:       clusterLister,
+				helmRenderer:        helmRenderer,
+				revisions:           revisions,
 			},
 			&statusReconciler{manifestWorkLister: manifestWorkInformer.Lister()},
 		},
@@ -237,7 +260,30 @@ func (m *ManifestWorkReplicaSetController) sync(ctx context.Context, controllerC
 		*workapiv1alpha1.ManifestWorkReplicaSet, workapiv1alpha1.ManifestWorkReplicaSetSpec, workapiv1alpha1.ManifestWorkReplicaSetStatus](
 		m.workClient.WorkV1alpha1().ManifestWorkReplicaSets(namespace))
 
-	// Patch status
+	// Patch the annotations set by the reconcilers first.
+	newMeta := oldManifestWorkReplicaSet.ObjectMeta.DeepCopy()
+	for _, key := range rollout.ControllerAnnotations {
+		value, ok := manifestWorkReplicaSet.Annotations[key]
+		switch {
+		case ok && newMeta.Annotations == nil:
+			newMeta.Annotations = map[string]string{key: value}
+		case ok:
+			newMeta.Annotations[key] = value
+		default:
+			delete(newMeta.Annotations, key)
+		}
+	}
+	patched, err := workSetPatcher.PatchLabelAnnotations(
+		ctx, manifestWorkReplicaSet, *newMeta, oldManifestWorkReplicaSet.ObjectMeta)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	// Patch status. The resource version is changed by the patch of the annotations above, so it is not
+	// matched in this case. The status is only set by this controller.
+	if patched {
+		workSetPatcher = workSetPatcher.WithOptions(patcher.PatchOptions{IgnoreResourceVersion: true})
+	}
 	if _, err := workSetPatcher.PatchStatus(ctx, manifestWorkReplicaSet, manifestWorkReplicaSet.Status, oldManifestWorkReplicaSet.Status); err != nil {
 		errs = append(errs, err)
 	}
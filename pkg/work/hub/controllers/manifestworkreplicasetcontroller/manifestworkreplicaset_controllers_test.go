@@ -21,6 +21,7 @@ import (
 	workapplier "open-cluster-management.io/sdk-go/pkg/apis/work/v1/applier"
 
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/hub/rollout"
 	helpertest "open-cluster-management.io/ocm/pkg/work/hub/test"
 )
 
@@ -175,6 +176,54 @@ func TestManifestWorkReplicaSetControllerPatchStatus(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "patch the annotations and the status",
+			mwrSet: func() *workapiv1alpha1.ManifestWorkReplicaSet {
+				w := helpertest.CreateTestManifestWorkReplicaSet("test", "default", "placement")
+				w.Finalizers = []string{workapiv1alpha1.ManifestWorkReplicaSetFinalizer}
+				w.Annotations = map[string]string{
+					rollout.PromotionGateAnnotation:    "true",
+					rollout.PromotedGroupAnnotation:    "1",
+					rollout.PromotedRevisionAnnotation: "stale",
+				}
+				return w
+			}(),
+			works: helpertest.CreateTestManifestWorks("test", "default", "placement", "cluster1", "cluster2"),
+			placement: func() *clusterv1beta1.Placement {
+				p, _ := helpertest.CreateTestPlacement("placement", "default", "cluster1", "cluster2")
+				return p
+			}(),
+			decision: func() *clusterv1beta1.PlacementDecision {
+				_, d := helpertest.CreateTestPlacement("placement", "default", "cluster1", "cluster2")
+				return d
+			}(),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch", "patch")
+				annotationPatch := struct {
+					Metadata struct {
+						Annotations map[string]*string `json:"annotations"`
+					} `json:"metadata"`
+				}{}
+				if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, &annotationPatch); err != nil {
+					t.Fatal(err)
+				}
+				annotations := annotationPatch.Metadata.Annotations
+				if len(annotations) != 2 || annotations[rollout.PromotedGroupAnnotation] != nil ||
+					annotations[rollout.PromotedRevisionAnnotation] != nil {
+					t.Error(spew.Sdump(annotationPatch))
+				}
+				if actions[1].GetSubresource() != "status" {
+					t.Errorf("expected status patch, but got %v", actions[1])
+				}
+				workSet := &workapiv1alpha1.ManifestWorkReplicaSet{}
+				if err := json.Unmarshal(actions[1].(clienttesting.PatchActionImpl).Patch, workSet); err != nil {
+					t.Fatal(err)
+				}
+				if workSet.Status.Summary.Applied != 2 {
+					t.Error(spew.Sdump(workSet.Status.Summary))
+				}
+			},
+		},
 		{
 			name: "add and delete",
 			mwrSet: func() *workapiv1alpha1.ManifestWorkReplicaSet {
@@ -243,6 +292,9 @@ func TestManifestWorkReplicaSetControllerPatchStatus(t *testing.T) {
 				workInformers.Work().V1().ManifestWorks(),
 				clusterInformers.Cluster().V1beta1().Placements(),
 				clusterInformers.Cluster().V1beta1().PlacementDecisions(),
+				nil,
+				nil,
+				nil,
 			)
 
 			controllerContext := testingcommon.NewFakeSyncContext(t, c.mwrSet.Namespace+"/"+c.mwrSet.Name)
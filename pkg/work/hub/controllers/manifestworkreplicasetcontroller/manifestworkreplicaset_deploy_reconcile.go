@@ -3,6 +3,8 @@ package manifestworkreplicasetcontroller
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
@@ -10,6 +12,7 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterlister "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
 	worklisterv1 "open-cluster-management.io/api/client/work/listers/work/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
@@ -19,6 +22,19 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/hub/clustertemplate"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
+)
+
+const (
+	// ManifestWorkReplicaSetConditionManifestsRendered is the condition of the ManifestWorkReplicaSet with
+	// per-cluster templates or a helm source, which is False if the manifests fail to render for any cluster.
+	ManifestWorkReplicaSetConditionManifestsRendered = "ManifestsRendered"
+	// ReasonRenderFailed is the reason of the ManifestsRendered condition when rendering fails.
+	ReasonRenderFailed = "RenderFailed"
+
+	// maxRenderFailedClusters is the max number of the clusters listed in the ManifestsRendered condition.
+	maxRenderFailedClusters = 10
 )
 
 // deployReconciler is to manage ManifestWork based on the placement.
@@ -27,6 +43,10 @@ type deployReconciler struct {
 	manifestWorkLister  worklisterv1.ManifestWorkLister
 	placeDecisionLister clusterlister.PlacementDecisionLister
 	placementLister     clusterlister.PlacementLister
+	clusterLister       clusterlisterv1.ManagedClusterLister
+	helmRenderer        *helmsource.Renderer
+	// revisions stores the revisions of the ManifestWorkReplicaSets with auto rollback enabled
+	revisions *revisionStore
 }
 
 func (d *deployReconciler) reconcile(
@@ -36,6 +56,32 @@ func (d *deployReconciler) reconcile(
 	var plcsSummary []workapiv1alpha1.PlacementSummary
 	minRequeue := maxRequeueTime
 	count, total, succeededCount := 0, 0, 0
+	// the errors rendering the manifests of the clusters, surfaced in the status instead of retrying
+	renderErrs := map[string]error{}
+	maxFailureBreach := false
+	gate, err := newRolloutGate(mwrSet)
+	if err != nil {
+		return mwrSet, reconcileContinue, err
+	}
+
+	// deploySet is the ManifestWorkReplicaSet with the template deployed to the clusters, which is the
+	// last succeeded revision once the current revision is rolled back.
+	deploySet := mwrSet
+	var history *revisionHistory
+	if autoRollbackEnabled(mwrSet) && d.revisions != nil {
+		var err error
+		history, err = d.revisions.sync(ctx, mwrSet)
+		if err != nil {
+			return mwrSet, reconcileContinue, err
+		}
+		if revisionPhase(history.current) == revisionRolledBack && history.lastSucceeded != nil {
+			state, err := getRevisionState(history.lastSucceeded)
+			if err != nil {
+				return mwrSet, reconcileContinue, err
+			}
+			deploySet = state.applyTo(mwrSet)
+		}
+	}
 
 	// Clean up ManifestWorks from placements no longer in the spec
 	currentPlacementNames := sets.New[string]()
@@ -79,9 +125,14 @@ func (d *deployReconciler) reconcile(
 		manifestWorks := allManifestWorks.workByPlacement[placement.Name]
 		for _, mw := range manifestWorks {
 			// Check if ManifestWorkTemplate changes, ManifestWork will need to be updated.
+			spec, err := d.manifestWorkSpec(ctx, deploySet, mw.Namespace)
+			if err != nil {
+				renderErrs[mw.Namespace] = err
+				continue
+			}
 			newMW := &workv1.ManifestWork{}
 			mw.ObjectMeta.DeepCopyInto(&newMW.ObjectMeta)
-			mwrSet.Spec.ManifestWorkTemplate.DeepCopyInto(&newMW.Spec)
+			spec.DeepCopyInto(&newMW.Spec)
 
 			// TODO: Create NeedToApply function by workApplier to check the manifestWork->spec hash value from the cache.
 			if !workapplier.ManifestWorkEqual(newMW, mw) {
@@ -126,6 +177,10 @@ func (d *deployReconciler) reconcile(
 			continue
 		}
 
+		if rolloutResult.MaxFailureBreach {
+			maxFailureBreach = true
+		}
+
 		if rolloutResult.RecheckAfter != nil && *rolloutResult.RecheckAfter < minRequeue {
 			minRequeue = *rolloutResult.RecheckAfter
 		}
@@ -133,13 +188,22 @@ func (d *deployReconciler) reconcile(
 		// Create or update ManifestWorks
 		for _, rolloutStatus := range rolloutResult.ClustersToRollout {
 			if rolloutStatus.Status == clustersdkv1alpha1.ToApply {
+				if gate.hold(placementRef, rolloutStatus) {
+					continue
+				}
 				var workName string
 				if relatedManifestWork, ok := allManifestWorks.workByCluster[rolloutStatus.ClusterName]; ok {
 					if relatedManifestWork.placements.Has(placementRef.Name) {
 						workName = relatedManifestWork.work.Name
 					}
 				}
+				spec, err := d.manifestWorkSpec(ctx, deploySet, rolloutStatus.ClusterName)
+				if err != nil {
+					renderErrs[rolloutStatus.ClusterName] = err
+					continue
+				}
 				mw := buildManifestWork(mwrSet, workName, rolloutStatus.ClusterName, placementRef.Name)
+				mw.Spec = *spec
 				_, err = d.workApplier.Apply(ctx, mw)
 				if err != nil {
 					errs = append(errs, err)
@@ -174,6 +238,11 @@ func (d *deployReconciler) reconcile(
 		mwrSetSummary := workapiv1alpha1.ManifestWorkReplicaSetSummary{
 			Total: len(existingClusterNames),
 		}
+		if gate.enabled() {
+			// the clusters held by the rollout gate are the desired ones not updated yet
+			mwrSetSummary.DesiredTotal = int(placement.Status.NumberOfSelectedClusters)
+			mwrSetSummary.Updated = len(existingClusterNames)
+		}
 		plcSummary.Summary = mwrSetSummary
 		plcsSummary = append(plcsSummary, plcSummary)
 
@@ -183,12 +252,33 @@ func (d *deployReconciler) reconcile(
 	// Set the placements summary
 	mwrSet.Status.PlacementsSummary = plcsSummary
 
+	setManifestsRenderedCondition(mwrSet, renderErrs)
+
+	if history != nil {
+		recheckAfter, err := d.revisions.updatePhase(ctx, mwrSet, history,
+			total > 0 && total == succeededCount && len(renderErrs) == 0, maxFailureBreach, gate.holding())
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if recheckAfter > 0 && recheckAfter < minRequeue {
+			minRequeue = recheckAfter
+		}
+	}
+	if err := setRevisionHistory(mwrSet, history); err != nil {
+		errs = append(errs, err)
+	}
+
 	// Set the Summary
 	if mwrSet.Status.Summary == (workapiv1alpha1.ManifestWorkReplicaSetSummary{}) {
 		mwrSet.Status.Summary = workapiv1alpha1.ManifestWorkReplicaSetSummary{}
 	}
 
 	mwrSet.Status.Summary.Total = count
+	mwrSet.Status.Summary.DesiredTotal, mwrSet.Status.Summary.Updated = 0, 0
+	if gate.enabled() {
+		mwrSet.Status.Summary.DesiredTotal = total
+		mwrSet.Status.Summary.Updated = count
+	}
 	if count == 0 {
 		mwrSet.Status.Summary.Applied = 0
 		mwrSet.Status.Summary.Available = 0
@@ -201,6 +291,8 @@ func (d *deployReconciler) reconcile(
 
 	if total == succeededCount {
 		apimeta.SetStatusCondition(&mwrSet.Status.Conditions, getPlacementRollOut(workapiv1alpha1.ReasonComplete, ""))
+	} else if reason, message, held := gate.rolledOutCondition(); held {
+		apimeta.SetStatusCondition(&mwrSet.Status.Conditions, getPlacementRollOut(reason, message))
 	} else {
 		apimeta.SetStatusCondition(&mwrSet.Status.Conditions, getPlacementRollOut(workapiv1alpha1.ReasonProgressing, ""))
 	}
@@ -216,6 +308,85 @@ func (d *deployReconciler) reconcile(
 	return mwrSet, reconcileContinue, nil
 }
 
+// manifestWorkSpec returns the spec of the ManifestWork on the cluster. It is the ManifestWorkTemplate with
+// the templates rendered for the cluster, or with the manifests rendered from the helm source of the
+// ManifestWorkReplicaSet, which replace the manifests of the template.
+func (d *deployReconciler) manifestWorkSpec(
+	ctx context.Context, mwrSet *workapiv1alpha1.ManifestWorkReplicaSet, clusterName string) (*workv1.ManifestWorkSpec, error) {
+	spec := mwrSet.Spec.ManifestWorkTemplate.DeepCopy()
+
+	if clustertemplate.Enabled(mwrSet.Annotations) {
+		values, err := clustertemplate.GetValues(mwrSet.Annotations)
+		if err != nil {
+			return nil, err
+		}
+		if d.clusterLister == nil {
+			return nil, fmt.Errorf("cluster template is not supported")
+		}
+		cluster, err := d.clusterLister.Get(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		spec.Workload.Manifests, err = clustertemplate.RenderManifests(spec.Workload.Manifests, clustertemplate.NewData(cluster, values))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	source, ok, err := helmsource.GetHelmSource(mwrSet.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return spec, nil
+	}
+	if d.helmRenderer == nil {
+		return nil, fmt.Errorf("helm source is not supported")
+	}
+
+	manifests, err := d.helmRenderer.Render(ctx, mwrSet.Namespace, clusterName, source)
+	if err != nil {
+		return nil, err
+	}
+	spec.Workload.Manifests = manifests
+	return spec, nil
+}
+
+// setManifestsRenderedCondition sets the ManifestsRendered condition with the clusters failing to render
+// the manifests. The condition is removed if the ManifestWorkReplicaSet does not render the manifests.
+func setManifestsRenderedCondition(mwrSet *workapiv1alpha1.ManifestWorkReplicaSet, renderErrs map[string]error) {
+	_, hasHelmSource := mwrSet.Annotations[helmsource.HelmSourceAnnotation]
+	if !hasHelmSource && !clustertemplate.Enabled(mwrSet.Annotations) {
+		apimeta.RemoveStatusCondition(&mwrSet.Status.Conditions, ManifestWorkReplicaSetConditionManifestsRendered)
+		return
+	}
+
+	if len(renderErrs) == 0 {
+		apimeta.SetStatusCondition(&mwrSet.Status.Conditions, getCondition(
+			ManifestWorkReplicaSetConditionManifestsRendered, workapiv1alpha1.ReasonAsExpected, "", metav1.ConditionTrue))
+		return
+	}
+
+	clusterNames := make([]string, 0, len(renderErrs))
+	for clusterName := range renderErrs {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+
+	var messages []string
+	for i, clusterName := range clusterNames {
+		if i == maxRenderFailedClusters {
+			messages = append(messages, fmt.Sprintf("and %d more clusters", len(clusterNames)-i))
+			break
+		}
+		messages = append(messages, fmt.Sprintf("%s: %v", clusterName, renderErrs[clusterName]))
+	}
+	apimeta.SetStatusCondition(&mwrSet.Status.Conditions, getCondition(
+		ManifestWorkReplicaSetConditionManifestsRendered, ReasonRenderFailed,
+		fmt.Sprintf("Failed to render manifests for %d clusters; %s", len(clusterNames), strings.Join(messages, "; ")),
+		metav1.ConditionFalse))
+}
+
 func (d *deployReconciler) clusterRolloutStatusFunc(clusterName string, manifestWork workv1.ManifestWork) (clustersdkv1alpha1.ClusterRolloutStatus, error) {
 	// Initialize default status as ToApply, LastTransitionTime is not needed for ToApply status.
 	clsRolloutStatus := clustersdkv1alpha1.ClusterRolloutStatus{
@@ -2,23 +2,30 @@ package manifestworkreplicasetcontroller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	kubeinformers "k8s.io/client-go/informers"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
 
 	fakeclusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
 	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 	workclient "open-cluster-management.io/api/client/work/clientset/versioned"
 	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
 	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	workapiv1 "open-cluster-management.io/api/work/v1"
 	workapiv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
@@ -26,6 +33,9 @@ import (
 	workapplier "open-cluster-management.io/sdk-go/pkg/apis/work/v1/applier"
 
 	"open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/work/hub/clustertemplate"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
+	"open-cluster-management.io/ocm/pkg/work/hub/rollout"
 	helpertest "open-cluster-management.io/ocm/pkg/work/hub/test"
 )
 
@@ -1750,3 +1760,534 @@ func TestIsConditionReady(t *testing.T) {
 		})
 	}
 }
+
+func TestDeployReconcileWithHelmSource(t *testing.T) {
+	archive, err := helpertest.CreateTestChartArchive("nginx", map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: nginx\nversion: 0.1.0\n",
+		"templates/cm.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+  namespace: {{ .Release.Namespace }}
+data:
+  region: {{ .Values.region }}
+`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mwrSet := helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+	mwrSet.Annotations = map[string]string{
+		helmsource.HelmSourceAnnotation: `{"chart":{"configMap":"nginx"},"values":"region: {{ .ClusterLabels.region }}"}`,
+	}
+	mwrSet.Spec.ManifestWorkTemplate.Workload.Manifests = nil
+	fWorkClient := fakeworkclient.NewSimpleClientset(mwrSet)
+	workInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(fWorkClient, 1*time.Minute)
+	mwLister := workInformerFactory.Work().V1().ManifestWorks().Lister()
+
+	placement, placementDecision := helpertest.CreateTestPlacement("place-test", "default", "cls1", "cls2")
+	fClusterClient := fakeclusterclient.NewSimpleClientset(placement, placementDecision)
+	clusterInformerFactory := clusterinformers.NewSharedInformerFactoryWithOptions(fClusterClient, 1*time.Minute)
+	if err := clusterInformerFactory.Cluster().V1beta1().Placements().Informer().GetStore().Add(placement); err != nil {
+		t.Fatal(err)
+	}
+	if err := clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Informer().GetStore().Add(placementDecision); err != nil {
+		t.Fatal(err)
+	}
+	for _, cluster := range []string{"cls1", "cls2"} {
+		if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(&clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: cluster, Labels: map[string]string{"region": "region-" + cluster}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(fakekube.NewSimpleClientset(), 1*time.Minute)
+	if err := kubeInformerFactory.Core().V1().ConfigMaps().Informer().GetStore().Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		BinaryData: map[string][]byte{helmsource.DefaultChartKey: archive},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	pmwDeployController := deployReconciler{
+		workApplier:         workapplier.NewWorkApplierWithTypedClient(fWorkClient, mwLister),
+		manifestWorkLister:  mwLister,
+		placeDecisionLister: clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Lister(),
+		placementLister:     clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
+		helmRenderer: helmsource.NewRenderer(
+			kubeInformerFactory.Core().V1().ConfigMaps().Lister(),
+			clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+		),
+	}
+
+	if _, _, err := pmwDeployController.reconcile(context.TODO(), mwrSet); err != nil {
+		t.Fatal(err)
+	}
+
+	created := 0
+	for _, action := range fWorkClient.Actions() {
+		if action.GetVerb() != "create" {
+			continue
+		}
+		created++
+		mw := action.(clienttesting.CreateAction).GetObject().(*workapiv1.ManifestWork)
+		manifests := mw.Spec.Workload.Manifests
+		// the rendered manifests replace the manifests of the template
+		if len(manifests) != 1 {
+			t.Fatalf("expected 1 manifest in manifestwork of %s, but got %d", mw.Namespace, len(manifests))
+		}
+		cm := &corev1.ConfigMap{}
+		if err := json.Unmarshal(manifests[0].Raw, cm); err != nil {
+			t.Fatal(err)
+		}
+		if cm.Data["region"] != "region-"+mw.Namespace {
+			t.Errorf("expected region of %s rendered, but got %q", mw.Namespace, cm.Data["region"])
+		}
+	}
+	if created != 2 {
+		t.Errorf("expected 2 manifestworks created, but got %d", created)
+	}
+}
+
+func TestDeployReconcileWithClusterTemplate(t *testing.T) {
+	mwrSet := helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+	mwrSet.Annotations = map[string]string{
+		clustertemplate.ClusterTemplateAnnotation: "true",
+		clustertemplate.TemplateValuesAnnotation:  `{"registries": {"east": "east.registry.io"}}`,
+	}
+	mwrSet.Spec.ManifestWorkTemplate.Workload.Manifests[0].Raw = []byte(
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .ClusterName }}-config","namespace":"default"},` +
+			`"data":{"registry":"{{ index .Values.registries .ClusterLabels.region }}"}}`)
+	fWorkClient := fakeworkclient.NewSimpleClientset(mwrSet)
+	workInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(fWorkClient, 1*time.Minute)
+	mwLister := workInformerFactory.Work().V1().ManifestWorks().Lister()
+
+	placement, placementDecision := helpertest.CreateTestPlacement("place-test", "default", "cls1", "cls2")
+	fClusterClient := fakeclusterclient.NewSimpleClientset(placement, placementDecision)
+	clusterInformerFactory := clusterinformers.NewSharedInformerFactoryWithOptions(fClusterClient, 1*time.Minute)
+	if err := clusterInformerFactory.Cluster().V1beta1().Placements().Informer().GetStore().Add(placement); err != nil {
+		t.Fatal(err)
+	}
+	if err := clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Informer().GetStore().Add(placementDecision); err != nil {
+		t.Fatal(err)
+	}
+	// cls2 has no region label, so the template of the registry fails to render
+	clusters := []*clusterv1.ManagedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cls1", Labels: map[string]string{"region": "east"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cls2"}},
+	}
+	for _, cluster := range clusters {
+		if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(cluster); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pmwDeployController := deployReconciler{
+		workApplier:         workapplier.NewWorkApplierWithTypedClient(fWorkClient, mwLister),
+		manifestWorkLister:  mwLister,
+		placeDecisionLister: clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Lister(),
+		placementLister:     clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
+		clusterLister:       clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+	}
+
+	mwrSet, _, err := pmwDeployController.reconcile(context.TODO(), mwrSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var created []*workapiv1.ManifestWork
+	for _, action := range fWorkClient.Actions() {
+		if action.GetVerb() == "create" {
+			created = append(created, action.(clienttesting.CreateAction).GetObject().(*workapiv1.ManifestWork))
+		}
+	}
+	if len(created) != 1 || created[0].Namespace != "cls1" {
+		t.Fatalf("expected manifestwork created in cls1 only, but got %v", created)
+	}
+	cm := &corev1.ConfigMap{}
+	if err := json.Unmarshal(created[0].Spec.Workload.Manifests[0].Raw, cm); err != nil {
+		t.Fatal(err)
+	}
+	if cm.Name != "cls1-config" || cm.Data["registry"] != "east.registry.io" {
+		t.Errorf("expected configmap rendered for cls1, but got %s with data %v", cm.Name, cm.Data)
+	}
+
+	cond := apimeta.FindStatusCondition(mwrSet.Status.Conditions, ManifestWorkReplicaSetConditionManifestsRendered)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != ReasonRenderFailed {
+		t.Fatalf("expected ManifestsRendered condition false, but got %v", cond)
+	}
+	assert.Contains(t, cond.Message, "Failed to render manifests for 1 clusters; cls2: ")
+}
+
+func TestDeployReconcileWithRollback(t *testing.T) {
+	mwrSet := helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+	mwrSet.Annotations = map[string]string{
+		rollout.AutoRollbackAnnotation:    "true",
+		rollout.RollbackTimeoutAnnotation: "30m",
+	}
+	oldTemplate := mwrSet.Spec.ManifestWorkTemplate.DeepCopy()
+	mwrSet.Spec.ManifestWorkTemplate.Workload.Manifests[0].Raw = []byte(
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"new","namespace":"default"}}`)
+
+	// the old template is the last succeeded revision
+	oldRaw := mustMarshal(t, &revisionState{Template: *oldTemplate})
+	oldRevision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      revisionName(mwrSet.Name, oldRaw),
+			Namespace: mwrSet.Namespace,
+			Labels: map[string]string{
+				workapiv1alpha1.ManifestWorkReplicaSetControllerNameLabelKey: manifestWorkReplicaSetKey(mwrSet),
+			},
+			Annotations: map[string]string{revisionPhaseAnnotation: revisionSucceeded},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(mwrSet, workapiv1alpha1.SchemeGroupVersion.WithKind("ManifestWorkReplicaSet")),
+			},
+		},
+		Data:     runtime.RawExtension{Raw: oldRaw},
+		Revision: 1,
+	}
+
+	fKubeClient := fakekube.NewSimpleClientset(oldRevision)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(fKubeClient, 1*time.Minute)
+	revisionIndexer := kubeInformerFactory.Apps().V1().ControllerRevisions().Informer().GetStore()
+	if err := revisionIndexer.Add(oldRevision); err != nil {
+		t.Fatal(err)
+	}
+	// syncRevisions adds the revisions created or updated by the reconciler to the informer store
+	syncRevisions := func() {
+		revisions, err := fKubeClient.AppsV1().ControllerRevisions(mwrSet.Namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range revisions.Items {
+			if err := revisionIndexer.Update(&revisions.Items[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	fWorkClient := fakeworkclient.NewSimpleClientset(mwrSet)
+	workInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(fWorkClient, 1*time.Minute)
+	mwLister := workInformerFactory.Work().V1().ManifestWorks().Lister()
+	placement, placementDecision := helpertest.CreateTestPlacement("place-test", "default", "cls1")
+	fClusterClient := fakeclusterclient.NewSimpleClientset(placement, placementDecision)
+	clusterInformerFactory := clusterinformers.NewSharedInformerFactoryWithOptions(fClusterClient, 1*time.Minute)
+	if err := clusterInformerFactory.Cluster().V1beta1().Placements().Informer().GetStore().Add(placement); err != nil {
+		t.Fatal(err)
+	}
+	if err := clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Informer().GetStore().Add(placementDecision); err != nil {
+		t.Fatal(err)
+	}
+
+	pmwDeployController := deployReconciler{
+		workApplier:         workapplier.NewWorkApplierWithTypedClient(fWorkClient, mwLister),
+		manifestWorkLister:  mwLister,
+		placeDecisionLister: clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Lister(),
+		placementLister:     clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
+		revisions: &revisionStore{
+			client: fKubeClient.AppsV1(),
+			lister: kubeInformerFactory.Apps().V1().ControllerRevisions().Lister(),
+		},
+	}
+
+	// the new template is recorded as revision 2 and rolled out, and the timeout is rechecked later
+	mwrSet, _, err := pmwDeployController.reconcile(context.TODO(), mwrSet)
+	var rqe helpers.RequeueError
+	if !errors.As(err, &rqe) || rqe.RequeueTime > 30*time.Minute {
+		t.Fatalf("expected requeue in the rollback timeout, but got %v", err)
+	}
+	cond := apimeta.FindStatusCondition(mwrSet.Status.Conditions, ManifestWorkReplicaSetConditionRevisionRolledOut)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != ReasonRevisionProgressing {
+		t.Fatalf("expected RevisionRolledOut condition progressing, but got %v", cond)
+	}
+	assertRevisionHistory(t, mwrSet, "2 Progressing", "1 Succeeded")
+	works, err := listWorksByMWRS(context.TODO(), fWorkClient, "cls1", mwrSet.Namespace, mwrSet.Name, "place-test")
+	if err != nil || len(works) != 1 {
+		t.Fatalf("expected 1 manifestwork, but got %v: %v", works, err)
+	}
+	assert.JSONEq(t, string(mwrSet.Spec.ManifestWorkTemplate.Workload.Manifests[0].Raw),
+		string(works[0].Spec.Workload.Manifests[0].Raw))
+
+	// the revision is rolled back once the rollout does not succeed in the timeout
+	syncRevisions()
+	revision, err := fKubeClient.AppsV1().ControllerRevisions(mwrSet.Namespace).Get(
+		context.TODO(), revisionName(mwrSet.Name, mustMarshal(t, newRevisionState(mwrSet))), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	revision.Annotations[revisionStartTimeAnnotation] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	if err := revisionIndexer.Update(revision); err != nil {
+		t.Fatal(err)
+	}
+	mwrSet, _, err = pmwDeployController.reconcile(context.TODO(), mwrSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cond = apimeta.FindStatusCondition(mwrSet.Status.Conditions, ManifestWorkReplicaSetConditionRevisionRolledOut)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != ReasonRolledBack {
+		t.Fatalf("expected RevisionRolledOut condition rolled back, but got %v", cond)
+	}
+	assert.Contains(t, cond.Message, "Revision 2 is rolled back to revision 1 since the rollout does not succeed in 30m0s")
+	assertRevisionHistory(t, mwrSet, "2 RolledBack", "1 Succeeded")
+
+	// the old template is deployed to the clusters after the rollback
+	syncRevisions()
+	if err := workInformerFactory.Work().V1().ManifestWorks().Informer().GetStore().Add(&works[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = pmwDeployController.reconcile(context.TODO(), mwrSet); err != nil {
+		t.Fatal(err)
+	}
+	works, err = listWorksByMWRS(context.TODO(), fWorkClient, "cls1", mwrSet.Namespace, mwrSet.Name, "place-test")
+	if err != nil || len(works) != 1 {
+		t.Fatalf("expected 1 manifestwork, but got %v: %v", works, err)
+	}
+	assert.JSONEq(t, string(oldTemplate.Workload.Manifests[0].Raw), string(works[0].Spec.Workload.Manifests[0].Raw))
+}
+
+func TestRevisionStoreUpdatePhase(t *testing.T) {
+	mwrSet := helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+	mwrSet.Annotations = map[string]string{
+		rollout.AutoRollbackAnnotation:    "true",
+		rollout.RollbackTimeoutAnnotation: "30m",
+	}
+	newRevision := func(revision int64, phase string, startTime time.Time) *appsv1.ControllerRevision {
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d", mwrSet.Name, revision),
+				Namespace: mwrSet.Namespace,
+				Annotations: map[string]string{
+					revisionPhaseAnnotation:     phase,
+					revisionStartTimeAnnotation: startTime.UTC().Format(time.RFC3339),
+				},
+			},
+			Revision: revision,
+		}
+	}
+	expired := time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		name                     string
+		current                  *appsv1.ControllerRevision
+		lastSucceeded            bool
+		succeeded, breach, held  bool
+		expectedPhase            string
+		expectedHeld             bool
+		expectedRecheck          bool
+		expectedStartTimeChanged bool
+	}{
+		{
+			name:          "succeeded",
+			current:       newRevision(2, revisionProgressing, expired),
+			lastSucceeded: true,
+			succeeded:     true,
+			expectedPhase: revisionSucceeded,
+		},
+		{
+			name:          "rolled back on max failure breach",
+			current:       newRevision(2, revisionProgressing, time.Now()),
+			lastSucceeded: true,
+			breach:        true,
+			expectedPhase: revisionRolledBack,
+		},
+		{
+			name:          "rolled back on timeout",
+			current:       newRevision(2, revisionProgressing, expired),
+			lastSucceeded: true,
+			expectedPhase: revisionRolledBack,
+		},
+		{
+			name:          "not rolled back without a succeeded revision",
+			current:       newRevision(2, revisionProgressing, expired),
+			expectedPhase: revisionProgressing,
+		},
+		{
+			name:            "progressing in the timeout",
+			current:         newRevision(2, revisionProgressing, time.Now()),
+			lastSucceeded:   true,
+			expectedPhase:   revisionProgressing,
+			expectedRecheck: true,
+		},
+		{
+			name:          "timeout stops while held",
+			current:       newRevision(2, revisionProgressing, expired),
+			lastSucceeded: true,
+			held:          true,
+			expectedPhase: revisionProgressing,
+			expectedHeld:  true,
+		},
+		{
+			name: "timeout restarts once resumed",
+			current: func() *appsv1.ControllerRevision {
+				revision := newRevision(2, revisionProgressing, expired)
+				revision.Annotations[revisionHeldAnnotation] = "true"
+				return revision
+			}(),
+			lastSucceeded:            true,
+			expectedPhase:            revisionProgressing,
+			expectedRecheck:          true,
+			expectedStartTimeChanged: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fKubeClient := fakekube.NewSimpleClientset(c.current)
+			store := &revisionStore{client: fKubeClient.AppsV1()}
+			history := &revisionHistory{current: c.current, revisions: []*appsv1.ControllerRevision{c.current}}
+			if c.lastSucceeded {
+				history.lastSucceeded = newRevision(1, revisionSucceeded, expired)
+			}
+
+			recheckAfter, err := store.updatePhase(context.TODO(), mwrSet, history, c.succeeded, c.breach, c.held)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, c.expectedPhase, revisionPhase(history.current))
+			_, held := history.current.Annotations[revisionHeldAnnotation]
+			assert.Equal(t, c.expectedHeld, held)
+			assert.Equal(t, c.expectedRecheck, recheckAfter > 0)
+			assert.Equal(t, c.expectedStartTimeChanged,
+				history.current.Annotations[revisionStartTimeAnnotation] != c.current.Annotations[revisionStartTimeAnnotation])
+		})
+	}
+}
+
+func TestRevisionState(t *testing.T) {
+	mwrSet := helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+	mwrSet.Annotations = map[string]string{
+		rollout.AutoRollbackAnnotation:            "true",
+		clustertemplate.ClusterTemplateAnnotation: "true",
+		clustertemplate.TemplateValuesAnnotation:  `{"registry":"quay.io"}`,
+	}
+	state := newRevisionState(mwrSet)
+
+	// the revision changes with the annotations rendering the manifests
+	changed := mwrSet.DeepCopy()
+	changed.Annotations[clustertemplate.TemplateValuesAnnotation] = `{"registry":"docker.io"}`
+	assert.NotEqual(t, revisionName(mwrSet.Name, mustMarshal(t, state)),
+		revisionName(mwrSet.Name, mustMarshal(t, newRevisionState(changed))))
+
+	// the annotations of the revision are restored on rollback
+	changed.Annotations[helmsource.HelmSourceAnnotation] = `{"chart":{"configMap":"chart"}}`
+	deploySet := state.applyTo(changed)
+	assert.Equal(t, `{"registry":"quay.io"}`, deploySet.Annotations[clustertemplate.TemplateValuesAnnotation])
+	assert.NotContains(t, deploySet.Annotations, helmsource.HelmSourceAnnotation)
+	assert.Equal(t, "true", deploySet.Annotations[rollout.AutoRollbackAnnotation])
+}
+
+func assertRevisionHistory(t *testing.T, mwrSet *workapiv1alpha1.ManifestWorkReplicaSet, expected ...string) {
+	cond := apimeta.FindStatusCondition(mwrSet.Status.Conditions, ManifestWorkReplicaSetConditionRevisionHistory)
+	if cond == nil {
+		t.Fatalf("expected RevisionHistory condition, but got %v", mwrSet.Status.Conditions)
+	}
+	var records []RevisionRecord
+	if err := json.Unmarshal([]byte(cond.Message), &records); err != nil {
+		t.Fatal(err)
+	}
+	var history []string
+	for _, record := range records {
+		history = append(history, fmt.Sprintf("%d %s", record.Revision, record.Phase))
+	}
+	assert.Equal(t, expected, history)
+}
+
+func mustMarshal(t *testing.T, obj interface{}) []byte {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestDeployReconcileWithRolloutGates(t *testing.T) {
+	clusters := []string{"cls1", "cls2", "cls3", "cls4", "cls5"}
+	placement, placementDecisions := helpertest.CreateTestPlacementWithDecisionStrategy("place-test", "default", 3, clusters...)
+	fClusterClient := fakeclusterclient.NewSimpleClientset(placement, placementDecisions[0])
+	clusterInformerFactory := clusterinformers.NewSharedInformerFactoryWithOptions(fClusterClient, 1*time.Second)
+	err := clusterInformerFactory.Cluster().V1beta1().Placements().Informer().GetStore().Add(placement)
+	assert.Nil(t, err)
+	for _, plcDecision := range placementDecisions {
+		err = clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Informer().GetStore().Add(plcDecision)
+		assert.Nil(t, err)
+	}
+
+	mwrSet := helpertest.CreateTestManifestWorkReplicaSetWithRollOutStrategy("mwrSet-test", "default",
+		map[string]clusterv1alpha1.RolloutStrategy{placement.Name: {Type: clusterv1alpha1.ProgressivePerGroup}})
+	mwrSet.Annotations = map[string]string{rollout.PromotionGateAnnotation: "true"}
+	fWorkClient := fakeworkclient.NewSimpleClientset(mwrSet)
+	workInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(fWorkClient, 1*time.Second)
+	// the clusters of the first decision group are rolled out
+	for _, cluster := range clusters[:3] {
+		mw := helpertest.CreateTestManifestWork(mwrSet.Name, mwrSet.Namespace, placement.Name, cluster)
+		err = workInformerFactory.Work().V1().ManifestWorks().Informer().GetStore().Add(mw)
+		assert.Nil(t, err)
+	}
+
+	mwLister := workInformerFactory.Work().V1().ManifestWorks().Lister()
+	pmwDeployController := deployReconciler{
+		workApplier:         workapplier.NewWorkApplierWithTypedClient(fWorkClient, mwLister),
+		manifestWorkLister:  mwLister,
+		placeDecisionLister: clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Lister(),
+		placementLister:     clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
+	}
+	appliedClusters := func() []string {
+		var names []string
+		for _, action := range fWorkClient.Actions() {
+			if action.GetVerb() == "create" || action.GetVerb() == "update" || action.GetVerb() == "patch" {
+				names = append(names, action.GetNamespace())
+			}
+		}
+		fWorkClient.ClearActions()
+		return names
+	}
+
+	// the second decision group waits for promotion
+	mwrSet, _, err = pmwDeployController.reconcile(context.TODO(), mwrSet)
+	assert.Nil(t, err)
+	assert.Empty(t, appliedClusters())
+	assert.Equal(t, 5, mwrSet.Status.PlacementsSummary[0].Summary.DesiredTotal)
+	assert.Equal(t, 3, mwrSet.Status.PlacementsSummary[0].Summary.Updated)
+	rollOutCondition := apimeta.FindStatusCondition(mwrSet.Status.Conditions, workapiv1alpha1.ManifestWorkReplicaSetConditionPlacementRolledOut)
+	assert.NotNil(t, rollOutCondition)
+	assert.Equal(t, metav1.ConditionFalse, rollOutCondition.Status)
+	assert.Equal(t, ReasonAwaitingPromotion, rollOutCondition.Reason)
+	assert.Contains(t, rollOutCondition.Message, "Decision group 1 of placement place-test awaiting promotion")
+
+	// the rollout is paused before the promotion
+	mwrSet.Annotations[rollout.RolloutPausedAnnotation] = "true"
+	mwrSet.Annotations[rollout.PromotedGroupAnnotation] = "1"
+	mwrSet, _, err = pmwDeployController.reconcile(context.TODO(), mwrSet)
+	assert.Nil(t, err)
+	hash, err := revisionHash(mwrSet)
+	assert.Nil(t, err)
+	assert.Equal(t, hash, mwrSet.Annotations[rollout.PromotedRevisionAnnotation])
+	assert.Empty(t, appliedClusters())
+	assert.Equal(t, 3, mwrSet.Status.PlacementsSummary[0].Summary.Updated)
+	rollOutCondition = apimeta.FindStatusCondition(mwrSet.Status.Conditions, workapiv1alpha1.ManifestWorkReplicaSetConditionPlacementRolledOut)
+	assert.NotNil(t, rollOutCondition)
+	assert.Equal(t, ReasonRolloutPaused, rollOutCondition.Reason)
+
+	// the promoted decision group is rolled out once the rollout resumes
+	mwrSet.Annotations[rollout.RolloutPausedAnnotation] = "false"
+	mwrSet, _, err = pmwDeployController.reconcile(context.TODO(), mwrSet)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"cls4", "cls5"}, appliedClusters())
+	assert.Equal(t, "2 (5 / 5 clusters applied)", mwrSet.Status.PlacementsSummary[0].AvailableDecisionGroups)
+	assert.Equal(t, 5, mwrSet.Status.PlacementsSummary[0].Summary.Updated)
+	assert.Equal(t, 5, mwrSet.Status.Summary.DesiredTotal)
+	rollOutCondition = apimeta.FindStatusCondition(mwrSet.Status.Conditions, workapiv1alpha1.ManifestWorkReplicaSetConditionPlacementRolledOut)
+	assert.NotNil(t, rollOutCondition)
+	assert.Equal(t, workapiv1alpha1.ReasonProgressing, rollOutCondition.Reason)
+
+	// the promotion is removed once the template changes
+	mwrSet.Spec.ManifestWorkTemplate.Workload.Manifests[0].Raw = []byte(
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"new","namespace":"default"}}`)
+	gate, err := newRolloutGate(mwrSet)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), gate.promotedGroup)
+	assert.NotContains(t, mwrSet.Annotations, rollout.PromotedGroupAnnotation)
+	assert.NotContains(t, mwrSet.Annotations, rollout.PromotedRevisionAnnotation)
+}
@@ -0,0 +1,120 @@
+package manifestworkreplicasetcontroller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	workapiv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
+	clustersdkv1alpha1 "open-cluster-management.io/sdk-go/pkg/apis/cluster/v1alpha1"
+
+	"open-cluster-management.io/ocm/pkg/work/hub/rollout"
+)
+
+const (
+	// ReasonRolloutPaused is the reason of the PlacementRolledOut condition when the rollout is paused.
+	ReasonRolloutPaused = "Paused"
+	// ReasonAwaitingPromotion is the reason of the PlacementRolledOut condition when the rollout waits
+	// for the promotion of a decision group.
+	ReasonAwaitingPromotion = "AwaitingPromotion"
+)
+
+// rolloutGate holds back the clusters to roll out while the rollout is paused or their decision groups
+// are not promoted.
+type rolloutGate struct {
+	paused        bool
+	promotionGate bool
+	promotedGroup int32
+	// awaitingGroups is the decision groups waiting for promotion of each placement
+	awaitingGroups map[string]sets.Set[int32]
+}
+
+// newRolloutGate returns the rollout gate of the ManifestWorkReplicaSet. The rollout.PromotedRevisionAnnotation is
+// set with the hash of the current revision once a decision group is promoted, and the promotion is removed
+// once the revision changes.
+func newRolloutGate(mwrSet *workapiv1alpha1.ManifestWorkReplicaSet) (*rolloutGate, error) {
+	gate := &rolloutGate{
+		paused:         mwrSet.Annotations[rollout.RolloutPausedAnnotation] == "true",
+		promotionGate:  mwrSet.Annotations[rollout.PromotionGateAnnotation] == "true",
+		awaitingGroups: map[string]sets.Set[int32]{},
+	}
+	if !gate.promotionGate {
+		return gate, nil
+	}
+
+	if _, ok := mwrSet.Annotations[rollout.PromotedGroupAnnotation]; !ok {
+		delete(mwrSet.Annotations, rollout.PromotedRevisionAnnotation)
+		return gate, nil
+	}
+	hash, err := revisionHash(mwrSet)
+	if err != nil {
+		return nil, err
+	}
+	switch mwrSet.Annotations[rollout.PromotedRevisionAnnotation] {
+	case "":
+		mwrSet.Annotations[rollout.PromotedRevisionAnnotation] = hash
+	case hash:
+	default:
+		// the promotion is for a previous revision
+		delete(mwrSet.Annotations, rollout.PromotedGroupAnnotation)
+		delete(mwrSet.Annotations, rollout.PromotedRevisionAnnotation)
+		return gate, nil
+	}
+
+	// an invalid promoted group is rejected by the webhook, and only the first group is promoted with it
+	gate.promotedGroup, _ = rollout.GetPromotedGroup(mwrSet.Annotations)
+	return gate, nil
+}
+
+// hold returns true if the cluster of the placement should not be rolled out yet.
+func (g *rolloutGate) hold(placementRef workapiv1alpha1.LocalPlacementReference,
+	status clustersdkv1alpha1.ClusterRolloutStatus) bool {
+	if g.paused {
+		return true
+	}
+	if !g.promotionGate || placementRef.RolloutStrategy.Type != clusterv1alpha1.ProgressivePerGroup {
+		return false
+	}
+	if status.GroupKey.GroupIndex <= g.promotedGroup {
+		return false
+	}
+	if g.awaitingGroups[placementRef.Name] == nil {
+		g.awaitingGroups[placementRef.Name] = sets.New[int32]()
+	}
+	g.awaitingGroups[placementRef.Name].Insert(status.GroupKey.GroupIndex)
+	return true
+}
+
+// enabled returns true if the rollout is gated by the pause or the promotion. The DesiredTotal and the
+// Updated of the summaries are set for the gated rollouts, so the clusters held by the gate are the
+// desired ones not updated yet.
+func (g *rolloutGate) enabled() bool {
+	return g.paused || g.promotionGate
+}
+
+// holding returns true if the rollout is held by the gate.
+func (g *rolloutGate) holding() bool {
+	return g.paused || len(g.awaitingGroups) > 0
+}
+
+// rolledOutCondition returns the reason and the message of the PlacementRolledOut condition if the
+// rollout in progress is held by the gate.
+func (g *rolloutGate) rolledOutCondition() (string, string, bool) {
+	if g.paused {
+		return ReasonRolloutPaused, fmt.Sprintf("The rollout is paused by the annotation %s", rollout.RolloutPausedAnnotation), true
+	}
+	if len(g.awaitingGroups) == 0 {
+		return "", "", false
+	}
+
+	var placements []string
+	for name, groups := range g.awaitingGroups {
+		placements = append(placements, fmt.Sprintf("%d of placement %s", sets.List(groups)[0], name))
+	}
+	sort.Strings(placements)
+	return ReasonAwaitingPromotion, fmt.Sprintf("Decision group %s awaiting promotion by the annotation %s",
+		strings.Join(placements, ", "), rollout.PromotedGroupAnnotation), true
+}
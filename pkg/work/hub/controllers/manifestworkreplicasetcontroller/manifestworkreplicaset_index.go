@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
@@ -12,6 +14,9 @@ import (
 
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	workapiv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
+
+	"open-cluster-management.io/ocm/pkg/work/hub/clustertemplate"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
 )
 
 const (
@@ -65,6 +70,38 @@ func (m *ManifestWorkReplicaSetController) placementDecisionQueueKeysFunc(obj ru
 	return keys
 }
 
+// helmChartQueueKeysFunc enqueues the manifestWorkReplicaSets rendering manifests in the namespace of the
+// changed chart ConfigMap.
+func (m *ManifestWorkReplicaSetController) helmChartQueueKeysFunc(obj runtime.Object) []string {
+	accessor, _ := meta.Accessor(obj)
+	return m.renderedKeys(accessor.GetNamespace())
+}
+
+// clusterQueueKeysFunc enqueues the manifestWorkReplicaSets rendering manifests when a cluster changes,
+// since the templates and the values of the helm sources are rendered with the cluster labels and claims.
+func (m *ManifestWorkReplicaSetController) clusterQueueKeysFunc(_ runtime.Object) []string {
+	return m.renderedKeys(metav1.NamespaceAll)
+}
+
+// renderedKeys returns the keys of the manifestWorkReplicaSets with per-cluster templates or helm sources.
+func (m *ManifestWorkReplicaSetController) renderedKeys(namespace string) []string {
+	manifestWorkReplicaSets, err := m.manifestWorkReplicaSetLister.ManifestWorkReplicaSets(namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return []string{}
+	}
+
+	var keys []string
+	for _, manifestWorkReplicaSet := range manifestWorkReplicaSets {
+		_, hasHelmSource := manifestWorkReplicaSet.Annotations[helmsource.HelmSourceAnnotation]
+		if !hasHelmSource && !clustertemplate.Enabled(manifestWorkReplicaSet.Annotations) {
+			continue
+		}
+		keys = append(keys, fmt.Sprintf("%s/%s", manifestWorkReplicaSet.Namespace, manifestWorkReplicaSet.Name))
+	}
+	return keys
+}
+
 // we will generate manifestwork with a label
 func (m *ManifestWorkReplicaSetController) manifestWorkQueueKeyFunc(obj runtime.Object) string {
 	accessor, _ := meta.Accessor(obj)
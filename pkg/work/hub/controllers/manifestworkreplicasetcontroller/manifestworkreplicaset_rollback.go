@@ -0,0 +1,367 @@
+package manifestworkreplicasetcontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	workapiv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
+
+	"open-cluster-management.io/ocm/pkg/work/hub/clustertemplate"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
+	"open-cluster-management.io/ocm/pkg/work/hub/rollout"
+)
+
+const (
+	// ManifestWorkReplicaSetConditionRevisionRolledOut is the condition of the ManifestWorkReplicaSet
+	// with auto rollback enabled, which is True once the current revision succeeds on all the clusters.
+	ManifestWorkReplicaSetConditionRevisionRolledOut = "RevisionRolledOut"
+	ReasonRevisionSucceeded                          = "RevisionSucceeded"
+	ReasonRevisionProgressing                        = "RevisionProgressing"
+	ReasonRolledBack                                 = "RolledBack"
+	// ManifestWorkReplicaSetConditionRevisionHistory is the condition of the ManifestWorkReplicaSet with
+	// auto rollback enabled, whose message is the revision history, a json list of the RevisionRecord
+	// sorted from the newest, e.g. [{"revision":2,"name":"mwrs-1a2b3c4d5e","phase":"Progressing"}].
+	ManifestWorkReplicaSetConditionRevisionHistory = "RevisionHistory"
+	ReasonRevisionsRecorded                        = "RevisionsRecorded"
+
+	revisionPhaseAnnotation          = "work.open-cluster-management.io/revision-phase"
+	revisionStartTimeAnnotation      = "work.open-cluster-management.io/revision-start-time"
+	revisionRollbackReasonAnnotation = "work.open-cluster-management.io/rollback-reason"
+	// revisionHeldAnnotation is set on the revision in progress while the rollout is held by the rollout
+	// gate, so the rollback timeout restarts once the rollout resumes.
+	revisionHeldAnnotation = "work.open-cluster-management.io/revision-held"
+
+	revisionProgressing = "Progressing"
+	revisionSucceeded   = "Succeeded"
+	revisionRolledBack  = "RolledBack"
+
+	// revisionHistoryLimit is the max number of the revisions kept besides the current and the last
+	// succeeded one.
+	revisionHistoryLimit = 10
+)
+
+// revisionAnnotations are the annotations of the ManifestWorkReplicaSet rendering the manifests of the
+// ManifestWorkTemplate, which are a part of the revision.
+var revisionAnnotations = []string{
+	helmsource.HelmSourceAnnotation,
+	clustertemplate.ClusterTemplateAnnotation,
+	clustertemplate.TemplateValuesAnnotation,
+}
+
+// revisionState is the state of the ManifestWorkReplicaSet stored in a revision.
+type revisionState struct {
+	Template    workv1.ManifestWorkSpec `json:"template"`
+	Annotations map[string]string       `json:"annotations,omitempty"`
+}
+
+// RevisionRecord is a revision in the message of the RevisionHistory condition.
+type RevisionRecord struct {
+	Revision       int64  `json:"revision"`
+	Name           string `json:"name"`
+	Phase          string `json:"phase"`
+	RollbackReason string `json:"rollbackReason,omitempty"`
+}
+
+// revisionHistory is the revisions of a ManifestWorkReplicaSet sorted from the newest.
+type revisionHistory struct {
+	current       *appsv1.ControllerRevision
+	lastSucceeded *appsv1.ControllerRevision
+	revisions     []*appsv1.ControllerRevision
+}
+
+// revisionStore stores the revisions of the ManifestWorkReplicaSets in ControllerRevisions owned by them.
+type revisionStore struct {
+	client appsv1client.ControllerRevisionsGetter
+	lister appsv1listers.ControllerRevisionLister
+}
+
+func autoRollbackEnabled(mwrSet *workapiv1alpha1.ManifestWorkReplicaSet) bool {
+	return mwrSet.Annotations[rollout.AutoRollbackAnnotation] == "true"
+}
+
+func revisionPhase(revision *appsv1.ControllerRevision) string {
+	return revision.Annotations[revisionPhaseAnnotation]
+}
+
+// revisionHash returns the hash of the revision state of the ManifestWorkReplicaSet.
+func revisionHash(mwrSet *workapiv1alpha1.ManifestWorkReplicaSet) (string, error) {
+	raw, err := json.Marshal(newRevisionState(mwrSet))
+	if err != nil {
+		return "", err
+	}
+	return hashOf(raw), nil
+}
+
+func hashOf(raw []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(raw))[:10]
+}
+
+// revisionName returns the name of the revision with the hash of the revision state.
+func revisionName(mwrSetName string, state []byte) string {
+	hash := hashOf(state)
+	if len(mwrSetName) > validation.DNS1123SubdomainMaxLength-len(hash)-1 {
+		mwrSetName = mwrSetName[:validation.DNS1123SubdomainMaxLength-len(hash)-1]
+	}
+	return fmt.Sprintf("%s-%s", mwrSetName, hash)
+}
+
+func newRevisionState(mwrSet *workapiv1alpha1.ManifestWorkReplicaSet) *revisionState {
+	state := &revisionState{Template: mwrSet.Spec.ManifestWorkTemplate}
+	for _, key := range revisionAnnotations {
+		if value, ok := mwrSet.Annotations[key]; ok {
+			if state.Annotations == nil {
+				state.Annotations = map[string]string{}
+			}
+			state.Annotations[key] = value
+		}
+	}
+	return state
+}
+
+func getRevisionState(revision *appsv1.ControllerRevision) (*revisionState, error) {
+	state := &revisionState{}
+	if err := json.Unmarshal(revision.Data.Raw, state); err != nil {
+		return nil, fmt.Errorf("failed to decode revision %s: %w", revision.Name, err)
+	}
+	return state, nil
+}
+
+// applyTo returns a copy of the ManifestWorkReplicaSet with the template and the annotations of the state.
+func (s *revisionState) applyTo(mwrSet *workapiv1alpha1.ManifestWorkReplicaSet) *workapiv1alpha1.ManifestWorkReplicaSet {
+	deploySet := mwrSet.DeepCopy()
+	deploySet.Spec.ManifestWorkTemplate = s.Template
+	for _, key := range revisionAnnotations {
+		delete(deploySet.Annotations, key)
+		if value, ok := s.Annotations[key]; ok {
+			if deploySet.Annotations == nil {
+				deploySet.Annotations = map[string]string{}
+			}
+			deploySet.Annotations[key] = value
+		}
+	}
+	return deploySet
+}
+
+// sync records the ManifestWorkTemplate and the rendering annotations of the ManifestWorkReplicaSet as the
+// current revision, and prunes the old revisions. A state changed back to an older revision makes the
+// revision current again.
+func (s *revisionStore) sync(ctx context.Context, mwrSet *workapiv1alpha1.ManifestWorkReplicaSet) (*revisionHistory, error) {
+	selector := labels.SelectorFromSet(labels.Set{
+		workapiv1alpha1.ManifestWorkReplicaSetControllerNameLabelKey: manifestWorkReplicaSetKey(mwrSet),
+	})
+	all, err := s.lister.ControllerRevisions(mwrSet.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(newRevisionState(mwrSet))
+	if err != nil {
+		return nil, err
+	}
+	name := revisionName(mwrSet.Name, raw)
+
+	history := &revisionHistory{}
+	var maxRevision int64
+	for _, revision := range all {
+		// the revisions of a deleted ManifestWorkReplicaSet with the same name are ignored
+		if !metav1.IsControlledBy(revision, mwrSet) {
+			continue
+		}
+		if revision.Revision > maxRevision {
+			maxRevision = revision.Revision
+		}
+		if revision.Name == name {
+			history.current = revision
+			continue
+		}
+		history.revisions = append(history.revisions, revision)
+	}
+
+	now := metav1.Now().UTC().Format(time.RFC3339)
+	switch {
+	case history.current == nil:
+		revision := &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: mwrSet.Namespace,
+				Labels: map[string]string{
+					workapiv1alpha1.ManifestWorkReplicaSetControllerNameLabelKey: manifestWorkReplicaSetKey(mwrSet),
+				},
+				Annotations: map[string]string{
+					revisionPhaseAnnotation:     revisionProgressing,
+					revisionStartTimeAnnotation: now,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(mwrSet, workapiv1alpha1.SchemeGroupVersion.WithKind("ManifestWorkReplicaSet")),
+				},
+			},
+			Data:     runtime.RawExtension{Raw: raw},
+			Revision: maxRevision + 1,
+		}
+		history.current, err = s.client.ControllerRevisions(mwrSet.Namespace).Create(ctx, revision, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create revision %s: %w", name, err)
+		}
+	case history.current.Revision < maxRevision:
+		// the template is changed back to an older revision, which is rolled out again
+		revision := history.current.DeepCopy()
+		revision.Revision = maxRevision + 1
+		if revision.Annotations == nil {
+			revision.Annotations = map[string]string{}
+		}
+		revision.Annotations[revisionPhaseAnnotation] = revisionProgressing
+		revision.Annotations[revisionStartTimeAnnotation] = now
+		delete(revision.Annotations, revisionRollbackReasonAnnotation)
+		delete(revision.Annotations, revisionHeldAnnotation)
+		history.current, err = s.client.ControllerRevisions(mwrSet.Namespace).Update(ctx, revision, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update revision %s: %w", name, err)
+		}
+	}
+
+	sort.Slice(history.revisions, func(i, j int) bool {
+		return history.revisions[i].Revision > history.revisions[j].Revision
+	})
+	var kept []*appsv1.ControllerRevision
+	for _, revision := range history.revisions {
+		if history.lastSucceeded == nil && revisionPhase(revision) == revisionSucceeded {
+			history.lastSucceeded = revision
+		} else if len(kept) >= revisionHistoryLimit {
+			err := s.client.ControllerRevisions(revision.Namespace).Delete(ctx, revision.Name, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to delete revision %s: %w", revision.Name, err)
+			}
+			continue
+		}
+		kept = append(kept, revision)
+	}
+	history.revisions = append([]*appsv1.ControllerRevision{history.current}, kept...)
+	return history, nil
+}
+
+// updatePhase updates the phase of the current revision with the rollout result. The current revision
+// is rolled back if it could not succeed, and there is a succeeded revision to roll back to. The rollback
+// timeout does not count while the rollout is held by the rollout gate, and restarts once the rollout
+// resumes. It returns the time to recheck the rollback timeout of the revision in progress.
+func (s *revisionStore) updatePhase(
+	ctx context.Context, mwrSet *workapiv1alpha1.ManifestWorkReplicaSet, history *revisionHistory,
+	succeeded, maxFailureBreach, held bool) (time.Duration, error) {
+	if revisionPhase(history.current) != revisionProgressing {
+		return 0, nil
+	}
+
+	revision := history.current.DeepCopy()
+	if revision.Annotations == nil {
+		revision.Annotations = map[string]string{}
+	}
+	var recheckAfter time.Duration
+	switch {
+	case succeeded:
+		revision.Annotations[revisionPhaseAnnotation] = revisionSucceeded
+		delete(revision.Annotations, revisionHeldAnnotation)
+	case history.lastSucceeded == nil:
+		// no revision to roll back to
+		return 0, nil
+	case maxFailureBreach:
+		revision.Annotations[revisionPhaseAnnotation] = revisionRolledBack
+		revision.Annotations[revisionRollbackReasonAnnotation] = "the failed clusters exceed the max failures of the rollout strategy"
+		delete(revision.Annotations, revisionHeldAnnotation)
+	case held:
+		revision.Annotations[revisionHeldAnnotation] = "true"
+	default:
+		timeout, err := rollout.GetRollbackTimeout(mwrSet.Annotations)
+		if err != nil || timeout == 0 {
+			return 0, err
+		}
+		if _, ok := revision.Annotations[revisionHeldAnnotation]; ok {
+			// the rollout resumes, and the timeout restarts
+			delete(revision.Annotations, revisionHeldAnnotation)
+			revision.Annotations[revisionStartTimeAnnotation] = metav1.Now().UTC().Format(time.RFC3339)
+		}
+		startTime, err := time.Parse(time.RFC3339, revision.Annotations[revisionStartTimeAnnotation])
+		if err != nil {
+			startTime = revision.CreationTimestamp.Time
+		}
+		recheckAfter = time.Until(startTime.Add(timeout))
+		if recheckAfter <= 0 {
+			revision.Annotations[revisionPhaseAnnotation] = revisionRolledBack
+			revision.Annotations[revisionRollbackReasonAnnotation] = fmt.Sprintf("the rollout does not succeed in %s", timeout)
+			recheckAfter = 0
+		}
+	}
+
+	if equality.Semantic.DeepEqual(revision.Annotations, history.current.Annotations) {
+		return recheckAfter, nil
+	}
+	updated, err := s.client.ControllerRevisions(revision.Namespace).Update(ctx, revision, metav1.UpdateOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to update revision %s: %w", revision.Name, err)
+	}
+	history.current = updated
+	history.revisions[0] = updated
+	return recheckAfter, nil
+}
+
+// setRevisionHistory sets the RevisionRolledOut condition with the phase of the current revision, and the
+// RevisionHistory condition with the revision history.
+func setRevisionHistory(mwrSet *workapiv1alpha1.ManifestWorkReplicaSet, history *revisionHistory) error {
+	if history == nil {
+		apimeta.RemoveStatusCondition(&mwrSet.Status.Conditions, ManifestWorkReplicaSetConditionRevisionRolledOut)
+		apimeta.RemoveStatusCondition(&mwrSet.Status.Conditions, ManifestWorkReplicaSetConditionRevisionHistory)
+		return nil
+	}
+
+	records := make([]RevisionRecord, 0, len(history.revisions))
+	for _, revision := range history.revisions {
+		records = append(records, RevisionRecord{
+			Revision:       revision.Revision,
+			Name:           revision.Name,
+			Phase:          revisionPhase(revision),
+			RollbackReason: revision.Annotations[revisionRollbackReasonAnnotation],
+		})
+	}
+	raw, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	apimeta.SetStatusCondition(&mwrSet.Status.Conditions, getCondition(
+		ManifestWorkReplicaSetConditionRevisionHistory, ReasonRevisionsRecorded, string(raw), metav1.ConditionTrue))
+
+	current := history.current
+	switch revisionPhase(current) {
+	case revisionSucceeded:
+		apimeta.SetStatusCondition(&mwrSet.Status.Conditions, getCondition(
+			ManifestWorkReplicaSetConditionRevisionRolledOut, ReasonRevisionSucceeded,
+			fmt.Sprintf("Revision %d succeeded", current.Revision), metav1.ConditionTrue))
+	case revisionRolledBack:
+		message := fmt.Sprintf("Revision %d is rolled back", current.Revision)
+		if history.lastSucceeded != nil {
+			message = fmt.Sprintf("%s to revision %d", message, history.lastSucceeded.Revision)
+		}
+		apimeta.SetStatusCondition(&mwrSet.Status.Conditions, getCondition(
+			ManifestWorkReplicaSetConditionRevisionRolledOut, ReasonRolledBack,
+			fmt.Sprintf("%s since %s", message, current.Annotations[revisionRollbackReasonAnnotation]),
+			metav1.ConditionFalse))
+	default:
+		apimeta.SetStatusCondition(&mwrSet.Status.Conditions, getCondition(
+			ManifestWorkReplicaSetConditionRevisionRolledOut, ReasonRevisionProgressing,
+			fmt.Sprintf("Revision %d is progressing", current.Revision), metav1.ConditionFalse))
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package helmsource
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"sigs.k8s.io/yaml"
+
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	chartrender "open-cluster-management.io/ocm/pkg/operator/helpers/chart"
+	"open-cluster-management.io/ocm/pkg/work/hub/clustertemplate"
+	"open-cluster-management.io/ocm/pkg/work/webhook/common"
+)
+
+const (
+	// HelmSourceAnnotation is the annotation of the ManifestWork or ManifestWorkReplicaSet with the helm
+	// chart source rendered into the manifests of the work, in json or yaml. The rendered manifests
+	// replace the manifests of the ManifestWork, or the manifests of the ManifestWorkTemplate of the
+	// ManifestWorkReplicaSet, which should be empty.
+	HelmSourceAnnotation = "work.open-cluster-management.io/helm-source"
+
+	// HelmChartLabel is the label required on the hub ConfigMaps holding the packaged charts, only the
+	// labeled ones are watched by the hub controllers.
+	HelmChartLabel = "work.open-cluster-management.io/helm-chart"
+
+	// DefaultChartKey is the default key of the packaged chart in the ConfigMap.
+	DefaultChartKey = "chart.tgz"
+
+	// ociTagTTL is how long the chart of an OCI tag is cached before the tag is pulled again. The chart of
+	// a digest never changes, so it does not expire.
+	ociTagTTL = 10 * time.Minute
+)
+
+// HelmSource references a packaged helm chart on the hub and the values to render it with.
+type HelmSource struct {
+	// Chart is the reference to the packaged chart.
+	Chart ChartReference `json:"chart"`
+	// ReleaseName is the release name of the chart, defaults to the chart name.
+	ReleaseName string `json:"releaseName,omitempty"`
+	// Namespace is the release namespace of the chart, defaults to "default".
+	Namespace string `json:"namespace,omitempty"`
+	// Values is the yaml values of the chart. It is a go template with the hermetic sprig functions,
+	// rendered for each cluster with .ClusterName, .ClusterLabels, .ClusterAnnotations and .ClusterClaims.
+	Values string `json:"values,omitempty"`
+}
+
+// ChartReference references a packaged chart in a ConfigMap in the namespace of the ManifestWork or
+// ManifestWorkReplicaSet, or in an OCI registry.
+type ChartReference struct {
+	// ConfigMap is the name of the ConfigMap with the chart in its binary data.
+	ConfigMap string `json:"configMap,omitempty"`
+	// Key is the key of the chart in the ConfigMap, defaults to chart.tgz.
+	Key string `json:"key,omitempty"`
+	// OCI is the chart repository in an OCI registry, e.g. oci://registry.example.com/charts/nginx.
+	// The registry should allow the anonymous pulls, and be in the allowed registries of the hub.
+	OCI string `json:"oci,omitempty"`
+	// Version is the tag or the digest of the chart in the OCI registry, required with OCI.
+	Version string `json:"version,omitempty"`
+}
+
+// GetHelmSource returns the helm source in the annotations, false if there is no helm source.
+func GetHelmSource(annotations map[string]string) (*HelmSource, bool, error) {
+	value, ok := annotations[HelmSourceAnnotation]
+	if !ok {
+		return nil, false, nil
+	}
+
+	source := &HelmSource{}
+	if err := yaml.UnmarshalStrict([]byte(value), source); err != nil {
+		return nil, true, fmt.Errorf("invalid helm source annotation: %v", err)
+	}
+	if err := validateHelmSource(source); err != nil {
+		return nil, true, err
+	}
+	return source, true, nil
+}
+
+func validateHelmSource(source *HelmSource) error {
+	chartRef := source.Chart
+	switch {
+	case len(chartRef.ConfigMap) == 0 && len(chartRef.OCI) == 0:
+		return fmt.Errorf("invalid helm source annotation: one of chart.configMap or chart.oci is required")
+	case len(chartRef.ConfigMap) > 0 && len(chartRef.OCI) > 0:
+		return fmt.Errorf("invalid helm source annotation: only one of chart.configMap or chart.oci is allowed")
+	case len(chartRef.ConfigMap) > 0 && len(chartRef.Version) > 0:
+		return fmt.Errorf("invalid helm source annotation: chart.version is only allowed with chart.oci")
+	case len(chartRef.OCI) > 0 && len(chartRef.Key) > 0:
+		return fmt.Errorf("invalid helm source annotation: chart.key is only allowed with chart.configMap")
+	case len(chartRef.OCI) > 0 && len(chartRef.Version) == 0:
+		return fmt.Errorf("invalid helm source annotation: chart.version is required with chart.oci")
+	}
+	if len(chartRef.OCI) > 0 {
+		host, _, err := parseOCIReference(chartRef.OCI)
+		if err != nil {
+			return fmt.Errorf("invalid helm source annotation: %v", err)
+		}
+		if err := AllowedRegistries.Validate(host); err != nil {
+			return fmt.Errorf("invalid helm source annotation: %v", err)
+		}
+	}
+	if _, err := parseValuesTemplate(source.Values); err != nil {
+		return fmt.Errorf("invalid helm source annotation: %v", err)
+	}
+	return nil
+}
+
+func parseValuesTemplate(values string) (*template.Template, error) {
+	return clustertemplate.Parse("values", values, false)
+}
+
+type cachedChart struct {
+	// resourceVersion is the resource version of the ConfigMap of the chart.
+	resourceVersion string
+	// expires is when the chart of an OCI tag is pulled again, zero if the chart never expires.
+	expires time.Time
+	chart   *chart.Chart
+}
+
+// Renderer renders the helm sources into manifests for the managed clusters.
+type Renderer struct {
+	configMapLister corev1listers.ConfigMapLister
+	clusterLister   clusterlisterv1.ManagedClusterLister
+	ociClient       *ociClient
+
+	// charts caches the loaded charts keyed by the ConfigMap and key, or the OCI reference and version.
+	lock   sync.Mutex
+	charts map[string]cachedChart
+}
+
+// NewRenderer returns a Renderer reading the charts from the ConfigMaps or the OCI registries, and the
+// cluster labels and claims from the ManagedClusters.
+func NewRenderer(
+	configMapLister corev1listers.ConfigMapLister,
+	clusterLister clusterlisterv1.ManagedClusterLister) *Renderer {
+	return &Renderer{
+		configMapLister: configMapLister,
+		clusterLister:   clusterLister,
+		ociClient:       newOCIClient(nil, AllowedRegistries),
+		charts:          map[string]cachedChart{},
+	}
+}
+
+// Render renders the chart of the helm source in the namespace for the cluster. The CRDs of the chart
+// are returned first. The templates of the chart should set the namespace of the namespaced resources,
+// e.g. with .Release.Namespace, since the manifests are applied as they are on the managed cluster.
+func (r *Renderer) Render(ctx context.Context, namespace, clusterName string, source *HelmSource) ([]workapiv1.Manifest, error) {
+	helmChart, err := r.loadChart(ctx, namespace, source.Chart)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := r.clusterLister.Get(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	values, err := renderValues(source.Values, clustertemplate.NewData(cluster, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	releaseOptions := chartutil.ReleaseOptions{
+		Name:      source.ReleaseName,
+		Namespace: source.Namespace,
+		IsInstall: true,
+	}
+	if len(releaseOptions.Name) == 0 {
+		releaseOptions.Name = helmChart.Name()
+	}
+	if len(releaseOptions.Namespace) == 0 {
+		releaseOptions.Namespace = "default"
+	}
+
+	crdObjects, rawObjects, err := chartrender.RenderUserChart(ctx, helmChart, values, releaseOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %w", helmChart.Name(), err)
+	}
+
+	var manifests []workapiv1.Manifest
+	for _, raw := range append(crdObjects, rawObjects...) {
+		manifest, err := yaml.YAMLToJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, workapiv1.Manifest{RawExtension: runtime.RawExtension{Raw: manifest}})
+	}
+
+	// the rendered manifests are validated as the manifests of a ManifestWork, so an invalid chart is
+	// reported before any ManifestWork is updated with them.
+	if err := common.ManifestValidator.ValidateManifests(manifests); err != nil {
+		return nil, fmt.Errorf("invalid manifests rendered from chart %s: %w", helmChart.Name(), err)
+	}
+	return manifests, nil
+}
+
+func renderValues(valuesTemplate string, data clustertemplate.Data) (chartutil.Values, error) {
+	tmpl, err := parseValuesTemplate(valuesTemplate)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := clustertemplate.Execute(tmpl, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render values: %w", err)
+	}
+	values, err := chartutil.ReadValues([]byte(rendered))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read values: %w", err)
+	}
+	return values, nil
+}
+
+func (r *Renderer) loadChart(ctx context.Context, namespace string, ref ChartReference) (*chart.Chart, error) {
+	if len(ref.OCI) > 0 {
+		return r.loadOCIChart(ctx, ref)
+	}
+
+	key := ref.Key
+	if len(key) == 0 {
+		key = DefaultChartKey
+	}
+	cm, err := r.configMapLister.ConfigMaps(namespace).Get(ref.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := strings.Join([]string{"configmap", namespace, ref.ConfigMap, key}, "/")
+	archive := cm.BinaryData[key]
+	if len(archive) == 0 {
+		return nil, fmt.Errorf("chart is not found in key %q of %s", key, cacheKey)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if cached, ok := r.charts[cacheKey]; ok && cached.resourceVersion == cm.ResourceVersion {
+		return cached.chart, nil
+	}
+
+	helmChart, err := loader.LoadArchive(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart from %s: %w", cacheKey, err)
+	}
+	r.charts[cacheKey] = cachedChart{resourceVersion: cm.ResourceVersion, chart: helmChart}
+	return helmChart, nil
+}
+
+// loadOCIChart pulls the chart from the OCI registry. The lock is not held during the pull, so a chart
+// may be pulled more than once by the concurrent renders.
+func (r *Renderer) loadOCIChart(ctx context.Context, ref ChartReference) (*chart.Chart, error) {
+	cacheKey := fmt.Sprintf("%s:%s", ref.OCI, ref.Version)
+	r.lock.Lock()
+	cached, ok := r.charts[cacheKey]
+	r.lock.Unlock()
+	if ok && (cached.expires.IsZero() || time.Now().Before(cached.expires)) {
+		return cached.chart, nil
+	}
+
+	archive, err := r.ociClient.pull(ctx, ref.OCI, ref.Version)
+	if err != nil {
+		return nil, err
+	}
+	helmChart, err := loader.LoadArchive(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart from %s: %w", cacheKey, err)
+	}
+
+	cached = cachedChart{chart: helmChart}
+	if !isDigest(ref.Version) {
+		cached.expires = time.Now().Add(ociTagTTL)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.charts[cacheKey] = cached
+	return helmChart, nil
+}
@@ -0,0 +1,345 @@
+package helmsource
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	helpertest "open-cluster-management.io/ocm/pkg/work/hub/test"
+)
+
+const (
+	testChartYaml = `apiVersion: v2
+name: nginx
+version: 0.1.0
+`
+	testValuesYaml = `replicas: 1
+region: unknown
+`
+	testDeploymentYaml = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+  namespace: {{ .Release.Namespace }}
+  labels:
+    region: {{ .Values.region }}
+spec:
+  replicas: {{ .Values.replicas }}
+{{- if .Values.paused }}
+  paused: true
+{{- end }}
+`
+	testCRDYaml = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: foos.example.com
+`
+)
+
+func newTestRenderer(t *testing.T, objs ...interface{}) *Renderer {
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	clusterIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, obj := range objs {
+		var err error
+		switch obj.(type) {
+		case *corev1.ConfigMap:
+			err = configMapIndexer.Add(obj)
+		case *clusterv1.ManagedCluster:
+			err = clusterIndexer.Add(obj)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewRenderer(
+		corev1listers.NewConfigMapLister(configMapIndexer),
+		clusterlisterv1.NewManagedClusterLister(clusterIndexer),
+	)
+}
+
+func TestGetHelmSource(t *testing.T) {
+	AllowedRegistries.WithRegistries([]string{"registry.example.com"})
+	defer AllowedRegistries.WithRegistries(nil)
+
+	cases := []struct {
+		name           string
+		annotations    map[string]string
+		expectedFound  bool
+		expectedSource *HelmSource
+		expectedErr    string
+	}{
+		{
+			name: "no helm source",
+		},
+		{
+			name: "json helm source",
+			annotations: map[string]string{
+				HelmSourceAnnotation: `{"chart":{"configMap":"nginx"},"namespace":"web","values":"replicas: 2"}`,
+			},
+			expectedFound: true,
+			expectedSource: &HelmSource{
+				Chart:     ChartReference{ConfigMap: "nginx"},
+				Namespace: "web",
+				Values:    "replicas: 2",
+			},
+		},
+		{
+			name: "yaml helm source",
+			annotations: map[string]string{
+				HelmSourceAnnotation: "chart:\n  oci: oci://registry.example.com/charts/nginx\n  version: 0.1.0\nreleaseName: web\n",
+			},
+			expectedFound: true,
+			expectedSource: &HelmSource{
+				Chart:       ChartReference{OCI: "oci://registry.example.com/charts/nginx", Version: "0.1.0"},
+				ReleaseName: "web",
+			},
+		},
+		{
+			name: "unknown field",
+			annotations: map[string]string{
+				HelmSourceAnnotation: `{"chart":{"secret":"nginx"}}`,
+			},
+			expectedFound: true,
+			expectedErr:   "invalid helm source annotation",
+		},
+		{
+			name: "no chart",
+			annotations: map[string]string{
+				HelmSourceAnnotation: `{"values":"replicas: 2"}`,
+			},
+			expectedFound: true,
+			expectedErr:   "one of chart.configMap or chart.oci is required",
+		},
+		{
+			name: "oci chart without version",
+			annotations: map[string]string{
+				HelmSourceAnnotation: `{"chart":{"oci":"oci://registry.example.com/charts/nginx"}}`,
+			},
+			expectedFound: true,
+			expectedErr:   "chart.version is required with chart.oci",
+		},
+		{
+			name: "invalid oci reference",
+			annotations: map[string]string{
+				HelmSourceAnnotation: `{"chart":{"oci":"https://registry.example.com/charts/nginx","version":"0.1.0"}}`,
+			},
+			expectedFound: true,
+			expectedErr:   "should start with oci://",
+		},
+		{
+			name: "oci chart of a registry not allowed",
+			annotations: map[string]string{
+				HelmSourceAnnotation: `{"chart":{"oci":"oci://169.254.169.254/charts/nginx","version":"0.1.0"}}`,
+			},
+			expectedFound: true,
+			expectedErr:   `the registry "169.254.169.254" is not allowed`,
+		},
+		{
+			name: "version of configmap chart",
+			annotations: map[string]string{
+				HelmSourceAnnotation: `{"chart":{"configMap":"nginx","version":"0.1.0"}}`,
+			},
+			expectedFound: true,
+			expectedErr:   "chart.version is only allowed with chart.oci",
+		},
+		{
+			name: "env function in values template",
+			annotations: map[string]string{
+				HelmSourceAnnotation: `{"chart":{"configMap":"nginx"},"values":"token: {{ env \"KUBECONFIG\" }}"}`,
+			},
+			expectedFound: true,
+			expectedErr:   `function "env" not defined`,
+		},
+		{
+			name: "expandenv function in values template",
+			annotations: map[string]string{
+				HelmSourceAnnotation: `{"chart":{"configMap":"nginx"},"values":"home: {{ expandenv \"$HOME\" }}"}`,
+			},
+			expectedFound: true,
+			expectedErr:   `function "expandenv" not defined`,
+		},
+		{
+			name: "invalid values template",
+			annotations: map[string]string{
+				HelmSourceAnnotation: `{"chart":{"configMap":"nginx"},"values":"region: {{ .ClusterName"}`,
+			},
+			expectedFound: true,
+			expectedErr:   "invalid helm source annotation",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			source, found, err := GetHelmSource(c.annotations)
+			if found != c.expectedFound {
+				t.Errorf("expected found %v, but got %v", c.expectedFound, found)
+			}
+			if len(c.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), c.expectedErr) {
+					t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.expectedSource != nil && *source != *c.expectedSource {
+				t.Errorf("expected source %v, but got %v", c.expectedSource, source)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	archive, err := helpertest.CreateTestChartArchive("nginx", map[string]string{
+		"Chart.yaml":             testChartYaml,
+		"values.yaml":            testValuesYaml,
+		"templates/deploy.yaml":  testDeploymentYaml,
+		"templates/NOTES.txt":    "installed",
+		"crds/foos.example.yaml": testCRDYaml,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{"region": "east"}},
+		Status: clusterv1.ManagedClusterStatus{
+			ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: "replicas.example.com", Value: "3"}},
+		},
+	}
+
+	cases := []struct {
+		name             string
+		objs             []interface{}
+		source           *HelmSource
+		expectedErr      string
+		expectedReplicas int64
+		expectedRegion   string
+		expectedName     string
+	}{
+		{
+			name: "chart in configmap with default values",
+			objs: []interface{}{
+				cluster,
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "ns1"},
+					BinaryData: map[string][]byte{DefaultChartKey: archive},
+				},
+			},
+			source:           &HelmSource{Chart: ChartReference{ConfigMap: "nginx"}},
+			expectedReplicas: 1,
+			expectedRegion:   "unknown",
+			expectedName:     "nginx",
+		},
+		{
+			name: "chart in configmap key with cluster values",
+			objs: []interface{}{
+				cluster,
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "ns1"},
+					BinaryData: map[string][]byte{"nginx.tgz": archive},
+				},
+			},
+			source: &HelmSource{
+				Chart:       ChartReference{ConfigMap: "nginx", Key: "nginx.tgz"},
+				ReleaseName: "web",
+				Values: `region: {{ .ClusterLabels.region }}
+replicas: {{ index .ClusterClaims "replicas.example.com" }}
+name: {{ .ClusterName | upper }}`,
+			},
+			expectedReplicas: 3,
+			expectedRegion:   "east",
+			expectedName:     "web",
+		},
+		{
+			name: "chart not found",
+			objs: []interface{}{
+				cluster,
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "ns2"},
+					BinaryData: map[string][]byte{DefaultChartKey: archive},
+				},
+			},
+			source:      &HelmSource{Chart: ChartReference{ConfigMap: "nginx"}},
+			expectedErr: "not found",
+		},
+		{
+			name: "key not found",
+			objs: []interface{}{
+				cluster,
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "ns1"},
+					BinaryData: map[string][]byte{"nginx.tgz": archive},
+				},
+			},
+			source:      &HelmSource{Chart: ChartReference{ConfigMap: "nginx"}},
+			expectedErr: `chart is not found in key "chart.tgz"`,
+		},
+		{
+			name: "cluster not found",
+			objs: []interface{}{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "ns1"},
+					BinaryData: map[string][]byte{DefaultChartKey: archive},
+				},
+			},
+			source:      &HelmSource{Chart: ChartReference{ConfigMap: "nginx"}},
+			expectedErr: "not found",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			renderer := newTestRenderer(t, c.objs...)
+			manifests, err := renderer.Render(context.TODO(), "ns1", "cluster1", c.source)
+			if len(c.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), c.expectedErr) {
+					t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(manifests) != 2 {
+				t.Fatalf("expected 2 manifests, but got %d", len(manifests))
+			}
+			crd := map[string]interface{}{}
+			if err := json.Unmarshal(manifests[0].Raw, &crd); err != nil {
+				t.Fatal(err)
+			}
+			if crd["kind"] != "CustomResourceDefinition" {
+				t.Errorf("expected the crd first, but got %v", crd["kind"])
+			}
+
+			deploy := struct {
+				metav1.ObjectMeta `json:"metadata"`
+				Spec              struct {
+					Replicas int64 `json:"replicas"`
+				} `json:"spec"`
+			}{}
+			if err := json.Unmarshal(manifests[1].Raw, &deploy); err != nil {
+				t.Fatal(err)
+			}
+			if deploy.Name != c.expectedName || deploy.Namespace != "default" {
+				t.Errorf("expected deployment default/%s, but got %s/%s", c.expectedName, deploy.Namespace, deploy.Name)
+			}
+			if deploy.Spec.Replicas != c.expectedReplicas {
+				t.Errorf("expected replicas %d, but got %d", c.expectedReplicas, deploy.Spec.Replicas)
+			}
+			if deploy.Labels["region"] != c.expectedRegion {
+				t.Errorf("expected region %s, but got %s", c.expectedRegion, deploy.Labels["region"])
+			}
+		})
+	}
+}
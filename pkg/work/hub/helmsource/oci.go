@@ -0,0 +1,254 @@
+package helmsource
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// OCIScheme is the scheme of the chart references in the OCI registries.
+	OCIScheme = "oci://"
+
+	ociManifestMediaType   = "application/vnd.oci.image.manifest.v1+json"
+	ociChartLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	// maxChartSize is the max size of the chart pulled from an OCI registry, the rendered manifests are
+	// limited by the manifest size limit of the ManifestWork anyway.
+	maxChartSize = 10 * 1024 * 1024
+)
+
+// bearerChallengePattern matches the parameters of a Bearer challenge in the WWW-Authenticate header.
+var bearerChallengePattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// RegistryAllowList is the hosts that the charts are allowed to be pulled from, since the hub controllers
+// send the requests to the registries of the helm sources created by the hub users.
+type RegistryAllowList struct {
+	hosts sets.Set[string]
+}
+
+// AllowedRegistries is the hosts allowed in the OCI chart references, the token services and the
+// redirects of the registries. No host is allowed by default.
+var AllowedRegistries = &RegistryAllowList{hosts: sets.New[string]()}
+
+// WithRegistries sets the allowed hosts, in the format of <host> or <host>:<port>.
+func (l *RegistryAllowList) WithRegistries(registries []string) {
+	hosts := sets.New[string]()
+	for _, registry := range registries {
+		if registry = strings.ToLower(strings.TrimSpace(registry)); len(registry) > 0 {
+			hosts.Insert(registry)
+		}
+	}
+	l.hosts = hosts
+}
+
+// Validate returns an error if the host is not allowed.
+func (l *RegistryAllowList) Validate(host string) error {
+	if !l.hosts.Has(strings.ToLower(host)) {
+		return fmt.Errorf("the registry %q is not allowed", host)
+	}
+	return nil
+}
+
+// ociClient pulls the packaged charts from the OCI registries with the distribution api. Only the
+// anonymous pulls are supported, with the bearer tokens issued by the token service of the registry.
+// The requests are sent only to the allowed hosts, including the token services and the redirects.
+type ociClient struct {
+	httpClient *http.Client
+	allowList  *RegistryAllowList
+}
+
+func newOCIClient(httpClient *http.Client, allowList *RegistryAllowList) *ociClient {
+	client := &http.Client{Timeout: 30 * time.Second}
+	if httpClient != nil {
+		client = &http.Client{Transport: httpClient.Transport, Timeout: httpClient.Timeout}
+	}
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		if req.URL.Scheme != "https" {
+			return fmt.Errorf("the redirect to %q is not https", req.URL.Redacted())
+		}
+		return allowList.Validate(req.URL.Host)
+	}
+	return &ociClient{httpClient: client, allowList: allowList}
+}
+
+// parseOCIReference returns the registry host and the repository of the chart reference in the
+// format of oci://<registry>/<repository>.
+func parseOCIReference(ref string) (string, string, error) {
+	if !strings.HasPrefix(ref, OCIScheme) {
+		return "", "", fmt.Errorf("the oci reference %q should start with %s", ref, OCIScheme)
+	}
+	host, repository, ok := strings.Cut(strings.TrimPrefix(ref, OCIScheme), "/")
+	if !ok || len(host) == 0 || len(repository) == 0 {
+		return "", "", fmt.Errorf("the oci reference %q should be in the format of %s<registry>/<repository>", ref, OCIScheme)
+	}
+	return host, strings.TrimSuffix(repository, "/"), nil
+}
+
+// isDigest returns if the version is a content digest, the chart of which never changes.
+func isDigest(version string) bool {
+	return strings.HasPrefix(version, "sha256:")
+}
+
+// pull returns the packaged chart of the version, which is a tag or a digest, in the repository.
+func (c *ociClient) pull(ctx context.Context, ref, version string) ([]byte, error) {
+	host, repository, err := parseOCIReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.allowList.Validate(host); err != nil {
+		return nil, err
+	}
+
+	token := ""
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, repository, version)
+	data, token, err := c.get(ctx, manifestURL, ociManifestMediaType, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the manifest of %s:%s: %w", ref, version, err)
+	}
+	manifest := &ociManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest of %s:%s: %w", ref, version, err)
+	}
+
+	var layer *ociDescriptor
+	for i := range manifest.Layers {
+		if manifest.Layers[i].MediaType == ociChartLayerMediaType {
+			layer = &manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return nil, fmt.Errorf("%s:%s is not a helm chart", ref, version)
+	}
+	if layer.Size > maxChartSize {
+		return nil, fmt.Errorf("the chart %s:%s of size %d exceeds the limit %d", ref, version, layer.Size, maxChartSize)
+	}
+
+	blobURL := fmt.Sprintf("https://%s/v2/%s/blobs/%s", host, repository, layer.Digest)
+	archive, _, err := c.get(ctx, blobURL, ociChartLayerMediaType, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the chart of %s:%s: %w", ref, version, err)
+	}
+	digest := sha256.Sum256(archive)
+	if layer.Digest != "sha256:"+hex.EncodeToString(digest[:]) {
+		return nil, fmt.Errorf("the digest of the chart %s:%s does not match %s", ref, version, layer.Digest)
+	}
+	return archive, nil
+}
+
+// get requests the url with the bearer token. If the registry requires a token, the token is requested
+// from the token service in the challenge, and returned to be reused by the following requests.
+func (c *ociClient) get(ctx context.Context, rawURL, mediaType, token string) ([]byte, string, error) {
+	resp, err := c.do(ctx, rawURL, mediaType, token)
+	if err != nil {
+		return nil, token, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && len(token) == 0 {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		if token, err = c.token(ctx, challenge); err != nil {
+			return nil, token, err
+		}
+		if resp, err = c.do(ctx, rawURL, mediaType, token); err != nil {
+			return nil, token, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, token, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxChartSize+1))
+	if err != nil {
+		return nil, token, err
+	}
+	if len(data) > maxChartSize {
+		return nil, token, fmt.Errorf("the response exceeds the limit %d", maxChartSize)
+	}
+	return data, token, nil
+}
+
+func (c *ociClient) do(ctx context.Context, rawURL, mediaType, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaType)
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// token requests an anonymous token from the token service in the Bearer challenge of the registry.
+func (c *ociClient) token(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported authentication challenge %q, only the anonymous pulls are supported", challenge)
+	}
+	values := map[string]string{}
+	for _, match := range bearerChallengePattern.FindAllStringSubmatch(params, -1) {
+		values[match[1]] = match[2]
+	}
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.Scheme != "https" {
+		return "", fmt.Errorf("invalid token realm %q", values["realm"])
+	}
+	if err := c.allowList.Validate(realm.Host); err != nil {
+		return "", fmt.Errorf("invalid token realm %q: %w", values["realm"], err)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if len(values[key]) > 0 {
+			query.Set(key, values[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := c.do(ctx, realm.String(), "application/json", "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to request the token from %s: %s", realm.Host, resp.Status)
+	}
+	tokenResponse := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("invalid token response: %w", err)
+	}
+	if len(tokenResponse.Token) > 0 {
+		return tokenResponse.Token, nil
+	}
+	if len(tokenResponse.AccessToken) > 0 {
+		return tokenResponse.AccessToken, nil
+	}
+	return "", fmt.Errorf("no token in the response from %s", realm.Host)
+}
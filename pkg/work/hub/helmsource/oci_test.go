@@ -0,0 +1,162 @@
+package helmsource
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	helpertest "open-cluster-management.io/ocm/pkg/work/hub/test"
+)
+
+// newTestRegistry starts an OCI registry serving the chart archive as charts/nginx:0.1.0, which requires
+// an anonymous token from its token service.
+func newTestRegistry(t *testing.T, archive []byte) (*httptest.Server, *int) {
+	digest := sha256.Sum256(archive)
+	layerDigest := "sha256:" + hex.EncodeToString(digest[:])
+	pulls := 0
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/charts/foreign/"):
+			// the token service is on another host
+			w.Header().Set("WWW-Authenticate",
+				`Bearer realm="https://auth.example.com/token",service="registry",scope="repository:charts/foreign:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		case strings.HasPrefix(r.URL.Path, "/v2/charts/moved/"):
+			http.Redirect(w, r, "https://169.254.169.254/latest/meta-data", http.StatusTemporaryRedirect)
+			return
+		}
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:charts/nginx:pull" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+				`Bearer realm="%s/token",service="registry",scope="repository:charts/nginx:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/charts/nginx/manifests/0.1.0":
+			manifest := ociManifest{Layers: []ociDescriptor{{
+				MediaType: ociChartLayerMediaType, Digest: layerDigest, Size: int64(len(archive))}}}
+			_ = json.NewEncoder(w).Encode(manifest)
+		case "/v2/charts/nginx/manifests/0.2.0":
+			manifest := ociManifest{Layers: []ociDescriptor{{
+				MediaType: "application/vnd.oci.image.layer.v1.tar", Digest: layerDigest, Size: int64(len(archive))}}}
+			_ = json.NewEncoder(w).Encode(manifest)
+		case "/v2/charts/nginx/blobs/" + layerDigest:
+			pulls++
+			_, _ = w.Write(archive)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &pulls
+}
+
+func TestRenderOCIChart(t *testing.T) {
+	archive, err := helpertest.CreateTestChartArchive("nginx", map[string]string{
+		"Chart.yaml":            testChartYaml,
+		"values.yaml":           testValuesYaml,
+		"templates/deploy.yaml": testDeploymentYaml,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, pulls := newTestRegistry(t, archive)
+	registry := strings.TrimPrefix(server.URL, "https://")
+
+	cases := []struct {
+		name        string
+		ref         ChartReference
+		registries  []string
+		expectedErr string
+	}{
+		{
+			name:       "chart of a tag",
+			ref:        ChartReference{OCI: OCIScheme + registry + "/charts/nginx", Version: "0.1.0"},
+			registries: []string{registry},
+		},
+		{
+			name:        "registry not allowed",
+			ref:         ChartReference{OCI: OCIScheme + registry + "/charts/nginx", Version: "0.1.0"},
+			registries:  []string{"registry.example.com"},
+			expectedErr: "is not allowed",
+		},
+		{
+			name:        "token service not allowed",
+			ref:         ChartReference{OCI: OCIScheme + registry + "/charts/foreign", Version: "0.1.0"},
+			registries:  []string{registry},
+			expectedErr: `the registry "auth.example.com" is not allowed`,
+		},
+		{
+			name:        "redirect not allowed",
+			ref:         ChartReference{OCI: OCIScheme + registry + "/charts/moved", Version: "0.1.0"},
+			registries:  []string{registry},
+			expectedErr: `the registry "169.254.169.254" is not allowed`,
+		},
+		{
+			name:        "tag not found",
+			ref:         ChartReference{OCI: OCIScheme + registry + "/charts/nginx", Version: "0.3.0"},
+			registries:  []string{registry},
+			expectedErr: "404",
+		},
+		{
+			name:        "not a chart",
+			ref:         ChartReference{OCI: OCIScheme + registry + "/charts/nginx", Version: "0.2.0"},
+			registries:  []string{registry},
+			expectedErr: "is not a helm chart",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			renderer := newTestRenderer(t, &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}})
+			allowList := &RegistryAllowList{}
+			allowList.WithRegistries(c.registries)
+			renderer.ociClient = newOCIClient(server.Client(), allowList)
+
+			source := &HelmSource{Chart: c.ref}
+			manifests, err := renderer.Render(context.TODO(), "ns1", "cluster1", source)
+			if len(c.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), c.expectedErr) {
+					t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(manifests) != 1 {
+				t.Fatalf("expected 1 manifest, but got %d", len(manifests))
+			}
+
+			// the chart of the tag is cached
+			if _, err := renderer.Render(context.TODO(), "ns1", "cluster1", source); err != nil {
+				t.Fatal(err)
+			}
+			if *pulls != 1 {
+				t.Errorf("expected the chart pulled once, but got %d", *pulls)
+			}
+		})
+	}
+}
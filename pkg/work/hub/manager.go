@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 
 	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
@@ -13,6 +16,7 @@ import (
 	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
 	workv1informer "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
 	ocmfeature "open-cluster-management.io/api/feature"
+	workapiv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
 	workapplier "open-cluster-management.io/sdk-go/pkg/apis/work/v1/applier"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/work"
@@ -22,7 +26,10 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/work/hub/controllers/manifestworkgarbagecollection"
+	"open-cluster-management.io/ocm/pkg/work/hub/controllers/manifestworkhelmsource"
 	"open-cluster-management.io/ocm/pkg/work/hub/controllers/manifestworkreplicasetcontroller"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
+	"open-cluster-management.io/ocm/pkg/work/hub/metrics"
 )
 
 const sourceID = "mwrsctrl"
@@ -102,6 +109,7 @@ func (c *WorkHubManagerConfig) RunWorkHubManager(ctx context.Context, controller
 		watcherStore.SetInformer(informer.Informer())
 	}
 
+	helmsource.AllowedRegistries.WithRegistries(c.workOptions.HelmAllowedRegistries)
 	return RunControllerManagerWithInformers(
 		ctx,
 		controllerContext,
@@ -122,6 +130,28 @@ func RunControllerManagerWithInformers(
 ) error {
 	replicaSetInformerFactory := workinformers.NewSharedInformerFactory(replicaSetClient, 30*time.Minute)
 
+	kubeClient, err := kubernetes.NewForConfig(controllerContext.KubeConfig)
+	if err != nil {
+		return err
+	}
+	// only the ConfigMaps labeled as helm charts are watched
+	helmChartInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 30*time.Minute,
+		kubeinformers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.LabelSelector = helmsource.HelmChartLabel
+		}))
+	helmChartConfigMapInformer := helmChartInformerFactory.Core().V1().ConfigMaps()
+	// only the ControllerRevisions of the ManifestWorkReplicaSets are watched
+	revisionInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 30*time.Minute,
+		kubeinformers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.LabelSelector = workapiv1alpha1.ManifestWorkReplicaSetControllerNameLabelKey
+		}))
+	clusterInformer := clusterInformers.Cluster().V1().ManagedClusters()
+	// the helm sources are not rendered unless the feature is enabled
+	var helmRenderer *helmsource.Renderer
+	if features.HubMutableFeatureGate.Enabled(features.HelmSource) {
+		helmRenderer = helmsource.NewRenderer(helmChartConfigMapInformer.Lister(), clusterInformer.Lister())
+	}
+
 	manifestWorkReplicaSetController := manifestworkreplicasetcontroller.NewManifestWorkReplicaSetController(
 		replicaSetClient,
 		workapplier.NewWorkApplierWithTypedClient(workClient, workInformer.Lister()),
@@ -129,6 +159,19 @@ func RunControllerManagerWithInformers(
 		workInformer,
 		clusterInformers.Cluster().V1beta1().Placements(),
 		clusterInformers.Cluster().V1beta1().PlacementDecisions(),
+		helmRenderer,
+		helmChartConfigMapInformer,
+		clusterInformer,
+		kubeClient.AppsV1(),
+		revisionInformerFactory.Apps().V1().ControllerRevisions(),
+	)
+
+	manifestWorkHelmSourceController := manifestworkhelmsource.NewManifestWorkHelmSourceController(
+		workClient,
+		workInformer,
+		helmRenderer,
+		helmChartConfigMapInformer,
+		clusterInformer,
 	)
 
 	manifestWorkGarbageCollectionController := manifestworkgarbagecollection.NewManifestWorkGarbageCollectionController(
@@ -136,14 +179,21 @@ func RunControllerManagerWithInformers(
 		workInformer,
 	)
 
+	metrics.RegisterDriftCollector(workInformer.Lister())
+
 	go clusterInformers.Start(ctx.Done())
 	go replicaSetInformerFactory.Start(ctx.Done())
+	go helmChartInformerFactory.Start(ctx.Done())
+	go revisionInformerFactory.Start(ctx.Done())
 	if features.HubMutableFeatureGate.Enabled(ocmfeature.ManifestWorkReplicaSet) {
 		go manifestWorkReplicaSetController.Run(ctx, 5)
 	}
 	if features.HubMutableFeatureGate.Enabled(ocmfeature.CleanUpCompletedManifestWork) {
 		go manifestWorkGarbageCollectionController.Run(ctx, 5)
 	}
+	if features.HubMutableFeatureGate.Enabled(features.HelmSource) {
+		go manifestWorkHelmSourceController.Run(ctx, 5)
+	}
 
 	go workInformer.Informer().Run(ctx.Done())
 
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+const (
+	// Constants for drift metric names.
+	WorkSubsystem           = "manifestwork"
+	DriftedManifestsKey     = "drifted_manifests"
+	AuditedManifestWorksKey = "drift_audited"
+)
+
+var (
+	driftedManifestsDesc = k8smetrics.NewDesc(
+		k8smetrics.BuildFQName("", WorkSubsystem, DriftedManifestsKey),
+		"Number of the manifests whose resources diverge from the manifests in the ManifestWorks in the drift audit mode per cluster namespace.",
+		[]string{"namespace"}, nil, k8smetrics.ALPHA, "")
+
+	auditedManifestWorksDesc = k8smetrics.NewDesc(
+		k8smetrics.BuildFQName("", WorkSubsystem, AuditedManifestWorksKey),
+		"Number of the ManifestWorks in the drift audit mode per cluster namespace.",
+		[]string{"namespace"}, nil, k8smetrics.ALPHA, "")
+
+	registerOnce sync.Once
+	collector    = &driftCollector{}
+)
+
+// RegisterDriftCollector registers the collector of the drift metrics built from the Drifted conditions
+// in the status of the ManifestWorks in the lister. The metrics are collected on each scrape.
+func RegisterDriftCollector(lister worklister.ManifestWorkLister) {
+	collector.setLister(lister)
+	registerOnce.Do(func() {
+		legacyregistry.CustomMustRegister(collector)
+	})
+}
+
+type driftCollector struct {
+	k8smetrics.BaseStableCollector
+
+	lock   sync.RWMutex
+	lister worklister.ManifestWorkLister
+}
+
+var _ k8smetrics.StableCollector = &driftCollector{}
+
+// NewDriftCollector returns a collector of the drift metrics of the ManifestWorks in the lister.
+func NewDriftCollector(lister worklister.ManifestWorkLister) k8smetrics.StableCollector {
+	return &driftCollector{lister: lister}
+}
+
+func (c *driftCollector) setLister(lister worklister.ManifestWorkLister) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.lister = lister
+}
+
+func (c *driftCollector) DescribeWithStability(ch chan<- *k8smetrics.Desc) {
+	ch <- driftedManifestsDesc
+	ch <- auditedManifestWorksDesc
+}
+
+func (c *driftCollector) CollectWithStability(ch chan<- k8smetrics.Metric) {
+	c.lock.RLock()
+	lister := c.lister
+	c.lock.RUnlock()
+	if lister == nil {
+		return
+	}
+
+	works, err := lister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	// the metrics are aggregated per cluster namespace to keep the cardinality bounded by the clusters
+	audited, drifted := map[string]int{}, map[string]int{}
+	for _, work := range works {
+		if !helper.IsDriftAuditMode(work) {
+			continue
+		}
+		audited[work.Namespace]++
+		for _, manifest := range work.Status.ResourceStatus.Manifests {
+			if meta.IsStatusConditionPresentAndEqual(manifest.Conditions, helper.ManifestDrifted, metav1.ConditionTrue) {
+				drifted[work.Namespace]++
+			}
+		}
+	}
+
+	for namespace, count := range audited {
+		ch <- k8smetrics.NewLazyConstMetric(auditedManifestWorksDesc, k8smetrics.GaugeValue, float64(count), namespace)
+		ch <- k8smetrics.NewLazyConstMetric(driftedManifestsDesc, k8smetrics.GaugeValue, float64(drifted[namespace]), namespace)
+	}
+}
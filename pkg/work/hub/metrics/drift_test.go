@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/testutil"
+
+	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+func newWork(namespace, name string, audit bool, drifted ...bool) *workapiv1.ManifestWork {
+	work := &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+	}
+	if audit {
+		work.Annotations = map[string]string{helper.DriftDetectionAnnotation: helper.DriftDetectionAudit}
+	}
+	for _, d := range drifted {
+		status := metav1.ConditionFalse
+		if d {
+			status = metav1.ConditionTrue
+		}
+		work.Status.ResourceStatus.Manifests = append(work.Status.ResourceStatus.Manifests, workapiv1.ManifestCondition{
+			Conditions: []metav1.Condition{{Type: helper.ManifestDrifted, Status: status}},
+		})
+	}
+	return work
+}
+
+func TestDriftCollector(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, work := range []*workapiv1.ManifestWork{
+		newWork("cluster1", "work1", true, true, false, true),
+		newWork("cluster1", "work2", true, false),
+		newWork("cluster2", "work1", false, true),
+		newWork("cluster3", "work1", true, false),
+	} {
+		if err := indexer.Add(work); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := `
+# HELP manifestwork_drift_audited [ALPHA] Number of the ManifestWorks in the drift audit mode per cluster namespace.
+# TYPE manifestwork_drift_audited gauge
+manifestwork_drift_audited{namespace="cluster1"} 2
+manifestwork_drift_audited{namespace="cluster3"} 1
+# HELP manifestwork_drifted_manifests [ALPHA] Number of the manifests whose resources diverge from the manifests in the ManifestWorks in the drift audit mode per cluster namespace.
+# TYPE manifestwork_drifted_manifests gauge
+manifestwork_drifted_manifests{namespace="cluster1"} 2
+manifestwork_drifted_manifests{namespace="cluster3"} 0
+`
+	collector := NewDriftCollector(worklister.NewManifestWorkLister(indexer))
+	if err := testutil.CustomCollectAndCompare(collector, strings.NewReader(expected),
+		"manifestwork_drifted_manifests", "manifestwork_drift_audited"); err != nil {
+		t.Error(err)
+	}
+}
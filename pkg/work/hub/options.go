@@ -10,6 +10,9 @@ type WorkHubManagerOptions struct {
 	WorkDriverConfig string
 
 	CloudEventsClientID string
+
+	// HelmAllowedRegistries is the hosts that the charts of the helm sources are allowed to be pulled from.
+	HelmAllowedRegistries []string
 }
 
 func NewWorkHubManagerOptions() *WorkHubManagerOptions {
@@ -26,4 +29,7 @@ func (o *WorkHubManagerOptions) AddFlags(fs *pflag.FlagSet) {
 		o.WorkDriverConfig, "The config file path of current work driver")
 	fs.StringVar(&o.CloudEventsClientID, "cloudevents-client-id",
 		o.CloudEventsClientID, "The ID of the cloudevents client when publishing works with cloudevents")
+	fs.StringSliceVar(&o.HelmAllowedRegistries, "helm-allowed-registries", o.HelmAllowedRegistries,
+		"The hosts (host[:port]) that the charts of the helm sources are allowed to be pulled from, including "+
+			"the token services and the redirect targets of the registries. No OCI chart is allowed if not set.")
 }
@@ -0,0 +1,69 @@
+package rollout
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// RolloutPausedAnnotation pauses the rollout of the ManifestWorkReplicaSet when it is "true". No
+	// ManifestWork is created or updated while the rollout is paused, and the rollout resumes once the
+	// annotation is removed or set to "false".
+	RolloutPausedAnnotation = "work.open-cluster-management.io/rollout-paused"
+	// PromotionGateAnnotation enables the manual promotion between the decision groups of the placements
+	// with the ProgressivePerGroup rollout strategy when it is "true". The clusters of a decision group are
+	// not rolled out until the index of the group is promoted by the PromotedGroupAnnotation.
+	PromotionGateAnnotation = "work.open-cluster-management.io/promotion-gate"
+	// PromotedGroupAnnotation is the index of the last promoted decision group. The first decision group
+	// with index 0 is promoted if it is not set. The promotion is for the ManifestWorkTemplate it is set
+	// with, and the annotation is removed by the controller once the template changes, so the rollout of
+	// the new template is gated again.
+	PromotedGroupAnnotation = "work.open-cluster-management.io/promoted-group"
+	// PromotedRevisionAnnotation is set by the controller with the hash of the ManifestWorkTemplate and the
+	// rendering annotations that the PromotedGroupAnnotation is set with.
+	PromotedRevisionAnnotation = "work.open-cluster-management.io/promoted-revision"
+
+	// AutoRollbackAnnotation enables the rollback of the ManifestWorkReplicaSet when it is "true". The
+	// ManifestWorkTemplate of each spec change is stored as a revision, and the clusters are reverted to
+	// the last succeeded revision once the failed clusters exceed the MaxFailures of the rollout strategy
+	// or the rollout does not succeed in the rollback timeout.
+	AutoRollbackAnnotation = "work.open-cluster-management.io/auto-rollback"
+	// RollbackTimeoutAnnotation is the duration, e.g. 30m, in which the rollout of a revision should
+	// succeed before it is rolled back. There is no timeout if it is not set. The timeout does not count
+	// while the rollout is paused or awaiting promotion, and restarts once the rollout resumes.
+	RollbackTimeoutAnnotation = "work.open-cluster-management.io/rollback-timeout"
+)
+
+// ControllerAnnotations are the annotations of the ManifestWorkReplicaSet set by the controller.
+var ControllerAnnotations = []string{PromotedGroupAnnotation, PromotedRevisionAnnotation}
+
+// GetPromotedGroup returns the index of the last promoted decision group in the annotations, or 0 if it
+// is not set.
+func GetPromotedGroup(annotations map[string]string) (int32, error) {
+	value, ok := annotations[PromotedGroupAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	index, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid promoted group annotation: %s is not a decision group index", value)
+	}
+	return int32(index), nil
+}
+
+// GetRollbackTimeout returns the rollback timeout in the annotations, or 0 if it is not set.
+func GetRollbackTimeout(annotations map[string]string) (time.Duration, error) {
+	value, ok := annotations[RollbackTimeoutAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rollback timeout annotation: %v", err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid rollback timeout annotation: %s is not positive", value)
+	}
+	return timeout, nil
+}
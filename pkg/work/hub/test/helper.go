@@ -1,6 +1,9 @@
 package test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -237,3 +240,25 @@ func getClusterGroups(clusters []string, clsPerDecisionGroup int) [][]string {
 
 	return clusterGroups
 }
+
+// CreateTestChartArchive returns a packaged chart with the files, keyed by the path in the chart.
+func CreateTestChartArchive(chartName string, files map[string]string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: chartName + "/" + name, Mode: 0600, Size: int64(len(content))}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
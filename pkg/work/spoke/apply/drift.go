@@ -0,0 +1,198 @@
+package apply
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// identifierPattern matches the map keys which are written in the dot notation of the json paths.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// quantityFields are the fields whose values, or the values of whose entries, are resource quantities,
+// e.g. the resource requirements of the containers, the hard limits of the ResourceQuotas and the limits
+// of the LimitRanges. The quantities are normalized by the api server, so they are compared by value.
+var quantityFields = sets.New[string](
+	"limits", "requests", "hard", "capacity", "allocatable", "overhead",
+	"min", "max", "default", "defaultRequest", "maxLimitRequestRatio", "sizeLimit",
+)
+
+// DriftedPaths compares the resource on the cluster with the required manifest and returns the sorted
+// json paths of the fields in the manifest that the resource diverges from. Only the fields set in
+// the manifest are compared, so the fields defaulted or added by the cluster are not drifts. The
+// status and the metadata other than the labels and annotations are ignored. The stringData of a
+// Secret is compared with the data it is written to.
+func DriftedPaths(required, existing *unstructured.Unstructured) []string {
+	required = normalizeSecret(required)
+
+	var paths []string
+	for key, value := range required.Object {
+		switch key {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			paths = append(paths, metadataDriftedPaths(required, existing)...)
+			continue
+		}
+		existingValue, found := existing.Object[key]
+		paths = append(paths, driftedPaths(fieldPath("", key), value, existingValue, found, false)...)
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// normalizeSecret returns a copy of the Secret with the stringData merged into the data, which is how the
+// api server stores it. The stringData takes precedence over the data with the same key.
+func normalizeSecret(required *unstructured.Unstructured) *unstructured.Unstructured {
+	if required.GetAPIVersion() != "v1" || required.GetKind() != "Secret" {
+		return required
+	}
+	stringData, found, err := unstructured.NestedStringMap(required.Object, "stringData")
+	if err != nil || !found {
+		return required
+	}
+
+	required = required.DeepCopy()
+	data, ok := required.Object["data"].(map[string]any)
+	if !ok {
+		data = map[string]any{}
+	}
+	for key, value := range stringData {
+		data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+	required.Object["data"] = data
+	delete(required.Object, "stringData")
+	return required
+}
+
+func metadataDriftedPaths(required, existing *unstructured.Unstructured) []string {
+	var paths []string
+	for key, value := range required.GetLabels() {
+		if existingValue, found := existing.GetLabels()[key]; !found || existingValue != value {
+			paths = append(paths, fieldPath(".metadata.labels", key))
+		}
+	}
+	for key, value := range required.GetAnnotations() {
+		if existingValue, found := existing.GetAnnotations()[key]; !found || existingValue != value {
+			paths = append(paths, fieldPath(".metadata.annotations", key))
+		}
+	}
+	return paths
+}
+
+// driftedPaths compares the value of the path recursively. The scalar values are compared as resource
+// quantities if quantity is true, which is inherited by the entries of a map.
+func driftedPaths(path string, required, existing any, found, quantity bool) []string {
+	switch requiredValue := required.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		// an empty map in the manifest does not require any field
+		if len(requiredValue) == 0 {
+			return nil
+		}
+		existingValue, ok := existing.(map[string]any)
+		if !found || !ok {
+			return []string{path}
+		}
+		var paths []string
+		for key, value := range requiredValue {
+			v, f := existingValue[key]
+			paths = append(paths, driftedPaths(fieldPath(path, key), value, v, f, quantity || quantityFields.Has(key))...)
+		}
+		return paths
+	case []any:
+		existingValue, ok := existing.([]any)
+		if len(requiredValue) == 0 && (!found || len(existingValue) == 0) {
+			return nil
+		}
+		if !found || !ok || len(existingValue) != len(requiredValue) {
+			return []string{path}
+		}
+		var paths []string
+		for i := range requiredValue {
+			paths = append(paths, driftedPaths(fmt.Sprintf("%s[%d]", path, i), requiredValue[i], existingValue[i], true, false)...)
+		}
+		return paths
+	default:
+		if !found {
+			return []string{path}
+		}
+		if quantity {
+			if equal, ok := quantityEqual(required, existing); ok {
+				if !equal {
+					return []string{path}
+				}
+				return nil
+			}
+		}
+		if !scalarEqual(required, existing) {
+			return []string{path}
+		}
+		return nil
+	}
+}
+
+// quantityEqual compares the values as resource quantities, e.g. 1000m equals 1. It returns false for ok
+// if any of the values is not a quantity.
+func quantityEqual(a, b any) (equal bool, ok bool) {
+	aq, err := toQuantity(a)
+	if err != nil {
+		return false, false
+	}
+	bq, err := toQuantity(b)
+	if err != nil {
+		return false, false
+	}
+	return aq.Cmp(bq) == 0, true
+}
+
+func toQuantity(v any) (resource.Quantity, error) {
+	if s, ok := v.(string); ok {
+		return resource.ParseQuantity(s)
+	}
+	if f, ok := toFloat(v); ok {
+		return resource.ParseQuantity(strconv.FormatFloat(f, 'f', -1, 64))
+	}
+	return resource.Quantity{}, fmt.Errorf("%v is not a quantity", v)
+}
+
+// scalarEqual compares the scalar values, the numbers are compared by value since the integers in the
+// manifest may be decoded as float64.
+func scalarEqual(a, b any) bool {
+	af, aIsNumber := toFloat(a)
+	bf, bIsNumber := toFloat(b)
+	if aIsNumber && bIsNumber {
+		return af == bf
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func fieldPath(parent, key string) string {
+	if identifierPattern.MatchString(key) {
+		return parent + "." + key
+	}
+	return fmt.Sprintf("%s['%s']", parent, key)
+}
@@ -0,0 +1,117 @@
+package apply
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func newDriftDeployment(labels map[string]string, spec map[string]any, status map[string]any) *unstructured.Unstructured {
+	obj := testingcommon.NewUnstructured("apps/v1", "Deployment", "ns1", "deploy")
+	obj.SetLabels(labels)
+	if spec != nil {
+		obj.Object["spec"] = spec
+	}
+	if status != nil {
+		obj.Object["status"] = status
+	}
+	return obj
+}
+
+func newDriftSecret(data, stringData map[string]any) *unstructured.Unstructured {
+	obj := testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret")
+	obj.Object["data"] = data
+	if stringData != nil {
+		obj.Object["stringData"] = stringData
+	}
+	return obj
+}
+
+func TestDriftedPaths(t *testing.T) {
+	cases := []struct {
+		name          string
+		required      *unstructured.Unstructured
+		existing      *unstructured.Unstructured
+		expectedPaths []string
+	}{
+		{
+			name:     "no drift with defaulted fields and status",
+			required: newDriftDeployment(map[string]string{"app": "test"}, map[string]any{"replicas": float64(2)}, nil),
+			existing: newDriftDeployment(map[string]string{"app": "test", "extra": "label"},
+				map[string]any{"replicas": int64(2), "revisionHistoryLimit": int64(10)},
+				map[string]any{"replicas": int64(1)}),
+		},
+		{
+			name: "scalar and label drifted",
+			required: newDriftDeployment(map[string]string{"app.kubernetes.io/name": "test"},
+				map[string]any{"replicas": int64(2), "paused": false}, nil),
+			existing: newDriftDeployment(map[string]string{"app.kubernetes.io/name": "edited"},
+				map[string]any{"replicas": int64(3)}, nil),
+			expectedPaths: []string{
+				".metadata.labels['app.kubernetes.io/name']",
+				".spec.paused",
+				".spec.replicas",
+			},
+		},
+		{
+			name: "list elements compared by index",
+			required: newDriftDeployment(nil, map[string]any{"template": map[string]any{"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "c1", "image": "nginx:1.0"}},
+			}}}, nil),
+			existing: newDriftDeployment(nil, map[string]any{"template": map[string]any{"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "c1", "image": "nginx:2.0", "imagePullPolicy": "Always"}},
+			}}}, nil),
+			expectedPaths: []string{".spec.template.spec.containers[0].image"},
+		},
+		{
+			name: "list length changed",
+			required: newDriftDeployment(nil, map[string]any{"template": map[string]any{"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "c1"}},
+			}}}, nil),
+			existing: newDriftDeployment(nil, map[string]any{"template": map[string]any{"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "c1"}, map[string]any{"name": "c2"}},
+			}}}, nil),
+			expectedPaths: []string{".spec.template.spec.containers"},
+		},
+		{
+			name:          "field removed",
+			required:      newDriftDeployment(nil, map[string]any{"selector": map[string]any{"matchLabels": map[string]any{"a": "b"}}}, nil),
+			existing:      newDriftDeployment(nil, map[string]any{}, nil),
+			expectedPaths: []string{".spec.selector"},
+		},
+		{
+			name: "quantities compared by value",
+			required: newDriftDeployment(nil, map[string]any{"template": map[string]any{"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "c1", "resources": map[string]any{
+					"limits":   map[string]any{"cpu": "1", "memory": "1Gi"},
+					"requests": map[string]any{"cpu": int64(1), "memory": "512Mi"},
+				}}},
+			}}}, nil),
+			existing: newDriftDeployment(nil, map[string]any{"template": map[string]any{"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "c1", "resources": map[string]any{
+					"limits":   map[string]any{"cpu": "1000m", "memory": "1Gi"},
+					"requests": map[string]any{"cpu": "1", "memory": "256Mi"},
+				}}},
+			}}}, nil),
+			expectedPaths: []string{".spec.template.spec.containers[0].resources.requests.memory"},
+		},
+		{
+			name:          "secret string data compared with data",
+			required:      newDriftSecret(map[string]any{"a": "YQ==", "b": "Yg=="}, map[string]any{"b": "b2", "c": "c"}),
+			existing:      newDriftSecret(map[string]any{"a": "YQ==", "b": "YjI=", "c": "ZWRpdGVk"}, nil),
+			expectedPaths: []string{".data.c"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			paths := DriftedPaths(c.required, c.existing)
+			if !reflect.DeepEqual(paths, c.expectedPaths) {
+				t.Errorf("expected drifted paths %v, but got %v", c.expectedPaths, paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package localpolicy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"sigs.k8s.io/yaml"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/spoke/auth"
+	"open-cluster-management.io/ocm/pkg/work/spoke/conditions"
+)
+
+// DenyRule denies the manifests matching all the fields set in the rule. Each of the group, version
+// and kind matches any value if it is "*" or not set, except that the empty group is the core group
+// once the kind is set.
+type DenyRule struct {
+	// Name is the name of the rule in the denial message.
+	Name    string `json:"name"`
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	// Namespaces is the patterns of the namespaces, e.g. kube-*. A namespaced manifest matches the rule if
+	// its namespace matches any pattern, and a Namespace matches the rule if its name matches any pattern.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Expression is a CEL expression with the variable object, and the manifest matches the rule if it
+	// returns true, e.g. object.roleRef.name == "cluster-admin".
+	Expression string `json:"expression,omitempty"`
+
+	program cel.Program
+}
+
+// Policy is the local admission policy of the work agent, which denies the manifests matching any rule
+// before they are applied regardless of the permission of the hub, e.g.
+//
+//	deny:
+//	- name: protect-kube-system
+//	  namespaces: ["kube-system"]
+//	- name: no-cluster-admin-bindings
+//	  group: rbac.authorization.k8s.io
+//	  kind: ClusterRoleBinding
+//	  expression: object.roleRef.name == "cluster-admin"
+type Policy struct {
+	Deny []DenyRule `json:"deny"`
+}
+
+// DeniedError is returned when a manifest is denied by the local policy.
+type DeniedError struct {
+	Rule string
+	Err  error
+}
+
+func (e *DeniedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("denied by the local policy rule %s: %v", e.Rule, e.Err)
+	}
+	return fmt.Sprintf("denied by the local policy rule %s", e.Rule)
+}
+
+// LoadPolicy loads the local policy from the file, and compiles the expressions of the rules.
+func LoadPolicy(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to decode local policy %s: %v", file, err)
+	}
+
+	env, err := conditions.NewCELEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+	for i := range policy.Deny {
+		rule := &policy.Deny[i]
+		if len(rule.Name) == 0 {
+			return nil, fmt.Errorf("invalid local policy %s: name of rule %d is required", file, i)
+		}
+		for _, pattern := range rule.Namespaces {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid local policy %s: invalid namespace pattern %q of rule %s", file, pattern, rule.Name)
+			}
+		}
+		if len(rule.Expression) == 0 {
+			continue
+		}
+		ast, iss := env.Compile(rule.Expression)
+		if iss.Err() != nil {
+			return nil, fmt.Errorf("invalid local policy %s: invalid expression of rule %s: %v", file, rule.Name, iss.Err())
+		}
+		rule.program, err = env.Program(
+			ast,
+			cel.CostLimit(celconfig.PerCallLimit),
+			cel.CostTracking(conditions.NewCostEstimator()),
+			cel.InterruptCheckFrequency(celconfig.CheckFrequency),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid local policy %s: invalid expression of rule %s: %v", file, rule.Name, err)
+		}
+	}
+	return policy, nil
+}
+
+// Admit returns a DeniedError if the manifest matches any rule of the policy.
+func (p *Policy) Admit(ctx context.Context, obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	for _, rule := range p.Deny {
+		matched, err := rule.matches(ctx, gvk, obj)
+		if err != nil {
+			// the manifest is denied if the rule could not be evaluated
+			return &DeniedError{Rule: rule.Name, Err: err}
+		}
+		if matched {
+			return &DeniedError{Rule: rule.Name}
+		}
+	}
+	return nil
+}
+
+func (r *DenyRule) matches(ctx context.Context, gvk schema.GroupVersionKind, obj *unstructured.Unstructured) (bool, error) {
+	if !matchesField(r.Version, gvk.Version) || !matchesField(r.Kind, gvk.Kind) {
+		return false, nil
+	}
+	if (len(r.Group) > 0 || len(r.Kind) > 0) && r.Group != "*" && r.Group != gvk.Group {
+		return false, nil
+	}
+
+	if len(r.Namespaces) > 0 {
+		namespace := obj.GetNamespace()
+		if gvk.Group == "" && gvk.Kind == "Namespace" {
+			namespace = obj.GetName()
+		}
+		if len(namespace) == 0 || !matchesNamespace(r.Namespaces, namespace) {
+			return false, nil
+		}
+	}
+
+	if r.program == nil {
+		return true, nil
+	}
+	out, _, err := conditions.EvaluateCEL(ctx, r.program, celconfig.RuntimeCELCostBudget, r.Expression, map[string]any{
+		"object": obj.Object,
+	})
+	if err != nil {
+		return false, err
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q returns %v instead of a bool", r.Expression, out.Value())
+	}
+	return matched, nil
+}
+
+func matchesField(pattern, value string) bool {
+	return len(pattern) == 0 || pattern == "*" || pattern == value
+}
+
+func matchesNamespace(patterns []string, namespace string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Validator checks the manifests with the local policy before the executor is validated by the
+// delegated validator.
+type Validator struct {
+	policy   *Policy
+	delegate auth.ExecutorValidator
+}
+
+var _ auth.ExecutorValidator = &Validator{}
+
+// NewValidator returns a Validator with the local policy and the executor validator.
+func NewValidator(policy *Policy, delegate auth.ExecutorValidator) *Validator {
+	return &Validator{policy: policy, delegate: delegate}
+}
+
+func (v *Validator) Validate(ctx context.Context, executor *workapiv1.ManifestWorkExecutor, gvr schema.GroupVersionResource,
+	namespace, name string, ownedByTheWork bool, obj *unstructured.Unstructured) error {
+	if obj != nil {
+		if err := v.policy.Admit(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return v.delegate.Validate(ctx, executor, gvr, namespace, name, ownedByTheWork, obj)
+}
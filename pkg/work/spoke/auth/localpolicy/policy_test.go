@@ -0,0 +1,187 @@
+package localpolicy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+const policy = `
+deny:
+- name: protect-kube-system
+  namespaces: ["kube-system", "openshift-*"]
+- name: no-cluster-admin-bindings
+  group: rbac.authorization.k8s.io
+  kind: ClusterRoleBinding
+  expression: object.roleRef.name == "cluster-admin"
+- name: no-services
+  kind: Service
+`
+
+func loadPolicy(t *testing.T, content string) (*Policy, error) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return LoadPolicy(file)
+}
+
+func newClusterRoleBinding(name, role string) *unstructured.Unstructured {
+	obj := testingcommon.NewUnstructured("rbac.authorization.k8s.io/v1", "ClusterRoleBinding", "", name)
+	obj.Object["roleRef"] = map[string]interface{}{"kind": "ClusterRole", "name": role}
+	return obj
+}
+
+func TestLoadPolicy(t *testing.T) {
+	cases := []struct {
+		name        string
+		policy      string
+		expectedErr string
+	}{
+		{
+			name:   "valid policy",
+			policy: policy,
+		},
+		{
+			name:        "unknown field",
+			policy:      "deny:\n- name: rule\n  resource: secrets",
+			expectedErr: "failed to decode local policy",
+		},
+		{
+			name:        "missing name",
+			policy:      "deny:\n- kind: Secret",
+			expectedErr: "name of rule 0 is required",
+		},
+		{
+			name:        "invalid namespace pattern",
+			policy:      "deny:\n- name: rule\n  namespaces: [\"kube-[\"]",
+			expectedErr: "invalid namespace pattern",
+		},
+		{
+			name:        "invalid expression",
+			policy:      "deny:\n- name: rule\n  expression: object.roleRef.name ==",
+			expectedErr: "invalid expression of rule rule",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := loadPolicy(t, c.policy)
+			if len(c.expectedErr) == 0 && err != nil {
+				t.Errorf("expected no error, but got %v", err)
+			}
+			if len(c.expectedErr) > 0 && (err == nil || !strings.Contains(err.Error(), c.expectedErr)) {
+				t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	p, err := loadPolicy(t, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name         string
+		obj          *unstructured.Unstructured
+		expectedRule string
+	}{
+		{
+			name: "allowed manifest",
+			obj:  testingcommon.NewUnstructured("v1", "Secret", "default", "secret"),
+		},
+		{
+			name:         "manifest in protected namespace",
+			obj:          testingcommon.NewUnstructured("v1", "Secret", "kube-system", "secret"),
+			expectedRule: "protect-kube-system",
+		},
+		{
+			name:         "manifest in namespace matching pattern",
+			obj:          testingcommon.NewUnstructured("apps/v1", "Deployment", "openshift-monitoring", "deploy"),
+			expectedRule: "protect-kube-system",
+		},
+		{
+			name:         "protected namespace",
+			obj:          testingcommon.NewUnstructured("v1", "Namespace", "", "kube-system"),
+			expectedRule: "protect-kube-system",
+		},
+		{
+			name:         "binding to cluster-admin",
+			obj:          newClusterRoleBinding("admin", "cluster-admin"),
+			expectedRule: "no-cluster-admin-bindings",
+		},
+		{
+			name: "binding to other role",
+			obj:  newClusterRoleBinding("view", "view"),
+		},
+		{
+			name:         "core kind",
+			obj:          testingcommon.NewUnstructured("v1", "Service", "default", "svc"),
+			expectedRule: "no-services",
+		},
+		{
+			name: "same kind in other group",
+			obj:  testingcommon.NewUnstructured("serving.knative.dev/v1", "Service", "default", "svc"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := p.Admit(context.TODO(), c.obj)
+			if len(c.expectedRule) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, but got %v", err)
+				}
+				return
+			}
+			var deniedErr *DeniedError
+			if !errors.As(err, &deniedErr) || deniedErr.Rule != c.expectedRule {
+				t.Errorf("expected denied by rule %s, but got %v", c.expectedRule, err)
+			}
+		})
+	}
+}
+
+type fakeValidator struct {
+	called bool
+}
+
+func (f *fakeValidator) Validate(_ context.Context, _ *workapiv1.ManifestWorkExecutor, _ schema.GroupVersionResource,
+	_, _ string, _ bool, _ *unstructured.Unstructured) error {
+	f.called = true
+	return nil
+}
+
+func TestValidator(t *testing.T) {
+	p, err := loadPolicy(t, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delegate := &fakeValidator{}
+	validator := NewValidator(p, delegate)
+	err = validator.Validate(context.TODO(), nil, schema.GroupVersionResource{Version: "v1", Resource: "secrets"},
+		"kube-system", "secret", true, testingcommon.NewUnstructured("v1", "Secret", "kube-system", "secret"))
+	var deniedErr *DeniedError
+	if !errors.As(err, &deniedErr) || delegate.called {
+		t.Errorf("expected denied without validating the executor, but got %v", err)
+	}
+
+	err = validator.Validate(context.TODO(), nil, schema.GroupVersionResource{Version: "v1", Resource: "secrets"},
+		"default", "secret", true, testingcommon.NewUnstructured("v1", "Secret", "default", "secret"))
+	if err != nil || !delegate.called {
+		t.Errorf("expected the executor validated, but got %v", err)
+	}
+}
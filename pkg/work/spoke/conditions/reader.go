@@ -32,11 +32,11 @@ type ConditionReader struct {
 }
 
 func NewConditionReader() (*ConditionReader, error) {
-	ruleEnv, err := celEnv(cel.Variable("object", cel.DynType))
+	ruleEnv, err := NewCELEnv(cel.Variable("object", cel.DynType))
 	if err != nil {
 		return nil, err
 	}
-	messageEnv, err := celEnv(cel.Variable("object", cel.DynType), cel.Variable("result", cel.BoolType))
+	messageEnv, err := NewCELEnv(cel.Variable("object", cel.DynType), cel.Variable("result", cel.BoolType))
 	if err != nil {
 		return nil, err
 	}
@@ -48,6 +48,12 @@ func NewConditionReader() (*ConditionReader, error) {
 	}, nil
 }
 
+// WithWellKnownConditions sets the resolver of the rules of the WellKnownConditions condition rule.
+func (s *ConditionReader) WithWellKnownConditions(wellKnownConditions rules.WellKnownConditionRuleResolver) *ConditionReader {
+	s.wellKnownConditions = wellKnownConditions
+	return s
+}
+
 func (s *ConditionReader) EvaluateConditions(ctx context.Context, obj *unstructured.Unstructured, rules []workapiv1.ConditionRule) []metav1.Condition {
 	var conditionResults []metav1.Condition
 	remainingBudget := globalCostBudget
@@ -71,6 +77,38 @@ func (s *ConditionReader) EvaluateConditions(ctx context.Context, obj *unstructu
 	return conditionResults
 }
 
+// IsComplete returns if the well-known ManifestComplete condition of the resource is true, with the
+// message of the condition. A resource without the well-known rule is never complete.
+func (s *ConditionReader) IsComplete(ctx context.Context, obj *unstructured.Unstructured) (bool, string) {
+	condition, _, err := s.GetConditionByRule(ctx, obj, workapiv1.ConditionRule{
+		Type:      workapiv1.WellKnownConditionsType,
+		Condition: workapiv1.ManifestComplete,
+	}, globalCostBudget)
+	switch {
+	case err != nil:
+		return false, err.Error()
+	case len(condition.Type) == 0:
+		return false, fmt.Sprintf("no %s condition rule for %s", workapiv1.ManifestComplete, obj.GroupVersionKind())
+	}
+	return condition.Status == metav1.ConditionTrue, condition.Message
+}
+
+// IsReady returns if the well-known Ready condition of the resource is true, with the message of the
+// condition. found is false if there is no well-known Ready rule of the kind of the resource.
+func (s *ConditionReader) IsReady(ctx context.Context, obj *unstructured.Unstructured) (ready, found bool, message string) {
+	condition, _, err := s.GetConditionByRule(ctx, obj, workapiv1.ConditionRule{
+		Type:      workapiv1.WellKnownConditionsType,
+		Condition: rules.ManifestReady,
+	}, globalCostBudget)
+	switch {
+	case err != nil:
+		return false, true, err.Error()
+	case len(condition.Type) == 0:
+		return false, false, ""
+	}
+	return condition.Status == metav1.ConditionTrue, true, condition.Message
+}
+
 func (s *ConditionReader) GetConditionByRule(
 	ctx context.Context, obj *unstructured.Unstructured, rule workapiv1.ConditionRule, budget int64,
 ) (metav1.Condition, int64, error) {
@@ -129,7 +167,7 @@ func (s *ConditionReader) evaluateCelExpressions(
 	ctx context.Context, obj *unstructured.Unstructured, expressions []string, budget int64,
 ) (status metav1.ConditionStatus, reason string, remainingBudget int64, err error) {
 	remainingBudget = budget
-	estimator := newEstimator()
+	estimator := NewCostEstimator()
 	for _, expression := range expressions {
 		ast, iss := s.ruleEnv.Compile(expression)
 		err = iss.Err()
@@ -149,7 +187,7 @@ func (s *ConditionReader) evaluateCelExpressions(
 			return metav1.ConditionUnknown, workapiv1.ConditionRuleInternalError, remainingBudget, err
 		}
 
-		out, newBudget, err := evaluate(ctx, prg, remainingBudget, expression, map[string]any{
+		out, newBudget, err := EvaluateCEL(ctx, prg, remainingBudget, expression, map[string]any{
 			"object": obj.Object,
 		})
 		if err != nil {
@@ -191,7 +229,7 @@ func (s *ConditionReader) getConditionMessageByRule(
 			return "", budget, err
 		}
 
-		out, newBudget, err := evaluate(ctx, prg, budget, rule.MessageExpression, map[string]any{
+		out, newBudget, err := EvaluateCEL(ctx, prg, budget, rule.MessageExpression, map[string]any{
 			"object": obj.Object,
 			"result": result,
 		})
@@ -219,7 +257,8 @@ func (s *ConditionReader) getConditionMessageByRule(
 	return "Manifest is not " + rule.Condition, budget, nil
 }
 
-func celEnv(opts ...cel.EnvOption) (*cel.Env, error) {
+// NewCELEnv returns the CEL environment with the OCM libraries and the variables in the opts.
+func NewCELEnv(opts ...cel.EnvOption) (*cel.Env, error) {
 	opts = slices.Concat(
 		opts,
 		ocmcelcommon.BaseEnvOpts,
@@ -230,7 +269,8 @@ func celEnv(opts ...cel.EnvOption) (*cel.Env, error) {
 	return cel.NewEnv(opts...)
 }
 
-func evaluate(
+// EvaluateCEL evaluates the program with the input, and returns the result with the remaining cost budget.
+func EvaluateCEL(
 	ctx context.Context,
 	program cel.Program,
 	budget int64,
@@ -267,8 +307,8 @@ func evaluate(
 	return evalResult, remainingBudget, nil
 }
 
-// newEstimator creates a new cost estimator for CEL expressions.
-func newEstimator() *ocmcelcommon.BaseEnvCostEstimator {
+// NewCostEstimator creates a new cost estimator for CEL expressions.
+func NewCostEstimator() *ocmcelcommon.BaseEnvCostEstimator {
 	return &ocmcelcommon.BaseEnvCostEstimator{
 		CostEstimator: &ocmcellibrary.CostEstimator{},
 	}
@@ -381,3 +381,61 @@ func TestConditionReader(t *testing.T) {
 		})
 	}
 }
+
+func TestIsReady(t *testing.T) {
+	cases := []struct {
+		name          string
+		object        string
+		expectedReady bool
+		expectedFound bool
+	}{
+		{
+			name: "statefulset without status",
+			object: `{"apiVersion":"apps/v1","kind":"StatefulSet","metadata":{"name":"test","generation":1},
+				"spec":{"replicas":2}}`,
+			expectedFound: true,
+		},
+		{
+			name: "statefulset not ready",
+			object: `{"apiVersion":"apps/v1","kind":"StatefulSet","metadata":{"name":"test","generation":1},
+				"spec":{"replicas":2},"status":{"observedGeneration":1,"replicas":2,"updatedReplicas":2,"readyReplicas":1}}`,
+			expectedFound: true,
+		},
+		{
+			name: "statefulset ready",
+			object: `{"apiVersion":"apps/v1","kind":"StatefulSet","metadata":{"name":"test","generation":1},
+				"spec":{"replicas":2},"status":{"observedGeneration":1,"replicas":2,"updatedReplicas":2,"readyReplicas":2}}`,
+			expectedReady: true,
+			expectedFound: true,
+		},
+		{
+			name: "deployment of the old generation",
+			object: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"test","generation":2},
+				"status":{"observedGeneration":1,"replicas":1,"updatedReplicas":1,"readyReplicas":1,"availableReplicas":1}}`,
+			expectedFound: true,
+		},
+		{
+			name:          "crd established",
+			object:        `{"apiVersion":"apiextensions.k8s.io/v1","kind":"CustomResourceDefinition","metadata":{"name":"test"},"status":{"conditions":[{"type":"Established","status":"True"}]}}`,
+			expectedReady: true,
+			expectedFound: true,
+		},
+		{
+			name:   "unknown kind",
+			object: `{"apiVersion":"example.com/v1","kind":"Foo","metadata":{"name":"test"}}`,
+		},
+	}
+
+	reader, err := NewConditionReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ready, found, message := reader.IsReady(context.TODO(), unstrctureObject(c.object))
+			if ready != c.expectedReady || found != c.expectedFound {
+				t.Errorf("expected ready %v found %v, but got %v %v: %s", c.expectedReady, c.expectedFound, ready, found, message)
+			}
+		})
+	}
+}
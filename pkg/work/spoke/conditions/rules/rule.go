@@ -1,11 +1,17 @@
 package rules
 
 import (
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	workapiv1 "open-cluster-management.io/api/work/v1"
 )
 
+// ManifestReady is the well-known condition of the workloads which are ready, e.g. all the replicas of
+// a Deployment are available on its latest generation. It gates the next apply wave of a ManifestWork.
+const ManifestReady = "Ready"
+
 type WellKnownConditionRuleResolver interface {
 	GetRuleByKindCondition(gvk schema.GroupVersionKind, condition string) workapiv1.ConditionRule
 }
@@ -30,11 +36,100 @@ var podCompleteRule = workapiv1.ConditionRule{
 	MessageExpression: `"Pod is in phase " + object.status.phase`,
 }
 
+// observedLatestExpression is true if the status of the resource is observed on its latest generation.
+const observedLatestExpression = `has(object.status) && has(object.status.observedGeneration) &&
+	object.status.observedGeneration >= (has(object.metadata.generation) ? object.metadata.generation : 0)`
+
+// replicasReadyRule returns the rule of the resources with replicas, which are ready once the status
+// fields equal the desired replicas, 1 by default.
+func replicasReadyRule(kind string, fields ...string) workapiv1.ConditionRule {
+	expressions := []string{observedLatestExpression}
+	for _, field := range fields {
+		expressions = append(expressions, fmt.Sprintf(
+			"(has(object.status.%s) ? object.status.%s : 0) == (has(object.spec) && has(object.spec.replicas) ? object.spec.replicas : 1)",
+			field, field))
+	}
+	return workapiv1.ConditionRule{
+		Condition:         ManifestReady,
+		Type:              workapiv1.CelConditionExpressionsType,
+		CelExpressions:    expressions,
+		MessageExpression: fmt.Sprintf(`result ? "%s is ready" : "%s is not ready"`, kind, kind),
+	}
+}
+
+var daemonSetReadyRule = workapiv1.ConditionRule{
+	Condition: ManifestReady,
+	Type:      workapiv1.CelConditionExpressionsType,
+	CelExpressions: []string{
+		observedLatestExpression,
+		"has(object.status.desiredNumberScheduled)",
+		"(has(object.status.numberReady) ? object.status.numberReady : 0) >= object.status.desiredNumberScheduled",
+		"(has(object.status.numberAvailable) ? object.status.numberAvailable : 0) >= object.status.desiredNumberScheduled",
+	},
+	MessageExpression: `result ? "DaemonSet is ready" : "DaemonSet is not ready"`,
+}
+
+var jobReadyRule = workapiv1.ConditionRule{
+	Condition: ManifestReady,
+	Type:      workapiv1.CelConditionExpressionsType,
+	CelExpressions: []string{
+		`has(object.status) && hasConditions(object.status)
+			? object.status.conditions.exists(c, c.type == 'Complete' && c.status == 'True')
+			: false`,
+	},
+	MessageExpression: `result ? "Job is complete" : "Job is not complete"`,
+}
+
+var podReadyRule = workapiv1.ConditionRule{
+	Condition: ManifestReady,
+	Type:      workapiv1.CelConditionExpressionsType,
+	CelExpressions: []string{
+		`has(object.status) && ((has(object.status.phase) && object.status.phase == 'Succeeded') ||
+			(hasConditions(object.status) && object.status.conditions.exists(c, c.type == 'Ready' && c.status == 'True')))`,
+	},
+	MessageExpression: `result ? "Pod is ready" : "Pod is not ready"`,
+}
+
+var crdReadyRule = workapiv1.ConditionRule{
+	Condition: ManifestReady,
+	Type:      workapiv1.CelConditionExpressionsType,
+	CelExpressions: []string{
+		`has(object.status) && hasConditions(object.status)
+			? object.status.conditions.exists(c, c.type == 'Established' && c.status == 'True')
+			: false`,
+	},
+	MessageExpression: `result ? "CustomResourceDefinition is established" : "CustomResourceDefinition is not established"`,
+}
+
 func DefaultWellKnownConditionResolver() WellKnownConditionRuleResolver {
 	return &defaultWellKnownConditionResolver{
 		rules: map[schema.GroupVersionKind]map[string]workapiv1.ConditionRule{
-			{Group: "batch", Version: "v1", Kind: "Job"}: {workapiv1.ManifestComplete: jobCompleteRule},
-			{Group: "", Version: "v1", Kind: "Pod"}:      {workapiv1.ManifestComplete: podCompleteRule},
+			{Group: "batch", Version: "v1", Kind: "Job"}: {
+				workapiv1.ManifestComplete: jobCompleteRule,
+				ManifestReady:              jobReadyRule,
+			},
+			{Group: "", Version: "v1", Kind: "Pod"}: {
+				workapiv1.ManifestComplete: podCompleteRule,
+				ManifestReady:              podReadyRule,
+			},
+			{Group: "apps", Version: "v1", Kind: "Deployment"}: {
+				ManifestReady: replicasReadyRule("Deployment", "replicas", "updatedReplicas", "readyReplicas", "availableReplicas"),
+			},
+			{Group: "apps", Version: "v1", Kind: "StatefulSet"}: {
+				ManifestReady: replicasReadyRule("StatefulSet", "replicas", "updatedReplicas", "readyReplicas"),
+			},
+			{Group: "apps", Version: "v1", Kind: "ReplicaSet"}: {
+				ManifestReady: replicasReadyRule("ReplicaSet", "replicas", "readyReplicas", "availableReplicas"),
+			},
+			{Group: "", Version: "v1", Kind: "ReplicationController"}: {
+				ManifestReady: replicasReadyRule("ReplicationController", "replicas", "readyReplicas", "availableReplicas"),
+			},
+			{Group: "apps", Version: "v1", Kind: "DaemonSet"}: {
+				ManifestReady: daemonSetReadyRule,
+			},
+			{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}: {
+				ManifestReady: crdReadyRule,
+			},
 		},
 	}
 }
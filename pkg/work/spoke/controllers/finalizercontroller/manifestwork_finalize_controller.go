@@ -3,11 +3,13 @@ package finalizercontroller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
@@ -21,6 +23,8 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/auth"
+	"open-cluster-management.io/ocm/pkg/work/spoke/conditions"
 )
 
 const manifestWorkFinalizer = "ManifestWorkFinalizer"
@@ -31,6 +35,11 @@ type ManifestWorkFinalizeController struct {
 	manifestWorkLister        worklister.ManifestWorkNamespaceLister
 	appliedManifestWorkClient workv1client.AppliedManifestWorkInterface
 	appliedManifestWorkLister worklister.AppliedManifestWorkLister
+	spokeDynamicClient        dynamic.Interface
+	restMapper                meta.RESTMapper
+	validator                 auth.ExecutorValidator
+	conditionReader           *conditions.ConditionReader
+	preDeleteHookTimeout      time.Duration
 	hubHash                   string
 	rateLimiter               workqueue.RateLimiter
 }
@@ -41,6 +50,11 @@ func NewManifestWorkFinalizeController(
 	manifestWorkLister worklister.ManifestWorkNamespaceLister,
 	appliedManifestWorkClient workv1client.AppliedManifestWorkInterface,
 	appliedManifestWorkInformer workinformer.AppliedManifestWorkInformer,
+	spokeDynamicClient dynamic.Interface,
+	restMapper meta.RESTMapper,
+	validator auth.ExecutorValidator,
+	conditionReader *conditions.ConditionReader,
+	preDeleteHookTimeout time.Duration,
 	hubHash string,
 ) factory.Controller {
 
@@ -51,6 +65,11 @@ func NewManifestWorkFinalizeController(
 		manifestWorkLister:        manifestWorkLister,
 		appliedManifestWorkClient: appliedManifestWorkClient,
 		appliedManifestWorkLister: appliedManifestWorkInformer.Lister(),
+		spokeDynamicClient:        spokeDynamicClient,
+		restMapper:                restMapper,
+		validator:                 validator,
+		conditionReader:           conditionReader,
+		preDeleteHookTimeout:      preDeleteHookTimeout,
 		hubHash:                   hubHash,
 		rateLimiter:               workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second),
 	}
@@ -85,7 +104,16 @@ func (m *ManifestWorkFinalizeController) sync(ctx context.Context, controllerCon
 		// set tracing key from work if there is any
 		logger = logging.SetLogTracingByObject(logger, manifestWork)
 		ctx = klog.NewContext(ctx, logger)
-		err := m.deleteAppliedManifestWork(ctx, manifestWork, appliedManifestWorkName)
+		// the applied resources are not removed until the pre-delete hooks are complete
+		completed, timedOutHooks, err := m.runPreDeleteHooks(ctx, controllerContext.Recorder(), manifestWork, appliedManifestWorkName)
+		if err != nil {
+			return err
+		}
+		if !completed {
+			controllerContext.Queue().AddAfter(manifestWorkName, PreDeleteHookRequeueInterval)
+			return nil
+		}
+		err = m.deleteAppliedManifestWork(ctx, manifestWork, appliedManifestWorkName, timedOutHooks)
 		if err != nil {
 			return err
 		}
@@ -121,7 +149,10 @@ func (m *ManifestWorkFinalizeController) sync(ctx context.Context, controllerCon
 	return nil
 }
 
-func (m *ManifestWorkFinalizeController) deleteAppliedManifestWork(ctx context.Context, work *workapiv1.ManifestWork, appliedManifestWorkName string) error {
+// deleteAppliedManifestWork deletes the AppliedManifestWork to remove the applied resources. The WorkDeleting
+// condition reports the pre-delete hooks which are not complete in the timeout.
+func (m *ManifestWorkFinalizeController) deleteAppliedManifestWork(ctx context.Context, work *workapiv1.ManifestWork,
+	appliedManifestWorkName string, timedOutHooks []string) error {
 	appliedManifestWork, err := m.appliedManifestWorkLister.Get(appliedManifestWorkName)
 	switch {
 	case errors.IsNotFound(err):
@@ -132,14 +163,21 @@ func (m *ManifestWorkFinalizeController) deleteAppliedManifestWork(ctx context.C
 		return nil
 	}
 
-	workCopy := work.DeepCopy()
-	meta.SetStatusCondition(&workCopy.Status.Conditions, metav1.Condition{
+	condition := metav1.Condition{
 		Type:               workapiv1.WorkDeleting,
 		Reason:             "WorkDeleting",
 		Status:             metav1.ConditionTrue,
 		Message:            "ManifestWork is being deleted",
-		ObservedGeneration: workCopy.Generation,
-	})
+		ObservedGeneration: work.Generation,
+	}
+	if len(timedOutHooks) > 0 {
+		condition.Reason = PreDeleteHooksTimedOut
+		condition.Message = fmt.Sprintf("ManifestWork is being deleted, the pre-delete hooks are not complete in %s: %s",
+			m.preDeleteHookTimeout, strings.Join(timedOutHooks, ", "))
+	}
+
+	workCopy := work.DeepCopy()
+	meta.SetStatusCondition(&workCopy.Status.Conditions, condition)
 
 	if _, err = m.patcher.PatchStatus(ctx, work, workCopy.Status, work.Status); err != nil {
 		return err
@@ -9,6 +9,10 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	fakekube "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/util/workqueue"
 
@@ -18,6 +22,10 @@ import (
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/auth/basic"
+	"open-cluster-management.io/ocm/pkg/work/spoke/conditions"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
 )
 
 func TestSyncManifestWorkController(t *testing.T) {
@@ -230,3 +238,148 @@ func TestSyncManifestWorkController(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncManifestWorkControllerWithPreDeleteHooks(t *testing.T) {
+	requeueInterval := PreDeleteHookRequeueInterval
+	PreDeleteHookRequeueInterval = 0
+	defer func() { PreDeleteHookRequeueInterval = requeueInterval }()
+
+	hubHash := "test"
+	now := metav1.Now()
+	newHookJob := func(conditionType string) *unstructured.Unstructured {
+		obj := testingcommon.NewUnstructured("batch/v1", "Job", "ns1", "hook")
+		obj.SetAnnotations(map[string]string{helper.HookAnnotation: helper.HookPreDelete})
+		if len(conditionType) > 0 {
+			obj.Object["status"] = map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": conditionType, "status": "True"},
+				},
+			}
+		}
+		return obj
+	}
+
+	cases := []struct {
+		name                        string
+		existingJobs                []runtime.Object
+		expectedDynamicActions      []string
+		expectedAppliedWorkActions  []string
+		expectedDeletingReason      string
+		deletedAgo                  time.Duration
+		expectedQueueLen            int
+		validateHookJobOwnerCreated bool
+	}{
+		{
+			name:                        "wait for the pre-delete hook to complete",
+			expectedDynamicActions:      []string{"create"},
+			expectedDeletingReason:      WaitingForPreDeleteHooks,
+			expectedQueueLen:            1,
+			validateHookJobOwnerCreated: true,
+		},
+		{
+			name:                       "delete applied work once the pre-delete hook is complete",
+			existingJobs:               []runtime.Object{newHookJob("Complete")},
+			expectedDynamicActions:     []string{"create", "get"},
+			expectedAppliedWorkActions: []string{"delete"},
+			expectedDeletingReason:     "WorkDeleting",
+			expectedQueueLen:           1,
+		},
+		{
+			name:                       "delete applied work when the pre-delete hook failed",
+			existingJobs:               []runtime.Object{newHookJob("Failed")},
+			expectedDynamicActions:     []string{"create", "get"},
+			expectedAppliedWorkActions: []string{"delete"},
+			expectedDeletingReason:     "WorkDeleting",
+			expectedQueueLen:           1,
+		},
+		{
+			name:                       "delete applied work when the pre-delete hook times out",
+			deletedAgo:                 11 * time.Minute,
+			expectedDynamicActions:     []string{"create"},
+			expectedAppliedWorkActions: []string{"delete"},
+			expectedDeletingReason:     PreDeleteHooksTimedOut,
+			expectedQueueLen:           1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work, _ := spoketesting.NewManifestWork(0,
+				testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"), newHookJob(""))
+			work.Name = "work"
+			deletionTimestamp := metav1.NewTime(now.Add(-c.deletedAgo))
+			work.DeletionTimestamp = &deletionTimestamp
+			work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+			appliedWork := &workapiv1.AppliedManifestWork{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-work", hubHash), UID: "applied-work"},
+			}
+
+			fakeClient := fakeworkclient.NewSimpleClientset(work, appliedWork)
+			informerFactory := workinformers.NewSharedInformerFactory(fakeClient, 5*time.Minute)
+			if err := informerFactory.Work().V1().ManifestWorks().Informer().GetStore().Add(work); err != nil {
+				t.Fatal(err)
+			}
+			if err := informerFactory.Work().V1().AppliedManifestWorks().Informer().GetStore().Add(appliedWork); err != nil {
+				t.Fatal(err)
+			}
+			dynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), c.existingJobs...)
+			conditionReader, err := conditions.NewConditionReader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			controller := &ManifestWorkFinalizeController{
+				patcher: patcher.NewPatcher[
+					*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](
+					fakeClient.WorkV1().ManifestWorks("cluster1")),
+				manifestWorkLister:        informerFactory.Work().V1().ManifestWorks().Lister().ManifestWorks("cluster1"),
+				appliedManifestWorkClient: fakeClient.WorkV1().AppliedManifestWorks(),
+				appliedManifestWorkLister: informerFactory.Work().V1().AppliedManifestWorks().Lister(),
+				spokeDynamicClient:        dynamicClient,
+				restMapper:                spoketesting.NewFakeRestMapper(),
+				validator:                 basic.NewSARValidator(nil, fakekube.NewSimpleClientset()),
+				conditionReader:           conditionReader,
+				preDeleteHookTimeout:      10 * time.Minute,
+				hubHash:                   hubHash,
+				rateLimiter:               workqueue.NewItemExponentialFailureRateLimiter(0, 1*time.Second),
+			}
+
+			controllerContext := testingcommon.NewFakeSyncContext(t, work.Name)
+			if err := controller.sync(context.TODO(), controllerContext, work.Name); err != nil {
+				t.Errorf("Expect no sync error, but got %v", err)
+			}
+
+			testingcommon.AssertActions(t, dynamicClient.Actions(), c.expectedDynamicActions...)
+			if c.validateHookJobOwnerCreated {
+				job := dynamicClient.Actions()[0].(clienttesting.CreateActionImpl).Object.(*unstructured.Unstructured)
+				owners := job.GetOwnerReferences()
+				if len(owners) != 1 || owners[0].UID != appliedWork.UID {
+					t.Errorf("expected the hook owned by the applied work, but got %v", owners)
+				}
+			}
+
+			var workActions, appliedWorkActions []clienttesting.Action
+			for _, action := range fakeClient.Actions() {
+				switch action.GetResource().Resource {
+				case "manifestworks":
+					workActions = append(workActions, action)
+				case "appliedmanifestworks":
+					appliedWorkActions = append(appliedWorkActions, action)
+				}
+			}
+			testingcommon.AssertActions(t, appliedWorkActions, c.expectedAppliedWorkActions...)
+			testingcommon.AssertActions(t, workActions, "patch")
+			patchedWork := &workapiv1.ManifestWork{}
+			if err := json.Unmarshal(workActions[0].(clienttesting.PatchActionImpl).Patch, patchedWork); err != nil {
+				t.Fatal(err)
+			}
+			condition := meta.FindStatusCondition(patchedWork.Status.Conditions, workapiv1.WorkDeleting)
+			if condition == nil || condition.Reason != c.expectedDeletingReason {
+				t.Errorf("expected deleting condition with reason %s, but got %v", c.expectedDeletingReason, condition)
+			}
+
+			if queueLen := controllerContext.Queue().Len(); queueLen != c.expectedQueueLen {
+				t.Errorf("expected %d, but %d", c.expectedQueueLen, queueLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package finalizercontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/basecontroller/events"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+const (
+	// WaitingForPreDeleteHooks is the reason of the WorkDeleting condition when the applied resources are
+	// not removed until the pre-delete hooks of the work are complete.
+	WaitingForPreDeleteHooks = "WaitingForPreDeleteHooks"
+	// PreDeleteHooksTimedOut is the reason of the WorkDeleting condition when the applied resources are
+	// removed since the pre-delete hooks of the work are not complete in the timeout.
+	PreDeleteHooksTimedOut = "PreDeleteHooksTimedOut"
+)
+
+// PreDeleteHookRequeueInterval is the interval to check the pre-delete hooks again when any of them is
+// not complete.
+var PreDeleteHookRequeueInterval = 10 * time.Second
+
+// runPreDeleteHooks creates the Jobs of the pre-delete hooks of the deleting work, and returns true once
+// all of them are complete or failed, or the preDeleteHookTimeout since the deletion of the work expires.
+// The hooks which are not complete in the timeout are returned, and the Jobs are owned by the
+// AppliedManifestWork, so they are garbage collected after the applied resources are removed. A hook which
+// fails or is not allowed for the executor of the work does not block the deletion, and a warning event is
+// recorded for it.
+//
+// The hooks are not run if the AppliedManifestWork is not found, since nothing is applied by the work, or
+// if it is already deleting, since the applied resources are being removed and the Jobs owned by it would
+// be garbage collected right away.
+func (m *ManifestWorkFinalizeController) runPreDeleteHooks(
+	ctx context.Context,
+	recorder events.Recorder,
+	work *workapiv1.ManifestWork,
+	appliedManifestWorkName string) (bool, []string, error) {
+	logger := klog.FromContext(ctx)
+
+	var hooks []*unstructured.Unstructured
+	for _, manifest := range work.Spec.Workload.Manifests {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(manifest.Raw); err != nil {
+			continue
+		}
+		// the invalid hooks are reported by the Applied condition of the manifests
+		if hook, err := helper.GetHook(obj); err == nil && hook == helper.HookPreDelete {
+			hooks = append(hooks, obj)
+		}
+	}
+	if len(hooks) == 0 {
+		return true, nil, nil
+	}
+
+	appliedManifestWork, err := m.appliedManifestWorkLister.Get(appliedManifestWorkName)
+	switch {
+	case errors.IsNotFound(err):
+		logger.V(2).Info("Pre-delete hooks are skipped since nothing is applied by the work")
+		return true, nil, nil
+	case err != nil:
+		return false, nil, err
+	case !appliedManifestWork.DeletionTimestamp.IsZero():
+		logger.V(2).Info("Pre-delete hooks are skipped since the applied resources are being removed")
+		return true, nil, nil
+	}
+	owner := helper.NewAppliedManifestWorkOwner(appliedManifestWork)
+
+	var pending []string
+	for _, hook := range hooks {
+		resourceMeta, gvr, err := helper.BuildResourceMeta(0, hook, m.restMapper)
+		if err != nil {
+			return false, nil, err
+		}
+		key := fmt.Sprintf("%s/%s", resourceMeta.Namespace, resourceMeta.Name)
+
+		if err := m.validator.Validate(ctx, work.Spec.Executor, gvr, resourceMeta.Namespace, resourceMeta.Name, true, hook); err != nil {
+			recorder.Warningf(ctx, "PreDeleteHookSkipped", "pre-delete hook %s of work %s is skipped: %v", key, work.Name, err)
+			continue
+		}
+
+		hook.SetUID("")
+		hook.SetOwnerReferences([]metav1.OwnerReference{*owner})
+		job, err := m.spokeDynamicClient.Resource(gvr).Namespace(resourceMeta.Namespace).Create(ctx, hook, metav1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			job, err = m.spokeDynamicClient.Resource(gvr).Namespace(resourceMeta.Namespace).Get(
+				ctx, resourceMeta.Name, metav1.GetOptions{})
+		}
+		if err != nil {
+			return false, nil, fmt.Errorf("failed to run pre-delete hook %s: %w", key, err)
+		}
+
+		if helper.IsJobFailed(job) {
+			recorder.Warningf(ctx, "PreDeleteHookFailed", "pre-delete hook %s of work %s failed", key, work.Name)
+			continue
+		}
+		if complete, message := m.conditionReader.IsComplete(ctx, job); !complete {
+			logger.V(4).Info("Pre-delete hook is not complete", "hook", key, "message", message)
+			pending = append(pending, key)
+		}
+	}
+	if len(pending) == 0 {
+		return true, nil, nil
+	}
+
+	if time.Since(work.DeletionTimestamp.Time) > m.preDeleteHookTimeout {
+		recorder.Warningf(ctx, "PreDeleteHookTimeout", "pre-delete hooks %s of work %s are not complete in %s",
+			strings.Join(pending, ", "), work.Name, m.preDeleteHookTimeout)
+		return true, pending, nil
+	}
+
+	workCopy := work.DeepCopy()
+	meta.SetStatusCondition(&workCopy.Status.Conditions, metav1.Condition{
+		Type:               workapiv1.WorkDeleting,
+		Reason:             WaitingForPreDeleteHooks,
+		Status:             metav1.ConditionTrue,
+		Message:            fmt.Sprintf("Waiting for the pre-delete hooks to complete: %s", strings.Join(pending, ", ")),
+		ObservedGeneration: workCopy.Generation,
+	})
+	if _, err := m.patcher.PatchStatus(ctx, work, workCopy.Status, work.Status); err != nil {
+		return false, nil, err
+	}
+	return false, nil, nil
+}
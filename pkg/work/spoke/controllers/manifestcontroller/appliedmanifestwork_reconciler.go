@@ -55,6 +55,10 @@ func (m *appliedManifestWorkReconciler) reconcile(
 	var appliedResources []workapiv1.AppliedManifestResourceMeta
 	var errs []error
 	for _, result := range results {
+		// the pre-delete hooks are not maintained until the work is deleted
+		if result.deferred {
+			continue
+		}
 		uid, err := m.getUIDFromResult(ctx, result)
 		switch {
 		case errors.IsNotFound(err):
@@ -11,6 +11,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -31,7 +32,9 @@ import (
 	commonhelper "open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/work/spoke/apply"
 	"open-cluster-management.io/ocm/pkg/work/spoke/auth"
+	"open-cluster-management.io/ocm/pkg/work/spoke/conditions"
 	"open-cluster-management.io/ocm/pkg/work/spoke/objectreader"
+	"open-cluster-management.io/ocm/pkg/work/spoke/wellknownrules"
 )
 
 var (
@@ -76,7 +79,9 @@ func NewManifestWorkController(
 	objectReader objectreader.ObjectReader,
 	hubHash, agentID string,
 	restMapper meta.RESTMapper,
-	validator auth.ExecutorValidator) factory.Controller {
+	validator auth.ExecutorValidator,
+	conditionReader *conditions.ConditionReader,
+	wellKnownRules *wellknownrules.Resolver) factory.Controller {
 
 	syncCtx := factory.NewSyncContext("manifestwork-controller")
 
@@ -94,9 +99,11 @@ func NewManifestWorkController(
 		agentID:                   agentID,
 		reconcilers: []workReconcile{
 			&manifestworkReconciler{
-				restMapper: restMapper,
-				appliers:   apply.NewAppliers(spokeDynamicClient, spokeKubeClient, spokeAPIExtensionClient),
-				validator:  validator,
+				restMapper:         restMapper,
+				appliers:           apply.NewAppliers(spokeDynamicClient, spokeKubeClient, spokeAPIExtensionClient),
+				validator:          validator,
+				spokeDynamicClient: spokeDynamicClient,
+				conditionReader:    conditionReader,
 			},
 			&appliedManifestWorkReconciler{
 				spokeDynamicClient: spokeDynamicClient,
@@ -112,6 +119,21 @@ func NewManifestWorkController(
 	})
 	utilruntime.Must(err)
 
+	// the apply waves of the works are gated by the well-known Ready condition rules, so the works are
+	// requeued once the rules change.
+	if wellKnownRules != nil {
+		wellKnownRules.AddEventHandler(func() {
+			works, err := manifestWorkLister.List(labels.Everything())
+			if err != nil {
+				utilruntime.HandleError(err)
+				return
+			}
+			for _, work := range works {
+				syncCtx.Queue().Add(work.Name)
+			}
+		})
+	}
+
 	return factory.New().
 		WithBareInformers(
 			manifestWorkInformer.Informer(),
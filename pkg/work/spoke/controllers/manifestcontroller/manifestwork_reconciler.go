@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -14,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
@@ -26,13 +29,43 @@ import (
 	"open-cluster-management.io/ocm/pkg/work/spoke/apply"
 	"open-cluster-management.io/ocm/pkg/work/spoke/auth"
 	"open-cluster-management.io/ocm/pkg/work/spoke/auth/basic"
+	"open-cluster-management.io/ocm/pkg/work/spoke/auth/localpolicy"
+	"open-cluster-management.io/ocm/pkg/work/spoke/conditions"
 )
 
+const (
+	// ApplyWaveAnnotation is the annotation of a manifest with its apply wave, an integer defaulting
+	// to 0. The manifests are applied wave by wave in the ascending order, and the manifests of a wave
+	// are applied only after all the resources of the previous waves are ready.
+	ApplyWaveAnnotation = "work.open-cluster-management.io/apply-wave"
+
+	// AppliedManifestWaitingForWave is the reason of the Applied condition of a manifest not applied
+	// since a previous apply wave is not ready.
+	AppliedManifestWaitingForWave = "AppliedManifestWaitingForWave"
+	// AppliedManifestWorkWaitingForWave is the reason of the Applied condition of the work with manifests
+	// waiting for a previous apply wave.
+	AppliedManifestWorkWaitingForWave = "AppliedManifestWorkWaitingForWave"
+
+	// DeniedByLocalPolicy is the reason of the Applied condition of a manifest, and of the work with
+	// manifests, denied by the local policy of the work agent.
+	DeniedByLocalPolicy = "DeniedByLocalPolicy"
+
+	// PreDeleteHookDeferred is the reason of the Applied condition of a pre-delete hook, which is not
+	// applied until the work is deleted.
+	PreDeleteHookDeferred = "PreDeleteHookDeferred"
+)
+
+// WaveRequeueInterval is the interval to check the readiness of the apply wave again when the
+// manifests of the next wave are waiting.
+var WaveRequeueInterval = 10 * time.Second
+
 type applyResult struct {
 	Result runtime.Object
 	Error  error
 
 	resourceMeta workapiv1.ManifestResourceMeta
+	// deferred is true for the pre-delete hooks, which are not applied until the work is deleted.
+	deferred bool
 }
 
 // resourceApplyOrder defines the priority rank for applying resources by kind.
@@ -97,9 +130,11 @@ var resourceApplyOrder = map[string]int{
 }
 
 type manifestworkReconciler struct {
-	restMapper meta.RESTMapper
-	appliers   *apply.Appliers
-	validator  auth.ExecutorValidator
+	restMapper         meta.RESTMapper
+	appliers           *apply.Appliers
+	validator          auth.ExecutorValidator
+	spokeDynamicClient dynamic.Interface
+	conditionReader    *conditions.ConditionReader
 }
 
 func (m *manifestworkReconciler) reconcile(
@@ -116,8 +151,7 @@ func (m *manifestworkReconciler) reconcile(
 	// Apply resources on spoke cluster.
 	resourceResults := make([]applyResult, len(manifestWork.Spec.Workload.Manifests))
 	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		resourceResults = m.applyManifests(
-			ctx, manifestWork.Spec.Workload.Manifests, manifestWork.Spec, manifestWork.Status, controllerContext.Recorder(), *owner, resourceResults)
+		resourceResults = m.applyManifests(ctx, manifestWork, controllerContext.Recorder(), *owner, resourceResults)
 
 		for _, result := range resourceResults {
 			if apierrors.IsConflict(result.Error) {
@@ -133,6 +167,7 @@ func (m *manifestworkReconciler) reconcile(
 
 	var newManifestConditions []workapiv1.ManifestCondition
 	var requeueTime = ResyncInterval
+	var authorizationFailed, waitingForWave, deniedByLocalPolicy bool
 	for _, result := range resourceResults {
 		manifestCondition := workapiv1.ManifestCondition{
 			ResourceMeta: result.resourceMeta,
@@ -151,11 +186,32 @@ func (m *manifestworkReconciler) reconcile(
 			logger.V(2).Info("apply work failed", "error", result.Error)
 			result.Error = nil
 
+			authorizationFailed = true
 			if authError.RequeueTime < requeueTime {
 				requeueTime = authError.RequeueTime
 			}
 		}
 
+		// the manifests waiting for a previous apply wave are not errors, the work is requeued to
+		// check the readiness of the wave again.
+		var waveError *WaveBlockedError
+		if errors.As(result.Error, &waveError) {
+			result.Error = nil
+			waitingForWave = true
+			if WaveRequeueInterval < requeueTime {
+				requeueTime = WaveRequeueInterval
+			}
+		}
+
+		// the manifests denied by the local policy are not retried until the work is resynced, since
+		// the policy is only changed with the restart of the agent.
+		var deniedError *localpolicy.DeniedError
+		if errors.As(result.Error, &deniedError) {
+			logger.V(2).Info("manifest denied by the local policy", "error", result.Error)
+			result.Error = nil
+			deniedByLocalPolicy = true
+		}
+
 		// ignore server side apply conflict error since it cannot be resolved by error fallback.
 		var ssaConflict *apply.ServerSideApplyConflictError
 		if result.Error != nil && !errors.As(result.Error, &ssaConflict) {
@@ -174,31 +230,45 @@ func (m *manifestworkReconciler) reconcile(
 			Reason:             "AppliedManifestWorkFailed",
 			Message:            "Failed to apply manifest work",
 		}
-		if inCondition {
+		switch {
+		case inCondition:
 			appliedCondition.Status = metav1.ConditionTrue
 			appliedCondition.Reason = "AppliedManifestWorkComplete"
 			appliedCondition.Message = "Apply manifest work complete"
+		case deniedByLocalPolicy && len(errs) == 0 && !authorizationFailed:
+			appliedCondition.Reason = DeniedByLocalPolicy
+			appliedCondition.Message = "Manifests are denied by the local policy of the work agent"
+		case waitingForWave && len(errs) == 0 && !authorizationFailed:
+			appliedCondition.Reason = AppliedManifestWorkWaitingForWave
+			appliedCondition.Message = "Waiting for the previous apply waves to be ready"
 		}
 		meta.SetStatusCondition(&manifestWork.Status.Conditions, appliedCondition)
 	}
 
 	if len(errs) > 0 {
 		err = utilerrors.NewAggregate(errs)
-	} else if requeueTime != ResyncInterval {
+	} else if authorizationFailed {
 		err = commonhelper.NewRequeueError(
 			fmt.Sprintf("requeue work %s due to authorization error", manifestWork.Name),
 			requeueTime,
 		)
+	} else if waitingForWave {
+		err = commonhelper.NewRequeueError(
+			fmt.Sprintf("requeue work %s to wait for the apply wave", manifestWork.Name),
+			requeueTime,
+		)
 	}
 
 	return manifestWork, appliedManifestWork, resourceResults, err
 }
 
 // orderedManifest holds a pre-parsed manifest together with its original position in the spec.
-// Manifests are sorted by resource kind for apply ordering, while specIndex preserves the
-// original position for ordinal tracking and status updates.
+// Manifests are sorted by hook stage, apply wave and then resource kind for apply ordering, while
+// specIndex preserves the original position for ordinal tracking and status updates.
 type orderedManifest struct {
 	specIndex    int
+	wave         int
+	hook         string
 	obj          *unstructured.Unstructured
 	gvr          schema.GroupVersionResource
 	resourceMeta workapiv1.ManifestResourceMeta
@@ -217,16 +287,48 @@ func (m *manifestworkReconciler) parseAndSortManifests(manifests []workapiv1.Man
 		}
 
 		resMeta, gvr, err := helper.BuildResourceMeta(i, obj, m.restMapper)
-		ordered[i] = orderedManifest{specIndex: i, obj: obj, gvr: gvr, resourceMeta: resMeta, err: err}
+		wave, waveErr := applyWave(obj)
+		if err == nil {
+			err = waveErr
+		}
+		hook, hookErr := helper.GetHook(obj)
+		if err == nil {
+			err = hookErr
+		}
+		ordered[i] = orderedManifest{specIndex: i, wave: wave, hook: hook, obj: obj, gvr: gvr, resourceMeta: resMeta, err: err}
 	}
 
 	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].stage() != ordered[j].stage() {
+			return ordered[i].stage() < ordered[j].stage()
+		}
+		if ordered[i].wave != ordered[j].wave {
+			return ordered[i].wave < ordered[j].wave
+		}
 		return kindOrder(ordered[i].obj) < kindOrder(ordered[j].obj)
 	})
 
 	return ordered
 }
 
+// stage returns the order of the hook stage of the manifest. The pre-apply hooks are applied before
+// the other manifests, and the post-apply hooks after them.
+func (om orderedManifest) stage() int {
+	switch om.hook {
+	case helper.HookPreApply:
+		return -1
+	case helper.HookPostApply:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// inSameWave returns if the two manifests are in the same apply wave of the same hook stage.
+func inSameWave(a, b orderedManifest) bool {
+	return a.stage() == b.stage() && a.wave == b.wave
+}
+
 func kindOrder(obj *unstructured.Unstructured) int {
 	if obj == nil {
 		return 1001
@@ -237,40 +339,127 @@ func kindOrder(obj *unstructured.Unstructured) int {
 	return 1000
 }
 
+// applyWave returns the apply wave of the manifest set by the ApplyWaveAnnotation.
+func applyWave(obj *unstructured.Unstructured) (int, error) {
+	value, ok := obj.GetAnnotations()[ApplyWaveAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	wave, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q of the annotation %s: it should be an integer", value, ApplyWaveAnnotation)
+	}
+	return wave, nil
+}
+
+// applyManifests applies the manifests in the order of hook stages, apply waves and resource kinds.
+// When the manifests have more than one apply wave, the manifests of a wave are applied only after all
+// the resources of the previous waves are ready, otherwise a WaveBlockedError is returned for them.
+// The hooks of a stage are ready once the Jobs are complete, and the pre-delete hooks are deferred.
+// The manifests already applied in the current generation of the work are not blocked, so
+// the waves are only enforced on the first rollout of each generation.
 func (m *manifestworkReconciler) applyManifests(
 	ctx context.Context,
-	manifests []workapiv1.Manifest,
-	workSpec workapiv1.ManifestWorkSpec,
-	workStatus workapiv1.ManifestWorkStatus,
+	manifestWork *workapiv1.ManifestWork,
 	recorder events.Recorder,
 	owner metav1.OwnerReference,
 	existingResults []applyResult) []applyResult {
+	workSpec, workStatus := manifestWork.Spec, manifestWork.Status
 
-	ordered := m.parseAndSortManifests(manifests)
+	var ordered []orderedManifest
+	for _, om := range m.parseAndSortManifests(workSpec.Workload.Manifests) {
+		if om.hook == helper.HookPreDelete && om.err == nil {
+			existingResults[om.specIndex] = applyResult{resourceMeta: om.resourceMeta, deferred: true}
+			continue
+		}
+		ordered = append(ordered, om)
+	}
+	multipleWaves := len(ordered) > 0 && !inSameWave(ordered[0], ordered[len(ordered)-1])
+
+	var blocked *WaveBlockedError
+	waveStart := 0
+	for i, om := range ordered {
+		// check the readiness of the previous wave when a new wave starts
+		if multipleWaves && i > 0 && !inSameWave(ordered[i-1], om) {
+			if blocked == nil {
+				blocked = m.checkWave(ctx, ordered[waveStart:i], workSpec, workStatus, existingResults)
+			}
+			waveStart = i
+		}
 
-	for _, om := range ordered {
 		needsApply := existingResults[om.specIndex].Result == nil || apierrors.IsConflict(existingResults[om.specIndex].Error)
 		if !needsApply {
 			continue
 		}
+		if blocked != nil && !appliedInGeneration(om, workStatus, manifestWork.Generation) {
+			existingResults[om.specIndex] = applyResult{Error: blocked, resourceMeta: om.resourceMeta}
+			continue
+		}
 		if om.err != nil {
 			existingResults[om.specIndex] = applyResult{Error: om.err, resourceMeta: om.resourceMeta}
 		} else {
-			existingResults[om.specIndex] = m.applyOneManifest(ctx, om, workSpec, workStatus, recorder, owner)
+			existingResults[om.specIndex] = m.applyOneManifest(ctx, om, manifestWork, recorder, owner)
 		}
 	}
 
 	return existingResults
 }
 
-func (m *manifestworkReconciler) applyOneManifest(
+// checkWave returns a WaveBlockedError if any resource of the manifests in the wave is not ready.
+func (m *manifestworkReconciler) checkWave(
 	ctx context.Context,
-	om orderedManifest,
+	wave []orderedManifest,
 	workSpec workapiv1.ManifestWorkSpec,
 	workStatus workapiv1.ManifestWorkStatus,
+	results []applyResult) *WaveBlockedError {
+	var notReady []string
+	for _, om := range wave {
+		result := results[om.specIndex]
+		if om.err != nil {
+			result.Error = om.err
+		}
+		var ready bool
+		var reason string
+		if len(om.hook) > 0 {
+			ready, reason = m.isHookComplete(ctx, om, result)
+		} else {
+			ready, reason = m.isReady(ctx, om, result, workSpec, workStatus)
+		}
+		if !ready {
+			notReady = append(notReady, fmt.Sprintf("%s %s (%s)", om.resourceMeta.Kind, resourceName(om.resourceMeta), reason))
+		}
+	}
+	if len(notReady) == 0 {
+		return nil
+	}
+	return &WaveBlockedError{Wave: wave[0].wave, Hook: wave[0].hook, NotReady: notReady}
+}
+
+func resourceName(resourceMeta workapiv1.ManifestResourceMeta) string {
+	if len(resourceMeta.Namespace) == 0 {
+		return resourceMeta.Name
+	}
+	return resourceMeta.Namespace + "/" + resourceMeta.Name
+}
+
+// appliedInGeneration returns if the manifest is applied in the generation of the work.
+func appliedInGeneration(om orderedManifest, workStatus workapiv1.ManifestWorkStatus, generation int64) bool {
+	manifestCondition := helper.FindManifestCondition(om.resourceMeta, workStatus.ResourceStatus.Manifests)
+	if manifestCondition == nil {
+		return false
+	}
+	condition := meta.FindStatusCondition(manifestCondition.Conditions, workapiv1.ManifestApplied)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == generation
+}
+
+func (m *manifestworkReconciler) applyOneManifest(
+	ctx context.Context,
+	om orderedManifest,
+	manifestWork *workapiv1.ManifestWork,
 	recorder events.Recorder,
 	owner metav1.OwnerReference) applyResult {
 	logger := klog.FromContext(ctx)
+	workSpec, workStatus := manifestWork.Spec, manifestWork.Status
 	result := applyResult{resourceMeta: om.resourceMeta}
 
 	// ignore the required object UID to avoid UID precondition failed error
@@ -308,6 +497,11 @@ func (m *manifestworkReconciler) applyOneManifest(
 	if option != nil && option.UpdateStrategy != nil {
 		strategy = *option.UpdateStrategy
 	}
+	// the existing hooks are not updated, since they only run once.
+	if len(om.hook) > 0 &&
+		(strategy.Type == workapiv1.UpdateStrategyTypeUpdate || strategy.Type == workapiv1.UpdateStrategyTypeServerSideApply) {
+		strategy.Type = workapiv1.UpdateStrategyTypeCreateOnly
+	}
 
 	applier := m.appliers.GetApplier(strategy.Type)
 	result.Result, result.Error = applier.Apply(ctx, om.gvr, om.obj, requiredOwner, option, recorder)
@@ -334,6 +528,16 @@ func allInCondition(conditionType string, manifests []workapiv1.ManifestConditio
 }
 
 func buildAppliedStatusCondition(result applyResult, generation int64) metav1.Condition {
+	if result.deferred {
+		return metav1.Condition{
+			Type:               workapiv1.ManifestApplied,
+			Status:             metav1.ConditionTrue,
+			Reason:             PreDeleteHookDeferred,
+			Message:            "Pre-delete hook is applied when the work is deleted",
+			ObservedGeneration: generation,
+		}
+	}
+
 	if result.Error != nil {
 		// Check if this is an ignoreFields processing error
 		reason := workapiv1.AppliedManifestFailed
@@ -346,6 +550,18 @@ func buildAppliedStatusCondition(result applyResult, generation int64) metav1.Co
 			message = fmt.Sprintf("Failed to process ignoreFields: %v", result.Error)
 		}
 
+		var waveErr *WaveBlockedError
+		if errors.As(result.Error, &waveErr) {
+			reason = AppliedManifestWaitingForWave
+			message = fmt.Sprintf("Manifest is not applied, %v", result.Error)
+		}
+
+		var deniedErr *localpolicy.DeniedError
+		if errors.As(result.Error, &deniedErr) {
+			reason = DeniedByLocalPolicy
+			message = fmt.Sprintf("Manifest is not applied, %v", result.Error)
+		}
+
 		return metav1.Condition{
 			Type:               workapiv1.ManifestApplied,
 			Status:             metav1.ConditionFalse,
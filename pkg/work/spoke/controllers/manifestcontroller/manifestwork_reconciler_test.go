@@ -31,6 +31,8 @@ import (
 	"open-cluster-management.io/ocm/pkg/work/helper"
 	"open-cluster-management.io/ocm/pkg/work/spoke/apply"
 	"open-cluster-management.io/ocm/pkg/work/spoke/auth/basic"
+	"open-cluster-management.io/ocm/pkg/work/spoke/auth/localpolicy"
+	"open-cluster-management.io/ocm/pkg/work/spoke/conditions"
 	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
 	"open-cluster-management.io/ocm/test/integration/util"
 )
@@ -71,18 +73,25 @@ func newController(t *testing.T, work *workapiv1.ManifestWork, appliedWork *work
 		}
 	}
 
+	conditionReader, err := conditions.NewConditionReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	return &testController{
 		controller: controller,
 		workClient: fakeWorkClient,
 		mwReconciler: &manifestworkReconciler{
-			restMapper: mapper,
-			validator:  basic.NewSARValidator(nil, spokeKubeClient),
+			restMapper:      mapper,
+			validator:       basic.NewSARValidator(nil, spokeKubeClient),
+			conditionReader: conditionReader,
 		},
 	}
 }
 
 func (t *testController) toController() *ManifestWorkController {
 	t.mwReconciler.appliers = apply.NewAppliers(t.dynamicClient, t.kubeClient, nil)
+	t.mwReconciler.spokeDynamicClient = t.dynamicClient
 	t.controller.reconcilers = []workReconcile{
 		t.mwReconciler,
 	}
@@ -1315,6 +1324,17 @@ func TestParseAndSortManifests(t *testing.T) {
 			expectedSpecOrder: []int{0, 2, 1, 3},
 			expectedKinds:     []string{"Secret", "Secret", "Deployment", "Deployment"},
 		},
+		{
+			name: "apply waves sorted before kinds",
+			manifests: []workapiv1.Manifest{
+				toRawManifest(withApplyWave(testingcommon.NewUnstructured("v1", "Secret", "ns1", "s1"), "1")),
+				toRawManifest(testingcommon.NewUnstructured("apps/v1", "Deployment", "ns1", "d1")),
+				toRawManifest(withApplyWave(testingcommon.NewUnstructured("v1", "Namespace", "", "ns1"), "-1")),
+				toRawManifest(testingcommon.NewUnstructured("v1", "ConfigMap", "ns1", "cm")),
+			},
+			expectedSpecOrder: []int{2, 3, 1, 0},
+			expectedKinds:     []string{"Namespace", "ConfigMap", "Deployment", "Secret"},
+		},
 		{
 			name:              "empty manifests",
 			manifests:         []workapiv1.Manifest{},
@@ -1418,3 +1438,359 @@ func TestApplyManifestsInDependencyOrder(t *testing.T) {
 	}
 
 }
+
+func withApplyWave(obj *unstructured.Unstructured, wave string) *unstructured.Unstructured {
+	obj.SetAnnotations(map[string]string{ApplyWaveAnnotation: wave})
+	return obj
+}
+
+func newReadyDeployment(namespace, name string) *unstructured.Unstructured {
+	obj := testingcommon.NewUnstructured("apps/v1", "Deployment", namespace, name)
+	obj.Object["status"] = map[string]interface{}{
+		"observedGeneration": int64(1),
+		"replicas":           int64(1),
+		"updatedReplicas":    int64(1),
+		"readyReplicas":      int64(1),
+		"availableReplicas":  int64(1),
+	}
+	return obj
+}
+
+func TestApplyManifestsInWaves(t *testing.T) {
+	cases := []*testCase{
+		newTestCase("next wave blocked by the deployment not ready").
+			withWorkManifest(
+				withApplyWave(testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"), "1"),
+				testingcommon.NewUnstructured("apps/v1", "Deployment", "ns1", "deploy"),
+			).
+			withExpectedWorkAction("patch").
+			withAppliedWorkAction("create").
+			withExpectedDynamicAction("get", "create").
+			withExpectedManifestCondition(
+				newCondition(workapiv1.ManifestApplied, string(metav1.ConditionFalse), AppliedManifestWaitingForWave, "", 0, nil),
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+			).
+			withExpectedWorkCondition(
+				newCondition(workapiv1.WorkApplied, string(metav1.ConditionFalse), AppliedManifestWorkWaitingForWave, "", 0, nil)),
+		newTestCase("next wave applied after the resource of a kind without readiness rule is applied").
+			withWorkManifest(
+				withApplyWave(testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"), "1"),
+				testingcommon.NewUnstructured("v1", "NewObject", "ns1", "n1"),
+			).
+			withExpectedWorkAction("patch").
+			withAppliedWorkAction("create").
+			withExpectedKubeAction("get", "create").
+			withExpectedDynamicAction("get", "create").
+			withExpectedManifestCondition(
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+			).
+			withExpectedWorkCondition(expectedCondition(workapiv1.WorkApplied, metav1.ConditionTrue)),
+		newTestCase("next wave blocked by the condition rules of a kind without readiness rule").
+			withWorkManifest(
+				withApplyWave(testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"), "1"),
+				testingcommon.NewUnstructured("v1", "NewObject", "ns1", "n1"),
+			).
+			withManifestConfig(workapiv1.ManifestConfigOption{
+				ResourceIdentifier: workapiv1.ResourceIdentifier{
+					Resource: "newobjects", Namespace: "ns1", Name: "n1"},
+				ConditionRules: []workapiv1.ConditionRule{{
+					Type:           workapiv1.CelConditionExpressionsType,
+					Condition:      "Ready",
+					CelExpressions: []string{"object.status.ready == true"},
+				}},
+			}).
+			withExpectedWorkAction("patch").
+			withAppliedWorkAction("create").
+			withExpectedDynamicAction("get", "create").
+			withExpectedManifestCondition(
+				newCondition(workapiv1.ManifestApplied, string(metav1.ConditionFalse), AppliedManifestWaitingForWave, "", 0, nil),
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+			).
+			withExpectedWorkCondition(
+				newCondition(workapiv1.WorkApplied, string(metav1.ConditionFalse), AppliedManifestWorkWaitingForWave, "", 0, nil)),
+		newTestCase("next wave applied after the deployment is ready").
+			withWorkManifest(
+				withApplyWave(testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"), "1"),
+				newReadyDeployment("ns1", "deploy"),
+			).
+			withExpectedWorkAction("patch").
+			withAppliedWorkAction("create").
+			withExpectedKubeAction("get", "create").
+			withExpectedDynamicAction("get", "create").
+			withExpectedManifestCondition(
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+			).
+			withExpectedWorkCondition(expectedCondition(workapiv1.WorkApplied, metav1.ConditionTrue)),
+		newTestCase("next wave blocked by the condition rules").
+			withWorkManifest(
+				withApplyWave(testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"), "1"),
+				newReadyDeployment("ns1", "deploy"),
+			).
+			withManifestConfig(workapiv1.ManifestConfigOption{
+				ResourceIdentifier: workapiv1.ResourceIdentifier{
+					Group: "apps", Resource: "deployments", Namespace: "ns1", Name: "deploy"},
+				ConditionRules: []workapiv1.ConditionRule{{
+					Type:           workapiv1.CelConditionExpressionsType,
+					Condition:      "Progressing",
+					CelExpressions: []string{"object.status.readyReplicas > 1"},
+				}},
+			}).
+			withExpectedWorkAction("patch").
+			withAppliedWorkAction("create").
+			withExpectedDynamicAction("get", "create").
+			withExpectedManifestCondition(
+				newCondition(workapiv1.ManifestApplied, string(metav1.ConditionFalse), AppliedManifestWaitingForWave, "", 0, nil),
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+			).
+			withExpectedWorkCondition(expectedCondition(workapiv1.WorkApplied, metav1.ConditionFalse)),
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work, workKey := c.newManifestWork()
+			controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+				withKubeObject(c.spokeObject...).
+				withUnstructuredObject(c.spokeDynamicObject...)
+			syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+			err := controller.toController().sync(context.TODO(), syncContext, work.Name)
+			if err != nil {
+				t.Errorf("Should be success with no err: %v", err)
+			}
+
+			c.validate(t, controller.dynamicClient, controller.workClient, controller.kubeClient)
+		})
+	}
+}
+
+func TestApplyWave(t *testing.T) {
+	cases := []struct {
+		name         string
+		obj          *unstructured.Unstructured
+		expectedWave int
+		expectedErr  bool
+	}{
+		{
+			name: "no annotation",
+			obj:  testingcommon.NewUnstructured("v1", "Secret", "ns1", "s1"),
+		},
+		{
+			name:         "negative wave",
+			obj:          withApplyWave(testingcommon.NewUnstructured("v1", "Secret", "ns1", "s1"), "-2"),
+			expectedWave: -2,
+		},
+		{
+			name:        "invalid wave",
+			obj:         withApplyWave(testingcommon.NewUnstructured("v1", "Secret", "ns1", "s1"), "first"),
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			wave, err := applyWave(c.obj)
+			if (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, got %v", c.expectedErr, err)
+			}
+			if wave != c.expectedWave {
+				t.Errorf("expected wave %d, got %d", c.expectedWave, wave)
+			}
+		})
+	}
+}
+
+func TestDriftAuditMode(t *testing.T) {
+	cases := []struct {
+		name                    string
+		strategy                *workapiv1.UpdateStrategy
+		expectedDynamicActions  []string
+		expectedReplicasPatched bool
+	}{
+		{
+			name:                    "existing resource is updated by the default strategy in the audit mode",
+			expectedDynamicActions:  []string{"get", "update"},
+			expectedReplicasPatched: true,
+		},
+		{
+			name:                   "existing resource is not updated by the create only strategy in the audit mode",
+			strategy:               &workapiv1.UpdateStrategy{Type: workapiv1.UpdateStrategyTypeCreateOnly},
+			expectedDynamicActions: []string{"get", "patch"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			existing := testingcommon.NewUnstructured("apps/v1", "Deployment", "ns1", "deploy")
+			existing.Object["spec"] = map[string]interface{}{"replicas": int64(3)}
+			required := testingcommon.NewUnstructured("apps/v1", "Deployment", "ns1", "deploy")
+			required.Object["spec"] = map[string]interface{}{"replicas": int64(1)}
+
+			tc := newTestCase(c.name).
+				withWorkManifest(required).
+				withManifestConfig(newManifestConfigOption("apps", "deployments", "ns1", "deploy", c.strategy)).
+				withSpokeDynamicObject(existing).
+				withExpectedWorkAction("patch").
+				withAppliedWorkAction("create").
+				withExpectedDynamicAction(c.expectedDynamicActions...).
+				withExpectedManifestCondition(expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue)).
+				withExpectedWorkCondition(expectedCondition(workapiv1.WorkApplied, metav1.ConditionTrue))
+
+			work, workKey := tc.newManifestWork()
+			work.Annotations = map[string]string{helper.DriftDetectionAnnotation: helper.DriftDetectionAudit}
+			controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+				withKubeObject().
+				withUnstructuredObject(tc.spokeDynamicObject...)
+
+			syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+			if err := controller.toController().sync(context.TODO(), syncContext, work.Name); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tc.validate(t, controller.dynamicClient, controller.workClient, controller.kubeClient)
+
+			// the drift audit mode does not change the update strategy of the manifest
+			deploy, err := controller.dynamicClient.Resource(schema.GroupVersionResource{
+				Group: "apps", Version: "v1", Resource: "deployments"}).Namespace("ns1").Get(context.TODO(), "deploy", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			replicas, _, _ := unstructured.NestedInt64(deploy.Object, "spec", "replicas")
+			if patched := replicas == 1; patched != c.expectedReplicasPatched {
+				t.Errorf("expected the replicas updated %v, but got replicas %d", c.expectedReplicasPatched, replicas)
+			}
+		})
+	}
+}
+
+func TestLocalPolicy(t *testing.T) {
+	tc := newTestCase("manifest in the protected namespace is denied").
+		withWorkManifest(
+			testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"),
+			testingcommon.NewUnstructured("v1", "Secret", "kube-system", "secret"),
+		).
+		withExpectedWorkAction("patch").
+		withAppliedWorkAction("create").
+		withExpectedKubeAction("get", "create").
+		withExpectedManifestCondition(
+			expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+			newCondition(workapiv1.ManifestApplied, string(metav1.ConditionFalse), DeniedByLocalPolicy, "", 0, nil),
+		).
+		withExpectedWorkCondition(
+			newCondition(workapiv1.WorkApplied, string(metav1.ConditionFalse), DeniedByLocalPolicy, "", 0, nil))
+
+	work, workKey := tc.newManifestWork()
+	controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+		withKubeObject().
+		withUnstructuredObject()
+	policy := &localpolicy.Policy{Deny: []localpolicy.DenyRule{{Name: "protect-kube-system", Namespaces: []string{"kube-*"}}}}
+	controller.mwReconciler.validator = localpolicy.NewValidator(policy, controller.mwReconciler.validator)
+
+	syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+	if err := controller.toController().sync(context.TODO(), syncContext, work.Name); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tc.validate(t, controller.dynamicClient, controller.workClient, controller.kubeClient)
+}
+
+func newHookJob(name, hook string, conditionType string) *unstructured.Unstructured {
+	obj := testingcommon.NewUnstructured("batch/v1", "Job", "ns1", name)
+	obj.SetAnnotations(map[string]string{helper.HookAnnotation: hook})
+	if len(conditionType) > 0 {
+		obj.Object["status"] = map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": conditionType, "status": "True"},
+			},
+		}
+	}
+	return obj
+}
+
+func TestApplyManifestsWithHooks(t *testing.T) {
+	cases := []*testCase{
+		newTestCase("manifests blocked by the pre-apply hook not complete").
+			withWorkManifest(
+				testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"),
+				newHookJob("hook", helper.HookPreApply, ""),
+			).
+			withExpectedWorkAction("patch").
+			withAppliedWorkAction("create").
+			withExpectedDynamicAction("get", "create", "get").
+			withExpectedManifestCondition(
+				newCondition(workapiv1.ManifestApplied, string(metav1.ConditionFalse), AppliedManifestWaitingForWave, "", 0, nil),
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+			).
+			withExpectedWorkCondition(
+				newCondition(workapiv1.WorkApplied, string(metav1.ConditionFalse), AppliedManifestWorkWaitingForWave, "", 0, nil)),
+		newTestCase("manifests blocked by the pre-apply hook failed").
+			withWorkManifest(
+				testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"),
+				newHookJob("hook", helper.HookPreApply, ""),
+			).
+			withSpokeDynamicObject(newHookJob("hook", helper.HookPreApply, "Failed")).
+			withExpectedWorkAction("patch").
+			withAppliedWorkAction("create").
+			withExpectedDynamicAction("get", "patch", "get").
+			withExpectedManifestCondition(
+				newCondition(workapiv1.ManifestApplied, string(metav1.ConditionFalse), AppliedManifestWaitingForWave, "", 0, nil),
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+			),
+		newTestCase("manifests applied after the pre-apply hook is complete").
+			withWorkManifest(
+				testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"),
+				newHookJob("hook", helper.HookPreApply, ""),
+			).
+			withSpokeDynamicObject(newHookJob("hook", helper.HookPreApply, "Complete")).
+			withExpectedWorkAction("patch").
+			withAppliedWorkAction("create").
+			withExpectedKubeAction("get", "create").
+			withExpectedDynamicAction("get", "patch", "get").
+			withExpectedManifestCondition(
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+			).
+			withExpectedWorkCondition(expectedCondition(workapiv1.WorkApplied, metav1.ConditionTrue)),
+		newTestCase("post-apply hook applied after the manifests are ready").
+			withWorkManifest(
+				newHookJob("hook", helper.HookPostApply, ""),
+				testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"),
+			).
+			withExpectedWorkAction("patch").
+			withAppliedWorkAction("create").
+			withExpectedKubeAction("get", "create").
+			withExpectedDynamicAction("get", "create").
+			withExpectedManifestCondition(
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+			).
+			withExpectedWorkCondition(expectedCondition(workapiv1.WorkApplied, metav1.ConditionTrue)),
+		newTestCase("pre-delete hook not applied").
+			withWorkManifest(
+				newHookJob("hook", helper.HookPreDelete, ""),
+				testingcommon.NewUnstructured("v1", "Secret", "ns1", "secret"),
+			).
+			withExpectedWorkAction("patch").
+			withAppliedWorkAction("create").
+			withExpectedKubeAction("get", "create").
+			withExpectedManifestCondition(
+				newCondition(workapiv1.ManifestApplied, string(metav1.ConditionTrue), PreDeleteHookDeferred, "", 0, nil),
+				expectedCondition(workapiv1.ManifestApplied, metav1.ConditionTrue),
+			).
+			withExpectedWorkCondition(expectedCondition(workapiv1.WorkApplied, metav1.ConditionTrue)),
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work, workKey := c.newManifestWork()
+			controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+				withKubeObject(c.spokeObject...).
+				withUnstructuredObject(c.spokeDynamicObject...)
+			syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+			err := controller.toController().sync(context.TODO(), syncContext, work.Name)
+			if err != nil {
+				t.Errorf("Should be success with no err: %v", err)
+			}
+
+			c.validate(t, controller.dynamicClient, controller.workClient, controller.kubeClient)
+		})
+	}
+}
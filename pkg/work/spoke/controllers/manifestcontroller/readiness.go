@@ -0,0 +1,145 @@
+package manifestcontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+// WaveBlockedError is returned for the manifests not applied because the resources of a previous
+// apply wave are not ready yet.
+type WaveBlockedError struct {
+	// Wave is the previous apply wave the manifest is waiting for.
+	Wave int
+	// Hook is the hook stage of the previous apply wave if it is a wave of hooks.
+	Hook string
+	// NotReady lists the resources of the wave which are not ready.
+	NotReady []string
+}
+
+func (e *WaveBlockedError) Error() string {
+	if len(e.Hook) > 0 {
+		return fmt.Sprintf("waiting for the %s hooks to complete: %s", e.Hook, strings.Join(e.NotReady, ", "))
+	}
+	return fmt.Sprintf("waiting for apply wave %d to be ready: %s", e.Wave, strings.Join(e.NotReady, ", "))
+}
+
+// isReady returns if the applied resource is ready for the next apply wave. The condition rules of the
+// manifest are used if any, otherwise the well-known Ready condition rule of the kind. The kinds without
+// a rule, e.g. ConfigMaps or the custom resources, are ready once they are applied. A reason is returned
+// if the resource is not ready.
+func (m *manifestworkReconciler) isReady(
+	ctx context.Context,
+	om orderedManifest,
+	result applyResult,
+	workSpec workapiv1.ManifestWorkSpec,
+	workStatus workapiv1.ManifestWorkStatus) (bool, string) {
+	if result.Error != nil {
+		return false, "not applied"
+	}
+
+	manifestCondition := helper.FindManifestCondition(om.resourceMeta, workStatus.ResourceStatus.Manifests)
+	if manifestCondition != nil && meta.IsStatusConditionTrue(manifestCondition.Conditions, workapiv1.ManifestComplete) {
+		return true, ""
+	}
+
+	obj, err := m.readAppliedObject(ctx, om, result, workSpec)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	option := helper.FindManifestConfiguration(om.resourceMeta, workSpec.ManifestConfigs)
+	if option != nil && len(option.ConditionRules) > 0 && m.conditionReader != nil {
+		conditions := m.conditionReader.EvaluateConditions(ctx, obj, option.ConditionRules)
+		if len(conditions) > 0 {
+			var notMet []string
+			for _, condition := range conditions {
+				if condition.Status != metav1.ConditionTrue {
+					notMet = append(notMet, condition.Type)
+				}
+			}
+			if len(notMet) > 0 {
+				return false, fmt.Sprintf("condition %s is not true", strings.Join(notMet, ","))
+			}
+			return true, ""
+		}
+	}
+
+	if m.conditionReader == nil {
+		return false, "unable to read the readiness"
+	}
+	ready, found, message := m.conditionReader.IsReady(ctx, obj)
+	if found && !ready {
+		return false, message
+	}
+	return true, ""
+}
+
+// isHookComplete returns if the Job of the hook is complete by the well-known ManifestComplete condition
+// rule. A failed Job is never complete, and it should be renamed to run again. A reason is returned if the
+// hook is not complete.
+func (m *manifestworkReconciler) isHookComplete(
+	ctx context.Context,
+	om orderedManifest,
+	result applyResult) (bool, string) {
+	if result.Error != nil {
+		return false, "not applied"
+	}
+	if m.spokeDynamicClient == nil || m.conditionReader == nil {
+		return false, "unable to read the hook"
+	}
+	obj, err := m.spokeDynamicClient.Resource(om.gvr).Namespace(om.resourceMeta.Namespace).Get(
+		ctx, om.resourceMeta.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, err.Error()
+	}
+	if helper.IsJobFailed(obj) {
+		return false, "hook failed"
+	}
+	if complete, message := m.conditionReader.IsComplete(ctx, obj); !complete {
+		return false, message
+	}
+	return true, ""
+}
+
+// readAppliedObject returns the resource returned by the applier, which is the latest object on the
+// spoke cluster. The read only appliers return the manifest, so the resource is read from the cluster.
+func (m *manifestworkReconciler) readAppliedObject(
+	ctx context.Context,
+	om orderedManifest,
+	result applyResult,
+	workSpec workapiv1.ManifestWorkSpec) (*unstructured.Unstructured, error) {
+	option := helper.FindManifestConfiguration(om.resourceMeta, workSpec.ManifestConfigs)
+	if option != nil && option.UpdateStrategy != nil && option.UpdateStrategy.Type == workapiv1.UpdateStrategyTypeReadOnly {
+		if m.spokeDynamicClient == nil {
+			return nil, fmt.Errorf("unable to read the resource")
+		}
+		return m.spokeDynamicClient.Resource(om.gvr).Namespace(om.resourceMeta.Namespace).Get(
+			ctx, om.resourceMeta.Name, metav1.GetOptions{})
+	}
+
+	switch obj := result.Result.(type) {
+	case nil:
+		return nil, fmt.Errorf("not applied")
+	case *unstructured.Unstructured:
+		return obj, nil
+	default:
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		u := &unstructured.Unstructured{Object: content}
+		// the typed objects returned by the appliers do not have the type meta
+		u.SetGroupVersionKind(om.obj.GroupVersionKind())
+		return u, nil
+	}
+}
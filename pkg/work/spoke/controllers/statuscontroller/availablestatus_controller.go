@@ -3,6 +3,7 @@ package statuscontroller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -10,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -26,14 +28,19 @@ import (
 	commonhelper "open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/apply"
 	"open-cluster-management.io/ocm/pkg/work/spoke/conditions"
 	"open-cluster-management.io/ocm/pkg/work/spoke/objectreader"
 	"open-cluster-management.io/ocm/pkg/work/spoke/statusfeedback"
+	"open-cluster-management.io/ocm/pkg/work/spoke/wellknownrules"
 )
 
 const (
 	statusFeedbackConditionType = "StatusFeedbackSynced"
 
+	// maxDriftedPaths is the max number of the drifted paths listed in the Drifted condition message.
+	maxDriftedPaths = 10
+
 	controllerName = "AvailableStatusController"
 )
 
@@ -48,6 +55,8 @@ type AvailableStatusController struct {
 	statusReader       *statusfeedback.StatusReader
 	conditionReader    *conditions.ConditionReader
 	syncInterval       time.Duration
+	// defaultFeedbackScrapeType is the feedback scrape type of the manifests without the scrape type set.
+	defaultFeedbackScrapeType workapiv1.FeedbackScrapeType
 }
 
 // NewAvailableStatusController returns a AvailableStatusController
@@ -56,10 +65,13 @@ func NewAvailableStatusController(
 	manifestWorkInformer workinformer.ManifestWorkInformer,
 	manifestWorkLister worklister.ManifestWorkNamespaceLister,
 	conditionReader *conditions.ConditionReader,
+	statusReader *statusfeedback.StatusReader,
 	objectReader objectreader.ObjectReader,
-	maxJSONRawLength int32,
 	syncInterval time.Duration,
+	defaultFeedbackScrapeType workapiv1.FeedbackScrapeType,
+	wellKnownRules *wellknownrules.Resolver,
 ) factory.Controller {
+	syncCtx := factory.NewSyncContext(controllerName)
 	controller := &AvailableStatusController{
 		patcher: patcher.NewPatcher[
 			*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](
@@ -67,12 +79,30 @@ func NewAvailableStatusController(
 		manifestWorkLister: manifestWorkLister,
 		syncInterval:       syncInterval,
 		objectReader:       objectReader,
-		statusReader:       statusfeedback.NewStatusReader().WithMaxJsonRawLength(maxJSONRawLength),
+		statusReader:       statusReader,
 		conditionReader:    conditionReader,
+
+		defaultFeedbackScrapeType: defaultFeedbackScrapeType,
+	}
+
+	// the status feedback and the conditions of the works are read with the well-known rules, so the works
+	// are requeued once the rules change.
+	if wellKnownRules != nil {
+		wellKnownRules.AddEventHandler(func() {
+			works, err := manifestWorkLister.List(labels.Everything())
+			if err != nil {
+				utilruntime.HandleError(err)
+				return
+			}
+			for _, work := range works {
+				syncCtx.Queue().Add(work.Name)
+			}
+		})
 	}
 
 	return factory.New().
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, manifestWorkInformer.Informer()).
+		WithSyncContext(syncCtx).
 		WithSync(controller.sync).ToController(controllerName)
 }
 
@@ -128,8 +158,15 @@ func (c *AvailableStatusController) syncManifestWork(ctx context.Context, contro
 			continue
 		}
 
+		// Compare the resource with the manifest in the drift audit mode
+		if helper.IsDriftAuditMode(manifestWork) {
+			c.checkDrift(ctx, manifestConditions, obj, manifestWork, manifest.ResourceMeta)
+		} else {
+			meta.RemoveStatusCondition(manifestConditions, helper.ManifestDrifted)
+		}
+
 		option := helper.FindManifestConfiguration(manifest.ResourceMeta, manifestWork.Spec.ManifestConfigs)
-		if option != nil && option.FeedbackScrapeType == workapiv1.FeedbackWatchType {
+		if c.feedbackScrapeType(option) == workapiv1.FeedbackWatchType {
 			if err := c.objectReader.RegisterInformer(ctx, manifestWork.Name, manifest.ResourceMeta, controllerContext.Queue()); err != nil {
 				utilruntime.HandleErrorWithContext(ctx, err, "failed to register informer")
 			}
@@ -170,6 +207,15 @@ func (c *AvailableStatusController) syncManifestWork(ctx context.Context, contro
 	return err
 }
 
+// feedbackScrapeType returns the feedback scrape type in the manifest config option, or the default type if
+// it is not set.
+func (c *AvailableStatusController) feedbackScrapeType(option *workapiv1.ManifestConfigOption) workapiv1.FeedbackScrapeType {
+	if option != nil && len(option.FeedbackScrapeType) > 0 {
+		return option.FeedbackScrapeType
+	}
+	return c.defaultFeedbackScrapeType
+}
+
 func (c *AvailableStatusController) getFeedbackValues(
 	obj *unstructured.Unstructured,
 	option *workapiv1.ManifestConfigOption) ([]workapiv1.FeedbackValue, metav1.Condition) {
@@ -212,6 +258,49 @@ func (c *AvailableStatusController) getFeedbackValues(
 	}
 }
 
+// checkDrift sets the Drifted condition of the manifest by comparing the resource with the manifest.
+func (c *AvailableStatusController) checkDrift(ctx context.Context,
+	manifestConditions *[]metav1.Condition, obj *unstructured.Unstructured,
+	manifestWork *workapiv1.ManifestWork, resourceMeta workapiv1.ManifestResourceMeta,
+) {
+	manifests := manifestWork.Spec.Workload.Manifests
+	if int(resourceMeta.Ordinal) >= len(manifests) {
+		return
+	}
+	required := &unstructured.Unstructured{}
+	if err := required.UnmarshalJSON(manifests[resourceMeta.Ordinal].Raw); err != nil {
+		klog.FromContext(ctx).V(4).Info("failed to decode the manifest", "ordinal", resourceMeta.Ordinal, "error", err)
+		return
+	}
+	// the spec may be changed after the status is updated
+	if required.GetName() != resourceMeta.Name || required.GetNamespace() != resourceMeta.Namespace ||
+		required.GetKind() != resourceMeta.Kind {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               helper.ManifestDrifted,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoDrift",
+		Message:            "Resource matches the manifest",
+		ObservedGeneration: manifestWork.Generation,
+	}
+	if paths := apply.DriftedPaths(required, obj); len(paths) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ResourceDrifted"
+		condition.Message = fmt.Sprintf("Resource diverges from the manifest at %d path(s): %s",
+			len(paths), summarizePaths(paths))
+	}
+	meta.SetStatusCondition(manifestConditions, condition)
+}
+
+func summarizePaths(paths []string) string {
+	if len(paths) <= maxDriftedPaths {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:maxDriftedPaths], ", "), len(paths)-maxDriftedPaths)
+}
+
 // evaluateConditionRules updates manifestConditions based on configured condition rules for the manifest
 func (c *AvailableStatusController) evaluateConditionRules(ctx context.Context,
 	manifestConditions *[]metav1.Condition, obj *unstructured.Unstructured, option *workapiv1.ManifestConfigOption, generation int64,
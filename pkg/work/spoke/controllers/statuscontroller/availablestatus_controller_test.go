@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	fakedynamic "k8s.io/client-go/dynamic/fake"
@@ -24,6 +25,7 @@ import (
 
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	"open-cluster-management.io/ocm/pkg/features"
+	"open-cluster-management.io/ocm/pkg/work/helper"
 	"open-cluster-management.io/ocm/pkg/work/spoke/conditions"
 	"open-cluster-management.io/ocm/pkg/work/spoke/objectreader"
 	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
@@ -1125,3 +1127,136 @@ func TestRegisterUnregisterInformersBasedOnFeedbackType(t *testing.T) {
 		})
 	}
 }
+
+func TestDriftDetection(t *testing.T) {
+	newSecret := func(data map[string]any) *unstructured.Unstructured {
+		secret := testingcommon.NewUnstructuredSecret("ns1", "n1", false, "ns1-n1")
+		secret.Object["data"] = data
+		return secret
+	}
+
+	cases := []struct {
+		name                   string
+		annotations            map[string]string
+		existing               *unstructured.Unstructured
+		existingConditions     []metav1.Condition
+		expectedDriftCondition metav1.ConditionStatus
+		expectedMessage        string
+	}{
+		{
+			name:                   "resource drifted",
+			annotations:            map[string]string{helper.DriftDetectionAnnotation: helper.DriftDetectionAudit},
+			existing:               newSecret(map[string]any{"key1": "ZWRpdGVk", "key2": "dmFsdWU="}),
+			expectedDriftCondition: metav1.ConditionTrue,
+			expectedMessage:        "Resource diverges from the manifest at 1 path(s): .data.key1",
+		},
+		{
+			name:                   "resource not drifted",
+			annotations:            map[string]string{helper.DriftDetectionAnnotation: helper.DriftDetectionAudit},
+			existing:               newSecret(map[string]any{"key1": "dmFsdWU=", "key2": "dmFsdWU="}),
+			expectedDriftCondition: metav1.ConditionFalse,
+			expectedMessage:        "Resource matches the manifest",
+		},
+		{
+			name:     "drifted condition removed without audit mode",
+			existing: newSecret(map[string]any{"key1": "ZWRpdGVk"}),
+			existingConditions: []metav1.Condition{
+				{Type: helper.ManifestDrifted, Status: metav1.ConditionTrue, Reason: "ResourceDrifted"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			testingWork, _ := spoketesting.NewManifestWork(0, newSecret(map[string]any{"key1": "dmFsdWU=", "key2": "dmFsdWU="}))
+			testingWork.Annotations = c.annotations
+			testingWork.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+			manifest := newManifest("", "v1", "secrets", "ns1", "n1", c.existingConditions...)
+			manifest.ResourceMeta.Kind = "Secret"
+			testingWork.Status = workapiv1.ManifestWorkStatus{
+				Conditions: []metav1.Condition{{Type: workapiv1.WorkApplied}},
+				ResourceStatus: workapiv1.ManifestResourceStatus{
+					Manifests: []workapiv1.ManifestCondition{manifest},
+				},
+			}
+
+			fakeClient := fakeworkclient.NewSimpleClientset(testingWork)
+			fakeDynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), c.existing)
+			informerFactory := workinformers.NewSharedInformerFactory(fakeClient, 0)
+			r, err := objectreader.NewOptions().NewObjectReader(fakeDynamicClient, informerFactory.Work().V1().ManifestWorks())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			syncCtx := testingcommon.NewFakeSyncContext(t, testingWork.Namespace)
+			controller := AvailableStatusController{
+				patcher: patcher.NewPatcher[
+					*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](
+					fakeClient.WorkV1().ManifestWorks(testingWork.Namespace)),
+				objectReader: r,
+			}
+
+			if err := controller.syncManifestWork(context.TODO(), syncCtx, testingWork); err != nil {
+				t.Fatal(err)
+			}
+
+			actions := fakeClient.Actions()
+			testingcommon.AssertActions(t, actions, "patch")
+			work := &workapiv1.ManifestWork{}
+			if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, work); err != nil {
+				t.Fatal(err)
+			}
+			condition := meta.FindStatusCondition(work.Status.ResourceStatus.Manifests[0].Conditions, helper.ManifestDrifted)
+			if len(c.expectedDriftCondition) == 0 {
+				if condition != nil {
+					t.Errorf("expected no drifted condition, but got %v", condition)
+				}
+				return
+			}
+			if condition == nil || condition.Status != c.expectedDriftCondition || condition.Message != c.expectedMessage {
+				t.Errorf("unexpected drifted condition %v", condition)
+			}
+		})
+	}
+}
+
+func TestFeedbackScrapeType(t *testing.T) {
+	cases := []struct {
+		name        string
+		option      *workapiv1.ManifestConfigOption
+		defaultType workapiv1.FeedbackScrapeType
+		expected    workapiv1.FeedbackScrapeType
+	}{
+		{
+			name:        "no manifest config",
+			defaultType: workapiv1.FeedbackWatchType,
+			expected:    workapiv1.FeedbackWatchType,
+		},
+		{
+			name:        "scrape type not set",
+			option:      &workapiv1.ManifestConfigOption{},
+			defaultType: workapiv1.FeedbackWatchType,
+			expected:    workapiv1.FeedbackWatchType,
+		},
+		{
+			name:        "poll in manifest config",
+			option:      &workapiv1.ManifestConfigOption{FeedbackScrapeType: workapiv1.FeedbackPollType},
+			defaultType: workapiv1.FeedbackWatchType,
+			expected:    workapiv1.FeedbackPollType,
+		},
+		{
+			name:        "watch in manifest config",
+			option:      &workapiv1.ManifestConfigOption{FeedbackScrapeType: workapiv1.FeedbackWatchType},
+			defaultType: workapiv1.FeedbackPollType,
+			expected:    workapiv1.FeedbackWatchType,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := &AvailableStatusController{defaultFeedbackScrapeType: c.defaultType}
+			if actual := controller.feedbackScrapeType(c.option); actual != c.expected {
+				t.Errorf("expected %s, but got %s", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package objectreader
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	// Constants for metric names.
+	ObjectReaderSubsystem = "work_agent_object_reader"
+	WatchedResourcesKey   = "watched_resources"
+	WatchFallbacksKey     = "watch_fallbacks_total"
+	ReadsKey              = "reads_total"
+	readSourceCache       = "cache"
+	readSourceAPIServer   = "apiserver"
+)
+
+var (
+	watchedResources = k8smetrics.NewGaugeVec(&k8smetrics.GaugeOpts{
+		Subsystem:      ObjectReaderSubsystem,
+		Name:           WatchedResourcesKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of the resources watched by the shared informer of the resource type.",
+	}, []string{"group", "version", "resource"})
+
+	watchFallbacks = k8smetrics.NewCounterVec(&k8smetrics.CounterOpts{
+		Subsystem:      ObjectReaderSubsystem,
+		Name:           WatchFallbacksKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of the times a resource to watch falls back to be polled since the watch budget is exhausted.",
+	}, []string{"group", "version", "resource"})
+
+	reads = k8smetrics.NewCounterVec(&k8smetrics.CounterOpts{
+		Subsystem:      ObjectReaderSubsystem,
+		Name:           ReadsKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of the resource reads by the source, which is either the informer cache or the apiserver.",
+	}, []string{"group", "version", "resource", "source"})
+
+	metrics = []k8smetrics.Registerable{
+		watchedResources, watchFallbacks, reads,
+	}
+)
+
+func init() {
+	// Register metrics on initialization.
+	for _, m := range metrics {
+		legacyregistry.MustRegister(m)
+	}
+}
+
+func gvrLabels(gvr schema.GroupVersionResource, values ...string) []string {
+	return append([]string{gvr.Group, gvr.Version, gvr.Resource}, values...)
+}
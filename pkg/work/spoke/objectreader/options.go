@@ -1,26 +1,48 @@
 package objectreader
 
 import (
+	"fmt"
+
 	"github.com/spf13/pflag"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
 
 	workinformers "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
 )
 
 type Options struct {
+	// MaxFeedbackWatch is the max number of the resource types watched by the shared informers.
 	MaxFeedbackWatch int32
+	// DefaultFeedbackScrapeType is the feedback scrape type of the manifests without the scrape type set
+	// in the manifest configs, it is Watch or Poll.
+	DefaultFeedbackScrapeType string
 }
 
 func NewOptions() *Options {
 	return &Options{
-		MaxFeedbackWatch: 50,
+		MaxFeedbackWatch:          50,
+		DefaultFeedbackScrapeType: string(workapiv1.FeedbackWatchType),
 	}
 }
 
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.Int32Var(&o.MaxFeedbackWatch, "max-feedback-watch",
-		o.MaxFeedbackWatch, "The maximum number of watch for feedback results")
+		o.MaxFeedbackWatch, "The maximum number of the resource types watched for feedback results. An informer "+
+			"is shared by the resources of a type, and the resources are polled once the limit is reached.")
+	fs.StringVar(&o.DefaultFeedbackScrapeType, "default-feedback-scrape-type", o.DefaultFeedbackScrapeType,
+		"The way to monitor the resources without the feedback scrape type set in the manifest configs, "+
+			"it is Watch or Poll.")
+}
+
+func (o *Options) Validate() error {
+	switch workapiv1.FeedbackScrapeType(o.DefaultFeedbackScrapeType) {
+	case workapiv1.FeedbackWatchType, workapiv1.FeedbackPollType:
+	default:
+		return fmt.Errorf("default-feedback-scrape-type must be %s or %s, got %q",
+			workapiv1.FeedbackWatchType, workapiv1.FeedbackPollType, o.DefaultFeedbackScrapeType)
+	}
+	return nil
 }
 
 func (o *Options) NewObjectReader(dynamicClient dynamic.Interface, workInformer workinformers.ManifestWorkInformer) (ObjectReader, error) {
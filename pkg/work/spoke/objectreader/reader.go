@@ -28,13 +28,15 @@ type ObjectReader interface {
 	// Get returns an object based on resourceMeta
 	Get(ctx context.Context, resourceMeta workapiv1.ManifestResourceMeta) (*unstructured.Unstructured, metav1.Condition, error)
 
-	// RegisterInformer registers an informer to the ObjectReader
+	// RegisterInformer registers the resource to be watched by the shared informer of the resource type. The
+	// resource is polled if the informers reach the max number of watch.
 	RegisterInformer(
 		ctx context.Context, workName string,
 		resourceMeta workapiv1.ManifestResourceMeta,
 		queue workqueue.TypedRateLimitingInterface[string]) error
 
-	// UnRegisterInformer unregister the informer from the ObjectReader
+	// UnRegisterInformer unregisters the resource from the shared informer, and the informer is stopped once
+	// there is no resource watched by it.
 	UnRegisterInformer(workName string, resourceMeta workapiv1.ManifestResourceMeta) error
 }
 
@@ -55,15 +57,17 @@ type informerWithCancel struct {
 	informer cache.SharedIndexInformer
 	lister   cache.GenericLister
 	cancel   context.CancelFunc
+	// namespace is the namespace watched by the informer. It is the namespace of the first resource
+	// registered, and is widened to all namespaces once a resource in another namespace is registered.
+	namespace string
 
-	// registrations records all the event handler registrations, keyed by registrationKey
-	registrations map[registrationKey]cache.ResourceEventHandlerRegistration
+	// registrations records the resources watched by the works, it is the reference count of the informer.
+	registrations map[registrationKey]struct{}
 }
 
-// informerKey is the key to register an informer
+// informerKey is the key to register an informer, an informer is shared by all the resources of a type.
 type informerKey struct {
 	schema.GroupVersionResource
-	namespace string
 }
 
 type registrationKey struct {
@@ -115,16 +119,16 @@ func (o *objectReader) getObject(ctx context.Context, resourceMeta workapiv1.Man
 		Version:  resourceMeta.Version,
 		Resource: resourceMeta.Resource,
 	}
-	key := informerKey{GroupVersionResource: gvr, namespace: resourceMeta.Namespace}
+	key := informerKey{GroupVersionResource: gvr}
 
 	o.RLock()
 	i, found := o.informers[key]
 	o.RUnlock()
 
-	// Use informer cache only if it exists and has synced.
+	// Use informer cache only if it exists, has synced and watches the namespace of the resource.
 	// If informer is not synced (e.g., watch permission denied, initial sync in progress),
 	// fallback to direct client.Get() which only requires GET permission.
-	if found && i.informer.HasSynced() {
+	if found && i.watches(resourceMeta.Namespace) && i.informer.HasSynced() {
 		var runObj runtime.Object
 		var err error
 		// For cluster-scoped resources (empty namespace), use Get() directly
@@ -141,14 +145,16 @@ func (o *objectReader) getObject(ctx context.Context, resourceMeta workapiv1.Man
 		if !ok {
 			return nil, fmt.Errorf("unexpected type from lister: %T", runObj)
 		}
+		reads.WithLabelValues(gvrLabels(gvr, readSourceCache)...).Inc()
 		return obj, nil
 	}
 
+	reads.WithLabelValues(gvrLabels(gvr, readSourceAPIServer)...).Inc()
 	return o.dynamicClient.Resource(gvr).Namespace(resourceMeta.Namespace).Get(ctx, resourceMeta.Name, metav1.GetOptions{})
 }
 
-// RegisterInformer checks if there is an informer and if the event handler has been registered to the informer.
-// this is called each time a resource needs to be watched. It is idempotent.
+// RegisterInformer checks if there is an informer of the resource type and if the resource has been registered
+// to the informer. This is called each time a resource needs to be watched. It is idempotent.
 func (o *objectReader) RegisterInformer(
 	ctx context.Context, workName string,
 	resourceMeta workapiv1.ManifestResourceMeta,
@@ -163,7 +169,7 @@ func (o *objectReader) RegisterInformer(
 		Resource: resourceMeta.Resource,
 	}
 
-	key := informerKey{GroupVersionResource: gvr, namespace: resourceMeta.Namespace}
+	key := informerKey{GroupVersionResource: gvr}
 	regKey := registrationKey{
 		GroupVersionResource: gvr,
 		namespace:            resourceMeta.Namespace,
@@ -171,47 +177,85 @@ func (o *objectReader) RegisterInformer(
 		workName:             workName,
 	}
 	informer, found := o.informers[key]
-	if !found {
+	switch {
+	case !found:
+		// the resource is polled until an informer is stopped, and it is registered again on the next sync.
 		if len(o.informers) >= int(o.maxWatch) {
-			logger.Info("The number of registered informers has reached the maximum limit, fallback to feedback with poll")
+			logger.V(2).Info("The number of registered informers has reached the maximum limit, fallback to feedback with poll",
+				"informerKey", key)
+			watchFallbacks.WithLabelValues(gvrLabels(gvr)...).Inc()
 			return nil
 		}
 
-		resourceInformer := dynamicinformer.NewFilteredDynamicInformer(
-			o.dynamicClient, gvr, resourceMeta.Namespace, 24*time.Hour,
-			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, nil)
-		informerCtx, cancel := context.WithCancel(ctx)
-		informer = &informerWithCancel{
-			informer:      resourceInformer.Informer(),
-			cancel:        cancel,
-			lister:        resourceInformer.Lister(),
-			registrations: map[registrationKey]cache.ResourceEventHandlerRegistration{},
+		var err error
+		if informer, err = o.startInformer(ctx, key, resourceMeta.Namespace, queue); err != nil {
+			return err
 		}
+		informer.registrations = map[registrationKey]struct{}{}
 		o.informers[key] = informer
-		logger.V(4).Info("Registered informer for objecr reader", "informerKey", key)
-		go resourceInformer.Informer().Run(informerCtx.Done())
+		logger.V(4).Info("Registered informer for object reader", "informerKey", key, "namespace", resourceMeta.Namespace)
+	case !informer.watches(resourceMeta.Namespace):
+		// the informer watching the namespace of the other resources is replaced by an informer watching all
+		// namespaces, which keeps the registrations.
+		widened, err := o.startInformer(ctx, key, metav1.NamespaceAll, queue)
+		if err != nil {
+			return err
+		}
+		informer.cancel()
+		widened.registrations = informer.registrations
+		informer = widened
+		o.informers[key] = informer
+		logger.V(4).Info("Widened informer for object reader to all namespaces", "informerKey", key)
 	}
 
-	// check if the event handler has been registered.
+	// check if the resource has been registered.
 	if _, registrationFound := informer.registrations[regKey]; registrationFound {
 		return nil
 	}
 
-	logger.V(4).Info("Add event handler of informer for objecr reader", "informerKey", key, "resourceKey", regKey)
-	// Add event handler into the informer so it can trigger work reconcile
-	registration, err := informer.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: o.queueWorkByResourceFunc(ctx, gvr, queue),
+	logger.V(4).Info("Register resource to the informer of object reader", "informerKey", key, "resourceKey", regKey)
+	informer.registrations[regKey] = struct{}{}
+	watchedResources.WithLabelValues(gvrLabels(gvr)...).Set(float64(len(informer.registrations)))
+
+	return nil
+}
+
+// startInformer starts an informer of the resource type in the namespace, which enqueues the works watching
+// the resources. It must be called with the lock held.
+func (o *objectReader) startInformer(
+	ctx context.Context, key informerKey, namespace string,
+	queue workqueue.TypedRateLimitingInterface[string]) (*informerWithCancel, error) {
+	resourceInformer := dynamicinformer.NewFilteredDynamicInformer(
+		o.dynamicClient, key.GroupVersionResource, namespace, 24*time.Hour,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, nil)
+	// a single event handler is added to the informer, which enqueues the works watching the resource.
+	if _, err := resourceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: o.queueWorkByResourceFunc(ctx, key, queue),
 		UpdateFunc: func(old, new interface{}) {
-			o.queueWorkByResourceFunc(ctx, gvr, queue)(new)
+			o.queueWorkByResourceFunc(ctx, key, queue)(new)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			o.queueWorkByResourceFunc(ctx, key, queue)(obj)
 		},
-	})
-	if err != nil {
-		return err
+	}); err != nil {
+		return nil, err
 	}
-	// record the event handler registration
-	informer.registrations[regKey] = registration
+	informerCtx, cancel := context.WithCancel(ctx)
+	go resourceInformer.Informer().Run(informerCtx.Done())
+	return &informerWithCancel{
+		informer:  resourceInformer.Informer(),
+		cancel:    cancel,
+		lister:    resourceInformer.Lister(),
+		namespace: namespace,
+	}, nil
+}
 
-	return nil
+// watches returns if the informer watches the resources in the namespace.
+func (i *informerWithCancel) watches(namespace string) bool {
+	return i.namespace == metav1.NamespaceAll || i.namespace == namespace
 }
 
 // UnRegisterInformer is called each time a resource is not watched.
@@ -225,7 +269,7 @@ func (o *objectReader) UnRegisterInformer(workName string, resourceMeta workapiv
 		Resource: resourceMeta.Resource,
 	}
 
-	key := informerKey{GroupVersionResource: gvr, namespace: resourceMeta.Namespace}
+	key := informerKey{GroupVersionResource: gvr}
 	regKey := registrationKey{
 		GroupVersionResource: gvr,
 		namespace:            resourceMeta.Namespace,
@@ -237,15 +281,11 @@ func (o *objectReader) UnRegisterInformer(workName string, resourceMeta workapiv
 		return nil
 	}
 
-	registration, found := informer.registrations[regKey]
-	if !found {
+	if _, found := informer.registrations[regKey]; !found {
 		return nil
 	}
-
-	if err := informer.informer.RemoveEventHandler(registration); err != nil {
-		return err
-	}
 	delete(informer.registrations, regKey)
+	watchedResources.WithLabelValues(gvrLabels(gvr)...).Set(float64(len(informer.registrations)))
 
 	// stop the informer if no one use it.
 	if len(informer.registrations) == 0 {
@@ -256,7 +296,8 @@ func (o *objectReader) UnRegisterInformer(workName string, resourceMeta workapiv
 	return nil
 }
 
-func (o *objectReader) queueWorkByResourceFunc(ctx context.Context, gvr schema.GroupVersionResource, queue workqueue.TypedRateLimitingInterface[string]) func(object interface{}) {
+func (o *objectReader) queueWorkByResourceFunc(ctx context.Context, informerKey informerKey, queue workqueue.TypedRateLimitingInterface[string]) func(object interface{}) {
+	gvr := informerKey.GroupVersionResource
 	return func(object interface{}) {
 		logger := klog.FromContext(ctx)
 		accessor, err := meta.Accessor(object)
@@ -272,8 +313,23 @@ func (o *objectReader) queueWorkByResourceFunc(ctx context.Context, gvr schema.G
 			return
 		}
 
+		o.RLock()
+		defer o.RUnlock()
+		informer, found := o.informers[informerKey]
+		if !found {
+			return
+		}
 		for _, obj := range objects {
 			work := obj.(*workapiv1.ManifestWork)
+			// only the works watching the resource are enqueued, the others poll the resource
+			if _, watched := informer.registrations[registrationKey{
+				GroupVersionResource: gvr,
+				namespace:            accessor.GetNamespace(),
+				name:                 accessor.GetName(),
+				workName:             work.Name,
+			}]; !watched {
+				continue
+			}
 			logger.V(4).Info("enqueue work by resource", "resourceKey", key)
 			queue.Add(work.Name)
 		}
@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
 	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
@@ -283,7 +284,7 @@ func TestRegisterInformer(t *testing.T) {
 		Version:  resourceMeta.Version,
 		Resource: resourceMeta.Resource,
 	}
-	key := informerKey{GroupVersionResource: gvr, namespace: resourceMeta.Namespace}
+	key := informerKey{GroupVersionResource: gvr}
 
 	// Cast to concrete type to access internal fields in tests
 	concreteReader := reader.(*objectReader)
@@ -380,7 +381,7 @@ func TestRegisterInformer_MultipleResources(t *testing.T) {
 		Version:  "v1",
 		Resource: "secrets",
 	}
-	key := informerKey{GroupVersionResource: gvr, namespace: "default"}
+	key := informerKey{GroupVersionResource: gvr}
 
 	// Cast to concrete type to access internal fields in tests
 	concreteReader := reader.(*objectReader)
@@ -434,7 +435,7 @@ func TestUnRegisterInformer(t *testing.T) {
 		Version:  resourceMeta.Version,
 		Resource: resourceMeta.Resource,
 	}
-	key := informerKey{GroupVersionResource: gvr, namespace: resourceMeta.Namespace}
+	key := informerKey{GroupVersionResource: gvr}
 
 	// Verify informer exists
 	concreteReader := reader.(*objectReader)
@@ -509,7 +510,7 @@ func TestUnRegisterInformer_MultipleRegistrations(t *testing.T) {
 		Version:  "v1",
 		Resource: "secrets",
 	}
-	key := informerKey{GroupVersionResource: gvr, namespace: "default"}
+	key := informerKey{GroupVersionResource: gvr}
 
 	// Unregister first resource
 	err = reader.UnRegisterInformer("test-work", resourceMeta1)
@@ -873,3 +874,185 @@ func TestIndexWorkByResource(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterInformer_WidenNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	fakeDynamicClient := fakedynamic.NewSimpleDynamicClient(scheme)
+	fakeWorkClient := fakeworkclient.NewSimpleClientset()
+	workInformerFactory := workinformers.NewSharedInformerFactory(fakeWorkClient, 10*time.Minute)
+	workInformer := workInformerFactory.Work().V1().ManifestWorks()
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+
+	options := NewOptions()
+	options.MaxFeedbackWatch = 1
+	reader, err := options.NewObjectReader(fakeDynamicClient, workInformer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	concreteReader := reader.(*objectReader)
+	ctx := t.Context()
+
+	secretKey := informerKey{GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}}
+	configMapKey := informerKey{GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}}
+	secret1 := workapiv1.ManifestResourceMeta{Version: "v1", Resource: "secrets", Namespace: "ns1", Name: "secret1"}
+	secret2 := workapiv1.ManifestResourceMeta{Version: "v1", Resource: "secrets", Namespace: "ns1", Name: "secret2"}
+	secret3 := workapiv1.ManifestResourceMeta{Version: "v1", Resource: "secrets", Namespace: "ns2", Name: "secret3"}
+	configMap := workapiv1.ManifestResourceMeta{Version: "v1", Resource: "configmaps", Namespace: "ns1", Name: "cm1"}
+
+	// the secrets in a namespace are watched in the namespace only
+	for _, resource := range []workapiv1.ManifestResourceMeta{secret1, secret2} {
+		if err := reader.RegisterInformer(ctx, "test-work", resource, queue); err != nil {
+			t.Fatal(err)
+		}
+	}
+	concreteReader.RLock()
+	if informer := concreteReader.informers[secretKey]; len(concreteReader.informers) != 1 ||
+		informer.namespace != "ns1" || len(informer.registrations) != 2 {
+		t.Errorf("Expected 1 informer in ns1 with 2 registrations, got %v", concreteReader.informers)
+	}
+	concreteReader.RUnlock()
+
+	// the secret in another namespace shares the informer of the secrets, which watches all namespaces
+	if err := reader.RegisterInformer(ctx, "test-work", secret3, queue); err != nil {
+		t.Fatal(err)
+	}
+	concreteReader.RLock()
+	if informer := concreteReader.informers[secretKey]; len(concreteReader.informers) != 1 ||
+		informer.namespace != metav1.NamespaceAll || len(informer.registrations) != 3 {
+		t.Errorf("Expected 1 informer in all namespaces with 3 registrations, got %v", concreteReader.informers)
+	}
+	concreteReader.RUnlock()
+
+	// the configmap is polled since the watch budget of the resource types is exhausted
+	if err := reader.RegisterInformer(ctx, "test-work", configMap, queue); err != nil {
+		t.Fatal(err)
+	}
+	concreteReader.RLock()
+	_, found := concreteReader.informers[configMapKey]
+	concreteReader.RUnlock()
+	if found {
+		t.Error("Expected the configmap not to be watched")
+	}
+
+	// the configmap is watched once the informer of the secrets is stopped
+	for _, resource := range []workapiv1.ManifestResourceMeta{secret1, secret2, secret3} {
+		if err := reader.UnRegisterInformer("test-work", resource); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := reader.RegisterInformer(ctx, "test-work", configMap, queue); err != nil {
+		t.Fatal(err)
+	}
+	concreteReader.RLock()
+	_, found = concreteReader.informers[configMapKey]
+	concreteReader.RUnlock()
+	if !found {
+		t.Error("Expected the configmap to be watched")
+	}
+}
+
+func TestGet_NamespaceNotWatched(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	secret := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "secret2", "namespace": "ns2"},
+	}}
+	fakeDynamicClient := fakedynamic.NewSimpleDynamicClient(scheme, secret)
+	fakeWorkClient := fakeworkclient.NewSimpleClientset()
+	workInformerFactory := workinformers.NewSharedInformerFactory(fakeWorkClient, 10*time.Minute)
+	workInformer := workInformerFactory.Work().V1().ManifestWorks()
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+
+	reader, err := NewOptions().NewObjectReader(fakeDynamicClient, workInformer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := t.Context()
+	secret1 := workapiv1.ManifestResourceMeta{Version: "v1", Resource: "secrets", Namespace: "ns1", Name: "secret1"}
+	if err := reader.RegisterInformer(ctx, "test-work", secret1, queue); err != nil {
+		t.Fatal(err)
+	}
+	informer := reader.(*objectReader).informers[informerKey{
+		GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}}]
+	if !cache.WaitForCacheSync(ctx.Done(), informer.informer.HasSynced) {
+		t.Fatal("failed to sync the informer")
+	}
+
+	// the secret in the namespace not watched by the informer is read from the apiserver
+	secret2 := workapiv1.ManifestResourceMeta{Version: "v1", Resource: "secrets", Namespace: "ns2", Name: "secret2"}
+	obj, _, err := reader.Get(ctx, secret2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetName() != "secret2" {
+		t.Errorf("Expected secret2, got %s", obj.GetName())
+	}
+}
+
+func TestQueueWorkByResource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	newWork := func(name string) *workapiv1.ManifestWork {
+		return &workapiv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cluster1"},
+			Status: workapiv1.ManifestWorkStatus{
+				ResourceStatus: workapiv1.ManifestResourceStatus{
+					Manifests: []workapiv1.ManifestCondition{
+						{ResourceMeta: workapiv1.ManifestResourceMeta{
+							Version: "v1", Resource: "secrets", Namespace: "ns1", Name: "secret1"}},
+					},
+				},
+			},
+		}
+	}
+
+	fakeDynamicClient := fakedynamic.NewSimpleDynamicClient(scheme)
+	fakeWorkClient := fakeworkclient.NewSimpleClientset()
+	workInformerFactory := workinformers.NewSharedInformerFactory(fakeWorkClient, 10*time.Minute)
+	workInformer := workInformerFactory.Work().V1().ManifestWorks()
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+
+	reader, err := NewOptions().NewObjectReader(fakeDynamicClient, workInformer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// both works have the secret, but only work1 watches it
+	for _, work := range []*workapiv1.ManifestWork{newWork("work1"), newWork("work2")} {
+		if err := workInformer.Informer().GetIndexer().Add(work); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := reader.RegisterInformer(t.Context(), "work1", newWork("work1").Status.ResourceStatus.Manifests[0].ResourceMeta, queue); err != nil {
+		t.Fatal(err)
+	}
+
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	secret := &unstructured.Unstructured{}
+	secret.SetNamespace("ns1")
+	secret.SetName("secret1")
+	reader.(*objectReader).queueWorkByResourceFunc(t.Context(), informerKey{GroupVersionResource: gvr}, queue)(secret)
+
+	if queue.Len() != 1 {
+		t.Fatalf("Expected 1 work to be enqueued, got %d", queue.Len())
+	}
+	if key, _ := queue.Get(); key != "work1" {
+		t.Errorf("Expected work1 to be enqueued, got %s", key)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	options := NewOptions()
+	if err := options.Validate(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	options.DefaultFeedbackScrapeType = "Unknown"
+	if err := options.Validate(); err == nil {
+		t.Error("Expected error for unknown feedback scrape type")
+	}
+}
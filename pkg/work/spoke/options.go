@@ -23,6 +23,18 @@ type WorkloadAgentOptions struct {
 	CloudEventsClientID                    string
 	CloudEventsClientCodecs                []string
 	DefaultUserAgent                       string
+	// WellKnownRulesConfigMap is the name of the ConfigMap in the agent namespace with the extra
+	// well-known status paths and condition rules.
+	WellKnownRulesConfigMap string
+	// LocalPolicyFile is the yaml file of the local policy denying the manifests to apply.
+	LocalPolicyFile string
+	// StatusJournalDir is the directory of the journal of the status updates which fail to be published
+	// by a cloudevents based agent. The journal is disabled if it is empty.
+	StatusJournalDir string
+	// StatusJournalMaxEntries is the max number of the ManifestWorks whose status is kept in the journal.
+	StatusJournalMaxEntries int
+	// PreDeleteHookTimeout is the max duration to wait for the pre-delete hooks of a deleting ManifestWork.
+	PreDeleteHookTimeout time.Duration
 
 	WorkloadAgentWorkers int
 
@@ -40,6 +52,9 @@ func NewWorkloadAgentOptions() *WorkloadAgentOptions {
 		DefaultUserAgent:                       defaultUserAgent,
 		ObjectReaderOption:                     objectreader.NewOptions(),
 		WorkloadAgentWorkers:                   10,
+		WellKnownRulesConfigMap:                "work-agent-well-known-rules",
+		StatusJournalMaxEntries:                1000,
+		PreDeleteHookTimeout:                   10 * time.Minute,
 	}
 }
 
@@ -60,6 +75,26 @@ func (o *WorkloadAgentOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringSliceVar(&o.CloudEventsClientCodecs, "cloudevents-client-codecs", o.CloudEventsClientCodecs,
 		"The codecs for cloudevents client when workload source source is based on cloudevents, the valid codecs: manifest or manifestbundle")
 
+	fs.StringVar(&o.WellKnownRulesConfigMap, "well-known-rules-configmap", o.WellKnownRulesConfigMap,
+		"The name of the ConfigMap in the agent namespace with the extra well-known status paths and condition rules "+
+			"per kind. The rules are reloaded once the ConfigMap changes. Set it to empty to use the default rules only.")
+	fs.StringVar(&o.LocalPolicyFile, "local-policy-file", o.LocalPolicyFile,
+		"The yaml file of the local policy, which denies the manifests by kinds, namespaces or CEL expressions "+
+			"before they are applied, regardless of the permission granted by the hub.")
+
+	fs.StringVar(&o.StatusJournalDir, "status-journal-dir", o.StatusJournalDir,
+		"The directory of the journal keeping the latest status of each ManifestWork which fails to be published "+
+			"to the hub, the journal is replayed once the hub is reachable. It only works with the cloudevents "+
+			"based workload source drivers, and it is disabled if it is empty. The journal survives the restarts "+
+			"of the agent only if the directory is on a persistent volume.")
+	fs.IntVar(&o.StatusJournalMaxEntries, "status-journal-max-entries", o.StatusJournalMaxEntries,
+		"The max number of the ManifestWorks whose status is kept in the status journal, the oldest status is "+
+			"dropped once the journal is full.")
+
+	fs.DurationVar(&o.PreDeleteHookTimeout, "pre-delete-hook-timeout", o.PreDeleteHookTimeout,
+		"The max duration to wait for the pre-delete hooks of a deleting ManifestWork since it is deleted. "+
+			"The applied resources are removed once the timeout expires even if the hooks are not complete.")
+
 	fs.IntVar(&o.WorkloadAgentWorkers, "workload-agent-workers",
 		o.WorkloadAgentWorkers, "The number of workers for the workload agent controllers")
 
@@ -71,5 +106,14 @@ func (o *WorkloadAgentOptions) Validate() error {
 	if o.WorkloadAgentWorkers < 1 {
 		return fmt.Errorf("workload-agent-workers must be >= 1, got %d", o.WorkloadAgentWorkers)
 	}
+	if len(o.StatusJournalDir) > 0 && o.StatusJournalMaxEntries < 1 {
+		return fmt.Errorf("status-journal-max-entries must be >= 1, got %d", o.StatusJournalMaxEntries)
+	}
+	if o.PreDeleteHookTimeout <= 0 {
+		return fmt.Errorf("pre-delete-hook-timeout must be positive, got %s", o.PreDeleteHookTimeout)
+	}
+	if err := o.ObjectReaderOption.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
@@ -7,7 +7,10 @@ import (
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/dynamic"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -19,6 +22,7 @@ import (
 	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
 	workv1informers "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
 	ocmfeature "open-cluster-management.io/api/feature"
+	workapiv1 "open-cluster-management.io/api/work/v1"
 	cloudeventsoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/options"
 	cloudeventswork "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/work"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/work/agent/codec"
@@ -29,10 +33,14 @@ import (
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/work/helper"
 	"open-cluster-management.io/ocm/pkg/work/spoke/auth"
+	"open-cluster-management.io/ocm/pkg/work/spoke/auth/localpolicy"
 	"open-cluster-management.io/ocm/pkg/work/spoke/conditions"
 	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/finalizercontroller"
 	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/manifestcontroller"
 	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/statuscontroller"
+	"open-cluster-management.io/ocm/pkg/work/spoke/statusfeedback"
+	"open-cluster-management.io/ocm/pkg/work/spoke/statusjournal"
+	"open-cluster-management.io/ocm/pkg/work/spoke/wellknownrules"
 )
 
 type WorkAgentConfig struct {
@@ -123,11 +131,27 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 		o.agentOptions.SpokeClusterName,
 		restMapper,
 	).NewExecutorValidator(ctx, features.SpokeMutableFeatureGate.Enabled(ocmfeature.ExecutorValidatingCaches))
+	if len(o.workOptions.LocalPolicyFile) > 0 {
+		policy, err := localpolicy.LoadPolicy(o.workOptions.LocalPolicyFile)
+		if err != nil {
+			return err
+		}
+		validator = localpolicy.NewValidator(policy, validator)
+	}
 
 	conditionReader, err := conditions.NewConditionReader()
 	if err != nil {
 		return err
 	}
+	statusReader := statusfeedback.NewStatusReader().WithMaxJsonRawLength(o.workOptions.MaxJSONRawLength)
+	wellKnownRules, err := o.newWellKnownRulesResolver(ctx, controllerContext.KubeConfig)
+	if err != nil {
+		return err
+	}
+	if wellKnownRules != nil {
+		conditionReader.WithWellKnownConditions(wellKnownRules)
+		statusReader.WithWellKnownStatus(wellKnownRules)
+	}
 
 	objectReader, err := o.workOptions.ObjectReaderOption.NewObjectReader(spokeDynamicClient, hubWorkInformer)
 	if err != nil {
@@ -147,6 +171,8 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 		hubHash, agentID,
 		restMapper,
 		validator,
+		conditionReader,
+		wellKnownRules,
 	)
 	addFinalizerController := finalizercontroller.NewAddFinalizerController(
 		hubWorkClient,
@@ -166,6 +192,11 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 		hubWorkInformer.Lister().ManifestWorks(o.agentOptions.SpokeClusterName),
 		spokeWorkClient.WorkV1().AppliedManifestWorks(),
 		spokeWorkInformerFactory.Work().V1().AppliedManifestWorks(),
+		spokeDynamicClient,
+		restMapper,
+		validator,
+		conditionReader,
+		o.workOptions.PreDeleteHookTimeout,
 		hubHash,
 	)
 	unmanagedAppliedManifestWorkController := finalizercontroller.NewUnManagedAppliedWorkController(
@@ -181,9 +212,11 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 		hubWorkInformer,
 		hubWorkInformer.Lister().ManifestWorks(o.agentOptions.SpokeClusterName),
 		conditionReader,
+		statusReader,
 		objectReader,
-		o.workOptions.MaxJSONRawLength,
 		o.workOptions.StatusSyncInterval,
+		workapiv1.FeedbackScrapeType(o.workOptions.ObjectReaderOption.DefaultFeedbackScrapeType),
+		wellKnownRules,
 	)
 
 	go spokeWorkInformerFactory.Start(ctx.Done())
@@ -201,6 +234,43 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 	return nil
 }
 
+// newWellKnownRulesResolver returns the resolver of the well-known rules in the ConfigMap of the agent
+// namespace if the ConfigMap is specified. The ConfigMap informer is started here, and the rules are
+// reloaded by its event handler once the ConfigMap changes.
+func (o *WorkAgentConfig) newWellKnownRulesResolver(
+	ctx context.Context, kubeConfig *rest.Config) (*wellknownrules.Resolver, error) {
+	name := o.workOptions.WellKnownRulesConfigMap
+	if len(name) == 0 || len(o.agentOptions.ComponentNamespace) == 0 {
+		return nil, nil
+	}
+
+	// the ConfigMap is in the agent namespace, which is on the cluster the agent is running
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeInformers := kubeinformers.NewSharedInformerFactoryWithOptions(
+		kubeClient, 10*time.Minute,
+		kubeinformers.WithNamespace(o.agentOptions.ComponentNamespace),
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}),
+	)
+	configMapInformer := kubeInformers.Core().V1().ConfigMaps()
+	resolver, err := wellknownrules.NewResolver(configMapInformer.Lister().ConfigMaps(o.agentOptions.ComponentNamespace), name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := configMapInformer.Informer().AddEventHandler(resolver.EventHandler()); err != nil {
+		return nil, err
+	}
+	go kubeInformers.Start(ctx.Done())
+
+	// the works are evaluated with the rules of the ConfigMap from the start
+	kubeInformers.WaitForCacheSync(ctx.Done())
+	return resolver, nil
+}
+
 func (o *WorkAgentConfig) newWorkClientAndInformer(
 	ctx context.Context,
 ) (string, workv1client.ManifestWorkInterface, workv1informers.ManifestWorkInformer, error) {
@@ -247,6 +317,18 @@ func (o *WorkAgentConfig) newWorkClientAndInformer(
 		hubHost = serverHost
 		workClient = clientHolder.WorkInterface()
 	}
+	manifestWorkClient := workClient.WorkV1().ManifestWorks(o.agentOptions.SpokeClusterName)
+
+	// the status updates failing to be published are kept in the journal for the cloudevents drivers
+	if o.workOptions.WorkloadSourceDriver != "kube" && len(o.workOptions.StatusJournalDir) > 0 {
+		journal, err := statusjournal.NewJournal(o.workOptions.StatusJournalDir, o.workOptions.StatusJournalMaxEntries)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		journalClient := statusjournal.NewClient(manifestWorkClient, journal)
+		go journalClient.Run(ctx, o.workOptions.StatusSyncInterval)
+		manifestWorkClient = journalClient
+	}
 
 	factory := workinformers.NewSharedInformerFactoryWithOptions(
 		workClient,
@@ -257,5 +339,5 @@ func (o *WorkAgentConfig) newWorkClientAndInformer(
 	)
 	informer := factory.Work().V1().ManifestWorks()
 
-	return hubHost, workClient.WorkV1().ManifestWorks(o.agentOptions.SpokeClusterName), informer, nil
+	return hubHost, manifestWorkClient, informer, nil
 }
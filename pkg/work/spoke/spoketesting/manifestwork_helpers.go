@@ -105,6 +105,20 @@ func NewFakeRestMapper() meta.RESTMapper {
 				},
 			},
 		},
+		{
+			Group: metav1.APIGroup{
+				Name: "batch",
+				Versions: []metav1.GroupVersionForDiscovery{
+					{Version: "v1", GroupVersion: "batch/v1"},
+				},
+				PreferredVersion: metav1.GroupVersionForDiscovery{Version: "v1", GroupVersion: "batch/v1"},
+			},
+			VersionedResources: map[string][]metav1.APIResource{
+				"v1": {
+					{Name: "jobs", Group: "batch", Namespaced: true, Kind: "Job"},
+				},
+			},
+		},
 	}
 	return restmapper.NewDiscoveryRESTMapper(resources)
 }
@@ -0,0 +1,215 @@
+package statusfeedback
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/utils/lru"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/spoke/conditions"
+)
+
+// The aggregations of a feedback path in the form of <aggregation>(<expression>), e.g.
+// count(.status.containerStatuses[?(@.ready==false)]) or cel(object.status.replicas - object.status.readyReplicas).
+// The expression of count, sum, min and max is a json path, and the expression of cel is a CEL expression
+// with the variable object.
+const (
+	aggregationCount = "count"
+	aggregationSum   = "sum"
+	aggregationMin   = "min"
+	aggregationMax   = "max"
+	aggregationCEL   = "cel"
+)
+
+var aggregations = []string{aggregationCount, aggregationSum, aggregationMin, aggregationMax, aggregationCEL}
+
+// maxCachedPrograms is the max number of the compiled programs of the cel aggregations kept in the cache.
+const maxCachedPrograms = 1024
+
+var (
+	// celEnv builds the CEL environment of the cel aggregation once it is used.
+	celEnv = sync.OnceValues(func() (*cel.Env, error) {
+		return conditions.NewCELEnv(cel.Variable("object", cel.DynType))
+	})
+
+	// celPrograms caches the compiled programs of the cel aggregations by the expression, since the
+	// feedback rules are evaluated on every status sync of the manifests.
+	celPrograms = lru.New(maxCachedPrograms)
+)
+
+// ValidatePath validates the json path or the aggregation of a feedback path.
+func ValidatePath(path string) error {
+	aggregation, expression, ok := parseAggregation(path)
+	if !ok {
+		expression = path
+	}
+	if aggregation == aggregationCEL {
+		_, err := compileCEL(expression)
+		return err
+	}
+	return jsonpath.New("path").Parse(fmt.Sprintf("{%s}", expression))
+}
+
+// parseAggregation returns the aggregation and the expression of the path, or false if the path is a
+// plain json path.
+func parseAggregation(path string) (string, string, bool) {
+	path = strings.TrimSpace(path)
+	for _, aggregation := range aggregations {
+		if strings.HasPrefix(path, aggregation+"(") && strings.HasSuffix(path, ")") {
+			return aggregation, strings.TrimSpace(path[len(aggregation)+1 : len(path)-1]), true
+		}
+	}
+	return "", "", false
+}
+
+func (s *StatusReader) getAggregatedValue(
+	name, aggregation, expression string, obj *unstructured.Unstructured) (*workapiv1.FeedbackValue, error) {
+	if aggregation == aggregationCEL {
+		value, err := s.evaluateCEL(name, expression, obj)
+		if err != nil {
+			return nil, err
+		}
+		return s.toFeedbackValue(name, value)
+	}
+
+	results, err := findResults(name, expression, obj)
+	if err != nil {
+		return nil, err
+	}
+	// a list found by the path is aggregated by its items
+	var items []any
+	for _, result := range results {
+		if list, ok := result.([]any); ok {
+			items = append(items, list...)
+			continue
+		}
+		items = append(items, result)
+	}
+
+	if aggregation == aggregationCount {
+		return s.toFeedbackValue(name, int64(len(items)))
+	}
+
+	var value *int64
+	for _, item := range items {
+		number, err := toInteger(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to %s values for %s: %v", aggregation, name, err)
+		}
+		switch {
+		case value == nil:
+			value = &number
+		case aggregation == aggregationSum:
+			*value += number
+		case aggregation == aggregationMin:
+			*value = min(*value, number)
+		case aggregation == aggregationMax:
+			*value = max(*value, number)
+		}
+	}
+	if value == nil {
+		// the sum of no values is 0, while there is no min or max of no values
+		if aggregation != aggregationSum {
+			return nil, nil
+		}
+		value = new(int64)
+	}
+	return s.toFeedbackValue(name, *value)
+}
+
+// evaluateCEL evaluates the CEL expression with the object, and returns the result in the native type.
+func (s *StatusReader) evaluateCEL(name, expression string, obj *unstructured.Unstructured) (any, error) {
+	prg, err := compileCEL(expression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile cel expression of %s: %v", name, err)
+	}
+
+	out, _, err := conditions.EvaluateCEL(context.TODO(), prg, celconfig.RuntimeCELCostBudget, expression, map[string]any{
+		"object": obj.Object,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate cel expression of %s: %v", name, err)
+	}
+	return celToNative(out)
+}
+
+// compileCEL returns the program of the CEL expression from the cache, or compiles the expression and
+// caches the program.
+func compileCEL(expression string) (cel.Program, error) {
+	if prg, ok := celPrograms.Get(expression); ok {
+		return prg.(cel.Program), nil
+	}
+
+	env, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	prg, err := env.Program(
+		ast,
+		cel.CostLimit(celconfig.PerCallLimit),
+		cel.CostTracking(conditions.NewCostEstimator()),
+		cel.InterruptCheckFrequency(celconfig.CheckFrequency),
+	)
+	if err != nil {
+		return nil, err
+	}
+	celPrograms.Add(expression, prg)
+	return prg, nil
+}
+
+func celToNative(value ref.Val) (any, error) {
+	switch value.Type() {
+	case types.NullType:
+		return nil, nil
+	case types.UintType:
+		return toInteger(value.Value())
+	case types.DoubleType:
+		return toInteger(value.Value())
+	case types.ListType:
+		return value.ConvertToNative(reflect.TypeOf([]any{}))
+	case types.MapType:
+		return value.ConvertToNative(reflect.TypeOf(map[string]any{}))
+	default:
+		return value.Value(), nil
+	}
+}
+
+// toInteger converts the number to int64. A float is converted only if it is an integer.
+func toInteger(value any) (int64, error) {
+	switch n := value.(type) {
+	case int64:
+		return n, nil
+	case int32:
+		return int64(n), nil
+	case int:
+		return int64(n), nil
+	case uint64:
+		if n > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", n)
+		}
+		return int64(n), nil
+	case float64:
+		if n != math.Trunc(n) || n > math.MaxInt64 || n < math.MinInt64 {
+			return 0, fmt.Errorf("value %v is not an integer", n)
+		}
+		return int64(n), nil
+	default:
+		return 0, fmt.Errorf("value %v of type %v is not an integer", value, reflect.TypeOf(value))
+	}
+}
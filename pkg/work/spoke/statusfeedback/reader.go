@@ -36,6 +36,12 @@ func (s *StatusReader) WithMaxJsonRawLength(length int32) *StatusReader {
 	return s
 }
 
+// WithWellKnownStatus sets the resolver of the paths of the WellKnownStatus feedback rule.
+func (s *StatusReader) WithWellKnownStatus(wellKnownStatus rules.WellKnownStatusRuleResolver) *StatusReader {
+	s.wellKnownStatus = wellKnownStatus
+	return s
+}
+
 func (s *StatusReader) GetValuesByRule(obj *unstructured.Unstructured, rule workapiv1.FeedbackRule) ([]workapiv1.FeedbackValue, error) {
 	var errs []error
 	var values []workapiv1.FeedbackValue
@@ -82,6 +88,34 @@ func (s *StatusReader) GetValuesByRule(obj *unstructured.Unstructured, rule work
 }
 
 func (s *StatusReader) getValueByJsonPath(name, path string, obj *unstructured.Unstructured) (*workapiv1.FeedbackValue, error) {
+	if aggregation, expression, ok := parseAggregation(path); ok {
+		return s.getAggregatedValue(name, aggregation, expression, obj)
+	}
+
+	results, err := findResults(name, path, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		// no results are found here.
+		return nil, nil
+	}
+
+	var value any
+	// if the RawFeedbackJsonString is disabled, we always get the first item
+	if len(results) == 1 || !features.SpokeMutableFeatureGate.Enabled(ocmfeature.RawFeedbackJsonString) {
+		value = results[0]
+	} else {
+		value = results
+	}
+
+	return s.toFeedbackValue(name, value)
+}
+
+// findResults returns the values found by the json path in the object. Only the first item in the
+// results list of the json path is taken care.
+func findResults(name, path string, obj *unstructured.Unstructured) ([]any, error) {
 	j := jsonpath.New(name).AllowMissingKeys(true)
 	err := j.Parse(fmt.Sprintf("{%s}", path))
 	if err != nil {
@@ -94,25 +128,18 @@ func (s *StatusReader) getValueByJsonPath(name, path string, obj *unstructured.U
 		return nil, fmt.Errorf("failed to find value for %s with error: %v", name, err)
 	}
 
-	if len(results) == 0 || len(results[0]) == 0 {
-		// no results are found here.
+	if len(results) == 0 {
 		return nil, nil
 	}
 
-	var value any
-	// if the RawFeedbackJsonString is disabled, we always get the first item
-	if (len(results) == 1 && len(results[0]) == 1) ||
-		!features.SpokeMutableFeatureGate.Enabled(ocmfeature.RawFeedbackJsonString) {
-		value = results[0][0].Interface()
-	} else {
-		var resultList []any
-		// only take care the first item in the results list.
-		for _, r := range results[0] {
-			resultList = append(resultList, r.Interface())
-		}
-		value = resultList
+	var values []any
+	for _, r := range results[0] {
+		values = append(values, r.Interface())
 	}
+	return values, nil
+}
 
+func (s *StatusReader) toFeedbackValue(name string, value any) (*workapiv1.FeedbackValue, error) {
 	if value == nil {
 		// ignore the result if it is nil
 		return nil, nil
@@ -121,6 +121,23 @@ const (
 			"phase": "Succeeded"
 		}
 	}
+`
+	podJsonMultiContainer = `
+	{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {
+			"name": "test"
+		},
+		"status": {
+			"containerStatuses": [
+				{"name": "app", "ready": true, "restartCount": 3},
+				{"name": "sidecar", "ready": false, "restartCount": 1},
+				{"name": "proxy", "ready": false, "restartCount": 5}
+			],
+			"phase": "Running"
+		}
+	}
 `
 )
 
@@ -403,3 +420,167 @@ func TestStatusReader(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregatedValues(t *testing.T) {
+	cases := []struct {
+		name          string
+		enableRaw     bool
+		path          string
+		expectError   bool
+		expectedValue []workapiv1.FeedbackValue
+	}{
+		{
+			name:          "count of filtered items",
+			path:          `count(.status.containerStatuses[?(@.ready==false)])`,
+			expectedValue: []workapiv1.FeedbackValue{integerValue(2)},
+		},
+		{
+			name:          "count of list",
+			path:          `count(.status.containerStatuses)`,
+			expectedValue: []workapiv1.FeedbackValue{integerValue(3)},
+		},
+		{
+			name:          "count of missing field",
+			path:          `count(.status.initContainerStatuses)`,
+			expectedValue: []workapiv1.FeedbackValue{integerValue(0)},
+		},
+		{
+			name:          "sum",
+			path:          `sum(.status.containerStatuses[*].restartCount)`,
+			expectedValue: []workapiv1.FeedbackValue{integerValue(9)},
+		},
+		{
+			name:          "sum of missing field",
+			path:          `sum(.status.initContainerStatuses[*].restartCount)`,
+			expectedValue: []workapiv1.FeedbackValue{integerValue(0)},
+		},
+		{
+			name:          "min",
+			path:          `min(.status.containerStatuses[*].restartCount)`,
+			expectedValue: []workapiv1.FeedbackValue{integerValue(1)},
+		},
+		{
+			name:          "max",
+			path:          `max(.status.containerStatuses[*].restartCount)`,
+			expectedValue: []workapiv1.FeedbackValue{integerValue(5)},
+		},
+		{
+			name: "max of missing field",
+			path: `max(.status.initContainerStatuses[*].restartCount)`,
+		},
+		{
+			name:        "sum of strings",
+			path:        `sum(.status.containerStatuses[*].name)`,
+			expectError: true,
+		},
+		{
+			name:          "cel integer",
+			path:          `cel(object.status.containerStatuses.filter(c, !c.ready).size())`,
+			expectedValue: []workapiv1.FeedbackValue{integerValue(2)},
+		},
+		{
+			name: "cel string",
+			path: `cel(object.status.containerStatuses.filter(c, !c.ready).map(c, c.name).join(","))`,
+			expectedValue: []workapiv1.FeedbackValue{
+				{
+					Name:  "value",
+					Value: workapiv1.FieldValue{Type: workapiv1.String, String: pointer.String("sidecar,proxy")},
+				},
+			},
+		},
+		{
+			name: "cel bool",
+			path: `cel(object.status.phase == "Running")`,
+			expectedValue: []workapiv1.FeedbackValue{
+				{
+					Name:  "value",
+					Value: workapiv1.FieldValue{Type: workapiv1.Boolean, Boolean: pointer.Bool(true)},
+				},
+			},
+		},
+		{
+			name:      "cel list",
+			enableRaw: true,
+			path:      `cel(object.status.containerStatuses.map(c, c.name))`,
+			expectedValue: []workapiv1.FeedbackValue{
+				{
+					Name:  "value",
+					Value: workapiv1.FieldValue{Type: workapiv1.JsonRaw, JsonRaw: pointer.String(`["app","sidecar","proxy"]`)},
+				},
+			},
+		},
+		{
+			name:        "cel list without raw json",
+			path:        `cel(object.status.containerStatuses.map(c, c.name))`,
+			expectError: true,
+		},
+		{
+			name:        "invalid cel",
+			path:        `cel(object.status.phase ==)`,
+			expectError: true,
+		},
+		{
+			name:        "cel missing field",
+			path:        `cel(object.status.hostIP)`,
+			expectError: true,
+		},
+	}
+
+	reader := NewStatusReader()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := features.SpokeMutableFeatureGate.Set(fmt.Sprintf("%s=%t", ocmfeature.RawFeedbackJsonString, c.enableRaw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			values, err := reader.GetValuesByRule(unstrctureObject(podJsonMultiContainer), workapiv1.FeedbackRule{
+				Type:      workapiv1.JSONPathsType,
+				JsonPaths: []workapiv1.JsonPath{{Name: "value", Path: c.path}},
+			})
+			if err == nil && c.expectError {
+				t.Errorf("Expect error but got no error")
+			}
+			if err != nil && !c.expectError {
+				t.Errorf("Expect no error but got %v", err)
+			}
+			if !apiequality.Semantic.DeepEqual(c.expectedValue, values) {
+				t.Errorf("Expect value %v, but got %v", c.expectedValue, values)
+			}
+		})
+	}
+}
+
+func integerValue(value int64) workapiv1.FeedbackValue {
+	return workapiv1.FeedbackValue{
+		Name:  "value",
+		Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: pointer.Int64(value)},
+	}
+}
+
+func TestCELProgramCache(t *testing.T) {
+	expression := `object.status.containerStatuses.size()`
+	celPrograms.Clear()
+
+	prg, err := compileCEL(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if celPrograms.Len() != 1 {
+		t.Errorf("expect the program cached, but got %d programs", celPrograms.Len())
+	}
+	cached, err := compileCEL(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached != prg {
+		t.Errorf("expect the cached program returned")
+	}
+
+	// the expression failing to compile is not cached
+	if _, err := compileCEL(`object.status.phase ==`); err == nil {
+		t.Errorf("expect error, but got nil")
+	}
+	if celPrograms.Len() != 1 {
+		t.Errorf("expect only the valid program cached, but got %d programs", celPrograms.Len())
+	}
+}
@@ -0,0 +1,145 @@
+package statusjournal
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	cloudeventserrors "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/errors"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/utils"
+)
+
+var _ workv1client.ManifestWorkInterface = &Client{}
+
+// Client wraps the ManifestWork client of a cloudevents based agent. The status of a ManifestWork which
+// fails to be published to the hub is recorded in the journal, and the recorded status is replayed once
+// the hub is reachable again, so the hub view converges quickly after the outage without waiting for the
+// backoff of the controllers.
+type Client struct {
+	workv1client.ManifestWorkInterface
+	journal *Journal
+	// mu serializes the status patches and the journal updates, so an outdated status is not recorded
+	// after a newer status is published.
+	mu sync.Mutex
+}
+
+func NewClient(workClient workv1client.ManifestWorkInterface, journal *Journal) *Client {
+	return &Client{
+		ManifestWorkInterface: workClient,
+		journal:               journal,
+	}
+}
+
+func (c *Client) Patch(ctx context.Context, name string, pt types.PatchType, data []byte,
+	opts metav1.PatchOptions, subresources ...string) (*workapiv1.ManifestWork, error) {
+	if !utils.IsStatusPatch(subresources) {
+		return c.ManifestWorkInterface.Patch(ctx, name, pt, data, opts, subresources...)
+	}
+
+	logger := klog.FromContext(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, err := c.ManifestWorkInterface.Patch(ctx, name, pt, data, opts, subresources...)
+	switch {
+	case err == nil:
+		// the recorded status is outdated once a newer status is published
+		if err := c.journal.Remove(name, 0); err != nil {
+			logger.Error(err, "Failed to remove the status from the journal", "manifestWorkName", name)
+		}
+	case cloudeventserrors.IsPublishError(err):
+		if err := c.record(ctx, name, pt, data); err != nil {
+			logger.Error(err, "Failed to record the status in the journal", "manifestWorkName", name)
+		}
+	}
+	return result, err
+}
+
+// record applies the status patch on the ManifestWork in the store, and records the patched status.
+func (c *Client) record(ctx context.Context, name string, pt types.PatchType, data []byte) error {
+	work, err := c.ManifestWorkInterface.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	patchedWork, err := utils.Patch(pt, work, data)
+	if err != nil {
+		return err
+	}
+	klog.FromContext(ctx).V(2).Info("Record the status in the journal since it fails to be published",
+		"manifestWorkName", name, "resourceVersion", work.ResourceVersion)
+	return c.journal.Record(name, work.ResourceVersion, patchedWork.Status)
+}
+
+// Run replays the journal at the interval until the context is done, and then closes the journal.
+func (c *Client) Run(ctx context.Context, interval time.Duration) {
+	wait.UntilWithContext(ctx, c.replay, interval)
+	if err := c.journal.Close(); err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to close the status journal")
+	}
+}
+
+// replay publishes the recorded status in the order they are recorded. It stops at the first status which
+// still fails to be published, and the remaining status is replayed in the next round. The status of a
+// ManifestWork which is updated by the hub or fails to be patched is dropped, since the controllers will
+// publish the status of the current version.
+func (c *Client) replay(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	for _, entry := range c.journal.Entries() {
+		err := c.replayEntry(ctx, entry)
+		switch {
+		case cloudeventserrors.IsPublishError(err):
+			logger.V(4).Info("The hub is still not reachable, replay the status journal later", "error", err)
+			return
+		case errors.IsNotFound(err):
+			// the ManifestWorks are not in the store until they are resynced from the hub after the agent
+			// restarts, so the status is kept. The status of a ManifestWork deleted during the outage is
+			// dropped once the journal is full.
+			continue
+		case err != nil:
+			logger.Error(err, "Failed to replay the status, drop it", "manifestWorkName", entry.Name)
+		}
+		if err := c.journal.Remove(entry.Name, entry.Sequence); err != nil {
+			logger.Error(err, "Failed to remove the status from the journal", "manifestWorkName", entry.Name)
+		}
+	}
+}
+
+func (c *Client) replayEntry(ctx context.Context, entry Entry) error {
+	logger := klog.FromContext(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	work, err := c.ManifestWorkInterface.Get(ctx, entry.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	// the ManifestWork is updated by the hub, the controllers will publish the status of the new version
+	if work.ResourceVersion != entry.ResourceVersion {
+		logger.V(2).Info("Drop the outdated status in the journal", "manifestWorkName", entry.Name,
+			"resourceVersion", entry.ResourceVersion, "currentResourceVersion", work.ResourceVersion)
+		return nil
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"resourceVersion": entry.ResourceVersion},
+		"status":   entry.Status,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := c.ManifestWorkInterface.Patch(
+		ctx, entry.Name, types.MergePatchType, data, metav1.PatchOptions{}, "status"); err != nil {
+		return err
+	}
+	logger.V(2).Info("Replayed the status in the journal", "manifestWorkName", entry.Name)
+	return nil
+}
@@ -0,0 +1,127 @@
+package statusjournal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/common"
+	cloudeventserrors "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/errors"
+)
+
+// offlineClient fails to publish the status updates when it is offline.
+type offlineClient struct {
+	workv1client.ManifestWorkInterface
+	offline bool
+}
+
+func (c *offlineClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte,
+	opts metav1.PatchOptions, subresources ...string) (*workapiv1.ManifestWork, error) {
+	if c.offline {
+		return nil, cloudeventserrors.NewPublishError(common.ManifestWorkGR, name, fmt.Errorf("disconnected"))
+	}
+	return c.ManifestWorkInterface.Patch(ctx, name, pt, data, opts, subresources...)
+}
+
+func newWork(name, resourceVersion string) *workapiv1.ManifestWork {
+	return &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cluster1", ResourceVersion: resourceVersion},
+	}
+}
+
+func patchStatus(t *testing.T, client workv1client.ManifestWorkInterface, name, reason string) error {
+	data, err := json.Marshal(map[string]any{"status": newStatus(reason)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Patch(context.TODO(), name, types.MergePatchType, data, metav1.PatchOptions{}, "status")
+	return err
+}
+
+func assertStatusReason(t *testing.T, client workv1client.ManifestWorkInterface, name, expected string) {
+	work, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reason := ""
+	if len(work.Status.Conditions) > 0 {
+		reason = work.Status.Conditions[0].Reason
+	}
+	if reason != expected {
+		t.Errorf("expected status reason %q of %s, but got %q", expected, name, reason)
+	}
+}
+
+func TestClient(t *testing.T) {
+	fakeClient := fakeworkclient.NewSimpleClientset(newWork("work1", "1"), newWork("work2", "1"))
+	workClient := &offlineClient{ManifestWorkInterface: fakeClient.WorkV1().ManifestWorks("cluster1"), offline: true}
+	journal, err := NewJournal(t.TempDir(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(workClient, journal)
+
+	// the status failing to be published is recorded and compacted
+	for _, patch := range []struct{ name, reason string }{
+		{"work1", "first"}, {"work2", "first"}, {"work1", "second"},
+	} {
+		if err := patchStatus(t, client, patch.name, patch.reason); !cloudeventserrors.IsPublishError(err) {
+			t.Fatalf("expected publish error, but got %v", err)
+		}
+	}
+	if names := entryNames(journal.Entries()); len(names) != 2 || names[0] != "work2" || names[1] != "work1" {
+		t.Fatalf("expected entries [work2 work1], but got %v", names)
+	}
+
+	// the journal is kept when the hub is still not reachable
+	client.replay(context.TODO())
+	if journal.Len() != 2 {
+		t.Fatalf("expected 2 entries, but got %d", journal.Len())
+	}
+
+	// the work is updated by the hub during the outage, the status of work2 is outdated
+	work2 := newWork("work2", "2")
+	if _, err := fakeClient.WorkV1().ManifestWorks("cluster1").Update(context.TODO(), work2, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	workClient.offline = false
+	client.replay(context.TODO())
+	if journal.Len() != 0 {
+		t.Errorf("expected the journal is replayed, but got %v", entryNames(journal.Entries()))
+	}
+	assertStatusReason(t, workClient, "work1", "second")
+	assertStatusReason(t, workClient, "work2", "")
+
+	// the status of a work not in the store is kept
+	workClient.offline = true
+	if err := journal.Record("work3", "1", newStatus("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := patchStatus(t, client, "work1", "third"); !cloudeventserrors.IsPublishError(err) {
+		t.Fatalf("expected publish error, but got %v", err)
+	}
+	workClient.offline = false
+	client.replay(context.TODO())
+	if names := entryNames(journal.Entries()); len(names) != 1 || names[0] != "work3" {
+		t.Errorf("expected entries [work3], but got %v", names)
+	}
+	assertStatusReason(t, workClient, "work1", "third")
+
+	// the recorded status is removed once a newer status is published
+	if err := journal.Record("work1", "1", newStatus("fourth")); err != nil {
+		t.Fatal(err)
+	}
+	if err := patchStatus(t, client, "work1", "fifth"); err != nil {
+		t.Fatal(err)
+	}
+	if names := entryNames(journal.Entries()); len(names) != 1 || names[0] != "work3" {
+		t.Errorf("expected entries [work3], but got %v", names)
+	}
+}
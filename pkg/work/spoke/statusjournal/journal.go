@@ -0,0 +1,275 @@
+package statusjournal
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// journalFileName is the name of the journal file in the journal directory.
+const journalFileName = "status-journal.log"
+
+// minCompactRecords is the min number of the obsolete records in the journal file before it is compacted.
+const minCompactRecords = 100
+
+// Entry is the latest status of a ManifestWork which is not published to the hub yet.
+type Entry struct {
+	// Name is the name of the ManifestWork.
+	Name string `json:"name"`
+	// ResourceVersion is the resource version of the ManifestWork when the status is recorded. The status
+	// is dropped if the ManifestWork is updated by the hub before it is replayed.
+	ResourceVersion string `json:"resourceVersion"`
+	// Sequence is the order of the entry in the journal, the entries are replayed by this order.
+	Sequence int64                        `json:"sequence"`
+	Status   workapiv1.ManifestWorkStatus `json:"status"`
+}
+
+// record is a line of the journal file, which either records an entry or removes the entry of a
+// ManifestWork.
+type record struct {
+	Entry   *Entry `json:"entry,omitempty"`
+	Removed string `json:"removed,omitempty"`
+}
+
+// Journal is a bounded journal of the pending status updates, which is persisted in a file. It is
+// compacted per ManifestWork, so only the latest status of a ManifestWork is kept.
+//
+// Each update of the journal is appended to the journal file as a record, and the file is rewritten with
+// the current entries only when it is loaded or the obsolete records outnumber the entries, so the cost of
+// an update does not grow with the size of the journal.
+type Journal struct {
+	path       string
+	maxEntries int
+
+	mu       sync.Mutex
+	entries  map[string]Entry
+	sequence int64
+	file     *os.File
+	// records is the number of the records in the journal file
+	records int
+}
+
+// NewJournal loads the journal from the journal file in the dir. The oldest entries are dropped once the
+// number of the entries exceeds the maxEntries.
+func NewJournal(dir string, maxEntries int) (*Journal, error) {
+	if maxEntries < 1 {
+		return nil, fmt.Errorf("the max entries of the status journal must be >= 1, got %d", maxEntries)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	j := &Journal{
+		path:       filepath.Join(dir, journalFileName),
+		maxEntries: maxEntries,
+		entries:    map[string]Entry{},
+	}
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	// the loaded journal file is compacted, which also drops the record truncated by a crash.
+	if err := j.compact(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// load replays the records of the journal file. The last record is ignored if it is truncated, since the
+// agent may be stopped while appending it.
+func (j *Journal) load() error {
+	f, err := os.Open(j.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	}
+	defer f.Close()
+
+	decoder := json.NewDecoder(f)
+	for {
+		r := record{}
+		err := decoder.Decode(&r)
+		switch {
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			j.trim()
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to load status journal %s: %w", j.path, err)
+		}
+
+		if r.Entry != nil {
+			j.entries[r.Entry.Name] = *r.Entry
+			j.sequence = max(j.sequence, r.Entry.Sequence)
+		}
+		if len(r.Removed) > 0 {
+			delete(j.entries, r.Removed)
+		}
+	}
+}
+
+// Record records the status of a ManifestWork in the journal, it replaces the previous status of the
+// ManifestWork.
+func (j *Journal) Record(name, resourceVersion string, status workapiv1.ManifestWorkStatus) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.sequence++
+	entry := Entry{
+		Name:            name,
+		ResourceVersion: resourceVersion,
+		Sequence:        j.sequence,
+		Status:          status,
+	}
+	j.entries[name] = entry
+	records := []record{{Entry: &entry}}
+
+	// drop the oldest entries once the journal is full
+	for _, dropped := range j.trim() {
+		records = append(records, record{Removed: dropped})
+	}
+	return j.append(records...)
+}
+
+// Remove removes the status of a ManifestWork from the journal. If the sequence is not 0, the status is
+// removed only when it is not recorded again after the sequence.
+func (j *Journal) Remove(name string, sequence int64) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry, ok := j.entries[name]
+	if !ok || (sequence != 0 && entry.Sequence != sequence) {
+		return nil
+	}
+	delete(j.entries, name)
+	return j.append(record{Removed: name})
+}
+
+// Entries returns the entries of the journal in the order they are recorded.
+func (j *Journal) Entries() []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.sortedEntries()
+}
+
+// Len returns the number of the entries in the journal.
+func (j *Journal) Len() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.entries)
+}
+
+// Close closes the journal file. The journal cannot be updated after it is closed.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+func (j *Journal) sortedEntries() []Entry {
+	entries := make([]Entry, 0, len(j.entries))
+	for _, entry := range j.entries {
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+	return entries
+}
+
+// trim drops the oldest entries which exceed the max entries, and returns their names.
+func (j *Journal) trim() []string {
+	overflow := len(j.entries) - j.maxEntries
+	if overflow <= 0 {
+		return nil
+	}
+	var dropped []string
+	for _, entry := range j.sortedEntries()[:overflow] {
+		delete(j.entries, entry.Name)
+		dropped = append(dropped, entry.Name)
+	}
+	return dropped
+}
+
+// append appends the records to the journal file with a single write, and compacts the journal file once
+// the obsolete records outnumber the entries.
+func (j *Journal) append(records ...record) error {
+	if j.file == nil {
+		return fmt.Errorf("status journal %s is closed", j.path)
+	}
+	data, err := encodeRecords(records)
+	if err != nil {
+		return err
+	}
+	if _, err := j.file.Write(data); err != nil {
+		return err
+	}
+	j.records += len(records)
+
+	if j.records-len(j.entries) > max(len(j.entries), minCompactRecords) {
+		return j.compact()
+	}
+	return nil
+}
+
+// compact writes the current entries to a temporary file and renames it to the journal file, so the
+// journal file is not corrupted if the agent is stopped while writing. The journal file is reopened to
+// append the following records.
+func (j *Journal) compact() error {
+	entries := j.sortedEntries()
+	records := make([]record, 0, len(entries))
+	for i := range entries {
+		records = append(records, record{Entry: &entries[i]})
+	}
+	data, err := encodeRecords(records)
+	if err != nil {
+		return err
+	}
+	tmpFile := j.path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFile, j.path); err != nil {
+		return err
+	}
+
+	if j.file != nil {
+		if err := j.file.Close(); err != nil {
+			return err
+		}
+		j.file = nil
+	}
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	j.file = f
+	j.records = len(records)
+	return nil
+}
+
+func encodeRecords(records []record) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	for _, r := range records {
+		if err := encoder.Encode(&r); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
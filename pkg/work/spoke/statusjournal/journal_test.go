@@ -0,0 +1,163 @@
+package statusjournal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newStatus(reason string) workapiv1.ManifestWorkStatus {
+	return workapiv1.ManifestWorkStatus{
+		Conditions: []metav1.Condition{
+			{Type: workapiv1.WorkApplied, Status: metav1.ConditionTrue, Reason: reason},
+		},
+	}
+}
+
+func entryNames(entries []Entry) []string {
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+func TestJournal(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := NewJournal(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the status of a work is compacted
+	for _, record := range []struct{ name, reason string }{
+		{"work1", "first"}, {"work2", "first"}, {"work1", "second"},
+	} {
+		if err := journal.Record(record.name, "1", newStatus(record.reason)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries := journal.Entries()
+	if names := entryNames(entries); len(names) != 2 || names[0] != "work2" || names[1] != "work1" {
+		t.Fatalf("expected entries [work2 work1], but got %v", names)
+	}
+	if reason := entries[1].Status.Conditions[0].Reason; reason != "second" {
+		t.Errorf("expected the latest status of work1, but got %s", reason)
+	}
+
+	// the oldest entry is dropped once the journal is full
+	if err := journal.Record("work3", "1", newStatus("first")); err != nil {
+		t.Fatal(err)
+	}
+	if names := entryNames(journal.Entries()); len(names) != 2 || names[0] != "work1" || names[1] != "work3" {
+		t.Fatalf("expected entries [work1 work3], but got %v", names)
+	}
+
+	// the entry is not removed by an outdated sequence
+	if err := journal.Remove("work1", entries[0].Sequence); err != nil {
+		t.Fatal(err)
+	}
+	if journal.Len() != 2 {
+		t.Errorf("expected 2 entries, but got %d", journal.Len())
+	}
+	if err := journal.Remove("work1", entries[1].Sequence); err != nil {
+		t.Fatal(err)
+	}
+
+	// the journal is loaded from the file
+	reloaded, err := NewJournal(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := entryNames(reloaded.Entries()); len(names) != 1 || names[0] != "work3" {
+		t.Fatalf("expected entries [work3], but got %v", names)
+	}
+	if err := reloaded.Record("work4", "1", newStatus("first")); err != nil {
+		t.Fatal(err)
+	}
+	if names := entryNames(reloaded.Entries()); len(names) != 2 || names[0] != "work3" || names[1] != "work4" {
+		t.Fatalf("expected entries [work3 work4], but got %v", names)
+	}
+}
+
+func TestNewJournal(t *testing.T) {
+	if _, err := NewJournal(t.TempDir(), 0); err == nil {
+		t.Errorf("expected error for 0 max entries")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, journalFileName), []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJournal(dir, 1); err == nil {
+		t.Errorf("expected error for invalid journal file")
+	}
+}
+
+func TestJournalFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, journalFileName)
+	journal, err := NewJournal(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the updates are appended to the journal file
+	if err := journal.Record("work1", "1", newStatus("first")); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Record("work2", "1", newStatus("first")); err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(after, before) || bytes.Count(after, []byte("\n")) != 2 {
+		t.Errorf("expected the record to be appended, but got %s", string(after))
+	}
+
+	// the journal file is compacted once the obsolete records outnumber the entries
+	for i := 0; i < minCompactRecords+1; i++ {
+		if err := journal.Record("work1", "1", newStatus("first")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if journal.records != 2 {
+		t.Errorf("expected the journal file compacted to 2 records, but got %d", journal.records)
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Record("work3", "1", newStatus("first")); err == nil {
+		t.Errorf("expected error to record in the closed journal")
+	}
+
+	// the record truncated by a crash is dropped
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"removed":"work`); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := NewJournal(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := entryNames(reloaded.Entries()); len(names) != 2 || names[0] != "work2" || names[1] != "work1" {
+		t.Fatalf("expected entries [work2 work1], but got %v", names)
+	}
+}
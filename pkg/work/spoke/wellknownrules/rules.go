@@ -0,0 +1,269 @@
+package wellknownrules
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/spoke/conditions"
+	conditionrules "open-cluster-management.io/ocm/pkg/work/spoke/conditions/rules"
+	"open-cluster-management.io/ocm/pkg/work/spoke/statusfeedback"
+	statusrules "open-cluster-management.io/ocm/pkg/work/spoke/statusfeedback/rules"
+)
+
+// Rule is the well-known status paths and condition rules of a kind. Each key of the ConfigMap is a yaml
+// list of the rules, e.g.
+//
+//	statefulset.yaml: |
+//	  - group: apps
+//	    version: v1
+//	    kind: StatefulSet
+//	    statusPaths:
+//	    - name: ReadyReplicas
+//	      path: .status.readyReplicas
+//	    conditionRules:
+//	    - condition: Ready
+//	      celExpressions:
+//	      - object.status.readyReplicas == object.spec.replicas
+//
+// The Ready condition rule of a kind gates the next apply wave of the ManifestWorks.
+type Rule struct {
+	Group          string                    `json:"group,omitempty"`
+	Version        string                    `json:"version"`
+	Kind           string                    `json:"kind"`
+	StatusPaths    []workapiv1.JsonPath      `json:"statusPaths,omitempty"`
+	ConditionRules []workapiv1.ConditionRule `json:"conditionRules,omitempty"`
+}
+
+type parsedRules struct {
+	status     map[schema.GroupVersionKind][]workapiv1.JsonPath
+	conditions map[schema.GroupVersionKind]map[string]workapiv1.ConditionRule
+}
+
+// Resolver resolves the well-known status paths and condition rules of a kind from the ConfigMap, and
+// falls back to the default rules for the kinds not in the ConfigMap. The status paths of a kind in the
+// ConfigMap replace the default ones of the kind, and a condition rule replaces the default one of the same
+// kind and condition. The rules are reloaded by the event handler of the ConfigMap informer once the
+// ConfigMap changes, and the invalid rules in it are ignored.
+type Resolver struct {
+	lister            corev1listers.ConfigMapNamespaceLister
+	name              string
+	defaultStatus     statusrules.WellKnownStatusRuleResolver
+	defaultConditions conditionrules.WellKnownConditionRuleResolver
+	ruleEnv           *cel.Env
+	messageEnv        *cel.Env
+
+	lock            sync.RWMutex
+	resourceVersion string
+	rules           *parsedRules
+	handlers        []func()
+}
+
+var _ statusrules.WellKnownStatusRuleResolver = &Resolver{}
+var _ conditionrules.WellKnownConditionRuleResolver = &Resolver{}
+
+// NewResolver returns a Resolver reading the rules from the ConfigMap with the name. The rules are loaded
+// by Reload, which is called by the EventHandler of the ConfigMap informer.
+func NewResolver(lister corev1listers.ConfigMapNamespaceLister, name string) (*Resolver, error) {
+	ruleEnv, err := conditions.NewCELEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+	messageEnv, err := conditions.NewCELEnv(cel.Variable("object", cel.DynType), cel.Variable("result", cel.BoolType))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Resolver{
+		lister:            lister,
+		name:              name,
+		defaultStatus:     statusrules.DefaultWellKnownStatusRule(),
+		defaultConditions: conditionrules.DefaultWellKnownConditionResolver(),
+		ruleEnv:           ruleEnv,
+		messageEnv:        messageEnv,
+		rules:             &parsedRules{},
+	}, nil
+}
+
+func (r *Resolver) GetPathsByKind(gvk schema.GroupVersionKind) []workapiv1.JsonPath {
+	if paths, ok := r.current().status[gvk]; ok {
+		return paths
+	}
+	return r.defaultStatus.GetPathsByKind(gvk)
+}
+
+func (r *Resolver) GetRuleByKindCondition(gvk schema.GroupVersionKind, condition string) workapiv1.ConditionRule {
+	if rule, ok := r.current().conditions[gvk][condition]; ok {
+		return rule
+	}
+	return r.defaultConditions.GetRuleByKindCondition(gvk, condition)
+}
+
+// AddEventHandler adds the handler called once the rules are reloaded from the changed ConfigMap, so the
+// ManifestWorks are requeued to be evaluated with the new rules.
+func (r *Resolver) AddEventHandler(handler func()) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.handlers = append(r.handlers, handler)
+}
+
+// EventHandler returns the event handler of the ConfigMap informer, which reloads the rules once the
+// ConfigMap is changed.
+func (r *Resolver) EventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(_ interface{}) { r.Reload() },
+		UpdateFunc: func(_, _ interface{}) { r.Reload() },
+		DeleteFunc: func(_ interface{}) { r.Reload() },
+	}
+}
+
+// Reload parses the rules of the current ConfigMap if it changes, and calls the event handlers once the
+// rules are changed. The last rules are kept if the ConfigMap could not be read.
+func (r *Resolver) Reload() {
+	if !r.reload() {
+		return
+	}
+
+	r.lock.RLock()
+	handlers := r.handlers
+	r.lock.RUnlock()
+	for _, handler := range handlers {
+		handler()
+	}
+}
+
+// reload returns true if the rules are changed.
+func (r *Resolver) reload() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	cm, err := r.lister.Get(r.name)
+	switch {
+	case apierrors.IsNotFound(err):
+		if len(r.resourceVersion) == 0 {
+			return false
+		}
+		klog.Infof("removed the well-known rules of configmap %s", r.name)
+		r.resourceVersion = ""
+		r.rules = &parsedRules{}
+		return true
+	case err != nil:
+		klog.Errorf("failed to get the well-known rules configmap %s: %v", r.name, err)
+		return false
+	case cm.ResourceVersion == r.resourceVersion:
+		return false
+	}
+
+	rules, errs := r.parse(cm.Data)
+	if len(errs) > 0 {
+		klog.Errorf("ignored invalid well-known rules in configmap %s/%s: %v",
+			cm.Namespace, cm.Name, utilerrors.NewAggregate(errs))
+	}
+	klog.Infof("loaded well-known status paths of %d kinds and condition rules of %d kinds from configmap %s/%s",
+		len(rules.status), len(rules.conditions), cm.Namespace, cm.Name)
+	r.resourceVersion = cm.ResourceVersion
+	r.rules = rules
+	return true
+}
+
+func (r *Resolver) current() *parsedRules {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.rules
+}
+
+// parse parses the rules in the keys of the ConfigMap in order, so a rule in a later key overrides the
+// rule of the same kind in an earlier key.
+func (r *Resolver) parse(data map[string]string) (*parsedRules, []error) {
+	parsed := &parsedRules{
+		status:     map[schema.GroupVersionKind][]workapiv1.JsonPath{},
+		conditions: map[schema.GroupVersionKind]map[string]workapiv1.ConditionRule{},
+	}
+	var errs []error
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var rules []Rule
+		if err := yaml.UnmarshalStrict([]byte(data[key]), &rules); err != nil {
+			errs = append(errs, fmt.Errorf("key %s: %v", key, err))
+			continue
+		}
+		for i, rule := range rules {
+			if err := r.validate(&rule); err != nil {
+				errs = append(errs, fmt.Errorf("key %s rule %d: %v", key, i, err))
+				continue
+			}
+
+			gvk := schema.GroupVersionKind{Group: rule.Group, Version: rule.Version, Kind: rule.Kind}
+			if len(rule.StatusPaths) > 0 {
+				parsed.status[gvk] = rule.StatusPaths
+			}
+			for _, conditionRule := range rule.ConditionRules {
+				if parsed.conditions[gvk] == nil {
+					parsed.conditions[gvk] = map[string]workapiv1.ConditionRule{}
+				}
+				parsed.conditions[gvk][conditionRule.Condition] = conditionRule
+			}
+		}
+	}
+	return parsed, errs
+}
+
+// validate validates the rule, and sets the type of the condition rules to CEL if it is not set.
+func (r *Resolver) validate(rule *Rule) error {
+	if len(rule.Version) == 0 || len(rule.Kind) == 0 {
+		return fmt.Errorf("version and kind are required")
+	}
+
+	for _, path := range rule.StatusPaths {
+		if len(path.Name) == 0 {
+			return fmt.Errorf("name of the status path %q is required", path.Path)
+		}
+		if err := statusfeedback.ValidatePath(path.Path); err != nil {
+			return fmt.Errorf("invalid status path %s: %v", path.Name, err)
+		}
+	}
+
+	for i := range rule.ConditionRules {
+		conditionRule := &rule.ConditionRules[i]
+		if len(conditionRule.Condition) == 0 {
+			return fmt.Errorf("condition of the condition rule is required")
+		}
+		if len(conditionRule.Type) == 0 {
+			conditionRule.Type = workapiv1.CelConditionExpressionsType
+		}
+		if conditionRule.Type != workapiv1.CelConditionExpressionsType {
+			return fmt.Errorf("type of the condition rule %s must be %s", conditionRule.Condition, workapiv1.CelConditionExpressionsType)
+		}
+		if len(conditionRule.CelExpressions) == 0 {
+			return fmt.Errorf("celExpressions of the condition rule %s are required", conditionRule.Condition)
+		}
+		for _, expression := range conditionRule.CelExpressions {
+			if _, iss := r.ruleEnv.Compile(expression); iss.Err() != nil {
+				return fmt.Errorf("invalid cel expression of the condition rule %s: %v", conditionRule.Condition, iss.Err())
+			}
+		}
+		if len(conditionRule.MessageExpression) > 0 {
+			if _, iss := r.messageEnv.Compile(conditionRule.MessageExpression); iss.Err() != nil {
+				return fmt.Errorf("invalid message expression of the condition rule %s: %v", conditionRule.Condition, iss.Err())
+			}
+		}
+	}
+	return nil
+}
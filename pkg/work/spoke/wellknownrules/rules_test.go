@@ -0,0 +1,261 @@
+package wellknownrules
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+var (
+	deploymentGVK  = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	statefulSetGVK = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}
+	jobGVK         = schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}
+	rolloutGVK     = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}
+)
+
+const (
+	statefulSetRules = `
+- group: apps
+  version: v1
+  kind: StatefulSet
+  statusPaths:
+  - name: ReadyReplicas
+    path: .status.readyReplicas
+  - name: NotReadyReplicas
+    path: cel(object.status.replicas - object.status.readyReplicas)
+  conditionRules:
+  - condition: Available
+    celExpressions:
+    - object.status.readyReplicas == object.spec.replicas
+    messageExpression: 'result ? "ready" : "not ready"'
+`
+	overrideRules = `
+- group: apps
+  version: v1
+  kind: Deployment
+  statusPaths:
+  - name: UpdatedReplicas
+    path: .status.updatedReplicas
+- group: batch
+  version: v1
+  kind: Job
+  conditionRules:
+  - condition: Complete
+    celExpressions:
+    - has(object.status.completionTime)
+`
+	invalidRules = `
+- group: argoproj.io
+  version: v1alpha1
+  kind: Rollout
+  statusPaths:
+  - name: Phase
+    path: .status.phase[
+- group: argoproj.io
+  version: v1alpha1
+  kind: Rollout
+  conditionRules:
+  - condition: Available
+    celExpressions:
+    - object.status.phase ==
+- kind: Rollout
+  statusPaths:
+  - name: Phase
+    path: .status.phase
+`
+)
+
+func newConfigMap(resourceVersion string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "rules",
+			Namespace:       "agent",
+			ResourceVersion: resourceVersion,
+		},
+		Data: data,
+	}
+}
+
+func TestResolver(t *testing.T) {
+	cases := []struct {
+		name               string
+		configMap          *corev1.ConfigMap
+		gvk                schema.GroupVersionKind
+		expectedPaths      []string
+		condition          string
+		expectedExpression string
+	}{
+		{
+			name:          "no configmap",
+			gvk:           deploymentGVK,
+			expectedPaths: []string{".status.readyReplicas", ".status.replicas", ".status.availableReplicas"},
+		},
+		{
+			name:               "no configmap with default condition",
+			gvk:                jobGVK,
+			condition:          workapiv1.ManifestComplete,
+			expectedExpression: "hasConditions(object.status)",
+		},
+		{
+			name:          "status paths of new kind",
+			configMap:     newConfigMap("1", map[string]string{"statefulset.yaml": statefulSetRules}),
+			gvk:           statefulSetGVK,
+			expectedPaths: []string{".status.readyReplicas", "cel(object.status.replicas - object.status.readyReplicas)"},
+		},
+		{
+			name:               "condition rule of new kind",
+			configMap:          newConfigMap("1", map[string]string{"statefulset.yaml": statefulSetRules}),
+			gvk:                statefulSetGVK,
+			condition:          "Available",
+			expectedExpression: "object.status.readyReplicas == object.spec.replicas",
+		},
+		{
+			name:          "default kinds are kept",
+			configMap:     newConfigMap("1", map[string]string{"statefulset.yaml": statefulSetRules}),
+			gvk:           deploymentGVK,
+			expectedPaths: []string{".status.readyReplicas", ".status.replicas", ".status.availableReplicas"},
+		},
+		{
+			name:          "override status paths",
+			configMap:     newConfigMap("1", map[string]string{"override.yaml": overrideRules}),
+			gvk:           deploymentGVK,
+			expectedPaths: []string{".status.updatedReplicas"},
+		},
+		{
+			name:               "override condition rule",
+			configMap:          newConfigMap("1", map[string]string{"override.yaml": overrideRules}),
+			gvk:                jobGVK,
+			condition:          workapiv1.ManifestComplete,
+			expectedExpression: "has(object.status.completionTime)",
+		},
+		{
+			name:          "default status paths are kept with condition rule",
+			configMap:     newConfigMap("1", map[string]string{"override.yaml": overrideRules}),
+			gvk:           jobGVK,
+			expectedPaths: []string{`.status.conditions[?(@.type=="Complete")].status`, ".status.succeeded"},
+		},
+		{
+			name: "invalid rules are ignored",
+			configMap: newConfigMap("1", map[string]string{
+				"invalid.yaml":     invalidRules,
+				"malformed.yaml":   "kind: Rollout",
+				"statefulset.yaml": statefulSetRules,
+			}),
+			gvk: rolloutGVK,
+		},
+		{
+			name: "valid rules are loaded with invalid ones",
+			configMap: newConfigMap("1", map[string]string{
+				"invalid.yaml":     invalidRules,
+				"statefulset.yaml": statefulSetRules,
+			}),
+			gvk:           statefulSetGVK,
+			expectedPaths: []string{".status.readyReplicas", "cel(object.status.replicas - object.status.readyReplicas)"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if c.configMap != nil {
+				if err := indexer.Add(c.configMap); err != nil {
+					t.Fatal(err)
+				}
+			}
+			resolver, err := NewResolver(corev1listers.NewConfigMapLister(indexer).ConfigMaps("agent"), "rules")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resolver.Reload()
+
+			if len(c.condition) > 0 {
+				rule := resolver.GetRuleByKindCondition(c.gvk, c.condition)
+				if len(rule.CelExpressions) != 1 || !strings.HasPrefix(rule.CelExpressions[0], c.expectedExpression) {
+					t.Errorf("expected expression %q, but got %v", c.expectedExpression, rule.CelExpressions)
+				}
+				if rule.Type != workapiv1.CelConditionExpressionsType {
+					t.Errorf("expected type %s, but got %s", workapiv1.CelConditionExpressionsType, rule.Type)
+				}
+				return
+			}
+
+			paths := resolver.GetPathsByKind(c.gvk)
+			if len(paths) != len(c.expectedPaths) {
+				t.Fatalf("expected paths %v, but got %v", c.expectedPaths, paths)
+			}
+			for i, path := range paths {
+				if path.Path != c.expectedPaths[i] {
+					t.Errorf("expected paths %v, but got %v", c.expectedPaths, paths)
+				}
+			}
+		})
+	}
+}
+
+func TestResolverReload(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	resolver, err := NewResolver(corev1listers.NewConfigMapLister(indexer).ConfigMaps("agent"), "rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloads := 0
+	resolver.AddEventHandler(func() { reloads++ })
+	handler := resolver.EventHandler()
+
+	resolver.Reload()
+	if paths := resolver.GetPathsByKind(statefulSetGVK); len(paths) != 0 {
+		t.Errorf("expected no paths, but got %v", paths)
+	}
+	if reloads != 0 {
+		t.Errorf("expected no reload without the configmap, but got %d", reloads)
+	}
+
+	added := newConfigMap("1", map[string]string{"statefulset.yaml": statefulSetRules})
+	if err := indexer.Add(added); err != nil {
+		t.Fatal(err)
+	}
+	// the rules are not reloaded until the event of the configmap
+	if paths := resolver.GetPathsByKind(statefulSetGVK); len(paths) != 0 {
+		t.Errorf("expected no paths, but got %v", paths)
+	}
+	handler.OnAdd(added, false)
+	if paths := resolver.GetPathsByKind(statefulSetGVK); len(paths) != 2 {
+		t.Errorf("expected 2 paths, but got %v", paths)
+	}
+
+	// the rules of the same configmap are not reloaded again
+	handler.OnUpdate(added, added)
+	if reloads != 1 {
+		t.Errorf("expected 1 reload, but got %d", reloads)
+	}
+
+	updated := newConfigMap("2", map[string]string{"override.yaml": overrideRules})
+	if err := indexer.Update(updated); err != nil {
+		t.Fatal(err)
+	}
+	handler.OnUpdate(added, updated)
+	if paths := resolver.GetPathsByKind(statefulSetGVK); len(paths) != 0 {
+		t.Errorf("expected no paths, but got %v", paths)
+	}
+	if paths := resolver.GetPathsByKind(deploymentGVK); len(paths) != 1 {
+		t.Errorf("expected 1 path, but got %v", paths)
+	}
+
+	if err := indexer.Delete(updated); err != nil {
+		t.Fatal(err)
+	}
+	handler.OnDelete(updated)
+	if paths := resolver.GetPathsByKind(deploymentGVK); len(paths) != 3 {
+		t.Errorf("expected 3 default paths, but got %v", paths)
+	}
+	if reloads != 3 {
+		t.Errorf("expected 3 reloads, but got %d", reloads)
+	}
+}
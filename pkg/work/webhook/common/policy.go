@@ -0,0 +1,246 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// AdmissionPolicy is the hub-side policy of the ManifestWorks, loaded from a yaml file, e.g.
+//
+//	maxManifestWorksPerNamespace: 100
+//	executorRequiredKinds:
+//	- group: rbac.authorization.k8s.io
+//	  kind: ClusterRole
+//	rules:
+//	- groups: ["team-a"]
+//	  allowedKinds:
+//	  - group: apps
+//	    kind: Deployment
+//	  deniedNamespaces: ["kube-*"]
+type AdmissionPolicy struct {
+	// MaxManifestWorksPerNamespace is the max number of the ManifestWorks in a cluster namespace. There is
+	// no limit if it is 0.
+	MaxManifestWorksPerNamespace int `json:"maxManifestWorksPerNamespace,omitempty"`
+	// ExecutorRequiredKinds is the kinds of the manifests which could only be applied by the ManifestWork
+	// with an executor.
+	ExecutorRequiredKinds []ResourceKind `json:"executorRequiredKinds,omitempty"`
+	// Rules restrict the manifests created by the users and groups. A manifest should be admitted by all
+	// the rules of the user.
+	Rules []AdmissionRule `json:"rules,omitempty"`
+}
+
+// AdmissionRule restricts the kinds and the namespaces of the manifests created by the users and groups.
+type AdmissionRule struct {
+	// Users and Groups are the subjects of the rule. The rule applies to all the users if both are empty.
+	Users  []string `json:"users,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+	// AllowedKinds is the kinds of the manifests allowed. All kinds are allowed if it is empty.
+	AllowedKinds []ResourceKind `json:"allowedKinds,omitempty"`
+	// DeniedKinds is the kinds of the manifests denied.
+	DeniedKinds []ResourceKind `json:"deniedKinds,omitempty"`
+	// AllowedNamespaces is the patterns of the namespaces allowed for the namespaced manifests, e.g. app-*.
+	// All namespaces are allowed if it is empty.
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// DeniedNamespaces is the patterns of the namespaces denied for the namespaced manifests.
+	DeniedNamespaces []string `json:"deniedNamespaces,omitempty"`
+}
+
+// ResourceKind is a kind of the manifests. Each field matches any value if it is "*", and the version
+// matches any version if it is empty. The empty group is the core group.
+type ResourceKind struct {
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version,omitempty"`
+	Kind    string `json:"kind"`
+}
+
+func (k ResourceKind) matches(gvk schema.GroupVersionKind) bool {
+	return (k.Group == "*" || k.Group == gvk.Group) &&
+		(k.Version == "" || k.Version == "*" || k.Version == gvk.Version) &&
+		(k.Kind == "*" || k.Kind == gvk.Kind)
+}
+
+func (k ResourceKind) String() string {
+	kind := k.Kind
+	if len(k.Version) > 0 {
+		kind = k.Version + "/" + kind
+	}
+	if len(k.Group) > 0 {
+		kind = k.Group + "/" + kind
+	}
+	return kind
+}
+
+func (r AdmissionRule) appliesTo(userInfo authenticationv1.UserInfo) bool {
+	if len(r.Users) == 0 && len(r.Groups) == 0 {
+		return true
+	}
+	if slices.Contains(r.Users, userInfo.Username) {
+		return true
+	}
+	for _, group := range userInfo.Groups {
+		if slices.Contains(r.Groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadAdmissionPolicy loads the admission policy from the file.
+func LoadAdmissionPolicy(file string) (*AdmissionPolicy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	policy := &AdmissionPolicy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to decode admission policy %s: %v", file, err)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid admission policy %s: %v", file, err)
+	}
+	return policy, nil
+}
+
+func (p *AdmissionPolicy) validate() error {
+	if p.MaxManifestWorksPerNamespace < 0 {
+		return fmt.Errorf("maxManifestWorksPerNamespace should not be negative")
+	}
+	kinds := slices.Clone(p.ExecutorRequiredKinds)
+	for i, rule := range p.Rules {
+		kinds = append(kinds, rule.AllowedKinds...)
+		kinds = append(kinds, rule.DeniedKinds...)
+		for _, pattern := range append(slices.Clone(rule.AllowedNamespaces), rule.DeniedNamespaces...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("rule %d: invalid namespace pattern %q", i, pattern)
+			}
+		}
+	}
+	for _, kind := range kinds {
+		if len(kind.Kind) == 0 {
+			return fmt.Errorf("kind is required in %s", kind)
+		}
+	}
+	return nil
+}
+
+// PolicyValidator validates the ManifestWorks with the admission policy.
+type PolicyValidator struct {
+	policy *AdmissionPolicy
+}
+
+// ManifestPolicyValidator admits all the ManifestWorks until an admission policy is set.
+var ManifestPolicyValidator = &PolicyValidator{policy: &AdmissionPolicy{}}
+
+func (v *PolicyValidator) WithPolicy(policy *AdmissionPolicy) {
+	v.policy = policy
+}
+
+// MaxManifestWorksPerNamespace returns the max number of the ManifestWorks in a cluster namespace, or 0
+// if there is no limit.
+func (v *PolicyValidator) MaxManifestWorksPerNamespace() int {
+	return v.policy.MaxManifestWorksPerNamespace
+}
+
+// ValidateCount validates the number of the ManifestWorks in the cluster namespace before a new one
+// is created.
+func (v *PolicyValidator) ValidateCount(namespace string, count int) error {
+	if limit := v.policy.MaxManifestWorksPerNamespace; limit > 0 && count >= limit {
+		return fmt.Errorf("the number of manifestworks in namespace %s reaches the limit %d of the admission policy",
+			namespace, limit)
+	}
+	return nil
+}
+
+// ValidateRenderedManifests validates the manifests rendered by the hub controller from the source, e.g. a
+// helm chart or the cluster templates. The rendered manifests are created by the controller rather than
+// the user, so they could not be validated with the rules of the user, and the source is denied if the user
+// is restricted by any rule.
+func (v *PolicyValidator) ValidateRenderedManifests(source string, userInfo authenticationv1.UserInfo) error {
+	if len(v.rulesOf(userInfo)) == 0 {
+		return nil
+	}
+	return fmt.Errorf("the manifests rendered from the %s are denied for user %s by the admission policy, "+
+		"since they could not be validated with the rules of the user", source, userInfo.Username)
+}
+
+func (v *PolicyValidator) rulesOf(userInfo authenticationv1.UserInfo) []AdmissionRule {
+	var rules []AdmissionRule
+	for _, rule := range v.policy.Rules {
+		if rule.appliesTo(userInfo) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// ValidateManifests validates the manifests created by the user, and the executor of the ManifestWork
+// applying the manifests.
+func (v *PolicyValidator) ValidateManifests(
+	manifests []workv1.Manifest, executor *workv1.ManifestWorkExecutor, userInfo authenticationv1.UserInfo) error {
+	rules := v.rulesOf(userInfo)
+	if len(rules) == 0 && len(v.policy.ExecutorRequiredKinds) == 0 {
+		return nil
+	}
+
+	for i, manifest := range manifests {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(manifest.Raw); err != nil {
+			return fmt.Errorf("failed to decode manifests[%d]: %v", i, err)
+		}
+		gvk := obj.GroupVersionKind()
+		resource := fmt.Sprintf("%s %s", gvk.Kind, obj.GetName())
+		if len(obj.GetNamespace()) > 0 {
+			resource = fmt.Sprintf("%s %s/%s", gvk.Kind, obj.GetNamespace(), obj.GetName())
+		}
+
+		if executor == nil && slices.ContainsFunc(v.policy.ExecutorRequiredKinds, func(kind ResourceKind) bool {
+			return kind.matches(gvk)
+		}) {
+			return fmt.Errorf("manifests[%d] %s requires an executor of the manifestwork by the admission policy", i, resource)
+		}
+
+		for _, rule := range rules {
+			if err := rule.admit(gvk, obj.GetNamespace()); err != nil {
+				return fmt.Errorf("manifests[%d] %s is denied for user %s by the admission policy: %v",
+					i, resource, userInfo.Username, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r AdmissionRule) admit(gvk schema.GroupVersionKind, namespace string) error {
+	matches := func(kind ResourceKind) bool { return kind.matches(gvk) }
+	kind := ResourceKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}
+	if len(r.AllowedKinds) > 0 && !slices.ContainsFunc(r.AllowedKinds, matches) {
+		return fmt.Errorf("kind %s is not allowed", kind)
+	}
+	if slices.ContainsFunc(r.DeniedKinds, matches) {
+		return fmt.Errorf("kind %s is denied", kind)
+	}
+
+	// the namespaces only restrict the namespaced manifests
+	if len(namespace) == 0 {
+		return nil
+	}
+	matchesNamespace := func(pattern string) bool {
+		matched, _ := path.Match(pattern, namespace)
+		return matched
+	}
+	if len(r.AllowedNamespaces) > 0 && !slices.ContainsFunc(r.AllowedNamespaces, matchesNamespace) {
+		return fmt.Errorf("namespace %s is not allowed", namespace)
+	}
+	if slices.ContainsFunc(r.DeniedNamespaces, matchesNamespace) {
+		return fmt.Errorf("namespace %s is denied", namespace)
+	}
+	return nil
+}
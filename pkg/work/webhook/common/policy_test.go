@@ -0,0 +1,208 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newRawManifest(raw string) workv1.Manifest {
+	manifest := workv1.Manifest{}
+	manifest.Raw = []byte(raw)
+	return manifest
+}
+
+func TestLoadAdmissionPolicy(t *testing.T) {
+	cases := []struct {
+		name        string
+		policy      string
+		expectedErr string
+	}{
+		{
+			name: "valid policy",
+			policy: `
+maxManifestWorksPerNamespace: 10
+executorRequiredKinds:
+- group: rbac.authorization.k8s.io
+  kind: ClusterRole
+rules:
+- groups: ["team-a"]
+  allowedKinds:
+  - group: apps
+    kind: Deployment
+  deniedNamespaces: ["kube-*"]
+`,
+		},
+		{
+			name:        "unknown field",
+			policy:      "maxWorks: 10",
+			expectedErr: "failed to decode admission policy",
+		},
+		{
+			name:        "negative limit",
+			policy:      "maxManifestWorksPerNamespace: -1",
+			expectedErr: "maxManifestWorksPerNamespace should not be negative",
+		},
+		{
+			name:        "missing kind",
+			policy:      "executorRequiredKinds:\n- group: apps",
+			expectedErr: "kind is required",
+		},
+		{
+			name:        "invalid namespace pattern",
+			policy:      "rules:\n- deniedNamespaces: [\"kube-[\"]",
+			expectedErr: "invalid namespace pattern",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "policy.yaml")
+			if err := os.WriteFile(file, []byte(c.policy), 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadAdmissionPolicy(file)
+			if len(c.expectedErr) == 0 && err != nil {
+				t.Errorf("expected no error, but got %v", err)
+			}
+			if len(c.expectedErr) > 0 && (err == nil || !strings.Contains(err.Error(), c.expectedErr)) {
+				t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateManifestsWithPolicy(t *testing.T) {
+	validator := &PolicyValidator{}
+	validator.WithPolicy(&AdmissionPolicy{
+		ExecutorRequiredKinds: []ResourceKind{{Group: "rbac.authorization.k8s.io", Kind: "*"}},
+		Rules: []AdmissionRule{
+			{
+				DeniedNamespaces: []string{"kube-*"},
+			},
+			{
+				Groups:            []string{"team-a"},
+				AllowedKinds:      []ResourceKind{{Group: "apps", Kind: "Deployment"}, {Kind: "ConfigMap"}},
+				AllowedNamespaces: []string{"team-a-*"},
+			},
+			{
+				Users:       []string{"bob"},
+				DeniedKinds: []ResourceKind{{Kind: "Secret", Version: "v1"}},
+			},
+		},
+	})
+
+	deployment := newRawManifest(
+		`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"app","namespace":"team-a-app"}}`)
+	secret := newRawManifest(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"app","namespace":"team-a-app"}}`)
+	clusterRole := newRawManifest(`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"app"}}`)
+	executor := &workv1.ManifestWorkExecutor{}
+	teamA := authenticationv1.UserInfo{Username: "alice", Groups: []string{"team-a"}}
+
+	cases := []struct {
+		name        string
+		manifests   []workv1.Manifest
+		executor    *workv1.ManifestWorkExecutor
+		userInfo    authenticationv1.UserInfo
+		expectedErr string
+	}{
+		{
+			name:      "allowed for the group",
+			manifests: []workv1.Manifest{deployment},
+			userInfo:  teamA,
+		},
+		{
+			name:        "kind not allowed for the group",
+			manifests:   []workv1.Manifest{deployment, secret},
+			userInfo:    teamA,
+			expectedErr: "manifests[1] Secret team-a-app/app is denied for user alice by the admission policy: kind v1/Secret is not allowed",
+		},
+		{
+			name: "namespace not allowed for the group",
+			manifests: []workv1.Manifest{newRawManifest(
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","namespace":"default"}}`)},
+			userInfo:    teamA,
+			expectedErr: "namespace default is not allowed",
+		},
+		{
+			name: "namespace denied for all users",
+			manifests: []workv1.Manifest{newRawManifest(
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","namespace":"kube-system"}}`)},
+			userInfo:    authenticationv1.UserInfo{Username: "carol"},
+			expectedErr: "namespace kube-system is denied",
+		},
+		{
+			name:        "kind denied for the user",
+			manifests:   []workv1.Manifest{secret},
+			userInfo:    authenticationv1.UserInfo{Username: "bob"},
+			expectedErr: "kind v1/Secret is denied",
+		},
+		{
+			name:      "kind allowed for other users",
+			manifests: []workv1.Manifest{secret},
+			userInfo:  authenticationv1.UserInfo{Username: "carol"},
+		},
+		{
+			name:        "executor required",
+			manifests:   []workv1.Manifest{clusterRole},
+			userInfo:    authenticationv1.UserInfo{Username: "carol"},
+			expectedErr: "manifests[0] ClusterRole app requires an executor of the manifestwork",
+		},
+		{
+			name:      "executor set",
+			manifests: []workv1.Manifest{clusterRole},
+			executor:  executor,
+			userInfo:  authenticationv1.UserInfo{Username: "carol"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validator.ValidateManifests(c.manifests, c.executor, c.userInfo)
+			if len(c.expectedErr) == 0 && err != nil {
+				t.Errorf("expected no error, but got %v", err)
+			}
+			if len(c.expectedErr) > 0 && (err == nil || !strings.Contains(err.Error(), c.expectedErr)) {
+				t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateCount(t *testing.T) {
+	validator := &PolicyValidator{}
+	validator.WithPolicy(&AdmissionPolicy{MaxManifestWorksPerNamespace: 2})
+	if err := validator.ValidateCount("cluster1", 1); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+	err := validator.ValidateCount("cluster1", 2)
+	if err == nil || !strings.Contains(err.Error(), "the number of manifestworks in namespace cluster1 reaches the limit 2") {
+		t.Errorf("expected limit error, but got %v", err)
+	}
+
+	if err := ManifestPolicyValidator.ValidateCount("cluster1", 1000); err != nil {
+		t.Errorf("expected no limit by default, but got %v", err)
+	}
+}
+
+func TestValidateRenderedManifests(t *testing.T) {
+	validator := &PolicyValidator{}
+	validator.WithPolicy(&AdmissionPolicy{
+		ExecutorRequiredKinds: []ResourceKind{{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}},
+		Rules:                 []AdmissionRule{{Groups: []string{"team-a"}, DeniedKinds: []ResourceKind{{Kind: "Secret"}}}},
+	})
+
+	if err := validator.ValidateRenderedManifests("helm source", authenticationv1.UserInfo{Username: "user1"}); err != nil {
+		t.Errorf("expected no error for the user without rules, but got %v", err)
+	}
+	err := validator.ValidateRenderedManifests("helm source",
+		authenticationv1.UserInfo{Username: "user2", Groups: []string{"team-a"}})
+	if err == nil || !strings.Contains(err.Error(), "the manifests rendered from the helm source are denied for user user2") {
+		t.Errorf("expected rendered manifests denied, but got %v", err)
+	}
+}
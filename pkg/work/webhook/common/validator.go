@@ -10,6 +10,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	workv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/spoke/statusfeedback"
 )
 
 type Validator struct {
@@ -80,6 +82,26 @@ func validateManifest(manifest []byte) error {
 	return nil
 }
 
+// ValidateManifestConfigs validates the json paths and the aggregations of the feedback rules, so an invalid
+// path is rejected on the hub instead of failing the status feedback on the managed cluster.
+func ValidateManifestConfigs(configs []workv1.ManifestConfigOption) error {
+	for _, config := range configs {
+		for _, rule := range config.FeedbackRules {
+			if rule.Type != workv1.JSONPathsType {
+				continue
+			}
+			for _, path := range rule.JsonPaths {
+				if err := statusfeedback.ValidatePath(path.Path); err != nil {
+					return fmt.Errorf("invalid feedback path %s of %s %s/%s: %v",
+						path.Name, config.ResourceIdentifier.Resource, config.ResourceIdentifier.Namespace,
+						config.ResourceIdentifier.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // manifestInfo contains the metadata needed for duplicate detection and error messages.
 type manifestInfo struct {
 	key       string // unique key for duplicate detection: apiVersion/kind/namespace/name
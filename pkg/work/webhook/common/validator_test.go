@@ -127,3 +127,51 @@ func Test_Validator(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateManifestConfigs(t *testing.T) {
+	cases := []struct {
+		name        string
+		paths       []string
+		expectedErr bool
+	}{
+		{
+			name:  "valid paths",
+			paths: []string{".status.replicas", "count(.status.conditions)", "cel(object.status.replicas > 0)"},
+		},
+		{
+			name:        "invalid json path",
+			paths:       []string{".status.conditions[?(@.type=="},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid aggregation",
+			paths:       []string{"sum(.status.replicas[)"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid cel",
+			paths:       []string{"cel(object.status.replicas >)"},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var jsonPaths []workv1.JsonPath
+			for i, path := range c.paths {
+				jsonPaths = append(jsonPaths, workv1.JsonPath{Name: fmt.Sprintf("path%d", i), Path: path})
+			}
+			configs := []workv1.ManifestConfigOption{{
+				ResourceIdentifier: workv1.ResourceIdentifier{Group: "apps", Resource: "deployments", Namespace: "default", Name: "test"},
+				FeedbackRules:      []workv1.FeedbackRule{{Type: workv1.JSONPathsType, JsonPaths: jsonPaths}},
+			}}
+			err := ValidateManifestConfigs(configs)
+			if c.expectedErr && err == nil {
+				t.Errorf("expected error, but got nil")
+			}
+			if !c.expectedErr && err != nil {
+				t.Errorf("expected no error, but got %v", err)
+			}
+		})
+	}
+}
@@ -5,6 +5,10 @@ import "github.com/spf13/pflag"
 // Config contains the server (the webhook) cert and key.
 type Options struct {
 	ManifestLimit int
+	// AdmissionPolicyFile is the yaml file of the admission policy of the ManifestWorks.
+	AdmissionPolicyFile string
+	// HelmAllowedRegistries is the hosts that the charts of the helm sources are allowed to be pulled from.
+	HelmAllowedRegistries []string
 }
 
 // NewOptions constructs a new set of default options for webhook.
@@ -17,4 +21,10 @@ func NewOptions() *Options {
 func (c *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&c.ManifestLimit, "manifestLimit", c.ManifestLimit,
 		"ManifestLimit is the max size of manifests in a manifestWork. If not set, the default is 500k.")
+	fs.StringVar(&c.AdmissionPolicyFile, "admission-policy-file", c.AdmissionPolicyFile,
+		"The yaml file of the admission policy, which limits the number of manifestWorks in a cluster namespace, "+
+			"the kinds and namespaces of the manifests of the users and groups, and the kinds requiring an executor.")
+	fs.StringSliceVar(&c.HelmAllowedRegistries, "helm-allowed-registries", c.HelmAllowedRegistries,
+		"The hosts (host[:port]) that the charts of the helm sources are allowed to be pulled from. The helm "+
+			"sources with the OCI charts of the other registries are rejected.")
 }
@@ -19,6 +19,10 @@ func TestOptions_AddFlags(t *testing.T) {
 		t.Error("manifestLimit flag not registered")
 	}
 
+	if flags.Lookup("admission-policy-file") == nil {
+		t.Error("admission-policy-file flag not registered")
+	}
+
 	// Verify default value is preserved
 	if opts.ManifestLimit != expectedDefault {
 		t.Errorf("expected %d, but got %d", expectedDefault, opts.ManifestLimit)
@@ -10,6 +10,7 @@ import (
 
 	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
 	"open-cluster-management.io/ocm/pkg/features"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
 	"open-cluster-management.io/ocm/pkg/work/webhook/common"
 	webhookv1 "open-cluster-management.io/ocm/pkg/work/webhook/v1"
 	webhookv1alpha1 "open-cluster-management.io/ocm/pkg/work/webhook/v1alpha1"
@@ -17,6 +18,14 @@ import (
 
 func (c *Options) SetupWebhookServer(opts *commonoptions.WebhookOptions) error {
 	common.ManifestValidator.WithLimit(c.ManifestLimit)
+	helmsource.AllowedRegistries.WithRegistries(c.HelmAllowedRegistries)
+	if len(c.AdmissionPolicyFile) > 0 {
+		policy, err := common.LoadAdmissionPolicy(c.AdmissionPolicyFile)
+		if err != nil {
+			return err
+		}
+		common.ManifestPolicyValidator.WithPolicy(policy)
+	}
 	if err := opts.InstallScheme(
 		clientgoscheme.AddToScheme,
 		workv1.Install,
@@ -7,6 +7,7 @@ import (
 
 	authenticationv1 "k8s.io/api/authentication/v1"
 	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -16,6 +17,7 @@ import (
 	workv1 "open-cluster-management.io/api/work/v1"
 
 	"open-cluster-management.io/ocm/pkg/features"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
 	"open-cluster-management.io/ocm/pkg/work/webhook/common"
 )
 
@@ -42,11 +44,30 @@ func (r *ManifestWorkWebhook) validateRequest(newWork, oldWork *workv1.ManifestW
 		return fmt.Errorf("newWork is nil")
 	}
 
-	if len(newWork.Spec.Workload.Manifests) == 0 {
+	// the manifests of the work with a helm source are rendered by the hub controller. The work is
+	// created without manifests, and the rendered manifests are validated when the controller updates
+	// the work with them.
+	_, hasHelmSource, err := helmsource.GetHelmSource(newWork.Annotations)
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	switch {
+	case hasHelmSource && !features.HubMutableFeatureGate.Enabled(features.HelmSource):
+		return apierrors.NewBadRequest("HelmSource feature is disabled")
+	case hasHelmSource && oldWork == nil && len(newWork.Spec.Workload.Manifests) > 0:
+		return apierrors.NewBadRequest("manifests should be empty with a helm source, they are rendered from the helm source")
+	case len(newWork.Spec.Workload.Manifests) == 0 && !hasHelmSource:
 		return apierrors.NewBadRequest("manifests should not be empty")
 	}
 
-	if err := common.ManifestValidator.ValidateManifests(newWork.Spec.Workload.Manifests); err != nil {
+	if len(newWork.Spec.Workload.Manifests) > 0 {
+		if err := common.ManifestValidator.ValidateManifests(newWork.Spec.Workload.Manifests); err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+	}
+
+	if err := common.ValidateManifestConfigs(newWork.Spec.ManifestConfigs); err != nil {
 		return apierrors.NewBadRequest(err.Error())
 	}
 
@@ -55,6 +76,15 @@ func (r *ManifestWorkWebhook) validateRequest(newWork, oldWork *workv1.ManifestW
 		return apierrors.NewBadRequest(err.Error())
 	}
 
+	if err := validatePolicy(newWork, oldWork, hasHelmSource, req.UserInfo); err != nil {
+		return apierrors.NewForbidden(workv1.Resource("manifestworks"), newWork.Name, err)
+	}
+	if oldWork == nil {
+		if err := r.validateCount(ctx, newWork); err != nil {
+			return err
+		}
+	}
+
 	// do not need to check the executor when it is not changed
 	if oldWork != nil && reflect.DeepEqual(oldWork.Spec.Executor, newWork.Spec.Executor) {
 		return nil
@@ -62,6 +92,42 @@ func (r *ManifestWorkWebhook) validateRequest(newWork, oldWork *workv1.ManifestW
 	return validateExecutor(r.kubeClient, newWork, req.UserInfo)
 }
 
+// validatePolicy validates the work with the admission policy. The helm source is validated when it is set
+// or changed, and the rendered manifests updated by the hub controller are validated with the rules of the
+// controller. The policy is not validated again when only the metadata of the work changes.
+func validatePolicy(newWork, oldWork *workv1.ManifestWork, hasHelmSource bool, userInfo authenticationv1.UserInfo) error {
+	helmSourceChanged := oldWork == nil ||
+		oldWork.Annotations[helmsource.HelmSourceAnnotation] != newWork.Annotations[helmsource.HelmSourceAnnotation]
+	if hasHelmSource && helmSourceChanged {
+		if err := common.ManifestPolicyValidator.ValidateRenderedManifests("helm source", userInfo); err != nil {
+			return err
+		}
+	}
+
+	if oldWork != nil && !helmSourceChanged &&
+		equality.Semantic.DeepEqual(oldWork.Spec.Workload, newWork.Spec.Workload) &&
+		equality.Semantic.DeepEqual(oldWork.Spec.Executor, newWork.Spec.Executor) {
+		return nil
+	}
+	return common.ManifestPolicyValidator.ValidateManifests(newWork.Spec.Workload.Manifests, newWork.Spec.Executor, userInfo)
+}
+
+// validateCount validates the number of the ManifestWorks in the namespace before the work is created.
+func (r *ManifestWorkWebhook) validateCount(ctx context.Context, work *workv1.ManifestWork) error {
+	if common.ManifestPolicyValidator.MaxManifestWorksPerNamespace() == 0 || r.metadataClient == nil {
+		return nil
+	}
+	works, err := r.metadataClient.Resource(workv1.SchemeGroupVersion.WithResource("manifestworks")).
+		Namespace(work.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if err := common.ManifestPolicyValidator.ValidateCount(work.Namespace, len(works.Items)); err != nil {
+		return apierrors.NewForbidden(workv1.Resource("manifestworks"), work.Name, err)
+	}
+	return nil
+}
+
 func validateExecutor(kubeClient kubernetes.Interface, work *workv1.ManifestWork, userInfo authenticationv1.UserInfo) error {
 	executor := work.Spec.Executor
 	if !features.HubMutableFeatureGate.Enabled(ocmfeature.NilExecutorValidating) {
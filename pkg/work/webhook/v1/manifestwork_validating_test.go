@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	admissionv1 "k8s.io/api/admission/v1"
@@ -15,14 +16,18 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	fakekube "k8s.io/client-go/kubernetes/fake"
+	fakemetadata "k8s.io/client-go/metadata/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	ocmfeature "open-cluster-management.io/api/feature"
 	workv1 "open-cluster-management.io/api/work/v1"
 
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	"open-cluster-management.io/ocm/pkg/features"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
 	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+	"open-cluster-management.io/ocm/pkg/work/webhook/common"
 )
 
 var manifestWorkSchema = metav1.GroupVersionResource{
@@ -287,7 +292,7 @@ func TestManifestWorkExecutorValidate(t *testing.T) {
 		},
 	}
 
-	utilruntime.Must(features.HubMutableFeatureGate.Add(ocmfeature.DefaultHubWorkFeatureGates))
+	utilruntime.Must(features.HubMutableFeatureGate.Add(features.DefaultHubWorkFeatureGates))
 	utilruntime.Must(features.HubMutableFeatureGate.Set(
 		fmt.Sprintf("%s=true", ocmfeature.NilExecutorValidating),
 	))
@@ -385,3 +390,180 @@ func TestManifestWorkExecutorValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestManifestWorkHelmSourceValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		manifests   int
+		disabled    bool
+		expectErr   error
+	}{
+		{
+			name:      "empty manifests without helm source",
+			expectErr: apierrors.NewBadRequest("manifests should not be empty"),
+		},
+		{
+			name: "empty manifests with helm source",
+			annotations: map[string]string{
+				helmsource.HelmSourceAnnotation: `{"chart":{"configMap":"nginx"},"values":"replicas: 2"}`,
+			},
+		},
+		{
+			name: "manifests with helm source",
+			annotations: map[string]string{
+				helmsource.HelmSourceAnnotation: `{"chart":{"configMap":"nginx"},"values":"replicas: 2"}`,
+			},
+			manifests: 1,
+			expectErr: apierrors.NewBadRequest(
+				"manifests should be empty with a helm source, they are rendered from the helm source"),
+		},
+		{
+			name: "helm source disabled",
+			annotations: map[string]string{
+				helmsource.HelmSourceAnnotation: `{"chart":{"configMap":"nginx"},"values":"replicas: 2"}`,
+			},
+			disabled:  true,
+			expectErr: apierrors.NewBadRequest("HelmSource feature is disabled"),
+		},
+		{
+			name: "invalid helm source",
+			annotations: map[string]string{
+				helmsource.HelmSourceAnnotation: `{"chart":{"configMap":"nginx","oci":"oci://registry/nginx"}}`,
+			},
+			expectErr: apierrors.NewBadRequest(
+				"invalid helm source annotation: only one of chart.configMap or chart.oci is allowed"),
+		},
+	}
+
+	utilruntime.Must(features.HubMutableFeatureGate.Add(features.DefaultHubWorkFeatureGates))
+	defer func() {
+		utilruntime.Must(features.HubMutableFeatureGate.Set(fmt.Sprintf("%s=false", features.HelmSource)))
+	}()
+
+	kubeClient := fakekube.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "subjectaccessreviews",
+		func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.SubjectAccessReview{Status: v1.SubjectAccessReviewStatus{Allowed: true}}, nil
+		},
+	)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mw := ManifestWorkWebhook{
+				kubeClient: kubeClient,
+			}
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:  manifestWorkSchema,
+					Operation: admissionv1.Create,
+					UserInfo:  authenticationv1.UserInfo{Username: "test1"},
+				},
+			})
+			utilruntime.Must(features.HubMutableFeatureGate.Set(fmt.Sprintf("%s=%v", features.HelmSource, !c.disabled)))
+			var objects []*unstructured.Unstructured
+			for i := 0; i < c.manifests; i++ {
+				objects = append(objects, testingcommon.NewUnstructured("v1", "ConfigMap", "ns1", fmt.Sprintf("cm%d", i)))
+			}
+			newWork, _ := spoketesting.NewManifestWork(0, objects...)
+			newWork.Annotations = c.annotations
+			err := mw.validateRequest(newWork, nil, ctx)
+			if !reflect.DeepEqual(err, c.expectErr) {
+				t.Errorf("case: %v, expected %v but got: %v", c.name, c.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestManifestWorkAdmissionPolicy(t *testing.T) {
+	common.ManifestPolicyValidator.WithPolicy(&common.AdmissionPolicy{
+		MaxManifestWorksPerNamespace: 1,
+		Rules: []common.AdmissionRule{
+			{Users: []string{"test1"}, DeniedKinds: []common.ResourceKind{{Kind: "Secret"}}},
+		},
+	})
+	defer common.ManifestPolicyValidator.WithPolicy(&common.AdmissionPolicy{})
+
+	existing := &metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: workv1.GroupVersion.String(), Kind: "ManifestWork"},
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "cluster1"},
+	}
+	scheme := fakemetadata.NewTestScheme()
+	if err := metav1.AddMetaToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	metadataClient := fakemetadata.NewSimpleMetadataClient(scheme, existing)
+	kubeClient := fakekube.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "subjectaccessreviews",
+		func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.SubjectAccessReview{Status: v1.SubjectAccessReviewStatus{Allowed: true}}, nil
+		},
+	)
+
+	cases := []struct {
+		name        string
+		namespace   string
+		kind        string
+		update      bool
+		expectedErr string
+	}{
+		{
+			name:      "create in namespace under limit",
+			namespace: "cluster2",
+			kind:      "ConfigMap",
+		},
+		{
+			name:        "create in namespace reaching limit",
+			namespace:   "cluster1",
+			kind:        "ConfigMap",
+			expectedErr: "the number of manifestworks in namespace cluster1 reaches the limit 1",
+		},
+		{
+			name:      "update in namespace reaching limit",
+			namespace: "cluster1",
+			kind:      "ConfigMap",
+			update:    true,
+		},
+		{
+			name:        "denied kind",
+			namespace:   "cluster2",
+			kind:        "Secret",
+			expectedErr: "manifests[0] Secret ns1/test is denied for user test1 by the admission policy: kind v1/Secret is denied",
+		},
+		{
+			name:      "metadata update of denied kind",
+			namespace: "cluster2",
+			kind:      "Secret",
+			update:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mw := ManifestWorkWebhook{
+				kubeClient:     kubeClient,
+				metadataClient: metadataClient,
+			}
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:  manifestWorkSchema,
+					Operation: admissionv1.Create,
+					UserInfo:  authenticationv1.UserInfo{Username: "test1"},
+				},
+			})
+			newWork, _ := spoketesting.NewManifestWork(0, testingcommon.NewUnstructured("v1", c.kind, "ns1", "test"))
+			newWork.Namespace = c.namespace
+			var oldWork *workv1.ManifestWork
+			if c.update {
+				oldWork = newWork.DeepCopy()
+			}
+			err := mw.validateRequest(newWork, oldWork, ctx)
+			if len(c.expectedErr) == 0 && err != nil {
+				t.Errorf("expected no error, but got %v", err)
+			}
+			if len(c.expectedErr) > 0 && (!apierrors.IsForbidden(err) || !strings.Contains(err.Error(), c.expectedErr)) {
+				t.Errorf("expected forbidden error %q, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
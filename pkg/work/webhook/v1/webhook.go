@@ -2,6 +2,7 @@ package v1
 
 import (
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	v1 "open-cluster-management.io/api/work/v1"
@@ -9,6 +10,8 @@ import (
 
 type ManifestWorkWebhook struct {
 	kubeClient kubernetes.Interface
+	// metadataClient counts the ManifestWorks in a cluster namespace for the admission policy
+	metadataClient metadata.Interface
 }
 
 func (r *ManifestWorkWebhook) Init(mgr ctrl.Manager) error {
@@ -17,6 +20,10 @@ func (r *ManifestWorkWebhook) Init(mgr ctrl.Manager) error {
 		return err
 	}
 	r.kubeClient, err = kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	r.metadataClient, err = metadata.NewForConfig(mgr.GetConfig())
 	return err
 }
 
@@ -25,6 +32,11 @@ func (r *ManifestWorkWebhook) SetExternalKubeClientSet(client kubernetes.Interfa
 	r.kubeClient = client
 }
 
+// SetExternalMetadataClient sets the client to count the ManifestWorks in a cluster namespace
+func (r *ManifestWorkWebhook) SetExternalMetadataClient(client metadata.Interface) {
+	r.metadataClient = client
+}
+
 func (r *ManifestWorkWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr, &v1.ManifestWork{}).
 		WithValidator(r).
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -12,6 +14,9 @@ import (
 	workv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
 
 	"open-cluster-management.io/ocm/pkg/features"
+	"open-cluster-management.io/ocm/pkg/work/hub/clustertemplate"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
+	"open-cluster-management.io/ocm/pkg/work/hub/rollout"
 	"open-cluster-management.io/ocm/pkg/work/webhook/common"
 )
 
@@ -40,7 +45,7 @@ func (r *ManifestWorkReplicaSetWebhook) ValidateDelete(_ context.Context, _ *wor
 }
 
 func (r *ManifestWorkReplicaSetWebhook) validateRequest(
-	newmwrSet *workv1alpha1.ManifestWorkReplicaSet, _ *workv1alpha1.ManifestWorkReplicaSet,
+	newmwrSet *workv1alpha1.ManifestWorkReplicaSet, oldmwrSet *workv1alpha1.ManifestWorkReplicaSet,
 	ctx context.Context) error {
 	if err := checkFeatureEnabled(); err != nil {
 		return err
@@ -50,22 +55,115 @@ func (r *ManifestWorkReplicaSetWebhook) validateRequest(
 		return fmt.Errorf("new manifestwork replica set is nil")
 	}
 
-	if err := validatePlaceManifests(newmwrSet); err != nil {
+	// the manifests are rendered from the helm source by the controller, and validated by the
+	// ManifestWork webhook when the ManifestWorks are created or updated.
+	_, hasHelmSource, err := helmsource.GetHelmSource(newmwrSet.Annotations)
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	if hasHelmSource {
+		if err := validateHelmSource(newmwrSet); err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+	} else if err := validatePlaceManifests(newmwrSet); err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	if err := validateClusterTemplate(newmwrSet); err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	if err := common.ValidateManifestConfigs(newmwrSet.Spec.ManifestWorkTemplate.ManifestConfigs); err != nil {
 		return apierrors.NewBadRequest(err.Error())
 	}
 
-	_, err := admission.RequestFromContext(ctx)
+	if _, err := rollout.GetRollbackTimeout(newmwrSet.Annotations); err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	if _, err := rollout.GetPromotedGroup(newmwrSet.Annotations); err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	req, err := admission.RequestFromContext(ctx)
 	if err != nil {
 		return apierrors.NewBadRequest(err.Error())
 	}
 
+	if err := validatePolicy(newmwrSet, oldmwrSet, hasHelmSource, req); err != nil {
+		return apierrors.NewForbidden(workv1alpha1.Resource("manifestworkreplicasets"), newmwrSet.Name, err)
+	}
+
 	return nil
 }
 
+// renderingAnnotations are the annotations of the ManifestWorkReplicaSet rendering the manifests of the
+// ManifestWorkTemplate.
+var renderingAnnotations = []string{
+	helmsource.HelmSourceAnnotation,
+	clustertemplate.ClusterTemplateAnnotation,
+	clustertemplate.TemplateValuesAnnotation,
+}
+
+// validatePolicy validates the ManifestWorkReplicaSet with the admission policy. The ManifestWorks are
+// created by the controller, so the manifests are validated with the user creating or updating the
+// ManifestWorkReplicaSet. The manifests rendered from a helm source or the cluster templates are denied
+// for the users restricted by the rules, and the policy is not validated again when the template and the
+// rendering annotations are not changed, e.g. the annotations and the finalizer set by the controller.
+func validatePolicy(newmwrSet, oldmwrSet *workv1alpha1.ManifestWorkReplicaSet, hasHelmSource bool,
+	req admission.Request) error {
+	if oldmwrSet != nil && equality.Semantic.DeepEqual(
+		oldmwrSet.Spec.ManifestWorkTemplate, newmwrSet.Spec.ManifestWorkTemplate) &&
+		!slices.ContainsFunc(renderingAnnotations, func(key string) bool {
+			return oldmwrSet.Annotations[key] != newmwrSet.Annotations[key]
+		}) {
+		return nil
+	}
+
+	if hasHelmSource {
+		if err := common.ManifestPolicyValidator.ValidateRenderedManifests("helm source", req.UserInfo); err != nil {
+			return err
+		}
+	}
+	if clustertemplate.Enabled(newmwrSet.Annotations) {
+		if err := common.ManifestPolicyValidator.ValidateRenderedManifests("cluster templates", req.UserInfo); err != nil {
+			return err
+		}
+	}
+
+	template := newmwrSet.Spec.ManifestWorkTemplate
+	return common.ManifestPolicyValidator.ValidateManifests(template.Workload.Manifests, template.Executor, req.UserInfo)
+}
+
 func validatePlaceManifests(mwrSet *workv1alpha1.ManifestWorkReplicaSet) error {
 	return common.ManifestValidator.ValidateManifests(mwrSet.Spec.ManifestWorkTemplate.Workload.Manifests)
 }
 
+// validateHelmSource validates the ManifestWorkReplicaSet with a helm source. The rendered manifests replace
+// the manifests of the ManifestWorkTemplate, so the template should not have any manifest.
+func validateHelmSource(mwrSet *workv1alpha1.ManifestWorkReplicaSet) error {
+	if !features.HubMutableFeatureGate.Enabled(features.HelmSource) {
+		return errors.New("HelmSource feature is disabled")
+	}
+	if len(mwrSet.Spec.ManifestWorkTemplate.Workload.Manifests) > 0 {
+		return errors.New("manifests should be empty with a helm source, they are rendered from the helm source")
+	}
+	return nil
+}
+
+// validateClusterTemplate validates the templates in the manifests and the template values if the
+// per-cluster templating is enabled. The templates are rendered for each cluster by the controller.
+func validateClusterTemplate(mwrSet *workv1alpha1.ManifestWorkReplicaSet) error {
+	if !clustertemplate.Enabled(mwrSet.Annotations) {
+		return nil
+	}
+	if _, err := clustertemplate.GetValues(mwrSet.Annotations); err != nil {
+		return err
+	}
+	return clustertemplate.ValidateManifests(mwrSet.Spec.ManifestWorkTemplate.Workload.Manifests)
+}
+
 func checkFeatureEnabled() error {
 	if !features.HubMutableFeatureGate.Enabled(ocmfeature.ManifestWorkReplicaSet) {
 		return errors.New("ManifestWorkReplicaSet feature is disabled")
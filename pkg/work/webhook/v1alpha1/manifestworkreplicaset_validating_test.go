@@ -8,13 +8,18 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	ocmfeature "open-cluster-management.io/api/feature"
 	workv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
 
 	"open-cluster-management.io/ocm/pkg/features"
+	"open-cluster-management.io/ocm/pkg/work/hub/clustertemplate"
+	"open-cluster-management.io/ocm/pkg/work/hub/helmsource"
+	"open-cluster-management.io/ocm/pkg/work/hub/rollout"
 	helpertest "open-cluster-management.io/ocm/pkg/work/hub/test"
+	"open-cluster-management.io/ocm/pkg/work/webhook/common"
 )
 
 var manifestWorkReplicaSetSchema = metav1.GroupVersionResource{
@@ -58,6 +63,133 @@ func TestWebHookValidateRequest(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	// the manifests are empty with a helm source
+	mwrSet = &workv1alpha1.ManifestWorkReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				helmsource.HelmSourceAnnotation: `{"chart":{"configMap":"nginx"}}`,
+			},
+		},
+		Spec: workv1alpha1.ManifestWorkReplicaSetSpec{
+			PlacementRefs: []workv1alpha1.LocalPlacementReference{placementRef},
+		},
+	}
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if !apierrors.IsBadRequest(err) {
+		t.Fatalf("Expecting bad request error for disabled helm source, but got %v", err)
+	}
+
+	if err := features.HubMutableFeatureGate.Set(fmt.Sprintf("%s=true", features.HelmSource)); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		utilruntime.Must(features.HubMutableFeatureGate.Set(fmt.Sprintf("%s=false", features.HelmSource)))
+	}()
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mwrSet.Annotations[helmsource.HelmSourceAnnotation] = `{"chart":{"configMap":"nginx"},"values":"{{ .ClusterName"}`
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if !apierrors.IsBadRequest(err) {
+		t.Fatalf("Expecting bad request error for invalid helm source, but got %v", err)
+	}
+
+	helmSet := helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+	helmSet.Annotations = map[string]string{helmsource.HelmSourceAnnotation: `{"chart":{"configMap":"nginx"}}`}
+	err = webHook.validateRequest(helmSet, nil, ctx)
+	if !apierrors.IsBadRequest(err) {
+		t.Fatalf("Expecting bad request error for manifests with helm source, but got %v", err)
+	}
+
+	mwrSet = helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+	mwrSet.Annotations = map[string]string{
+		clustertemplate.ClusterTemplateAnnotation: "true",
+		clustertemplate.TemplateValuesAnnotation:  `{"registry": "registry.io"}`,
+	}
+	mwrSet.Spec.ManifestWorkTemplate.Workload.Manifests[0].Raw = []byte(
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .ClusterName }}","namespace":"default"}}`)
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mwrSet.Annotations[clustertemplate.TemplateValuesAnnotation] = "registry"
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if !apierrors.IsBadRequest(err) {
+		t.Fatalf("Expecting bad request error for invalid template values, but got %v", err)
+	}
+
+	delete(mwrSet.Annotations, clustertemplate.TemplateValuesAnnotation)
+	mwrSet.Spec.ManifestWorkTemplate.Workload.Manifests[0].Raw = []byte(
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .ClusterName","namespace":"default"}}`)
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if !apierrors.IsBadRequest(err) {
+		t.Fatalf("Expecting bad request error for invalid template, but got %v", err)
+	}
+
+	mwrSet = helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+	mwrSet.Annotations = map[string]string{
+		rollout.AutoRollbackAnnotation:    "true",
+		rollout.RollbackTimeoutAnnotation: "30m",
+	}
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mwrSet.Annotations[rollout.RollbackTimeoutAnnotation] = "30"
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if !apierrors.IsBadRequest(err) {
+		t.Fatalf("Expecting bad request error for invalid rollback timeout, but got %v", err)
+	}
+
+	mwrSet.Annotations = map[string]string{
+		rollout.PromotionGateAnnotation: "true",
+		rollout.PromotedGroupAnnotation: "1",
+	}
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mwrSet.Annotations[rollout.PromotedGroupAnnotation] = "-1"
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if !apierrors.IsBadRequest(err) {
+		t.Fatalf("Expecting bad request error for invalid promoted group, but got %v", err)
+	}
+
+	common.ManifestPolicyValidator.WithPolicy(&common.AdmissionPolicy{
+		Rules: []common.AdmissionRule{{DeniedNamespaces: []string{"test-*"}}},
+	})
+	defer common.ManifestPolicyValidator.WithPolicy(&common.AdmissionPolicy{})
+	mwrSet = helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if !apierrors.IsForbidden(err) {
+		t.Fatalf("Expecting forbidden error for the namespace denied by the admission policy, but got %v", err)
+	}
+
+	// the annotations set by the controller are not validated with the policy again
+	oldmwrSet := mwrSet.DeepCopy()
+	mwrSet.Annotations = map[string]string{rollout.PromotedRevisionAnnotation: "1a2b3c4d5e"}
+	err = webHook.validateRequest(mwrSet, oldmwrSet, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the manifests rendered by the controller are denied for the users restricted by the rules
+	mwrSet = helpertest.CreateTestManifestWorkReplicaSet("mwrSet-test", "default", "place-test")
+	mwrSet.Spec.ManifestWorkTemplate.Workload.Manifests[0].Raw = []byte(
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .ClusterName }}","namespace":"default"}}`)
+	mwrSet.Annotations = map[string]string{clustertemplate.ClusterTemplateAnnotation: "true"}
+	err = webHook.validateRequest(mwrSet, nil, ctx)
+	if !apierrors.IsForbidden(err) {
+		t.Fatalf("Expecting forbidden error for the cluster templates, but got %v", err)
+	}
 }
 
 func TestWebHookCreateRequest(t *testing.T) {
@@ -119,7 +251,7 @@ func TestWebHookUpdateRequest(t *testing.T) {
 
 func setupFeatureGate(t *testing.T) {
 	defaultFG := features.HubMutableFeatureGate
-	if err := defaultFG.Add(ocmfeature.DefaultHubWorkFeatureGates); err != nil {
+	if err := defaultFG.Add(features.DefaultHubWorkFeatureGates); err != nil {
 		t.Fatal(err)
 	}
 	if err := defaultFG.Set(fmt.Sprintf("%s=true", ocmfeature.ManifestWorkReplicaSet)); err != nil {
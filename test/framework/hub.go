@@ -10,6 +10,7 @@ import (
 
 	ocmfeature "open-cluster-management.io/api/feature"
 
+	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
 )
 
@@ -76,7 +77,7 @@ func (hub *Hub) CheckHubReady() error {
 
 	// if manifestworkreplicaset feature is enabled, check the work controller
 	if cm.Spec.WorkConfiguration != nil &&
-		helpers.FeatureGateEnabled(cm.Spec.WorkConfiguration.FeatureGates, ocmfeature.DefaultHubWorkFeatureGates, ocmfeature.ManifestWorkReplicaSet) {
+		helpers.FeatureGateEnabled(cm.Spec.WorkConfiguration.FeatureGates, features.DefaultHubWorkFeatureGates, ocmfeature.ManifestWorkReplicaSet) {
 		if err = CheckDeploymentReady(ctx, hub.KubeClient, hub.ClusterManagerNamespace, fmt.Sprintf("%s-work-controller", hub.ClusterManagerName)); err != nil {
 			return fmt.Errorf("failed to check work controller: %w", err)
 		}